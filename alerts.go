@@ -0,0 +1,79 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+)
+
+var securityAlertsFlag = flag.Bool("security-alerts", false, "report open code scanning and Dependabot alert counts (needs security_events scope)")
+
+// securityAlerts summarises a repo's open code scanning and Dependabot
+// alerts. A source is nil when the token can't read it or the feature is
+// off for the repo; Unavailable says which and why.
+type securityAlerts struct {
+	CodeScanning *alertCounts      `json:"codeScanning"`
+	Dependabot   *alertCounts      `json:"dependabot"`
+	Unavailable  map[string]string `json:"unavailable,omitempty"`
+}
+
+type alertCounts struct {
+	Open       int `json:"open"`
+	OldestDays int `json:"oldestDays"`
+}
+
+type alert struct {
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// errNoAccess marks the 403/404 GitHub returns when a token lacks alert
+// permissions or the repo doesn't have the feature enabled.
+var errNoAccess = errors.New("not accessible")
+
+func ghListAlerts(ctx context.Context, owner, repo, kind string) ([]alert, error) {
+	alerts, err := ghAPIList[alert](ctx, fmt.Sprintf("repos/%s/%s/%s/alerts?state=open&per_page=100", owner, repo, kind))
+	if err != nil && (strings.Contains(err.Error(), "HTTP 403") || strings.Contains(err.Error(), "HTTP 404")) {
+		return nil, fmt.Errorf("%w: %v", errNoAccess, err)
+	}
+	return alerts, err
+}
+
+func countAlerts(alerts []alert, now time.Time) *alertCounts {
+	ac := &alertCounts{Open: len(alerts)}
+	for _, a := range alerts {
+		if age := daysBetween(a.CreatedAt, now); age > ac.OldestDays {
+			ac.OldestDays = age
+		}
+	}
+	return ac
+}
+
+func addSecurityAlerts(ctx context.Context, score *repoScore, owner, repo string, cfg config, now time.Time) {
+	if !cfg.SecurityAlerts {
+		return
+	}
+	sa := &securityAlerts{}
+	for _, kind := range []string{"code-scanning", "dependabot"} {
+		alerts, err := ghListAlerts(ctx, owner, repo, kind)
+		if err != nil {
+			if !errors.Is(err, errNoAccess) {
+				slog.Warn("security alerts unavailable", "repo", repo, "kind", kind, "error", err)
+			}
+			if sa.Unavailable == nil {
+				sa.Unavailable = map[string]string{}
+			}
+			sa.Unavailable[kind] = err.Error()
+			continue
+		}
+		if kind == "code-scanning" {
+			sa.CodeScanning = countAlerts(alerts, now)
+		} else {
+			sa.Dependabot = countAlerts(alerts, now)
+		}
+	}
+	score.SecurityAlerts = sa
+}
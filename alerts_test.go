@@ -0,0 +1,17 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCountAlerts(t *testing.T) {
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	ac := countAlerts([]alert{{CreatedAt: now.AddDate(0, 0, -3)}, {CreatedAt: now.AddDate(0, 0, -200)}}, now)
+	if ac.Open != 2 || ac.OldestDays != 200 {
+		t.Errorf("countAlerts = %+v, want 2 open, oldest 200", ac)
+	}
+	if empty := countAlerts(nil, now); empty.Open != 0 || empty.OldestDays != 0 {
+		t.Errorf("no alerts = %+v", empty)
+	}
+}
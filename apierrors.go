@@ -0,0 +1,28 @@
+package main
+
+import (
+	"log/slog"
+
+	ghscan "github.com/misty-step/fab-backlog/pkg/scan"
+)
+
+// apiError is a part of a repo's data GitHub couldn't return: a node of a
+// GraphQL response that came back null with an error while the rest of
+// the response was usable. The metric it belongs to was computed without
+// that node.
+type apiError struct {
+	Metric string `json:"metric"`
+	ghscan.GraphQLError
+}
+
+// addAPIErrors attaches errs from a partly resolved query for metric to
+// the repo.
+func addAPIErrors(score *repoScore, metric string, errs []ghscan.GraphQLError) {
+	if len(errs) == 0 {
+		return
+	}
+	for _, e := range errs {
+		score.APIErrors = append(score.APIErrors, apiError{Metric: metric, GraphQLError: e})
+	}
+	slog.Warn("partial response; metric computed from the nodes returned", "repo", score.fullName, "metric", metric, "errors", len(errs), "first", errs[0].Message)
+}
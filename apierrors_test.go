@@ -0,0 +1,33 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestLabelLatencyFromPartialResponse(t *testing.T) {
+	now := time.Date(2026, 10, 15, 0, 0, 0, 0, time.UTC)
+	prev := runCmd
+	t.Cleanup(func() { runCmd = prev })
+	runCmd = func(ctx context.Context, bin string, args ...string) ([]byte, error) {
+		return []byte(`{"data":{"search":{"nodes":[
+			{"createdAt":"2026-10-01T00:00:00Z","timelineItems":{"nodes":[{"createdAt":"2026-10-01T05:00:00Z"}]}},
+			{"createdAt":"2026-10-02T00:00:00Z","timelineItems":null},
+			null
+		]}},"errors":[
+			{"type":"FORBIDDEN","message":"timeline hidden","path":["search","nodes",1,"timelineItems"]},
+			{"type":"NOT_FOUND","message":"issue gone","path":["search","nodes",2]}
+		]}`), errors.New("gh: timeline hidden")
+	}
+	rs := repoScore{Name: "api", fullName: "acme/api"}
+	addLabelLatency(t.Context(), &rs, "acme", "api", config{LabelLatencyWindowDays: 30}, now)
+	if rs.FirstLabel == nil || rs.FirstLabel.Sampled != 1 || rs.FirstLabel.Labeled != 1 || rs.FirstLabel.Unlabeled != 0 {
+		t.Fatalf("firstLabel = %+v; want only the issue that came back", rs.FirstLabel)
+	}
+	// gh failed, so the result is also flagged as possibly missing pages.
+	if len(rs.APIErrors) != 3 || rs.APIErrors[0].Metric != "firstLabel" || rs.APIErrors[1].Type != "NOT_FOUND" || rs.APIErrors[2].Type != "INCOMPLETE" {
+		t.Errorf("apiErrors = %+v", rs.APIErrors)
+	}
+}
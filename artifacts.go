@@ -0,0 +1,112 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+var (
+	outputDir = flag.String("output-dir", "", "write the report to a timestamped file in this directory instead of stdout")
+	keepRuns  = flag.Int("keep", 0, "with -output-dir, keep only the newest N reports (0 keeps all)")
+)
+
+const (
+	artifactPrefix = "fab-backlog-"
+	artifactSuffix = ".json"
+	latestLink     = "latest.json"
+)
+
+// artifactName is sortable, so lexical order is chronological order.
+func artifactName(at time.Time) string {
+	return artifactPrefix + at.UTC().Format("20060102T150405Z") + artifactSuffix
+}
+
+// writeArtifact saves v under dir with a timestamped name, points
+// latest.json at it and prunes all but the newest keep reports. It returns
+// the path written.
+func writeArtifact(dir string, keep int, v any, at time.Time) (string, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", fmt.Errorf("create output dir: %w", err)
+	}
+	var buf bytes.Buffer
+	if err := writeJSON(&buf, v); err != nil {
+		return "", err
+	}
+	name := artifactName(at)
+	path := filepath.Join(dir, name)
+	if err := writeFileAtomic(path, buf.Bytes()); err != nil {
+		return "", err
+	}
+	if err := relink(filepath.Join(dir, latestLink), name); err != nil {
+		return path, err
+	}
+	if keep > 0 {
+		if err := pruneArtifacts(dir, keep); err != nil {
+			return path, err
+		}
+	}
+	return path, nil
+}
+
+// writeFileAtomic writes via a temp file and rename so readers never see a
+// partial report.
+func writeFileAtomic(path string, data []byte) error {
+	f, err := createAtomic(path)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.abort()
+		return fmt.Errorf("write %s: %w", path, err)
+	}
+	return f.commit()
+}
+
+// relink atomically replaces the symlink at link with one pointing at target.
+func relink(link, target string) error {
+	tmp := link + ".tmp"
+	_ = os.Remove(tmp)
+	if err := os.Symlink(target, tmp); err != nil {
+		return fmt.Errorf("link %s: %w", link, err)
+	}
+	if err := os.Rename(tmp, link); err != nil {
+		return fmt.Errorf("link %s: %w", link, err)
+	}
+	return nil
+}
+
+// listArtifacts returns the report file names in dir, oldest first.
+func listArtifacts(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, e := range entries {
+		if n := e.Name(); e.Type().IsRegular() && strings.HasPrefix(n, artifactPrefix) && strings.HasSuffix(n, artifactSuffix) {
+			names = append(names, n)
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+func pruneArtifacts(dir string, keep int) error {
+	names, err := listArtifacts(dir)
+	if err != nil {
+		return fmt.Errorf("prune %s: %w", dir, err)
+	}
+	for len(names) > keep {
+		if err := os.Remove(filepath.Join(dir, names[0])); err != nil {
+			return fmt.Errorf("prune %s: %w", dir, err)
+		}
+		names = names[1:]
+	}
+	return nil
+}
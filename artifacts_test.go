@@ -0,0 +1,36 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestWriteArtifactRetention(t *testing.T) {
+	dir := t.TempDir()
+	start := time.Date(2025, 6, 1, 9, 0, 0, 0, time.UTC)
+	var last string
+	for i := 0; i < 4; i++ {
+		path, err := writeArtifact(dir, 2, map[string]int{"run": i}, start.Add(time.Duration(i)*time.Hour))
+		if err != nil {
+			t.Fatalf("run %d: %v", i, err)
+		}
+		last = path
+	}
+	names, err := listArtifacts(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(names) != 2 || names[0] != "fab-backlog-20250601T110000Z.json" || names[1] != "fab-backlog-20250601T120000Z.json" {
+		t.Errorf("kept %v, want the two newest", names)
+	}
+	target, err := os.Readlink(filepath.Join(dir, latestLink))
+	if err != nil || target != filepath.Base(last) {
+		t.Errorf("latest -> %q (err %v), want %q", target, err, filepath.Base(last))
+	}
+	data, err := os.ReadFile(filepath.Join(dir, latestLink))
+	if err != nil || string(data) != "{\"run\":3}\n" {
+		t.Errorf("latest.json = %q (err %v)", data, err)
+	}
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"time"
+)
+
+var asOf = flag.String("as-of", "", "score the backlog as of a past date (YYYY-MM-DD, midnight UTC, or RFC 3339) instead of now")
+
+// pinAsOf sets the clock to -as-of. It runs after pinClock, so an explicit
+// date wins over $SOURCE_DATE_EPOCH.
+func pinAsOf() error {
+	if *asOf == "" {
+		return nil
+	}
+	at, err := parseAsOf(*asOf)
+	if err != nil {
+		return err
+	}
+	if at.After(time.Now()) {
+		return fmt.Errorf("-as-of %s is in the future", *asOf)
+	}
+	clock = func() time.Time { return at }
+	return nil
+}
+
+func parseAsOf(s string) (time.Time, error) {
+	if at, err := time.Parse("2006-01-02", s); err == nil {
+		return at, nil
+	}
+	at, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("-as-of: want YYYY-MM-DD or RFC 3339, got %q", s)
+	}
+	return at.UTC(), nil
+}
+
+// openAt drops issues created after now. GitHub only answers with the
+// backlog as it stands, so an -as-of scan can leave out what was filed
+// since, but not bring back what was closed since or undo later updates.
+func openAt(issues []issue, now time.Time) []issue {
+	out := issues[:0:0]
+	for _, is := range issues {
+		if !is.CreatedAt.After(now) {
+			out = append(out, is)
+		}
+	}
+	return out
+}
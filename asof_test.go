@@ -0,0 +1,55 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPinAsOf(t *testing.T) {
+	prevClock, prevFlag := clock, *asOf
+	t.Cleanup(func() { clock, *asOf = prevClock, prevFlag })
+	for in, want := range map[string]time.Time{
+		"2024-06-01":                time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC),
+		"2024-06-01T09:30:00+02:00": time.Date(2024, 6, 1, 7, 30, 0, 0, time.UTC),
+	} {
+		*asOf = in
+		if err := pinAsOf(); err != nil || !clock().Equal(want) {
+			t.Errorf("-as-of %s: clock %v, err %v; want %v", in, clock(), err, want)
+		}
+	}
+	for _, bad := range []string{"June 1", "2024-13-01", time.Now().AddDate(0, 0, 2).Format("2006-01-02")} {
+		*asOf = bad
+		if err := pinAsOf(); err == nil {
+			t.Errorf("-as-of %s: want error", bad)
+		}
+	}
+}
+
+func TestScanAsOf(t *testing.T) {
+	prevClock := clock
+	t.Cleanup(func() { clock = prevClock })
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	gh := newFakeGitHub(t)
+	gh.addRepo(&fakeRepo{Owner: "acme", Name: "api", Issues: []*fakeIssue{
+		{Number: 1, State: "open", CreatedAt: now.AddDate(0, -8, 0), UpdatedAt: now.AddDate(0, -6, 0)},
+		{Number: 2, State: "open", CreatedAt: now.AddDate(0, -8, 0), UpdatedAt: now.AddDate(0, -2, 0)},
+		{Number: 3, State: "open", CreatedAt: now.AddDate(0, 0, -10), UpdatedAt: now.AddDate(0, 0, -10)},
+	}})
+	sel := selection{Orgs: []string{"acme"}}
+	cfg := config{MinIssues: 1, StaleDays: 90}
+
+	clock = func() time.Time { return now }
+	if rs := scan(t.Context(), sel, []string{"api"}, cfg).Repos[0]; rs.TotalOpen != 3 || rs.StaleCount != 1 {
+		t.Errorf("today: open %d, stale %d; want 3, 1", rs.TotalOpen, rs.StaleCount)
+	}
+	// Twenty days back, before #3 was filed.
+	at := now.AddDate(0, 0, -20)
+	clock = func() time.Time { return at }
+	out := scan(t.Context(), sel, []string{"api"}, cfg)
+	if rs := out.Repos[0]; rs.TotalOpen != 2 || rs.StaleCount != 1 {
+		t.Errorf("as of %s: open %d, stale %d; want 2, 1", at.Format(time.DateOnly), rs.TotalOpen, rs.StaleCount)
+	}
+	if out.GeneratedAt != at.Format(time.RFC3339) {
+		t.Errorf("generatedAt = %s", out.GeneratedAt)
+	}
+}
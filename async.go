@@ -0,0 +1,20 @@
+package main
+
+// async starts f in its own goroutine and returns a function that waits for
+// and returns its result. The result is buffered, so abandoning the wait
+// doesn't leak the goroutine.
+func async[T any](f func() (T, error)) func() (T, error) {
+	type result struct {
+		v   T
+		err error
+	}
+	ch := make(chan result, 1)
+	go func() {
+		v, err := f()
+		ch <- result{v, err}
+	}()
+	return func() (T, error) {
+		r := <-ch
+		return r.v, r.err
+	}
+}
@@ -0,0 +1,23 @@
+package main
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestAsyncRunsConcurrently(t *testing.T) {
+	started := make(chan struct{})
+	wait := async(func() (int, error) {
+		close(started)
+		return 42, errors.New("partial")
+	})
+	select {
+	case <-started:
+	case <-time.After(time.Second):
+		t.Fatal("async did not start f before the caller waited")
+	}
+	if v, err := wait(); v != 42 || err == nil {
+		t.Errorf("got %d, %v", v, err)
+	}
+}
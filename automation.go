@@ -0,0 +1,87 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var automationBots = flag.String("automation-bots", "", "comma-separated bot logins whose open PRs are reported as automationBacklog, e.g. dependabot,renovate (empty disables)")
+
+// automationLimit caps the open PRs fetched per repo for the automation
+// backlog; a repo past it is already well into the failure mode.
+const automationLimit = 1000
+
+// automationBacklog reports unmerged dependency-bump PRs, kept apart from
+// the issue metrics because hundreds of them is its own kind of rot.
+type automationBacklog struct {
+	Open          int            `json:"open"`
+	OldestDays    int            `json:"oldestDays"`
+	MedianAgeDays int            `json:"medianAgeDays"`
+	ByBot         map[string]int `json:"byBot"`
+}
+
+type pullRequest struct {
+	Number    int       `json:"number"`
+	CreatedAt time.Time `json:"createdAt"`
+	Author    actor     `json:"author"`
+}
+
+func ghListOpenPRs(ctx context.Context, owner, repo string, limit int) ([]pullRequest, error) {
+	args := []string{"pr", "list", "--repo", owner + "/" + repo, "--state", "open", "--json", "number,createdAt,author", "--limit", strconv.Itoa(limit)}
+	stdout, err := runCmd(ctx, "gh", args...)
+	if err != nil {
+		return nil, err
+	}
+	var prs []pullRequest
+	if err := json.Unmarshal(stdout, &prs); err != nil {
+		return nil, fmt.Errorf("parse gh pr list json: %w", err)
+	}
+	return prs, nil
+}
+
+// botName normalises the spellings gh and the API use for an app's login:
+// "app/dependabot", "dependabot[bot]" and "dependabot" are the same bot.
+func botName(login string) string {
+	login = strings.TrimPrefix(strings.ToLower(login), "app/")
+	return strings.TrimSuffix(login, "[bot]")
+}
+
+func computeAutomationBacklog(prs []pullRequest, bots []string, now time.Time) *automationBacklog {
+	ab := &automationBacklog{ByBot: map[string]int{}}
+	want := map[string]bool{}
+	for _, b := range bots {
+		want[botName(b)] = true
+	}
+	var ages []int
+	for _, pr := range prs {
+		bot := botName(pr.Author.Login)
+		if !want[bot] {
+			continue
+		}
+		age := daysBetween(pr.CreatedAt, now)
+		ab.Open++
+		ab.ByBot[bot]++
+		ages = append(ages, age)
+		if age > ab.OldestDays {
+			ab.OldestDays = age
+		}
+	}
+	ab.MedianAgeDays = median(ages)
+	return ab
+}
+
+// addAutomationBacklog scores the open PRs computeRepoScore fetched
+// alongside the issues.
+func addAutomationBacklog(score *repoScore, repo string, cfg config, prs []pullRequest, err error, now time.Time) {
+	if err != nil {
+		slog.Warn("automation backlog unavailable", "repo", repo, "error", err)
+		return
+	}
+	score.AutomationBacklog = computeAutomationBacklog(prs, cfg.AutomationBots, now)
+}
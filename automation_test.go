@@ -0,0 +1,23 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestComputeAutomationBacklog(t *testing.T) {
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	prs := []pullRequest{
+		{Number: 1, CreatedAt: now.AddDate(0, 0, -120), Author: actor{Login: "app/dependabot"}},
+		{Number: 2, CreatedAt: now.AddDate(0, 0, -10), Author: actor{Login: "dependabot[bot]"}},
+		{Number: 3, CreatedAt: now.AddDate(0, 0, -30), Author: actor{Login: "app/renovate"}},
+		{Number: 4, CreatedAt: now.AddDate(0, 0, -300), Author: actor{Login: "alice"}},
+	}
+	ab := computeAutomationBacklog(prs, []string{"dependabot", "Renovate[bot]"}, now)
+	if ab.Open != 3 || ab.OldestDays != 120 || ab.MedianAgeDays != 30 {
+		t.Errorf("got %+v, want 3 open, oldest 120, median 30", ab)
+	}
+	if ab.ByBot["dependabot"] != 2 || ab.ByBot["renovate"] != 1 {
+		t.Errorf("byBot = %v", ab.ByBot)
+	}
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+)
+
+var badgeDir = flag.String("badge-dir", "", "write a shields.io endpoint JSON file per repo into this directory, for live README badges served from GitHub Pages")
+
+// badgeColors are the shields.io colours for each status.
+var badgeColors = map[string]string{"healthy": "brightgreen", "warning": "yellow", "critical": "red"}
+
+// badge is a shields.io endpoint response:
+// https://shields.io/badges/endpoint-badge
+type badge struct {
+	SchemaVersion int    `json:"schemaVersion"`
+	Label         string `json:"label"`
+	Message       string `json:"message"`
+	Color         string `json:"color"`
+}
+
+// repoBadge is rs's badge: its score coloured by status, or grey when it
+// has too few issues to score.
+func repoBadge(rs repoScore) badge {
+	b := badge{SchemaVersion: 1, Label: "backlog health", Message: fmt.Sprint(rs.HealthScore), Color: badgeColors[rs.Status]}
+	if rs.Status == unscored {
+		b.Message, b.Color = unscored, "lightgrey"
+	}
+	return b
+}
+
+// writeBadges writes a badge per repo into dir, creating it. Repos that
+// failed to scan keep the badge from their last good run.
+func writeBadges(dir string, out output) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	n := 0
+	for _, rs := range out.Repos {
+		if rs.Error != "" {
+			continue
+		}
+		data, err := json.Marshal(repoBadge(rs))
+		if err != nil {
+			return err
+		}
+		if err := writeFileAtomic(filepath.Join(dir, repoFile(rs.Name, ".json")), append(data, '\n')); err != nil {
+			return err
+		}
+		n++
+	}
+	slog.Info("badges written", "dir", dir, "badges", n)
+	return nil
+}
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWriteBadges(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "badges")
+	out := output{Repos: []repoScore{
+		{Name: "api", HealthScore: 72, Status: "healthy"},
+		{Name: "web", HealthScore: 35, Status: "critical"},
+		{Name: "globex/docs", Status: unscored},
+		{Name: "cli", Error: "gh: HTTP 502"},
+	}}
+	if err := writeBadges(dir, out); err != nil {
+		t.Fatal(err)
+	}
+	for file, want := range map[string]string{
+		"api.json":         `{"schemaVersion":1,"label":"backlog health","message":"72","color":"brightgreen"}`,
+		"web.json":         `{"schemaVersion":1,"label":"backlog health","message":"35","color":"red"}`,
+		"globex_docs.json": `{"schemaVersion":1,"label":"backlog health","message":"unscored","color":"lightgrey"}`,
+	} {
+		data, err := os.ReadFile(filepath.Join(dir, file))
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		if string(data) != want+"\n" {
+			t.Errorf("%s = %s, want %s", file, data, want)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dir, "cli.json")); !os.IsNotExist(err) {
+		t.Errorf("a repo that failed to scan keeps its old badge; stat = %v", err)
+	}
+}
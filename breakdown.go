@@ -0,0 +1,14 @@
+package main
+
+import "github.com/misty-step/fab-backlog/pkg/score"
+
+// scoreBreakdown shows how HealthScore was reached; see score.Breakdown.
+type scoreBreakdown = score.Breakdown
+
+func breakdownFor(totalOpen int, stalePercent, unlabeledPercent float64, minIssues int) scoreBreakdown {
+	return score.BreakdownFor(totalOpen, stalePercent, unlabeledPercent, minIssues)
+}
+
+func statusFor(s float64) string {
+	return score.StatusFor(s)
+}
@@ -0,0 +1,18 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestScoreBreakdownOverride(t *testing.T) {
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	old := now.AddDate(0, 0, -30)
+	issues := []issue{{Number: 1, CreatedAt: old, UpdatedAt: now, Labels: []label{{Name: "security"}}}}
+	rs := scoreIssues("api", issues, config{MinIssues: 1, StaleDays: 90, SecurityLabel: "security", SecurityMaxDays: 7}, now)
+	b := rs.ScoreBreakdown
+	if b == nil || b.Total() != rs.HealthScore || rs.Status != "critical" || !strings.HasPrefix(b.Override, "critical: 1 security issue open") {
+		t.Fatalf("status %s, breakdown %+v", rs.Status, b)
+	}
+}
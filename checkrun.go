@@ -0,0 +1,120 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+)
+
+var checkRun = flag.String("check-run", "", "publish the result as a Check Run on the default branch of owner/repo, or of every scanned repo with \"each\"")
+
+const checkRunName = "Backlog health"
+
+// GitHub rejects check run output fields longer than this.
+const checkRunMaxText = 65535
+
+// checkRunOutput is the body of a completed check run.
+type checkRunOutput struct {
+	Name       string `json:"name"`
+	HeadSHA    string `json:"head_sha"`
+	Status     string `json:"status"`
+	Conclusion string `json:"conclusion"`
+	Output     struct {
+		Title   string `json:"title"`
+		Summary string `json:"summary"`
+		Text    string `json:"text,omitempty"`
+	} `json:"output"`
+}
+
+func truncateText(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	const more = "\n\n…truncated"
+	return strings.ToValidUTF8(s[:n-len(more)], "") + more
+}
+
+// orgCheckRun summarises the whole scan: failure if the gate failed,
+// neutral if anything is critical, success otherwise.
+func orgCheckRun(out output) (checkRunOutput, error) {
+	c := checkRunOutput{Name: checkRunName, Status: "completed", Conclusion: "success"}
+	switch {
+	case out.Gate != nil && !out.Gate.Passed && !out.Gate.Observed:
+		c.Conclusion = "failure"
+	case out.Summary.Critical > 0:
+		c.Conclusion = "neutral"
+	}
+	c.Output.Title = summaryLine(out)
+	md, err := renderMarkdown(out)
+	if err != nil {
+		return c, err
+	}
+	c.Output.Summary = truncateText(md, checkRunMaxText)
+	return c, nil
+}
+
+// repoCheckRun reports one repo's score on its own default branch.
+func repoCheckRun(rs repoScore) checkRunOutput {
+	c := checkRunOutput{Name: checkRunName, Status: "completed"}
+	switch rs.Status {
+	case "healthy":
+		c.Conclusion = "success"
+	case "warning":
+		c.Conclusion = "neutral"
+	default:
+		c.Conclusion = "failure"
+	}
+	c.Output.Title = fmt.Sprintf("Health score %d/100 (%s)", rs.HealthScore, rs.Status)
+	c.Output.Summary = fmt.Sprintf("| Open | Stale | Unlabeled | Onboarding |\n|-----:|------:|----------:|-----------:|\n| %d | %d (%.0f%%) | %d | %d |",
+		rs.TotalOpen, rs.StaleCount, rs.StalePercent, rs.UnlabeledCount, rs.OnboardingCount)
+	return c
+}
+
+// createCheckRun attaches c to the head of repo's default branch.
+func createCheckRun(run func(string, ...string) ([]byte, error), repo string, c checkRunOutput) error {
+	sha, err := run("gh", "api", "repos/"+repo+"/commits/HEAD", "--jq", ".sha")
+	if err != nil {
+		return err
+	}
+	c.HeadSHA = strings.TrimSpace(string(sha))
+	body, err := json.Marshal(c)
+	if err != nil {
+		return err
+	}
+	path, err := tempFile("fab-backlog-check-*.json", body)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(path)
+	_, err = run("gh", "api", "-X", "POST", "repos/"+repo+"/check-runs", "--input", path)
+	return err
+}
+
+func publishCheckRuns(out output) {
+	if *checkRun == "" {
+		return
+	}
+	if *checkRun != "each" {
+		c, err := orgCheckRun(out)
+		if err == nil {
+			err = createCheckRun(runBackground, *checkRun, c)
+		}
+		if err != nil {
+			slog.Error("failed to publish check run", "repo", *checkRun, "error", err)
+		} else {
+			slog.Info("check run published", "repo", *checkRun, "conclusion", c.Conclusion)
+		}
+		return
+	}
+	for _, rs := range out.Repos {
+		if rs.Error != "" || rs.Status == unscored || rs.Source != "" {
+			continue
+		}
+		if err := createCheckRun(runBackground, rs.fullName, repoCheckRun(rs)); err != nil {
+			slog.Warn("failed to publish check run", "repo", rs.fullName, "error", err)
+		}
+	}
+}
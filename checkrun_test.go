@@ -0,0 +1,51 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestCreateCheckRun(t *testing.T) {
+	var posted checkRunOutput
+	var paths []string
+	run := func(bin string, args ...string) ([]byte, error) {
+		paths = append(paths, args[len(args)-1])
+		if strings.HasSuffix(args[1], "/commits/HEAD") {
+			return []byte("abc123\n"), nil
+		}
+		data, err := os.ReadFile(args[len(args)-1])
+		if err != nil {
+			t.Fatal(err)
+		}
+		return nil, json.Unmarshal(data, &posted)
+	}
+	if err := createCheckRun(run, "org/a", repoCheckRun(repoScore{HealthScore: 35, Status: "critical", TotalOpen: 10})); err != nil {
+		t.Fatal(err)
+	}
+	if posted.HeadSHA != "abc123" || posted.Conclusion != "failure" || posted.Output.Title != "Health score 35/100 (critical)" {
+		t.Errorf("posted = %+v", posted)
+	}
+	if _, err := os.Stat(paths[1]); !os.IsNotExist(err) {
+		t.Error("temp input file should be removed")
+	}
+}
+
+func TestOrgCheckRunConclusion(t *testing.T) {
+	out := sampleReport()
+	c, err := orgCheckRun(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Conclusion != "neutral" || !strings.Contains(c.Output.Summary, "| neglected | 35 |") {
+		t.Errorf("check = %+v", c)
+	}
+	out.Gate = &gateResult{Passed: false}
+	if c, _ := orgCheckRun(out); c.Conclusion != "failure" {
+		t.Errorf("failed gate conclusion = %s", c.Conclusion)
+	}
+	if got := truncateText(strings.Repeat("x", 100), 50); len(got) != 50 {
+		t.Errorf("truncated length = %d", len(got))
+	}
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	ghscan "github.com/misty-step/fab-backlog/pkg/scan"
+)
+
+// TestScanWithFakeClient runs the scan loop end to end against the
+// in-memory client: no gh process, no HTTP.
+func TestScanWithFakeClient(t *testing.T) {
+	now := time.Now()
+	old := now.AddDate(0, 0, -200)
+	fresh := now.AddDate(0, 0, -3)
+	bug := []label{{Name: "bug"}}
+	prevClient, prevRun := client, runCmd
+	t.Cleanup(func() { client, runCmd = prevClient, prevRun })
+	runCmd = func(ctx context.Context, bin string, args ...string) ([]byte, error) {
+		t.Errorf("unexpected process: %s %v", bin, args)
+		return nil, context.Canceled
+	}
+	client = &ghscan.Fake{Repos: []ghscan.FakeRepo{
+		{Owner: "acme", Name: "api", Issues: []issue{
+			{Number: 1, Labels: bug, CreatedAt: old, UpdatedAt: old},
+			{Number: 2, Labels: bug, CreatedAt: old, UpdatedAt: old},
+			{Number: 3, Labels: bug, CreatedAt: fresh, UpdatedAt: fresh},
+		}},
+		{Owner: "acme", Name: "web", Issues: []issue{
+			{Number: 1, Labels: bug, CreatedAt: fresh, UpdatedAt: fresh},
+			{Number: 2, Labels: bug, CreatedAt: fresh, UpdatedAt: fresh},
+		}},
+		{Owner: "acme", Name: "docs", Files: map[string]string{repoConfigPath: "optOut: true\n"}},
+		{Owner: "acme", Name: "attic", Archived: true},
+	}}
+
+	sel := selection{Orgs: []string{"acme"}}
+	repos, err := sel.resolve(t.Context())
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := scan(t.Context(), sel, repos, config{MinIssues: 1, StaleDays: 90})
+	if len(out.Repos) != 2 {
+		t.Fatalf("scored %d repos, want api and web (docs opted out, attic archived)", len(out.Repos))
+	}
+	api, web := out.Repos[0], out.Repos[1]
+	if api.Name != "api" || api.StaleCount != 2 || web.Name != "web" || web.StaleCount != 0 || api.HealthScore >= web.HealthScore {
+		t.Errorf("api %+v\nweb %+v", api, web)
+	}
+}
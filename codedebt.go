@@ -0,0 +1,77 @@
+package main
+
+import (
+	"flag"
+	"regexp"
+	"time"
+)
+
+var (
+	codeDebtFlag    = flag.Bool("code-debt", false, "report TODO/FIXME issues as codeDebt, separately from user-reported issues and the health score")
+	codeDebtAuthors = flag.String("code-debt-authors", "", "with -code-debt, comma-separated logins whose issues are code debt whatever their title (default todo, the todo bot)")
+)
+
+// codeDebtTitle matches titles lifted from TODO/FIXME comments, as todo
+// bots file them: "TODO: drop the v1 API", "[FIXME] race in cache".
+var codeDebtTitle = regexp.MustCompile(`(?i)^\W*(todo|fixme)\b`)
+
+// codeDebt reports issues tracking debt left in the code. They go stale by
+// nature — the code, not the issue, changes — so they are kept out of the
+// backlog metrics and scored on their own.
+type codeDebt struct {
+	Open          int     `json:"open"`
+	StaleCount    int     `json:"staleCount"`
+	StalePercent  float64 `json:"stalePercent"`
+	OldestDays    int     `json:"oldestDays"`
+	MedianAgeDays int     `json:"medianAgeDays"`
+}
+
+// defaultCodeDebtAuthors is the todo bot, which files an issue per TODO.
+var defaultCodeDebtAuthors = []string{"todo"}
+
+func (c config) codeDebtAuthors() []string {
+	if len(c.CodeDebtAuthors) > 0 {
+		return c.CodeDebtAuthors
+	}
+	return defaultCodeDebtAuthors
+}
+
+func isCodeDebt(is issue, authors map[string]bool) bool {
+	return codeDebtTitle.MatchString(is.Title) || authors[botName(is.Author.Login)]
+}
+
+// splitCodeDebt separates code-debt issues from the rest.
+func splitCodeDebt(issues []issue, authors []string) (rest, debt []issue) {
+	want := map[string]bool{}
+	for _, a := range authors {
+		want[botName(a)] = true
+	}
+	for _, is := range issues {
+		if isCodeDebt(is, want) {
+			debt = append(debt, is)
+		} else {
+			rest = append(rest, is)
+		}
+	}
+	return rest, debt
+}
+
+func computeCodeDebt(debt []issue, staleDays int, now time.Time) *codeDebt {
+	cd := &codeDebt{Open: len(debt)}
+	ages := make([]int, 0, len(debt))
+	for _, is := range debt {
+		if is.UpdatedAt.Before(now.AddDate(0, 0, -staleDays)) {
+			cd.StaleCount++
+		}
+		age := daysBetween(is.CreatedAt, now)
+		ages = append(ages, age)
+		if age > cd.OldestDays {
+			cd.OldestDays = age
+		}
+	}
+	if cd.Open > 0 {
+		cd.StalePercent = float64(cd.StaleCount) / float64(cd.Open) * 100
+	}
+	cd.MedianAgeDays = median(ages)
+	return cd
+}
@@ -0,0 +1,25 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSplitCodeDebt(t *testing.T) {
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	old := now.AddDate(0, 0, -200)
+	issues := []issue{
+		{Number: 1, Title: "TODO: drop the v1 API", CreatedAt: old, UpdatedAt: old},
+		{Number: 2, Title: "[fixme] race in cache", CreatedAt: now.AddDate(0, 0, -20), UpdatedAt: now},
+		{Number: 3, Title: "Remove legacy flag", Author: actor{Login: "app/todo"}, CreatedAt: now.AddDate(0, 0, -40), UpdatedAt: now},
+		{Number: 4, Title: "Todolist widget crashes", CreatedAt: old, UpdatedAt: old},
+	}
+	rest, debt := splitCodeDebt(issues, (config{}).codeDebtAuthors())
+	if len(rest) != 1 || rest[0].Number != 4 || len(debt) != 3 {
+		t.Fatalf("rest = %v, debt = %v", rest, debt)
+	}
+	cd := computeCodeDebt(debt, 90, now)
+	if cd.Open != 3 || cd.StaleCount != 1 || cd.OldestDays != 200 || cd.MedianAgeDays != 40 {
+		t.Errorf("got %+v", cd)
+	}
+}
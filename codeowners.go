@@ -0,0 +1,138 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"flag"
+	"log/slog"
+	"slices"
+	"strings"
+
+	ghscan "github.com/misty-step/fab-backlog/pkg/scan"
+)
+
+var codeOwnersFlag = flag.Bool("codeowners", false, "read each repo's CODEOWNERS file and summarise the backlog per owning user and team")
+
+// codeOwnersPaths are where GitHub looks for CODEOWNERS, in the order it
+// looks; the first found is the one it uses.
+var codeOwnersPaths = []string{".github/CODEOWNERS", "CODEOWNERS", "docs/CODEOWNERS"}
+
+// codeOwners is who a repo's CODEOWNERS file names.
+type codeOwners struct {
+	Path string `json:"path"`
+	// Owners is everyone owning any path, sorted: @user, @org/team or an
+	// email address, as written in the file.
+	Owners []string `json:"owners"`
+	// Default is who owns the catch-all "*" rule, if the file has one.
+	Default []string `json:"default,omitempty"`
+}
+
+// ownerRollup is one CODEOWNERS owner's slice of the backlog: the scanned
+// repos naming them, summarised as the report summary is. A shared repo
+// counts towards each of its owners.
+type ownerRollup struct {
+	Owner string `json:"owner"`
+	summary
+	// HealthScore is the mean health score of the owner's scored repos.
+	HealthScore float64  `json:"healthScore"`
+	Repos       []string `json:"repos"`
+	// DefaultFor lists the repos the owner holds the catch-all rule of.
+	DefaultFor []string `json:"defaultFor,omitempty"`
+}
+
+// parseCodeOwners reads the owners out of a CODEOWNERS file: one rule a
+// line, a path pattern followed by its owners, with # comments. A pattern
+// with no owners leaves its paths unowned. As in GitHub, the last "*" rule
+// decides the default owners.
+func parseCodeOwners(src string) (owners, def []string) {
+	for _, line := range strings.Split(src, "\n") {
+		if i := strings.Index(line, "#"); i >= 0 {
+			line = line[:i]
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		if fields[0] == "*" {
+			def = fields[1:]
+		}
+		owners = append(owners, fields[1:]...)
+	}
+	slices.SortFunc(owners, func(a, b string) int { return cmp.Compare(strings.ToLower(a), strings.ToLower(b)) })
+	owners = slices.CompactFunc(owners, strings.EqualFold)
+	if len(def) == 0 {
+		def = nil
+	}
+	return owners, def
+}
+
+// ghCodeOwners fetches and parses a repo's CODEOWNERS file, nil if it has
+// none.
+func ghCodeOwners(ctx context.Context, owner, repo string) (*codeOwners, error) {
+	for _, path := range codeOwnersPaths {
+		data, err := client.RepoFile(ctx, owner, repo, path)
+		if errors.Is(err, ghscan.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		owners, def := parseCodeOwners(string(data))
+		if len(owners) == 0 {
+			return nil, nil
+		}
+		return &codeOwners{Path: path, Owners: owners, Default: def}, nil
+	}
+	return nil, nil
+}
+
+func addCodeOwners(ctx context.Context, score *repoScore, owner, repo string, cfg config) {
+	if !cfg.CodeOwners {
+		return
+	}
+	co, err := ghCodeOwners(ctx, owner, repo)
+	if err != nil {
+		slog.Warn("CODEOWNERS unavailable", "repo", repo, "error", err)
+		return
+	}
+	score.CodeOwners = co
+}
+
+// rollupOwners summarises repos for each CODEOWNERS owner, worst mean
+// score first. Owners are matched case-insensitively, as GitHub does.
+func rollupOwners(repos []repoScore) []ownerRollup {
+	type group struct {
+		owner string
+		repos []repoScore
+		def   []string
+	}
+	groups := map[string]*group{}
+	for _, rs := range repos {
+		if rs.CodeOwners == nil {
+			continue
+		}
+		for _, o := range rs.CodeOwners.Owners {
+			g := groups[strings.ToLower(o)]
+			if g == nil {
+				g = &group{owner: o}
+				groups[strings.ToLower(o)] = g
+			}
+			g.repos = append(g.repos, rs)
+			if slices.ContainsFunc(rs.CodeOwners.Default, func(d string) bool { return strings.EqualFold(d, o) }) {
+				g.def = append(g.def, rs.Name)
+			}
+		}
+	}
+	var out []ownerRollup
+	for _, g := range groups {
+		r := ownerRollup{Owner: g.owner, DefaultFor: g.def}
+		r.summary, r.HealthScore, r.Repos = rollupOf(g.repos)
+		slices.Sort(r.DefaultFor)
+		out = append(out, r)
+	}
+	slices.SortFunc(out, func(a, b ownerRollup) int {
+		return cmp.Or(cmp.Compare(a.HealthScore, b.HealthScore), cmp.Compare(strings.ToLower(a.Owner), strings.ToLower(b.Owner)))
+	})
+	return out
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+
+	ghscan "github.com/misty-step/fab-backlog/pkg/scan"
+)
+
+func TestParseCodeOwners(t *testing.T) {
+	owners, def := parseCodeOwners(`# Default owners
+*       @acme/platform @Alice
+
+/billing/   @acme/payments  # money
+docs/**     dev@acme.com @alice
+/vendor/
+*       @acme/platform
+`)
+	if want := []string{"@acme/payments", "@acme/platform", "@Alice", "dev@acme.com"}; !slices.Equal(owners, want) {
+		t.Errorf("owners = %v, want %v", owners, want)
+	}
+	if want := []string{"@acme/platform"}; !slices.Equal(def, want) {
+		t.Errorf("default = %v, want %v (the last * rule wins)", def, want)
+	}
+	if owners, def := parseCodeOwners("# nobody\n/legacy/\n"); owners != nil || def != nil {
+		t.Errorf("unowned file = %v, %v, want nothing", owners, def)
+	}
+}
+
+func TestGHCodeOwners(t *testing.T) {
+	prev := client
+	t.Cleanup(func() { client = prev })
+	client = &ghscan.Fake{Repos: []ghscan.FakeRepo{
+		{Owner: "acme", Name: "api", Files: map[string]string{
+			"CODEOWNERS":         "* @acme/legacy\n",
+			".github/CODEOWNERS": "* @acme/platform\n",
+		}},
+		{Owner: "acme", Name: "docs", Files: map[string]string{"docs/CODEOWNERS": "* @acme/writers\n"}},
+		{Owner: "acme", Name: "web"},
+	}}
+	ctx := context.Background()
+
+	co, err := ghCodeOwners(ctx, "acme", "api")
+	if err != nil || co == nil || co.Path != ".github/CODEOWNERS" || !slices.Equal(co.Owners, []string{"@acme/platform"}) {
+		t.Errorf("api = %+v, %v; want .github/CODEOWNERS, which GitHub reads first", co, err)
+	}
+	if co, err := ghCodeOwners(ctx, "acme", "docs"); err != nil || co == nil || co.Path != "docs/CODEOWNERS" {
+		t.Errorf("docs = %+v, %v", co, err)
+	}
+	if co, err := ghCodeOwners(ctx, "acme", "web"); err != nil || co != nil {
+		t.Errorf("web = %+v, %v; want no owners", co, err)
+	}
+}
+
+func TestRollupOwners(t *testing.T) {
+	platform := &codeOwners{Owners: []string{"@acme/platform", "@alice"}, Default: []string{"@acme/platform"}}
+	repos := []repoScore{
+		{Name: "billing", HealthScore: 30, Status: "critical", CodeOwners: &codeOwners{
+			Owners: []string{"@acme/payments", "@acme/platform"}, Default: []string{"@acme/payments"}}},
+		{Name: "api", HealthScore: 80, Status: "healthy", CodeOwners: platform},
+		{Name: "web", HealthScore: 70, Status: "warning", CodeOwners: &codeOwners{Owners: []string{"@ALICE"}}},
+		{Name: "attic", HealthScore: 10, Status: "critical"},
+	}
+	got := rollupOwners(repos)
+
+	var names []string
+	for _, r := range got {
+		names = append(names, r.Owner)
+	}
+	if want := []string{"@acme/payments", "@acme/platform", "@alice"}; !slices.Equal(names, want) {
+		t.Fatalf("owners = %v, want %v, worst mean first", names, want)
+	}
+	pay, plat, alice := got[0], got[1], got[2]
+	if pay.HealthScore != 30 || pay.Critical != 1 || !slices.Equal(pay.DefaultFor, []string{"billing"}) {
+		t.Errorf("payments = %+v", pay)
+	}
+	// A shared repo counts towards every owner it names.
+	if plat.HealthScore != 55 || !slices.Equal(plat.Repos, []string{"api", "billing"}) || !slices.Equal(plat.DefaultFor, []string{"api"}) {
+		t.Errorf("platform = %+v", plat)
+	}
+	if alice.HealthScore != 75 || !slices.Equal(alice.Repos, []string{"api", "web"}) || alice.DefaultFor != nil {
+		t.Errorf("alice = %+v, want owners matched case-insensitively", alice)
+	}
+
+	md, err := renderMarkdown(output{Ownership: got, Repos: repos})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(md, "## Ownership") || !strings.Contains(md, "| @acme/platform | 2 | 55.0 |") {
+		t.Errorf("markdown lacks the ownership table:\n%s", md)
+	}
+}
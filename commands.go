@@ -0,0 +1,224 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+)
+
+var formatFlag = flag.String("format", "", "output format: with report, markdown (default), html, sla (the public support SLA page) or table; with scan and focus, json, ndjson (one line per repo as it is scored) or table, table being the default on a terminal and json otherwise; with trend, table (default) or json")
+
+// command is one subcommand. Offline commands work from files or storage
+// and never need an org to scan, so they skip org discovery.
+type command struct {
+	name    string
+	usage   string
+	offline bool
+	run     func(ctx context.Context, sel selection, args []string, cfg config) int
+}
+
+// commands in help order. A bare invocation, or one starting with a flag,
+// runs scan.
+var commands = []command{
+	{name: "scan", usage: "scan [flags] [-format json|ndjson|table]\n\tscore every repo in the selection and publish the report (default)",
+		run: func(ctx context.Context, sel selection, args []string, cfg config) int {
+			return runScan(ctx, sel, args, cfg, false)
+		}},
+	{name: "focus", usage: "focus REPO... [flags] [-format json|ndjson|table]\n\trescan only the named repos",
+		run: func(ctx context.Context, sel selection, args []string, cfg config) int {
+			return runScan(ctx, sel, args, cfg, true)
+		}},
+	{name: "report", usage: "report [REPORT.json] [-format markdown|html|sla|table] [-template FILE] [-out FORMAT=DEST]\n\trender a saved JSON report (stdin without a file)", offline: true,
+		run: func(_ context.Context, _ selection, args []string, cfg config) int { return runReport(args, cfg) }},
+	{name: "compare", usage: "compare OLD.json NEW.json\n\tlist repos whose score or status changed between two saved reports", offline: true,
+		run: func(_ context.Context, _ selection, args []string, _ config) int { return runCompare(args) }},
+	{name: "triage", usage: "triage [REPO...] [-plan-out plan.json]\n\tscan and print the suggested remediation plan for review",
+		run: runTriage},
+	{name: "apply", usage: "apply PLAN.json [-dry-run]\n\tmake the changes a reviewed plan lists", offline: true,
+		run: func(_ context.Context, _ selection, args []string, _ config) int { return runApply(args) }},
+	{name: "serve", usage: "serve [-addr :8080] [-tenants tenants.json]\n\trun as a daemon with hourly delta and nightly full scans",
+		run: func(ctx context.Context, sel selection, _ []string, cfg config) int { return runServe(ctx, sel, cfg) }},
+	{name: "runs", usage: "runs [show ID | diff [FROM TO]]\n\tinspect stored runs", offline: true,
+		run: func(_ context.Context, _ selection, args []string, cfg config) int { return runRuns(args, cfg) }},
+	{name: "trend", usage: "trend [REPO] [-format table|json] [-svg-dir DIR]\n\tprint score history from stored runs as a table with sparklines", offline: true,
+		run: func(_ context.Context, _ selection, args []string, cfg config) int { return runTrend(args, cfg) }},
+	{name: "publish", usage: "publish [-dir site]\n\twrite a static dashboard of stored runs for GitHub Pages", offline: true,
+		run: func(_ context.Context, _ selection, args []string, cfg config) int { return runPublish(args, cfg) }},
+	{name: "score", usage: "score -issues-file FILE [NAME]\n\tscore exported issue JSON", offline: true,
+		run: func(_ context.Context, _ selection, args []string, cfg config) int { return runScore(args, cfg) }},
+	{name: "suggest-labels", usage: "suggest-labels [REPO...] [-apply]\n\tsuggest labels for unlabeled issues",
+		run: runSuggestLabels},
+	{name: "fix", usage: "fix rename-label -from OLD -to NEW [REPO...]\n\tbulk remediations",
+		run: func(ctx context.Context, sel selection, args []string, _ config) int { return runFix(ctx, sel, args) }},
+	{name: "check", usage: "check issue OWNER/REPO#NUMBER\n\tcheck one issue against the intake policy", offline: true,
+		run: runCheck},
+	{name: "login", usage: "login [-client-id ID | -with-token] [-hostname HOST]\n\tauthorize through the OAuth device flow and keep the token in the OS keyring", offline: true,
+		run: func(ctx context.Context, _ selection, args []string, _ config) int { return runLogin(ctx, args) }},
+}
+
+func init() {
+	flag.Usage = func() {
+		w := flag.CommandLine.Output()
+		fmt.Fprintln(w, "usage: fab-backlog [command] [flags]\n\ncommands:")
+		for _, c := range commands {
+			fmt.Fprintf(w, "  %s\n", c.usage)
+		}
+		fmt.Fprintln(w, "\nflags:")
+		flag.PrintDefaults()
+	}
+}
+
+// findCommand splits the subcommand off args.
+func findCommand(args []string) (command, []string, error) {
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+		return commands[0], args, nil
+	}
+	for _, c := range commands {
+		if c.name == args[0] {
+			return c, args[1:], nil
+		}
+	}
+	return command{}, nil, fmt.Errorf("unknown command %q", args[0])
+}
+
+func commandFailed(code int, msg string, err error) int {
+	slog.Error(msg, "error", err)
+	emitJSON(map[string]any{"ok": false, "error": err.Error()})
+	return code
+}
+
+// readReport loads a JSON report as written by scan; "-" reads stdin.
+func readReport(path string) (output, error) {
+	var out output
+	var r io.Reader = os.Stdin
+	if path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			return out, fmt.Errorf("open report: %w", err)
+		}
+		defer f.Close()
+		r = f
+	}
+	if err := json.NewDecoder(r).Decode(&out); err != nil {
+		return out, fmt.Errorf("parse report %s: %w", path, err)
+	}
+	return out, nil
+}
+
+// runReport implements `fab-backlog report [REPORT.json]`. The report keeps
+// the locale it was scanned with unless -locale is given.
+func runReport(args []string, cfg config) int {
+	path := "-"
+	switch len(args) {
+	case 0:
+	case 1:
+		path = args[0]
+	default:
+		return commandFailed(2, "invalid report command", errors.New("usage: fab-backlog report [REPORT.json] [-format markdown|html|sla|table | -template FILE | -out FORMAT=DEST...]"))
+	}
+	render := renderMarkdown
+	switch *formatFlag {
+	case "":
+		if customTmpl != nil {
+			render = renderTemplate
+		}
+	case "markdown":
+	case "html":
+		render = renderHTML
+	case "sla":
+		render = renderSLA
+	case "table":
+		render = func(out output) (string, error) { return renderTable(out, colorTable()), nil }
+	default:
+		return commandFailed(2, "invalid report command", fmt.Errorf("-format must be markdown, html, sla or table, got %q", *formatFlag))
+	}
+	out, err := readReport(path)
+	if err != nil {
+		return commandFailed(1, "failed to read report", err)
+	}
+	if flagSet("locale") {
+		out.Config.Locale = cfg.Locale
+	}
+	if *demo {
+		if out, err = pseudonymize(out); err != nil {
+			return commandFailed(1, "failed to pseudonymize report", err)
+		}
+	}
+	if len(outFlags) > 0 {
+		outs, err := openOutputs(outFlags)
+		if err != nil {
+			return commandFailed(1, "failed to open output", err)
+		}
+		defer outs.discard()
+		if err := outs.write(out); err != nil {
+			return commandFailed(1, "failed to write report", err)
+		}
+		return 0
+	}
+	doc, err := render(out)
+	if err != nil {
+		return commandFailed(1, "failed to render report", err)
+	}
+	sink, err := openReportSink()
+	if err != nil {
+		return commandFailed(1, "failed to open output", err)
+	}
+	defer sink.discard()
+	if _, err := fmt.Fprint(sink, doc); err != nil {
+		sink.discard()
+		return commandFailed(1, "failed to write report", err)
+	}
+	if err := sink.close(); err != nil {
+		return commandFailed(1, "failed to write report", err)
+	}
+	return 0
+}
+
+// runCompare implements `fab-backlog compare OLD.json NEW.json`, the file
+// counterpart of `runs diff`.
+func runCompare(args []string) int {
+	if len(args) != 2 {
+		return commandFailed(2, "invalid compare command", errors.New("usage: fab-backlog compare OLD.json NEW.json"))
+	}
+	from, err := readReport(args[0])
+	if err != nil {
+		return commandFailed(1, "failed to read report", err)
+	}
+	to, err := readReport(args[1])
+	if err != nil {
+		return commandFailed(1, "failed to read report", err)
+	}
+	emitJSON(diffReports(args[0], args[1], from, to))
+	return 0
+}
+
+// runTriage implements `fab-backlog triage [REPO...]`: a scan that only
+// plans. It prints the plan, or writes it to -plan-out, and changes nothing.
+func runTriage(ctx context.Context, sel selection, args []string, cfg config) int {
+	ctx, cancel := withScanTimeout(ctx)
+	defer cancel()
+	repos := dedupeRepos(sel.primaryOrg(), args)
+	if len(repos) == 0 {
+		var err error
+		if repos, err = sel.resolve(ctx); err != nil {
+			return commandFailed(1, "failed to list repos", fmt.Errorf("failed to list repos: %w", err))
+		}
+	}
+	now := clock()
+	p := buildPlan(scan(ctx, sel, repos, cfg), cfg, now)
+	if *planOut == "" {
+		emitJSON(p)
+		return 0
+	}
+	if err := writePlan(*planOut, p); err != nil {
+		return commandFailed(1, "failed to write plan", err)
+	}
+	slog.Info("plan written", "path", *planOut, "actions", len(p.Actions))
+	return 0
+}
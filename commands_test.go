@@ -0,0 +1,48 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFindCommand(t *testing.T) {
+	for _, tc := range []struct {
+		args []string
+		want string
+		rest int
+	}{
+		{nil, "scan", 0},
+		{[]string{"-org", "acme"}, "scan", 2},
+		{[]string{"focus", "api", "-deep"}, "focus", 2},
+		{[]string{"compare", "a.json", "b.json"}, "compare", 2},
+	} {
+		c, rest, err := findCommand(tc.args)
+		if err != nil || c.name != tc.want || len(rest) != tc.rest {
+			t.Errorf("findCommand(%v) = %s %v, %v", tc.args, c.name, rest, err)
+		}
+	}
+	if _, _, err := findCommand([]string{"scna"}); err == nil {
+		t.Error("unknown command should be rejected")
+	}
+}
+
+func TestReadReportRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "report.json")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := writeJSON(f, sampleReport()); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+	out, err := readReport(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := diffReports("old", "new", sampleReport(), out)
+	if len(d.Changed) != 0 || len(d.Added) != 0 || len(d.Removed) != 0 || out.Org != "misty-step" {
+		t.Errorf("round trip changed the report: %+v", d)
+	}
+}
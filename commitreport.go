@@ -0,0 +1,144 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log/slog"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+)
+
+var (
+	commitReport = flag.String("commit-report", "", "owner/repo to commit report.json and report.md to after each run, for versioned history without a database")
+	commitBranch = flag.String("commit-branch", "backlog-reports", "with -commit-report, the branch to commit to; created, holding only the reports, if missing")
+)
+
+// reportFiles are the files each run commits, by path.
+func reportFiles(out output) (map[string]string, error) {
+	var b strings.Builder
+	if err := writeJSON(&b, out); err != nil {
+		return nil, err
+	}
+	md, err := renderMarkdown(out)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]string{"report.json": b.String(), "report.md": md}, nil
+}
+
+type gitTreeEntry struct {
+	Path    string `json:"path"`
+	Mode    string `json:"mode"`
+	Type    string `json:"type"`
+	Content string `json:"content"`
+}
+
+type gitObject struct {
+	SHA    string `json:"sha"`
+	Object struct {
+		SHA string `json:"sha"`
+	} `json:"object"` // a ref's commit
+	Tree struct {
+		SHA string `json:"sha"`
+	} `json:"tree"` // a commit's tree
+}
+
+// commitFiles commits files to branch in repo as one commit through the
+// Git Data API, creating the branch as an orphan if it doesn't exist yet so
+// it carries nothing but reports. It returns the new commit's SHA. The
+// branch is moved without force, so a concurrent push fails the commit
+// rather than being lost.
+func commitFiles(run func(string, ...string) ([]byte, error), repo, branch, message string, files map[string]string) (string, error) {
+	api := func(method, path string, body any) ([]byte, error) {
+		args := []string{"api", "-X", method, "repos/" + repo + "/" + path}
+		if body == nil {
+			return run("gh", args...)
+		}
+		data, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		in, err := tempFile("fab-backlog-git-*.json", data)
+		if err != nil {
+			return nil, err
+		}
+		defer os.Remove(in)
+		return run("gh", append(args, "--input", in)...)
+	}
+	// Refs, trees and commits answer with the fields needed from them.
+	parse := func(data []byte, err error) (gitObject, error) {
+		var o gitObject
+		if err == nil {
+			err = json.Unmarshal(data, &o)
+		}
+		return o, err
+	}
+
+	var parent, baseTree string
+	ref, err := parse(api("GET", "git/ref/heads/"+branch, nil))
+	switch {
+	case err != nil && !strings.Contains(err.Error(), "HTTP 404"):
+		return "", fmt.Errorf("read branch %s: %w", branch, err)
+	case err == nil:
+		parent = ref.Object.SHA
+		c, err := parse(api("GET", "git/commits/"+parent, nil))
+		if err != nil {
+			return "", fmt.Errorf("read commit %s: %w", parent, err)
+		}
+		baseTree = c.Tree.SHA
+	}
+
+	tree := map[string]any{}
+	if baseTree != "" {
+		tree["base_tree"] = baseTree
+	}
+	var entries []gitTreeEntry
+	for _, path := range slices.Sorted(maps.Keys(files)) {
+		entries = append(entries, gitTreeEntry{Path: path, Mode: "100644", Type: "blob", Content: files[path]})
+	}
+	tree["tree"] = entries
+	t, err := parse(api("POST", "git/trees", tree))
+	if err != nil {
+		return "", fmt.Errorf("create tree: %w", err)
+	}
+	commit := map[string]any{"message": message, "tree": t.SHA, "parents": []string{}}
+	if parent != "" {
+		commit["parents"] = []string{parent}
+	}
+	c, err := parse(api("POST", "git/commits", commit))
+	if err != nil {
+		return "", fmt.Errorf("create commit: %w", err)
+	}
+	head := c.SHA
+	if parent == "" {
+		_, err = api("POST", "git/refs", map[string]string{"ref": "refs/heads/" + branch, "sha": head})
+	} else {
+		_, err = api("PATCH", "git/refs/heads/"+branch, map[string]any{"sha": head, "force": false})
+	}
+	if err != nil {
+		return "", fmt.Errorf("update branch %s: %w", branch, err)
+	}
+	return head, nil
+}
+
+// publishReportCommit commits the report to -commit-report.
+func publishReportCommit(out output) {
+	if *commitReport == "" {
+		return
+	}
+	files, err := reportFiles(out)
+	if err != nil {
+		slog.Error("failed to render report", "error", err)
+		return
+	}
+	message := fmt.Sprintf("Backlog health report: %s %s\n\n%s\n", out.Org, out.GeneratedAt, summaryLine(out))
+	head, err := commitFiles(runBackground, *commitReport, *commitBranch, message, files)
+	if err != nil {
+		slog.Error("failed to commit report", "repo", *commitReport, "branch", *commitBranch, "error", err)
+		return
+	}
+	slog.Info("report committed", "repo", *commitReport, "branch", *commitBranch, "sha", head)
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+)
+
+// fakeGitData answers the Git Data API calls commitFiles makes, recording
+// each as "METHOD path" with the body it sent.
+type fakeGitData struct {
+	branchExists bool
+	calls        []string
+	bodies       map[string]map[string]any
+}
+
+func (f *fakeGitData) run(bin string, args ...string) ([]byte, error) {
+	call := args[2] + " " + strings.TrimPrefix(args[3], "repos/acme/backlog/")
+	f.calls = append(f.calls, call)
+	if len(args) > 4 {
+		data, err := os.ReadFile(args[5])
+		if err != nil {
+			return nil, err
+		}
+		var body map[string]any
+		if err := json.Unmarshal(data, &body); err != nil {
+			return nil, err
+		}
+		f.bodies[call] = body
+	}
+	switch call {
+	case "GET git/ref/heads/backlog-reports":
+		if !f.branchExists {
+			return nil, errors.New("gh: Not Found (HTTP 404)")
+		}
+		return []byte(`{"ref":"refs/heads/backlog-reports","object":{"sha":"parent1","type":"commit"}}`), nil
+	case "GET git/commits/parent1":
+		return []byte(`{"sha":"parent1","tree":{"sha":"tree0"}}`), nil
+	case "POST git/trees":
+		return []byte(`{"sha":"tree1"}`), nil
+	case "POST git/commits":
+		return []byte(`{"sha":"commit2","tree":{"sha":"tree1"}}`), nil
+	case "POST git/refs", "PATCH git/refs/heads/backlog-reports":
+		return []byte(`{}`), nil
+	}
+	return nil, errors.New("unexpected call " + call)
+}
+
+func TestCommitFiles(t *testing.T) {
+	files := map[string]string{"report.md": "# Backlog health\n", "report.json": "{}\n"}
+
+	f := &fakeGitData{branchExists: true, bodies: map[string]map[string]any{}}
+	head, err := commitFiles(f.run, "acme/backlog", "backlog-reports", "Backlog health report", files)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "GET git/ref/heads/backlog-reports,GET git/commits/parent1,POST git/trees,POST git/commits,PATCH git/refs/heads/backlog-reports"; head != "commit2" || strings.Join(f.calls, ",") != want {
+		t.Errorf("head %s, calls %v", head, f.calls)
+	}
+	tree := f.bodies["POST git/trees"]
+	entries, _ := tree["tree"].([]any)
+	if tree["base_tree"] != "tree0" || len(entries) != 2 || entries[0].(map[string]any)["path"] != "report.json" {
+		t.Errorf("tree = %v", tree)
+	}
+	if c := f.bodies["POST git/commits"]; c["tree"] != "tree1" || len(c["parents"].([]any)) != 1 {
+		t.Errorf("commit = %v", c)
+	}
+	if ref := f.bodies["PATCH git/refs/heads/backlog-reports"]; ref["sha"] != "commit2" || ref["force"] != false {
+		t.Errorf("ref update = %v", ref)
+	}
+
+	// A missing branch is created as an orphan holding only the reports.
+	f = &fakeGitData{bodies: map[string]map[string]any{}}
+	if _, err := commitFiles(f.run, "acme/backlog", "backlog-reports", "Backlog health report", files); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := f.bodies["POST git/trees"]["base_tree"]; ok {
+		t.Errorf("orphan tree has a base: %v", f.bodies["POST git/trees"])
+	}
+	if c := f.bodies["POST git/commits"]; len(c["parents"].([]any)) != 0 {
+		t.Errorf("orphan commit = %v", c)
+	}
+	if ref := f.bodies["POST git/refs"]; ref["ref"] != "refs/heads/backlog-reports" || ref["sha"] != "commit2" {
+		t.Errorf("ref created = %v", ref)
+	}
+}
+
+func TestReportFiles(t *testing.T) {
+	files, err := reportFiles(sampleReport())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out output
+	if err := json.Unmarshal([]byte(files["report.json"]), &out); err != nil || len(out.Repos) == 0 {
+		t.Errorf("report.json = %v, %v", out, err)
+	}
+	if !strings.HasPrefix(files["report.md"], "# Backlog health:") {
+		t.Errorf("report.md = %q", files["report.md"])
+	}
+}
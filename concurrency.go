@@ -0,0 +1,32 @@
+package main
+
+import (
+	"flag"
+	"sync"
+)
+
+var concurrency = flag.Int("concurrency", 4, "number of repos to fetch and score in parallel")
+
+// parallelEach calls f(i) for every i in [0, n) from at most workers
+// goroutines and returns once all calls have. f must only write state
+// owned by index i, such as its slot in a results slice; anything shared
+// needs its own lock, as linkChecker has.
+func parallelEach(n, workers int, f func(i int)) {
+	workers = max(min(workers, n), 1)
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				f(i)
+			}
+		}()
+	}
+	for i := range n {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+}
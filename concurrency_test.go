@@ -0,0 +1,73 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestParallelEachVisitsEveryIndexOnce(t *testing.T) {
+	for _, workers := range []int{0, 1, 3, 64} {
+		var running, peak atomic.Int32
+		hits := make([]int, 50)
+		parallelEach(len(hits), workers, func(i int) {
+			n := running.Add(1)
+			for p := peak.Load(); n > p && !peak.CompareAndSwap(p, n); p = peak.Load() {
+			}
+			hits[i]++
+			time.Sleep(time.Millisecond)
+			running.Add(-1)
+		})
+		for i, h := range hits {
+			if h != 1 {
+				t.Fatalf("workers %d: index %d ran %d times", workers, i, h)
+			}
+		}
+		if p := int(peak.Load()); p > max(workers, 1) {
+			t.Errorf("workers %d: %d calls ran at once", workers, p)
+		}
+	}
+	parallelEach(0, 4, func(int) { t.Error("called for an empty range") })
+}
+
+// TestScanConcurrencyStress scores a large fixture serially and in parallel
+// many times over; run with -race it also checks the pipeline shares no
+// unguarded state between repos.
+func TestScanConcurrencyStress(t *testing.T) {
+	gh := newFakeGitHub(t)
+	genFixture(gh, "acme", 42, 40, 30, time.Now())
+	var repos []string
+	for r := range 40 {
+		repos = append(repos, fmt.Sprintf("acme/repo-%02d", r))
+	}
+	cfg := config{MinIssues: 5, StaleDays: 90, OnboardingLabels: []string{"good first issue"}, MostWanted: 5, HallOfShame: 5, DuplicateThreshold: 0.8}
+	render := func(workers int) string {
+		prev := *concurrency
+		*concurrency = workers
+		defer func() { *concurrency = prev }()
+		out := scan(t.Context(), selection{Orgs: []string{"acme"}}, repos, cfg)
+		out.GeneratedAt = ""
+		b, err := json.Marshal(out)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(b)
+	}
+	want := render(1)
+	for range 5 {
+		if got := render(16); got != want {
+			t.Fatalf("parallel scan differs from serial:\n got %s\nwant %s", got, want)
+		}
+	}
+	out := scan(t.Context(), selection{Orgs: []string{"acme"}}, repos, cfg)
+	names := make([]string, 0, len(out.Repos))
+	for _, rs := range out.Repos {
+		names = append(names, rs.Name)
+	}
+	if s := out.Summary; s.Total != 40 || s.Healthy+s.Warning+s.Critical != 40 || len(slices.Compact(slices.Sorted(slices.Values(names)))) != 40 {
+		t.Errorf("summary %+v over repos %v", s, names)
+	}
+}
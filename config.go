@@ -0,0 +1,238 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/misty-step/fab-backlog/pkg/report"
+)
+
+var configPath = flag.String("config", "", "path to a JSON config file (same shape as the report's config object)")
+
+// buildConfig assembles the effective config: flag defaults first, then the
+// -config file, then any flag set explicitly on the command line.
+func buildConfig() (config, error) {
+	cfg := config{
+		MinIssues:              *minIssues,
+		StaleDays:              *staleDays,
+		UnlabeledGraceHours:    *unlabeledGrace,
+		OnboardingLabels:       splitList(*onboardingLabels),
+		HallOfShame:            *hallOfShameN,
+		MostWanted:             *mostWantedN,
+		ResponseWindowDays:     *responseWindowDays,
+		LabelLatencyWindowDays: *labelLatencyWindowDays,
+		ContributorWindowDays:  *contributorWindowDays,
+		QAWindowDays:           *qaWindowDays,
+		AutomationBots:         splitList(*automationBots),
+		CodeDebt:               *codeDebtFlag,
+		CodeDebtAuthors:        splitList(*codeDebtAuthors),
+		SecurityAlerts:         *securityAlertsFlag,
+		LinkCheck:              *linkCheck,
+		TeamRollups:            *teamRollupsFlag,
+		CodeOwners:             *codeOwnersFlag,
+		DuplicateThreshold:     *duplicateThreshold,
+		Scopes:                 splitList(*scopeFlag),
+		Locale:                 *localeFlag,
+		Deep:                   *deep,
+	}
+	if *triageEffortFlag {
+		cfg.TriageEffort = &triageCosts{}
+	}
+	if *projectCoverageFlag {
+		cfg.ProjectCoverage = &projectCoverage{}
+	}
+	if *historyFile != "" {
+		cfg.Storage = &storageConfig{Driver: "file", Path: *historyFile}
+	}
+	if *configPath != "" {
+		if err := loadConfigFile(*configPath, &cfg); err != nil {
+			return cfg, err
+		}
+		flag.Visit(func(f *flag.Flag) { applyFlag(&cfg, f.Name) })
+	}
+	return cfg, cfg.validate()
+}
+
+// applyFlag copies an explicitly set flag over the value loaded from file.
+func applyFlag(cfg *config, name string) {
+	switch name {
+	case "min-issues":
+		cfg.MinIssues = *minIssues
+	case "stale-days":
+		cfg.StaleDays = *staleDays
+	case "unlabeled-grace-hours":
+		cfg.UnlabeledGraceHours = *unlabeledGrace
+	case "onboarding-labels":
+		cfg.OnboardingLabels = splitList(*onboardingLabels)
+	case "security-label":
+		cfg.SecurityLabel = *securityLabel
+	case "security-max-days":
+		cfg.SecurityMaxDays = *securityMaxDays
+	case "hall-of-shame":
+		cfg.HallOfShame = *hallOfShameN
+	case "most-wanted":
+		cfg.MostWanted = *mostWantedN
+	case "response-window-days":
+		cfg.ResponseWindowDays = *responseWindowDays
+	case "label-latency-window-days":
+		cfg.LabelLatencyWindowDays = *labelLatencyWindowDays
+	case "contributor-window-days":
+		cfg.ContributorWindowDays = *contributorWindowDays
+	case "qa-window-days":
+		cfg.QAWindowDays = *qaWindowDays
+	case "automation-bots":
+		cfg.AutomationBots = splitList(*automationBots)
+	case "code-debt":
+		cfg.CodeDebt = *codeDebtFlag
+	case "code-debt-authors":
+		cfg.CodeDebtAuthors = splitList(*codeDebtAuthors)
+	case "security-alerts":
+		cfg.SecurityAlerts = *securityAlertsFlag
+	case "link-check":
+		cfg.LinkCheck = *linkCheck
+	case "team-rollups":
+		cfg.TeamRollups = *teamRollupsFlag
+	case "codeowners":
+		cfg.CodeOwners = *codeOwnersFlag
+	case "duplicate-threshold":
+		cfg.DuplicateThreshold = *duplicateThreshold
+	case "triage-effort":
+		if !*triageEffortFlag {
+			cfg.TriageEffort = nil
+		} else if cfg.TriageEffort == nil {
+			cfg.TriageEffort = &triageCosts{}
+		}
+	case "project-coverage":
+		if !*projectCoverageFlag {
+			cfg.ProjectCoverage = nil
+		} else if cfg.ProjectCoverage == nil {
+			cfg.ProjectCoverage = &projectCoverage{}
+		}
+	case "history-file":
+		cfg.Storage = &storageConfig{Driver: "file", Path: *historyFile}
+	case "scope":
+		cfg.Scopes = splitList(*scopeFlag)
+	case "locale":
+		cfg.Locale = *localeFlag
+	case "deep":
+		cfg.Deep = *deep
+	}
+}
+
+func loadConfigFile(path string, cfg *config) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("read config: %w", err)
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(cfg); err != nil {
+		return fmt.Errorf("parse config %s: %w", path, err)
+	}
+	return cfg.validate()
+}
+
+func (c config) validate() error {
+	patterns := append([]string{c.SecurityLabel}, c.OnboardingLabels...)
+	patterns = append(patterns, c.IgnoreLabels...)
+	for group, members := range c.LabelAliases {
+		patterns = append(patterns, group)
+		patterns = append(patterns, members...)
+	}
+	for _, s := range c.SLOs {
+		if s.Label == "" {
+			return fmt.Errorf("slo: label required")
+		}
+		patterns = append(patterns, s.Label)
+		if s.FirstResponseDays <= 0 && s.ResolutionDays <= 0 {
+			return fmt.Errorf("slo %q: set firstResponseDays and/or resolutionDays", s.Label)
+		}
+	}
+	if _, err := report.Lookup(c.Locale); err != nil {
+		return err
+	}
+	if err := validateScopes(c.Scopes); err != nil {
+		return err
+	}
+	if err := validateObserve(c.Observe); err != nil {
+		return err
+	}
+	if err := validateTriageCosts(c.TriageEffort); err != nil {
+		return err
+	}
+	if err := validateProjectCoverage(c.ProjectCoverage); err != nil {
+		return err
+	}
+	if err := validateDuplicateThreshold(c.DuplicateThreshold); err != nil {
+		return err
+	}
+	if _, err := newTitleMatcher(c.StaleExcludeTitles); err != nil {
+		return err
+	}
+	if err := validateTopics(c.Topics); err != nil {
+		return err
+	}
+	if err := validateRamps(c.Ramps); err != nil {
+		return err
+	}
+	if err := validateGroups(c.Groups); err != nil {
+		return err
+	}
+	if err := validateLabelRules(c.LabelRules); err != nil {
+		return err
+	}
+	if c.Intake != nil {
+		if err := c.Intake.validate(); err != nil {
+			return err
+		}
+	}
+	if c.PublicFeed != nil {
+		if err := c.PublicFeed.validate(); err != nil {
+			return err
+		}
+	}
+	if c.SupportSLA != nil {
+		if err := c.SupportSLA.validate(); err != nil {
+			return err
+		}
+	}
+	for _, e := range c.Exemptions {
+		if err := e.validate(); err != nil {
+			return err
+		}
+	}
+	for _, p := range c.OOO {
+		if err := p.validate(); err != nil {
+			return err
+		}
+	}
+	if c.StalePolicy != nil {
+		if err := c.StalePolicy.validate(c.StaleDays); err != nil {
+			return err
+		}
+	}
+	if c.Storage != nil {
+		if err := c.Storage.validate(); err != nil {
+			return err
+		}
+	}
+	if c.SMTP != nil {
+		if err := c.SMTP.validate(); err != nil {
+			return err
+		}
+	}
+	for _, s := range c.Escalation {
+		if err := s.validate(); err != nil {
+			return err
+		}
+	}
+	for _, p := range patterns {
+		if err := validatePattern(p); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,29 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadConfigFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	data := `{"staleDays": 60, "slos": [{"label": "security", "firstResponseDays": 2, "resolutionDays": 30}]}`
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cfg := config{MinIssues: 5, StaleDays: 90}
+	if err := loadConfigFile(path, &cfg); err != nil {
+		t.Fatalf("loadConfigFile: %v", err)
+	}
+	if cfg.MinIssues != 5 || cfg.StaleDays != 60 || len(cfg.SLOs) != 1 || cfg.SLOs[0].ResolutionDays != 30 {
+		t.Errorf("unexpected config: %+v", cfg)
+	}
+
+	if err := os.WriteFile(path, []byte(`{"staleDayz": 60}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := loadConfigFile(path, &cfg); err == nil {
+		t.Error("unknown keys should be rejected")
+	}
+}
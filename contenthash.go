@@ -0,0 +1,121 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+)
+
+var skipUnchanged = flag.Bool("skip-publish-if-unchanged", false, "skip notifications and published reports when the report's content hash matches the previous run's (needs -history-file)")
+
+// reportMeta describes the report itself rather than the backlog.
+type reportMeta struct {
+	// ContentHash is a SHA-256 of the scores and the counts behind them,
+	// without anything that changes with the clock alone, so two runs over
+	// an untouched backlog share it.
+	ContentHash string `json:"contentHash"`
+	// Unchanged is set when ContentHash matches the previous stored run.
+	Unchanged bool `json:"unchanged,omitempty"`
+	// API is the run's GitHub API call count and remaining quota.
+	API *apiUsage `json:"api,omitempty"`
+	// AsOf is the -as-of date the scan was backdated to; generatedAt is
+	// then that time too.
+	AsOf string `json:"asOf,omitempty"`
+}
+
+// hashedRepo is what a repo contributes to the content hash: its score,
+// the counts behind it and the issues it flags. None of them moves with the
+// clock alone; ages, medians and the like, which do, are left out, along
+// with the history-derived streaks and smoothed scores.
+type hashedRepo struct {
+	Name                 string         `json:"name"`
+	Error                string         `json:"error,omitempty"`
+	HealthScore          int            `json:"healthScore"`
+	Status               string         `json:"status"`
+	TotalOpen            int            `json:"totalOpen"`
+	StaleCount           int            `json:"staleCount"`
+	StaleExempt          []int          `json:"staleExempt,omitempty"`
+	UnlabeledCount       int            `json:"unlabeledCount"`
+	OnboardingCount      int            `json:"onboardingCount"`
+	SecurityOverdueCount int            `json:"securityOverdueCount,omitempty"`
+	SLOBreaches          []hashedBreach `json:"sloBreaches,omitempty"`
+	Duplicates           [][2]int       `json:"duplicates,omitempty"`
+	Resurrected          []int          `json:"resurrected,omitempty"`
+	Exemption            *exemption     `json:"exemption,omitempty"`
+}
+
+// hashedBreach is an SLO breach without how many days it has run.
+type hashedBreach struct {
+	Number    int    `json:"number"`
+	Label     string `json:"label"`
+	Kind      string `json:"kind"`
+	Responded bool   `json:"responded"`
+}
+
+// hashedGate is a group's gate verdict.
+type hashedGate struct {
+	Group string      `json:"group"`
+	Gate  *gateResult `json:"gate"`
+}
+
+func hashRepo(rs repoScore) hashedRepo {
+	hr := hashedRepo{Name: rs.Name, Error: rs.Error, HealthScore: rs.HealthScore, Status: rs.Status, TotalOpen: rs.TotalOpen,
+		StaleCount: rs.StaleCount, StaleExempt: rs.StaleExempt, UnlabeledCount: rs.UnlabeledCount, OnboardingCount: rs.OnboardingCount,
+		SecurityOverdueCount: rs.SecurityOverdueCount, Exemption: rs.Exemption}
+	for _, b := range rs.SLOBreaches {
+		hr.SLOBreaches = append(hr.SLOBreaches, hashedBreach{b.Number, b.Label, b.Kind, b.Responded})
+	}
+	for _, d := range rs.Duplicates {
+		hr.Duplicates = append(hr.Duplicates, [2]int{d.Number, d.DuplicateOf})
+	}
+	if rs.Resurrections != nil {
+		hr.Resurrected = rs.Resurrections.Issues
+	}
+	return hr
+}
+
+// contentHash hashes what out's verdicts rest on: the orgs, the config,
+// each repo's hashedRepo and the gates, so two runs over an untouched
+// backlog share it however far apart they are, incremental runs reusing
+// stored scores share it with the full run they reuse, and a gate that
+// flips changes it even when no count does.
+func contentHash(out output) string {
+	repos := make([]hashedRepo, 0, len(out.Repos))
+	for _, rs := range out.Repos {
+		repos = append(repos, hashRepo(rs))
+	}
+	var groups []hashedGate
+	for _, g := range out.Groups {
+		if g.Gate != nil {
+			groups = append(groups, hashedGate{g.Name, g.Gate})
+		}
+	}
+	h := sha256.New()
+	_ = json.NewEncoder(h).Encode(struct {
+		Org        string       `json:"org"`
+		Orgs       []string     `json:"orgs,omitempty"`
+		Config     config       `json:"config"`
+		Repos      []hashedRepo `json:"repos"`
+		Gate       *gateResult  `json:"gate,omitempty"`
+		GroupGates []hashedGate `json:"groupGates,omitempty"`
+	}{out.Org, out.Orgs, out.Config, repos, out.Gate, groups})
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// previousContentHash returns the content hash of the newest stored run, or
+// "" if there is none or it predates content hashing.
+func previousContentHash(s Storage) (string, error) {
+	runs, err := s.ListRuns()
+	if err != nil || len(runs) == 0 {
+		return "", err
+	}
+	r, err := s.GetRun(runs[0].ID)
+	if err != nil {
+		return "", err
+	}
+	if r.Report.Meta == nil {
+		return "", nil
+	}
+	return r.Report.Meta.ContentHash, nil
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestContentHashIgnoresRunToRunFields(t *testing.T) {
+	repos := []repoScore{{Name: "a", HealthScore: 30, Status: "critical", CriticalRuns: 1}}
+	a := output{GeneratedAt: "2025-06-01T00:00:00Z", Org: "org", Repos: repos, Summary: summarize(repos)}
+	b := a
+	b.GeneratedAt = "2025-06-02T00:00:00Z"
+	b.Repos = []repoScore{repos[0]}
+	b.Repos[0].CriticalRuns = 2
+	smoothed := 31.5
+	b.Repos[0].SmoothedScore = &smoothed
+	b.Meta = &reportMeta{ContentHash: "old"}
+	if contentHash(a) != contentHash(b) {
+		t.Error("hash should ignore timestamps, meta and history-derived fields")
+	}
+	b.Repos[0].SLOBreaches = []sloBreach{{Number: 1, Label: "bug", Kind: "resolution", TargetDays: 30, ActualDays: 40}}
+	a.Repos = []repoScore{repos[0]}
+	a.Repos[0].SLOBreaches = []sloBreach{{Number: 1, Label: "bug", Kind: "resolution", TargetDays: 30, ActualDays: 31}}
+	b.Repos[0].Deep = &deepMetrics{MedianAgeDays: 40}
+	b.SecurityBacklog = []securityIssue{{Repo: "a", Number: 1, AgeDays: 91}}
+	if contentHash(a) != contentHash(b) {
+		t.Error("hash should ignore ages and other measures that grow with the clock")
+	}
+	b.Gate = &gateResult{Passed: false, Reasons: []string{"1 critical repo"}}
+	if contentHash(a) == contentHash(b) {
+		t.Error("hash should change when the gate does, even if no count did")
+	}
+	b.Gate = nil
+	b.Repos[0].Resurrections = &resurrections{Count: 1, Issues: []int{7}}
+	if contentHash(a) == contentHash(b) {
+		t.Error("hash should change with the flagged issues")
+	}
+	b.Repos[0].Resurrections = nil
+	b.Repos[0].StaleCount = 4
+	if contentHash(a) == contentHash(b) {
+		t.Error("hash should change with the backlog")
+	}
+}
+
+func TestPreviousContentHash(t *testing.T) {
+	s := openStorage(&storageConfig{Driver: "memory"})
+	if h, err := previousContentHash(s); err != nil || h != "" {
+		t.Fatalf("empty storage: %q, %v", h, err)
+	}
+	h := &history{Repos: map[string]*repoHistory{}}
+	t0 := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	_ = s.SaveRun(newRun(output{Org: "org"}, h, t0))
+	_ = s.SaveRun(newRun(output{Org: "org", Meta: &reportMeta{ContentHash: "abc"}}, h, t0.Add(time.Hour)))
+	if got, err := previousContentHash(s); err != nil || got != "abc" {
+		t.Errorf("previousContentHash = %q, %v", got, err)
+	}
+}
@@ -0,0 +1,171 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+var contributorWindowDays = flag.Int("contributor-window-days", 0, "measure contributor concentration over commits and issue closes in the last N days (0 disables; --deep uses 90)")
+
+// contributorMetrics reports how concentrated recent maintenance work is.
+// BusFactor is the smallest number of people accounting for at least half
+// of the activity; a bus factor of 1 means one person carries the repo.
+type contributorMetrics struct {
+	WindowDays          int     `json:"windowDays"`
+	Commits             int     `json:"commits"`
+	IssuesClosed        int     `json:"issuesClosed"`
+	ActiveContributors  int     `json:"activeContributors"`
+	BusFactor           int     `json:"busFactor"`
+	TopContributor      string  `json:"topContributor,omitempty"`
+	TopContributorShare float64 `json:"topContributorShare"`
+}
+
+func (c config) contributorWindow() int {
+	if c.ContributorWindowDays > 0 {
+		return c.ContributorWindowDays
+	}
+	if c.Deep {
+		return defaultResponseWindowDays
+	}
+	return 0
+}
+
+type commitInfo struct {
+	Author *actor `json:"author"`
+	Commit struct {
+		Author struct {
+			Name string `json:"name"`
+		} `json:"author"`
+	} `json:"commit"`
+}
+
+type issueEvent struct {
+	Event     string    `json:"event"`
+	Actor     *actor    `json:"actor"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// fetchContributorMetrics gathers commit authors and issue closers since the
+// window start. Bots are left out: they don't go on vacation.
+func fetchContributorMetrics(ctx context.Context, owner, repo string, windowDays int, now time.Time) (*contributorMetrics, error) {
+	since := now.AddDate(0, 0, -windowDays)
+	commits, err := ghAPIList[commitInfo](ctx, fmt.Sprintf("repos/%s/%s/commits?per_page=100&since=%s", owner, repo, url.QueryEscape(since.UTC().Format(time.RFC3339))))
+	if err != nil {
+		return nil, err
+	}
+	events, err := ghListIssueEventsSince(ctx, owner, repo, since)
+	if err != nil {
+		return nil, err
+	}
+	var commitAuthors, closers []string
+	for _, c := range commits {
+		switch {
+		case c.Author != nil && c.Author.Login != "":
+			commitAuthors = append(commitAuthors, c.Author.Login)
+		case c.Commit.Author.Name != "":
+			commitAuthors = append(commitAuthors, c.Commit.Author.Name)
+		}
+	}
+	for _, e := range events {
+		if e.Event == "closed" && e.Actor != nil && !e.CreatedAt.Before(since) {
+			closers = append(closers, e.Actor.Login)
+		}
+	}
+	return computeContributorMetrics(commitAuthors, closers, windowDays), nil
+}
+
+// maxEventPages bounds how far back ghListIssueEventsSince will page.
+const maxEventPages = 10
+
+// ghListIssueEventsSince pages through a repo's issue events, newest first,
+// until it passes since. The endpoint has no since filter, and paging a busy
+// repo's full history would burn the rate limit.
+func ghListIssueEventsSince(ctx context.Context, owner, repo string, since time.Time) ([]issueEvent, error) {
+	var events []issueEvent
+	for page := 1; page <= maxEventPages; page++ {
+		stdout, err := runCmd(ctx, "gh", "api", fmt.Sprintf("repos/%s/%s/issues/events?per_page=100&page=%d", owner, repo, page))
+		if err != nil {
+			return nil, err
+		}
+		var batch []issueEvent
+		if err := json.Unmarshal(stdout, &batch); err != nil {
+			return nil, fmt.Errorf("parse gh api issue events: %w", err)
+		}
+		events = append(events, batch...)
+		if len(batch) < 100 || batch[len(batch)-1].CreatedAt.Before(since) {
+			break
+		}
+	}
+	return events, nil
+}
+
+func isBot(login string) bool {
+	return strings.HasSuffix(login, "[bot]")
+}
+
+func computeContributorMetrics(commitAuthors, closers []string, windowDays int) *contributorMetrics {
+	cm := &contributorMetrics{WindowDays: windowDays}
+	counts := map[string]int{}
+	total := 0
+	for _, who := range commitAuthors {
+		if !isBot(who) {
+			counts[who]++
+			cm.Commits++
+			total++
+		}
+	}
+	for _, who := range closers {
+		if !isBot(who) {
+			counts[who]++
+			cm.IssuesClosed++
+			total++
+		}
+	}
+	cm.ActiveContributors = len(counts)
+	if total == 0 {
+		return cm
+	}
+	people := make([]string, 0, len(counts))
+	for who := range counts {
+		people = append(people, who)
+	}
+	sort.Slice(people, func(i, j int) bool {
+		if counts[people[i]] != counts[people[j]] {
+			return counts[people[i]] > counts[people[j]]
+		}
+		return people[i] < people[j]
+	})
+	cm.TopContributor = people[0]
+	cm.TopContributorShare = float64(counts[people[0]]) / float64(total) * 100
+	covered := 0
+	for _, who := range people {
+		covered += counts[who]
+		cm.BusFactor++
+		if covered*2 >= total {
+			break
+		}
+	}
+	return cm
+}
+
+// addContributorMetrics fills in score.Contributors when the analysis is
+// enabled. Failures are logged and leave the core score untouched.
+func addContributorMetrics(ctx context.Context, score *repoScore, owner, repo string, cfg config, now time.Time) {
+	window := cfg.contributorWindow()
+	if window <= 0 {
+		return
+	}
+	cm, err := fetchContributorMetrics(ctx, owner, repo, window, now)
+	if err != nil {
+		slog.Warn("contributor metric unavailable", "repo", repo, "error", err)
+		return
+	}
+	score.Contributors = cm
+}
@@ -0,0 +1,23 @@
+package main
+
+import "testing"
+
+func TestComputeContributorMetrics(t *testing.T) {
+	commits := []string{"alice", "alice", "alice", "bob", "dependabot[bot]", "carol"}
+	closers := []string{"alice", "bob", "github-actions[bot]"}
+	cm := computeContributorMetrics(commits, closers, 90)
+	if cm.Commits != 5 || cm.IssuesClosed != 2 || cm.ActiveContributors != 3 {
+		t.Errorf("counts = %+v, want 5 commits, 2 closes, 3 people", cm)
+	}
+	if cm.BusFactor != 1 || cm.TopContributor != "alice" || cm.TopContributorShare < 57 || cm.TopContributorShare > 58 {
+		t.Errorf("alice holds 4 of 7 events: got %+v", cm)
+	}
+
+	spread := computeContributorMetrics([]string{"a", "b", "c", "d"}, nil, 90)
+	if spread.BusFactor != 2 {
+		t.Errorf("four equal contributors: bus factor = %d, want 2", spread.BusFactor)
+	}
+	if empty := computeContributorMetrics(nil, nil, 90); empty.BusFactor != 0 || empty.TopContributor != "" {
+		t.Errorf("no activity should report zero: %+v", empty)
+	}
+}
@@ -0,0 +1,307 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	serveAddr     = flag.String("addr", ":8080", "serve: HTTP listen address")
+	deltaInterval = flag.Duration("delta-interval", time.Hour, "serve: interval between incremental delta scans")
+	fullInterval  = flag.Duration("full-interval", 24*time.Hour, "serve: interval between full deep scans")
+)
+
+// freshness records when each metric tier of a served repo was last
+// refreshed. Core covers the counts, score, status, SLO and security
+// checks; deep covers the --deep drill-down, firstResponse, firstLabel and
+// contributors, which only full scans fetch.
+type freshness struct {
+	Core string `json:"core"`
+	Deep string `json:"deep,omitempty"`
+}
+
+// daemon keeps the latest report in memory, refreshing it with cheap delta
+// scans between full deep scans.
+type daemon struct {
+	name       string // tenant name; empty when serving a single selection
+	sel        selection
+	cfg        config
+	store      Storage // nil unless storage is configured
+	notify     notifiers
+	deltaEvery time.Duration
+	fullEvery  time.Duration
+	log        *slog.Logger
+
+	mu       sync.RWMutex
+	report   *output
+	repos    []repoScore
+	lastScan time.Time
+	feed     []byte // nil unless a public feed is configured
+	feedETag string
+}
+
+func newDaemon(name string, sel selection, cfg config, deltaEvery, fullEvery time.Duration) *daemon {
+	d := &daemon{name: name, sel: sel, cfg: cfg, deltaEvery: deltaEvery, fullEvery: fullEvery, log: slog.Default()}
+	if name != "" {
+		d.log = d.log.With("tenant", name)
+	}
+	if cfg.Storage != nil {
+		d.store = openStorage(cfg.Storage)
+	}
+	return d
+}
+
+// runServe implements `fab-backlog serve`.
+func runServe(ctx context.Context, sel selection, cfg config) int {
+	var daemons []*daemon
+	var handler http.Handler
+	if *tenantsFile != "" {
+		var err error
+		if daemons, err = loadTenants(*tenantsFile, cfg); err != nil {
+			slog.Error("invalid tenants", "file", *tenantsFile, "error", err)
+			emitJSON(map[string]any{"ok": false, "error": err.Error()})
+			return 2
+		}
+		handler = tenantsHandler(daemons)
+	} else {
+		if *deltaInterval <= 0 || *fullInterval <= 0 {
+			slog.Error("serve intervals must be positive", "delta_interval", *deltaInterval, "full_interval", *fullInterval)
+			return 2
+		}
+		daemons = []*daemon{newDaemon("", sel, cfg, *deltaInterval, *fullInterval)}
+		handler = daemons[0].handler()
+	}
+	ctx, stop := context.WithCancel(ctx)
+	defer stop()
+
+	srv := &http.Server{Addr: *serveAddr, Handler: handler}
+	go func() {
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			slog.Error("http server failed", "addr", *serveAddr, "error", err)
+			stop()
+		}
+	}()
+	var wg sync.WaitGroup
+	for _, d := range daemons {
+		d.log.Info("serving", "addr", *serveAddr, "orgs", d.sel.Orgs, "repos", d.sel.Repos, "delta_interval", d.deltaEvery, "full_interval", d.fullEvery)
+		wg.Go(func() { d.schedule(ctx) })
+	}
+	<-ctx.Done()
+	shutdown, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_ = srv.Shutdown(shutdown)
+	wg.Wait()
+	return 0
+}
+
+// schedule runs a full scan straight away, then delta and full scans on
+// the daemon's intervals until ctx is done.
+func (d *daemon) schedule(ctx context.Context) {
+	d.fullScan(ctx, time.Now())
+	full := time.NewTicker(d.fullEvery)
+	defer full.Stop()
+	delta := time.NewTicker(d.deltaEvery)
+	defer delta.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-full.C:
+			d.fullScan(ctx, now)
+		case now := <-delta.C:
+			d.deltaScan(ctx, now)
+		}
+	}
+}
+
+func (d *daemon) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /healthz", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("ok\n"))
+	})
+	mux.HandleFunc("GET /report", func(w http.ResponseWriter, r *http.Request) {
+		d.mu.RLock()
+		report := d.report
+		d.mu.RUnlock()
+		w.Header().Set("Content-Type", "application/json")
+		if report == nil {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			_ = writeJSON(w, map[string]any{"ok": false, "error": "first scan in progress"})
+			return
+		}
+		_ = writeJSON(w, report)
+	})
+	if d.cfg.PublicFeed != nil {
+		mux.HandleFunc("GET /public/feed.json", d.feedHandler)
+		mux.HandleFunc("OPTIONS /public/feed.json", d.feedHandler)
+	}
+	return mux
+}
+
+func (d *daemon) fullScan(ctx context.Context, now time.Time) {
+	org := d.sel.primaryOrg()
+	d.log.Info("full scan starting", "orgs", d.sel.Orgs, "repos", d.sel.Repos)
+	repos, err := d.sel.resolve(ctx)
+	if err != nil {
+		d.log.Error("full scan: failed to list repos", "orgs", d.sel.Orgs, "error", err)
+		return
+	}
+	cfg := d.cfg
+	cfg.Deep = true
+	scored := scoreRepos(ctx, org, repos, cfg, now)
+	if ctx.Err() != nil {
+		return // shutting down; keep serving the last complete report
+	}
+	stamp := now.UTC().Format(time.RFC3339)
+	for i := range scored {
+		scored[i].Freshness = &freshness{Core: stamp, Deep: stamp}
+	}
+	report := d.publish(ctx, scored, now)
+	d.notify.send(d.log, report)
+}
+
+func (d *daemon) deltaScan(ctx context.Context, now time.Time) {
+	d.mu.RLock()
+	since, known := d.lastScan, d.repos
+	d.mu.RUnlock()
+	if since.IsZero() {
+		// No full scan has succeeded yet; there is nothing to patch.
+		d.fullScan(ctx, now)
+		return
+	}
+	changed, err := d.changedSince(ctx, since, known)
+	if err != nil {
+		d.log.Error("delta scan: search failed", "error", err)
+		return
+	}
+	d.log.Info("delta scan starting", "changed", len(changed), "since", since.UTC().Format(time.RFC3339))
+	// Delta scans only refresh the core tier; deep metrics wait for the
+	// next full scan.
+	cfg := d.cfg
+	cfg.Deep = false
+	cfg.ResponseWindowDays = 0
+	cfg.LabelLatencyWindowDays = 0
+	cfg.ContributorWindowDays = 0
+	cfg.QAWindowDays = 0
+	fresh := scoreRepos(ctx, d.sel.primaryOrg(), changed, cfg, now)
+	if ctx.Err() != nil {
+		return
+	}
+	d.publish(ctx, mergeDelta(known, fresh, now), now)
+}
+
+// changedSince returns the selected repos with pushes or issue activity
+// since the given time. New repos count when their whole org is selected.
+func (d *daemon) changedSince(ctx context.Context, since time.Time, known []repoScore) ([]string, error) {
+	var names []string
+	for _, rs := range known {
+		names = append(names, rs.fullName)
+	}
+	owners := slices.Clone(d.sel.Orgs)
+	for _, o := range ownersOf(names) {
+		if !slices.ContainsFunc(owners, func(s string) bool { return strings.EqualFold(s, o) }) {
+			owners = append(owners, o)
+		}
+	}
+	activity, err := recentActivity(ctx, owners, since)
+	if err != nil {
+		return nil, err
+	}
+	var changed []string
+	isKnown := map[string]bool{}
+	for _, full := range names {
+		isKnown[strings.ToLower(full)] = true
+		if activity.changedRepo(full) {
+			changed = append(changed, full)
+		}
+	}
+	var created []string
+	for key, full := range activity.changed {
+		owner, _ := splitRepo("", full)
+		if !isKnown[key] && slices.ContainsFunc(d.sel.Orgs, func(o string) bool { return strings.EqualFold(o, owner) }) && !ignoredRepo(full, d.sel.Ignore) {
+			created = append(created, full)
+		}
+	}
+	slices.Sort(created)
+	return append(changed, created...), nil
+}
+
+func (d *daemon) publish(ctx context.Context, repos []repoScore, now time.Time) output {
+	report := assemble(d.sel.primaryOrg(), d.cfg, repos, now)
+	if len(d.sel.Orgs) > 1 {
+		report.Orgs = d.sel.Orgs
+	}
+	addOrgSections(ctx, &report, d.cfg, now)
+	var feed []byte
+	if d.cfg.PublicFeed != nil {
+		var err error
+		if feed, err = d.cfg.PublicFeed.build(report); err != nil {
+			d.log.Error("failed to build public feed", "error", err)
+		}
+	}
+	d.mu.Lock()
+	d.report, d.repos, d.lastScan = &report, repos, now
+	if feed != nil {
+		d.feed, d.feedETag = feed, feedETag(feed)
+	}
+	d.mu.Unlock()
+	if d.store != nil {
+		d.saveRun(report, now)
+	}
+	return report
+}
+
+// saveRun stores a published report. The daemon doesn't track cross-run
+// state itself, so the history is carried over from the previous run.
+func (d *daemon) saveRun(report output, now time.Time) {
+	h, err := latestHistory(d.store)
+	if err == nil {
+		err = d.store.SaveRun(newRun(report, h, now))
+	}
+	if err != nil {
+		d.log.Error("failed to save run", "driver", d.cfg.Storage.Driver, "error", err)
+	}
+}
+
+// mergeDelta overlays freshly scored repos onto the previous state. Deep
+// metrics and their freshness carry over, and a repo whose delta fetch
+// failed keeps its previous data rather than turning into an error.
+func mergeDelta(prev, fresh []repoScore, now time.Time) []repoScore {
+	stamp := now.UTC().Format(time.RFC3339)
+	byName := make(map[string]repoScore, len(fresh))
+	for _, rs := range fresh {
+		byName[strings.ToLower(rs.fullName)] = rs
+	}
+	merged := make([]repoScore, 0, len(prev)+len(fresh))
+	seen := map[string]bool{}
+	for _, old := range prev {
+		key := strings.ToLower(old.fullName)
+		rs, ok := byName[key]
+		seen[key] = true
+		if !ok || rs.Error != "" {
+			merged = append(merged, old)
+			continue
+		}
+		rs.Deep, rs.FirstResponse, rs.FirstLabel, rs.Contributors, rs.QA = old.Deep, old.FirstResponse, old.FirstLabel, old.Contributors, old.QA
+		rs.Freshness = &freshness{Core: stamp}
+		if old.Freshness != nil {
+			rs.Freshness.Deep = old.Freshness.Deep
+		}
+		merged = append(merged, rs)
+	}
+	// Repos created since the last full scan join with core metrics only.
+	for _, rs := range fresh {
+		if !seen[strings.ToLower(rs.fullName)] {
+			rs.Freshness = &freshness{Core: stamp}
+			merged = append(merged, rs)
+		}
+	}
+	return merged
+}
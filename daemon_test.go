@@ -0,0 +1,64 @@
+package main
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestMergeDelta(t *testing.T) {
+	full := "2025-06-01T00:00:00Z"
+	deep := &deepMetrics{OldestIssueDays: 400}
+	prev := []repoScore{
+		{Name: "a", fullName: "org/a", HealthScore: 85, Deep: deep, Freshness: &freshness{Core: full, Deep: full}},
+		{Name: "b", fullName: "org/b", HealthScore: 70, Freshness: &freshness{Core: full, Deep: full}},
+		{Name: "c", fullName: "org/c", HealthScore: 50, Freshness: &freshness{Core: full, Deep: full}},
+	}
+	fresh := []repoScore{
+		{Name: "a", fullName: "org/a", HealthScore: 55},
+		{Name: "b", fullName: "org/b", Error: "rate limited"},
+		{Name: "d", fullName: "org/d", HealthScore: 100},
+	}
+	now := time.Date(2025, 6, 1, 1, 0, 0, 0, time.UTC)
+	got := mergeDelta(prev, fresh, now)
+	if len(got) != 4 {
+		t.Fatalf("merged %d repos, want 4", len(got))
+	}
+	a := got[0]
+	if a.HealthScore != 55 || a.Deep != deep || a.Freshness.Core != "2025-06-01T01:00:00Z" || a.Freshness.Deep != full {
+		t.Errorf("refreshed repo should keep deep tier from the full scan: %+v %+v", a, a.Freshness)
+	}
+	if got[1].HealthScore != 70 || got[1].Error != "" || got[1].Freshness.Core != full {
+		t.Errorf("failed delta should keep previous data: %+v", got[1])
+	}
+	if got[2].Freshness.Core != full {
+		t.Errorf("untouched repo should keep its freshness: %+v", got[2].Freshness)
+	}
+	if got[3].Name != "d" || got[3].Freshness.Deep != "" {
+		t.Errorf("new repo should join with core metrics only: %+v", got[3])
+	}
+}
+
+func TestChangedSince(t *testing.T) {
+	gh := newFakeGitHub(t)
+	now := time.Now()
+	genFixture(gh, "acme", 2, 4, 3, now.Add(-time.Hour))
+	since := now.Add(-time.Minute)
+	gh.mu.Lock()
+	gh.repos["acme/repo-01"].Issues[0].UpdatedAt = now
+	gh.repos["acme/repo-02"].PushedAt = now
+	gh.mu.Unlock()
+	gh.addRepo(&fakeRepo{Owner: "acme", Name: "New-Repo", PushedAt: now, UpdatedAt: now})
+	gh.addRepo(&fakeRepo{Owner: "acme", Name: "scratch", PushedAt: now, UpdatedAt: now})
+
+	known := []repoScore{{fullName: "acme/repo-00"}, {fullName: "acme/repo-01"}, {fullName: "acme/repo-02"}}
+	d := newDaemon("", selection{Orgs: []string{"acme"}, Ignore: []string{"acme/scratch"}}, config{}, time.Hour, time.Hour)
+	changed, err := d.changedSince(t.Context(), since, known)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// New repos in the selected org join, unless they're ignored.
+	if want := []string{"acme/repo-01", "acme/repo-02", "acme/New-Repo"}; !slices.Equal(changed, want) {
+		t.Errorf("changed = %v, want %v", changed, want)
+	}
+}
@@ -0,0 +1,257 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"hash/fnv"
+	"slices"
+	"strings"
+)
+
+var demo = flag.Bool("demo", false, "replace org, repo, user, team and issue title names in the report with deterministic pseudonyms, keeping every number, for talks and demos")
+
+// Word lists pseudonyms are built from. They only grow at the end, so
+// existing pseudonyms stay put.
+var (
+	demoAdjectives = []string{"amber", "brisk", "cedar", "dusty", "eager", "fuzzy", "gentle", "hidden", "iron", "jolly",
+		"keen", "lunar", "mellow", "nimble", "olive", "polar", "quiet", "rapid", "silver", "tidal", "urban", "vivid", "woolly", "young"}
+	demoNouns = []string{"badger", "beacon", "canyon", "comet", "delta", "ember", "falcon", "garden", "harbor", "island",
+		"juniper", "kettle", "lantern", "meadow", "nebula", "orchard", "pebble", "quarry", "river", "summit", "thicket", "valley", "willow", "zephyr"}
+	demoOrgSuffixes = []string{"labs", "works", "systems", "cloud", "collective", "industries"}
+	demoVerbs       = []string{"Fix", "Add", "Improve", "Support", "Remove", "Handle", "Document", "Refactor", "Speed up", "Clarify"}
+	demoSubjects    = []string{"login timeout", "cache invalidation", "CSV export", "dark mode", "rate limiting", "error messages",
+		"pagination", "config reload", "webhook retries", "search ranking", "install script", "date parsing", "upload progress", "audit log"}
+	demoQualifiers = []string{"", " on Windows", " for large accounts", " after upgrading", " in the CLI", " when offline", " behind a proxy", " on first run"}
+)
+
+// demoNameKeys hold org, repo, user and team names, alone, as owner/name
+// pairs, CODEOWNERS owners, in URLs or quoted in error messages. Only their
+// values have names swapped out; every other string is left as it is, so a repo named like an
+// ordinary word doesn't rename the word in prose.
+var demoNameKeys = map[string]bool{"org": true, "orgs": true, "repo": true, "repos": true, "nameWithOwner": true, "excludeRepos": true,
+	"login": true, "topContributor": true, "username": true, "author": true, "user": true, "codeDebtAuthors": true,
+	"owner": true, "owners": true, "default": true, "team": true, "teams": true, "url": true,
+	"error": true, "message": true}
+
+func demoHash(kind, real string) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(kind + "\x00" + strings.ToLower(real)))
+	return h.Sum64()
+}
+
+func pick(words []string, h uint64) string {
+	return words[h%uint64(len(words))]
+}
+
+// pseudonymizer maps the real names in one report to pseudonyms. The same
+// name always gets the same pseudonym, in any report, unless two names
+// collide, when the later in sort order gets a numeric suffix.
+type pseudonymizer struct {
+	names map[string]string // lowercased real name -> pseudonym
+	used  map[string]bool
+}
+
+func newPseudonymizer(orgs, repos, users, teams []string) *pseudonymizer {
+	p := &pseudonymizer{names: map[string]string{}, used: map[string]bool{}}
+	p.assign("org", orgs, func(h uint64) string {
+		return pick(demoAdjectives, h) + "-" + pick(demoOrgSuffixes, h>>16)
+	})
+	p.assign("repo", repos, func(h uint64) string {
+		return pick(demoAdjectives, h) + "-" + pick(demoNouns, h>>16)
+	})
+	p.assign("user", users, func(h uint64) string {
+		return pick(demoNouns, h) + fmt.Sprint((h>>16)%90+10)
+	})
+	p.assign("team", teams, func(h uint64) string {
+		return pick(demoNouns, h) + "-team"
+	})
+	return p
+}
+
+func (p *pseudonymizer) assign(kind string, reals []string, name func(uint64) string) {
+	reals = slices.Sorted(slices.Values(reals))
+	for _, real := range slices.Compact(reals) {
+		key := strings.ToLower(real)
+		if _, ok := p.names[key]; ok || key == "" {
+			continue
+		}
+		base := name(demoHash(kind, real))
+		fake := base
+		for n := 2; p.used[fake]; n++ {
+			fake = fmt.Sprintf("%s-%d", base, n)
+		}
+		p.names[key], p.used[fake] = fake, true
+	}
+}
+
+// title makes up a plausible issue title for a real one.
+func (p *pseudonymizer) title(real string) string {
+	if real == "" {
+		return ""
+	}
+	h := demoHash("title", real)
+	return pick(demoVerbs, h) + " " + pick(demoSubjects, h>>16) + pick(demoQualifiers, h>>32)
+}
+
+func isNameChar(r rune) bool {
+	return r == '-' || r == '_' || r == '.' || r >= '0' && r <= '9' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z'
+}
+
+// text replaces every whole known name in s, so owner/name pairs and URLs
+// are covered as well as bare names.
+func (p *pseudonymizer) text(s string) string {
+	var b strings.Builder
+	for len(s) > 0 {
+		i := strings.IndexFunc(s, func(r rune) bool { return !isNameChar(r) })
+		if i == 0 {
+			b.WriteByte(s[0])
+			s = s[1:]
+			continue
+		}
+		if i < 0 {
+			i = len(s)
+		}
+		tok := s[:i]
+		// A name ending a sentence keeps its full stop.
+		trimmed := strings.TrimRight(tok, ".")
+		if fake, ok := p.names[strings.ToLower(trimmed)]; ok {
+			b.WriteString(fake + tok[len(trimmed):])
+		} else {
+			b.WriteString(tok)
+		}
+		s = s[i:]
+	}
+	return b.String()
+}
+
+// collectNames finds the orgs, repos, users and teams a decoded report
+// mentions.
+func collectNames(v any, key string, orgs, repos, users, teams *[]string) {
+	switch v := v.(type) {
+	case map[string]any:
+		for k, child := range v {
+			collectNames(child, k, orgs, repos, users, teams)
+		}
+	case []any:
+		for _, child := range v {
+			collectNames(child, key, orgs, repos, users, teams)
+		}
+	case string:
+		switch key {
+		case "org", "orgs":
+			*orgs = append(*orgs, v)
+		case "repo", "nameWithOwner":
+			if owner, name, ok := strings.Cut(v, "/"); ok {
+				*orgs = append(*orgs, owner)
+				v = name
+			}
+			*repos = append(*repos, v)
+		case "login", "topContributor", "username", "author", "user":
+			*users = append(*users, v)
+		case "owner", "owners", "default":
+			// CODEOWNERS owners: @user, @org/team or an email address.
+			v = strings.TrimPrefix(v, "@")
+			if org, team, ok := strings.Cut(v, "/"); ok {
+				*orgs, *teams = append(*orgs, org), append(*teams, team)
+			} else {
+				user, _, _ := strings.Cut(v, "@")
+				*users = append(*users, user)
+			}
+		}
+	}
+}
+
+// rewrite pseudonymizes a decoded report in place. Issue titles, which
+// sit beside a number, are replaced whole, and strings under demoNameKeys,
+// alone or in an array, have known names swapped out. Keys themselves are
+// left alone: none of the report's maps are keyed by repo. Everything else
+// is kept as it is.
+func (p *pseudonymizer) rewrite(v any, key string) any {
+	switch v := v.(type) {
+	case map[string]any:
+		_, isIssue := v["number"]
+		out := make(map[string]any, len(v))
+		for k, child := range v {
+			if s, ok := child.(string); ok && isIssue && (k == "title" || k == "ofTitle") {
+				out[k] = p.title(s)
+				continue
+			}
+			out[k] = p.rewrite(child, k)
+		}
+		return out
+	case []any:
+		for i, child := range v {
+			v[i] = p.rewrite(child, key)
+		}
+		return v
+	case string:
+		if demoNameKeys[key] {
+			return p.text(v)
+		}
+	}
+	return v
+}
+
+// pseudonymize returns the -demo version of out: the same report with org,
+// repo and user names and issue titles made up.
+func pseudonymize(out output) (output, error) {
+	data, err := json.Marshal(out)
+	if err != nil {
+		return out, err
+	}
+	var doc map[string]any
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return out, err
+	}
+	var orgs, repos, users, teams []string
+	collectNames(doc, "", &orgs, &repos, &users, &teams)
+	if list, ok := doc["repos"].([]any); ok {
+		for _, r := range list {
+			if name, ok := r.(map[string]any)["name"].(string); ok {
+				if owner, n, ok := strings.Cut(name, "/"); ok {
+					orgs, name = append(orgs, owner), n
+				}
+				repos = append(repos, name)
+			}
+		}
+	}
+	for _, r := range out.TeamRollups {
+		teams = append(teams, r.Team)
+	}
+	p := newPseudonymizer(orgs, repos, users, teams)
+	if data, err = json.Marshal(p.rewrite(doc, "")); err != nil {
+		return out, err
+	}
+	var fake output
+	if err := json.Unmarshal(data, &fake); err != nil {
+		return out, err
+	}
+	// "name" names repos here, but labels, groups and more elsewhere.
+	// Notifications link repos by their unexported full name.
+	for i := range fake.Repos {
+		fake.Repos[i].Name = p.text(out.Repos[i].Name)
+		fake.Repos[i].fullName = p.text(out.Repos[i].fullName)
+	}
+	// Team display names are free text; they take the slug's pseudonym.
+	for i := range fake.TeamRollups {
+		fake.TeamRollups[i].Name = fake.TeamRollups[i].Team
+	}
+	return fake, nil
+}
+
+// validateDemo checks -demo against the command and streaming.
+func validateDemo(cmd string) error {
+	if !*demo {
+		return nil
+	}
+	switch {
+	case cmd != "scan" && cmd != "focus" && cmd != "report":
+		return fmt.Errorf("-demo doesn't apply to %s", cmd)
+	case *formatFlag == "ndjson":
+		return fmt.Errorf("-demo can't be combined with -format ndjson: repos are streamed before the whole report is known")
+	case *checkRun != "" || *postIssue != "" || *commitReport != "":
+		return fmt.Errorf("-demo reports can't be published to GitHub with -check-run, -post-issue or -commit-report")
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	ghscan "github.com/misty-step/fab-backlog/pkg/scan"
+)
+
+func TestPseudonymize(t *testing.T) {
+	out := output{
+		Org: "globex",
+		Repos: []repoScore{
+			{Name: "billing", HealthScore: 35, Status: "critical", TotalOpen: 40, StaleCount: 12, fullName: "globex/billing",
+				Recommendations: []string{"3 triage actions queued — review them with -plan-out and apply"},
+				Contributors:    &contributorMetrics{Commits: 30, BusFactor: 1, TopContributor: "hank-scorpio", TopContributorShare: 0.8},
+				CodeOwners:      &codeOwners{Path: "CODEOWNERS", Owners: []string{"@globex/sales", "@frank-grimes"}}},
+			// A repo named like a status mustn't change the statuses.
+			{Name: "healthy", HealthScore: 90, Status: "healthy", fullName: "globex/healthy"},
+			// Nor one named like a word change the prose.
+			{Name: "triage", HealthScore: 90, Status: "healthy", fullName: "globex/triage",
+				APIErrors: []apiError{{Metric: "firstLabel", GraphQLError: ghscan.GraphQLError{Type: "NOT_FOUND", Message: "Could not resolve to a Repository with the name 'globex/billing'."}}}},
+			// Errors quote the command, repo and all.
+			{Name: "ledger", Error: "gh issue list --repo globex/ledger: exit status 1", fullName: "globex/ledger"},
+		},
+		MostWanted:  []wantedIssue{{Repo: "billing", Number: 7, Title: "Invoices for Springfield Nuclear are off by one", ThumbsUp: 9}},
+		HallOfShame: []shameIssue{{Repo: "globex/billing", Number: 3, Title: "Hammock district rollout", URL: "https://github.com/globex/billing/issues/3", AgeDays: 800}},
+		Summary:     summary{Total: 3, Healthy: 2, Critical: 1},
+		TeamRollups: []teamRollup{{Org: "globex", Team: "cypress-creek", Name: "Cypress Creek", Repos: []string{"billing"}, HealthScore: 35}},
+		Ownership:   []ownerRollup{{Owner: "@globex/sales", Repos: []string{"billing"}, HealthScore: 35}},
+	}
+	fake, err := pseudonymize(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, _ := json.Marshal(fake)
+	doc := strings.ToLower(string(data))
+	for _, real := range []string{"globex", "billing", "hank-scorpio", "springfield", "hammock", "cypress", "sales", "frank-grimes", "ledger"} {
+		if strings.Contains(doc, real) {
+			t.Errorf("%q survived pseudonymization:\n%s", real, data)
+		}
+	}
+
+	r := fake.Repos[0]
+	if r.HealthScore != 35 || r.Status != "critical" || r.TotalOpen != 40 || r.StaleCount != 12 || r.Contributors.TopContributorShare != 0.8 {
+		t.Errorf("numbers and statuses must be kept: %+v", r)
+	}
+	if fake.Repos[1].Status != "healthy" || fake.Repos[1].Name == "healthy" {
+		t.Errorf("status renamed or repo kept: %+v", fake.Repos[1])
+	}
+	if r.Recommendations[0] != out.Repos[0].Recommendations[0] {
+		t.Errorf("recommendation = %q, want it unchanged", r.Recommendations[0])
+	}
+	if fake.Repos[2].Name == "triage" {
+		t.Errorf("repo kept its name: %+v", fake.Repos[2])
+	}
+	if r.fullName != fake.Org+"/"+r.Name {
+		t.Errorf("fullName = %q, want %s/%s", r.fullName, fake.Org, r.Name)
+	}
+	shame := fake.HallOfShame[0]
+	if shame.Repo != fake.Org+"/"+r.Name || shame.URL != "https://github.com/"+shame.Repo+"/issues/3" || shame.Number != 3 || shame.AgeDays != 800 {
+		t.Errorf("hall of shame = %+v", shame)
+	}
+	if fake.MostWanted[0].Repo != r.Name || fake.MostWanted[0].Title == out.MostWanted[0].Title || fake.MostWanted[0].ThumbsUp != 9 {
+		t.Errorf("most wanted = %+v", fake.MostWanted[0])
+	}
+	if fake.Summary != out.Summary {
+		t.Errorf("summary = %+v", fake.Summary)
+	}
+
+	again, _ := pseudonymize(out)
+	if again2, _ := json.Marshal(again); string(again2) != string(data) {
+		t.Errorf("pseudonyms should be deterministic:\n%s\n%s", data, again2)
+	}
+}
+
+func TestValidateDemo(t *testing.T) {
+	prevDemo, prevFormat, prevCheck := *demo, *formatFlag, *checkRun
+	t.Cleanup(func() { *demo, *formatFlag, *checkRun = prevDemo, prevFormat, prevCheck })
+	*demo = true
+	if err := validateDemo("report"); err != nil {
+		t.Errorf("report: %v", err)
+	}
+	if err := validateDemo("serve"); err == nil {
+		t.Error("-demo with serve should be rejected")
+	}
+	*formatFlag = "ndjson"
+	if err := validateDemo("scan"); err == nil {
+		t.Error("-demo with ndjson should be rejected")
+	}
+	*formatFlag, *checkRun = "", "each"
+	if err := validateDemo("scan"); err == nil {
+		t.Error("-demo with -check-run should be rejected")
+	}
+}
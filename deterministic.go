@@ -0,0 +1,42 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var deterministic = flag.Bool("deterministic", false, "make reports reproducible: repos scanned in name order with ties broken by name, no run-specific API usage in meta, and the clock pinned to $SOURCE_DATE_EPOCH when it is set")
+
+// clock is where scans read the current time: generatedAt and every age,
+// window and staleness cutoff derive from it. -deterministic and -as-of
+// can pin it.
+var clock = time.Now
+
+// pinClock fixes the clock at $SOURCE_DATE_EPOCH (seconds since the Unix
+// epoch, as reproducible builds use) under -deterministic.
+func pinClock() error {
+	v := os.Getenv("SOURCE_DATE_EPOCH")
+	if !*deterministic || v == "" {
+		return nil
+	}
+	secs, err := strconv.ParseInt(v, 10, 64)
+	if err != nil {
+		return fmt.Errorf("SOURCE_DATE_EPOCH: want seconds since the epoch, got %q", v)
+	}
+	at := time.Unix(secs, 0).UTC()
+	clock = func() time.Time { return at }
+	return nil
+}
+
+// byName returns repos sorted case-insensitively, so the scan and every
+// list built in repo order don't depend on how GitHub ordered the listing.
+func byName(repos []string) []string {
+	return slices.SortedFunc(slices.Values(repos), func(a, b string) int {
+		return strings.Compare(strings.ToLower(a), strings.ToLower(b))
+	})
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"encoding/json"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestDeterministicScan(t *testing.T) {
+	prevClock, prevFlag := clock, *deterministic
+	t.Cleanup(func() { clock, *deterministic = prevClock, prevFlag })
+	*deterministic = true
+	t.Setenv("SOURCE_DATE_EPOCH", "1780000000")
+	if err := pinClock(); err != nil {
+		t.Fatal(err)
+	}
+	at := time.Unix(1780000000, 0).UTC()
+
+	gh := newFakeGitHub(t)
+	genFixture(gh, "acme", 7, 6, 10, at)
+	// Empty repos all score the same, so only the name can order them.
+	for _, name := range []string{"zeta", "alpha", "Mid"} {
+		gh.addRepo(&fakeRepo{Owner: "acme", Name: name})
+	}
+	sel := selection{Orgs: []string{"acme"}}
+	repos, err := sel.resolve(t.Context())
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := config{MinIssues: 0, StaleDays: 90, MostWanted: 3, HallOfShame: 3}
+	render := func(repos []string) (string, output) {
+		out := scan(t.Context(), sel, repos, cfg)
+		b, err := json.Marshal(out)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(b), out
+	}
+	want, out := render(repos)
+	reversed := slices.Clone(repos)
+	slices.Reverse(reversed)
+	if got, _ := render(reversed); got != want {
+		t.Errorf("report depends on listing order:\n got %s\nwant %s", got, want)
+	}
+	if out.GeneratedAt != at.Format(time.RFC3339) {
+		t.Errorf("generatedAt = %s, want the pinned clock", out.GeneratedAt)
+	}
+	var empties []string
+	for _, rs := range out.Repos {
+		if rs.TotalOpen == 0 {
+			empties = append(empties, rs.Name)
+		}
+	}
+	if !slices.Equal(empties, []string{"alpha", "Mid", "zeta"}) {
+		t.Errorf("equal scores ordered %v, want by name", empties)
+	}
+
+	t.Setenv("SOURCE_DATE_EPOCH", "yesterday")
+	if err := pinClock(); err == nil {
+		t.Error("a malformed SOURCE_DATE_EPOCH should be rejected")
+	}
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strconv"
+)
+
+var notifyDiscord = flag.String("notify-discord", "", "Discord webhook URL to post the scan summary to, with an embed per critical repo")
+
+// Discord rejects messages with more than ten embeds.
+const discordMaxEmbeds = 10
+
+const discordRed = 0xE74C3C
+
+func discordMessage(out output) map[string]any {
+	content := fmt.Sprintf("**Backlog health: %s**\n%s", out.Org, summaryLine(out))
+	embeds := []map[string]any{}
+	more := 0
+	for _, rs := range out.Repos {
+		if rs.Error != "" || rs.Status != "critical" {
+			continue
+		}
+		if len(embeds) == discordMaxEmbeds {
+			more++
+			continue
+		}
+		embed := map[string]any{
+			"title": rs.Name,
+			"color": discordRed,
+			"fields": []map[string]any{
+				{"name": "Health score", "value": strconv.Itoa(rs.HealthScore), "inline": true},
+				{"name": "Open", "value": strconv.Itoa(rs.TotalOpen), "inline": true},
+				{"name": "Stale", "value": fmt.Sprintf("%d (%.0f%%)", rs.StaleCount, rs.StalePercent), "inline": true},
+				{"name": "Unlabeled", "value": strconv.Itoa(rs.UnlabeledCount), "inline": true},
+			},
+		}
+		if rs.fullName != "" && rs.Source == "" {
+			embed["url"] = issuesURL(rs.fullName)
+		}
+		embeds = append(embeds, embed)
+	}
+	if more > 0 {
+		content += fmt.Sprintf("\n…and %d more critical repos", more)
+	}
+	return map[string]any{"content": content, "embeds": embeds}
+}
@@ -0,0 +1,30 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestDiscordMessage(t *testing.T) {
+	msg := discordMessage(sampleReport())
+	embeds := msg["embeds"].([]map[string]any)
+	if len(embeds) != 1 || embeds[0]["title"] != "neglected" {
+		t.Fatalf("want one embed for the critical repo, got %+v", embeds)
+	}
+	if !strings.Contains(msg["content"].(string), "1 critical (1 failed to scan)") {
+		t.Errorf("content = %q", msg["content"])
+	}
+
+	var out output
+	for i := 0; i < 12; i++ {
+		out.Repos = append(out.Repos, repoScore{Name: fmt.Sprint("r", i), Status: "critical"})
+	}
+	msg = discordMessage(out)
+	if n := len(msg["embeds"].([]map[string]any)); n != discordMaxEmbeds {
+		t.Errorf("embeds = %d, want %d", n, discordMaxEmbeds)
+	}
+	if !strings.Contains(msg["content"].(string), "and 2 more") {
+		t.Errorf("content should mention overflow: %q", msg["content"])
+	}
+}
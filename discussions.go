@@ -0,0 +1,127 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log/slog"
+	"time"
+
+	ghscan "github.com/misty-step/fab-backlog/pkg/scan"
+)
+
+var qaWindowDays = flag.Int("qa-window-days", 0, "measure answer rate and median time to answer over Q&A discussions created in the last N days (0 disables; --deep uses 90)")
+
+// qaMetrics covers discussions in answerable (Q&A) categories only. An
+// unanswered question is a different failure from an idle discussion, so
+// general discussion staleness isn't mixed in.
+type qaMetrics struct {
+	WindowDays          int     `json:"windowDays"`
+	Questions           int     `json:"questions"`
+	Answered            int     `json:"answered"`
+	AnsweredPercent     float64 `json:"answeredPercent"`
+	MedianHoursToAnswer int     `json:"medianHoursToAnswer"`
+}
+
+func (c config) qaWindow() int {
+	if c.QAWindowDays > 0 {
+		return c.QAWindowDays
+	}
+	if c.Deep {
+		return defaultResponseWindowDays
+	}
+	return 0
+}
+
+const qaQuery = `query($q: String!, $endCursor: String) {
+  search(query: $q, type: DISCUSSION, first: 100, after: $endCursor) {
+    pageInfo { hasNextPage endCursor }
+    nodes {
+      ... on Discussion {
+        createdAt
+        answerChosenAt
+        category { isAnswerable }
+      }
+    }
+  }
+}`
+
+// question is a discussion in a Q&A category; AnsweredAt is zero until an
+// answer is marked.
+type question struct {
+	CreatedAt  time.Time
+	AnsweredAt time.Time
+}
+
+type qaPage struct {
+	Search struct {
+		Nodes []*struct {
+			CreatedAt      time.Time  `json:"createdAt"`
+			AnswerChosenAt *time.Time `json:"answerChosenAt"`
+			Category       struct {
+				IsAnswerable bool `json:"isAnswerable"`
+			} `json:"category"`
+		} `json:"nodes"`
+	} `json:"search"`
+}
+
+func ghQuestions(ctx context.Context, owner, repo string, since time.Time) ([]question, []ghscan.GraphQLError, error) {
+	q := fmt.Sprintf("repo:%s/%s created:>=%s", owner, repo, since.Format("2006-01-02"))
+	pages, errs, err := ghscan.GraphQL[qaPage](ctx, runCmd, qaQuery, map[string]string{"q": q})
+	if err != nil {
+		return nil, errs, err
+	}
+	var qs []question
+	for _, page := range pages {
+		for _, n := range page.Search.Nodes {
+			if n == nil || !n.Category.IsAnswerable {
+				continue
+			}
+			q := question{CreatedAt: n.CreatedAt}
+			if n.AnswerChosenAt != nil {
+				q.AnsweredAt = *n.AnswerChosenAt
+			}
+			qs = append(qs, q)
+		}
+	}
+	return qs, errs, nil
+}
+
+func computeQAMetrics(qs []question, windowDays int) *qaMetrics {
+	qm := &qaMetrics{WindowDays: windowDays, Questions: len(qs)}
+	var hours []int
+	for _, q := range qs {
+		if q.AnsweredAt.IsZero() {
+			continue
+		}
+		qm.Answered++
+		h := int(q.AnsweredAt.Sub(q.CreatedAt).Hours())
+		if h < 0 {
+			h = 0
+		}
+		hours = append(hours, h)
+	}
+	if qm.Questions > 0 {
+		qm.AnsweredPercent = float64(qm.Answered) / float64(qm.Questions) * 100
+	}
+	qm.MedianHoursToAnswer = median(hours)
+	return qm
+}
+
+// addQAMetrics is a no-op for repos without Q&A activity in the window, so
+// repos that don't use discussions report nothing rather than zeros.
+func addQAMetrics(ctx context.Context, score *repoScore, owner, repo string, cfg config, now time.Time) {
+	window := cfg.qaWindow()
+	if window <= 0 {
+		return
+	}
+	qs, errs, err := ghQuestions(ctx, owner, repo, now.AddDate(0, 0, -window))
+	if err != nil {
+		slog.Warn("q&a metrics unavailable", "repo", repo, "error", err)
+		return
+	}
+	addAPIErrors(score, "qa", errs)
+	if len(qs) > 0 {
+		score.QA = computeQAMetrics(qs, window)
+	}
+}
@@ -0,0 +1,20 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestComputeQAMetrics(t *testing.T) {
+	t0 := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	qs := []question{
+		{CreatedAt: t0, AnsweredAt: t0.Add(2 * time.Hour)},
+		{CreatedAt: t0, AnsweredAt: t0.Add(10 * time.Hour)},
+		{CreatedAt: t0, AnsweredAt: t0.Add(48 * time.Hour)},
+		{CreatedAt: t0},
+	}
+	qm := computeQAMetrics(qs, 90)
+	if qm.Questions != 4 || qm.Answered != 3 || qm.AnsweredPercent != 75 || qm.MedianHoursToAnswer != 10 {
+		t.Errorf("qa = %+v", qm)
+	}
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+var duplicateThreshold = flag.Float64("duplicate-threshold", 0, "flag open issues whose titles are at least this similar (0-1) to an older one as probable duplicates (0 disables)")
+
+// duplicate pairs an issue with the older issue it most resembles.
+type duplicate struct {
+	Number      int     `json:"number"`
+	Title       string  `json:"title"`
+	DuplicateOf int     `json:"duplicateOf"`
+	OfTitle     string  `json:"ofTitle"`
+	Similarity  float64 `json:"similarity"`
+}
+
+var titleStopwords = map[string]bool{
+	"a": true, "an": true, "and": true, "are": true, "be": true, "can": true, "doesn": true, "for": true,
+	"from": true, "in": true, "is": true, "it": true, "not": true, "of": true, "on": true, "or": true,
+	"the": true, "this": true, "to": true, "when": true, "with": true,
+}
+
+// titleTokens lowercases, drops stopwords and one-character tokens, and
+// folds plurals, so "Crash when saving files" and "crash saving file" agree.
+func titleTokens(title string) []string {
+	seen := map[string]bool{}
+	var out []string
+	for _, t := range strings.FieldsFunc(strings.ToLower(title), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		if len(t) < 2 || titleStopwords[t] {
+			continue
+		}
+		if len(t) > 3 && strings.HasSuffix(t, "s") && !strings.HasSuffix(t, "ss") {
+			t = t[:len(t)-1]
+		}
+		if !seen[t] {
+			seen[t] = true
+			out = append(out, t)
+		}
+	}
+	return out
+}
+
+// tokensMatch allows one typo in longer words.
+func tokensMatch(a, b string) bool {
+	if a == b {
+		return true
+	}
+	if len(a) < 5 || len(b) < 5 {
+		return false
+	}
+	return editDistance(a, b) <= 1
+}
+
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
+
+// titleSimilarity is the Jaccard index of the token sets, with fuzzy
+// matches counted as shared.
+func titleSimilarity(a, b []string) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	shared := 0
+	for _, x := range a {
+		for _, y := range b {
+			if tokensMatch(x, y) {
+				shared++
+				break
+			}
+		}
+	}
+	return float64(shared) / float64(len(a)+len(b)-shared)
+}
+
+// findDuplicates compares every pair of issues and reports each issue whose
+// title is at least threshold similar to an older one, most similar first.
+// Titles with fewer than two meaningful words are too vague to compare.
+func findDuplicates(issues []issue, threshold float64) []duplicate {
+	if threshold <= 0 {
+		return nil
+	}
+	sorted := append([]issue(nil), issues...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if !sorted[i].CreatedAt.Equal(sorted[j].CreatedAt) {
+			return sorted[i].CreatedAt.Before(sorted[j].CreatedAt)
+		}
+		return sorted[i].Number < sorted[j].Number
+	})
+	tokens := make([][]string, len(sorted))
+	for i, is := range sorted {
+		tokens[i] = titleTokens(is.Title)
+	}
+	var dups []duplicate
+	for i := range sorted {
+		if len(tokens[i]) < 2 {
+			continue
+		}
+		best, bestSim := -1, 0.0
+		for j := 0; j < i; j++ {
+			if len(tokens[j]) < 2 {
+				continue
+			}
+			if sim := titleSimilarity(tokens[i], tokens[j]); sim >= threshold && sim > bestSim {
+				best, bestSim = j, sim
+			}
+		}
+		if best >= 0 {
+			dups = append(dups, duplicate{
+				Number: sorted[i].Number, Title: sorted[i].Title,
+				DuplicateOf: sorted[best].Number, OfTitle: sorted[best].Title,
+				Similarity: math.Round(bestSim*100) / 100,
+			})
+		}
+	}
+	sort.SliceStable(dups, func(i, j int) bool { return dups[i].Similarity > dups[j].Similarity })
+	return dups
+}
+
+func validateDuplicateThreshold(t float64) error {
+	if t < 0 || t > 1 {
+		return fmt.Errorf("duplicateThreshold must be between 0 and 1")
+	}
+	return nil
+}
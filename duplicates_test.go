@@ -0,0 +1,48 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFindDuplicates(t *testing.T) {
+	t0 := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	issues := []issue{
+		{Number: 7, Title: "crash saving file", CreatedAt: t0.Add(3 * time.Hour)},
+		{Number: 3, Title: "App crashes when saving files", CreatedAt: t0},
+		{Number: 5, Title: "Add dark mode", CreatedAt: t0.Add(time.Hour)},
+		{Number: 9, Title: "Crash when savng files", CreatedAt: t0.Add(4 * time.Hour)}, // typo
+		{Number: 11, Title: "crash", CreatedAt: t0.Add(5 * time.Hour)},
+	}
+	dups := findDuplicates(issues, 0.6)
+	if len(dups) != 2 {
+		t.Fatalf("dups = %+v", dups)
+	}
+	for _, d := range dups {
+		if d.Number != 7 && d.Number != 9 {
+			t.Errorf("unexpected %+v", d)
+		}
+		if d.DuplicateOf == 5 || d.Similarity < 0.6 {
+			t.Errorf("bad match %+v", d)
+		}
+	}
+	if dups[0].Similarity < dups[1].Similarity {
+		t.Errorf("not sorted by similarity: %+v", dups)
+	}
+	if got := findDuplicates(issues, 0); got != nil {
+		t.Errorf("disabled = %+v", got)
+	}
+}
+
+func TestTitleTokens(t *testing.T) {
+	got := titleTokens("The Tests don't pass on CI, again: tests")
+	want := []string{"test", "don", "pass", "ci", "again"}
+	if len(got) != len(want) {
+		t.Fatalf("tokens = %v", got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("tokens = %v, want %v", got, want)
+		}
+	}
+}
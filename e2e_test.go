@@ -0,0 +1,100 @@
+package main
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestEndToEnd runs scan, report, plan, dry-run, apply and verification
+// against the fake GitHub.
+func TestEndToEnd(t *testing.T) {
+	gh := newFakeGitHub(t)
+	now := time.Now()
+	genFixture(gh, "acme", 1, 6, 10, now)
+	stale := now.AddDate(-1, 0, 0)
+	gh.addRepo(&fakeRepo{Owner: "acme", Name: "rotting", Issues: []*fakeIssue{
+		{Number: 1, Title: "blocked upstream", Body: "Waiting on #99.", State: "open", CreatedAt: stale, UpdatedAt: stale, Labels: []string{"bug"}},
+		{Number: 2, Title: "follow-up", Body: "See #3.", State: "open", CreatedAt: stale, UpdatedAt: stale},
+		{Number: 3, Title: "fresh", State: "open", CreatedAt: stale, UpdatedAt: now, Labels: []string{"bug"}},
+		{Number: 4, Title: "a pull request", State: "open", CreatedAt: stale, UpdatedAt: stale, IsPR: true},
+	}})
+	gh.addRepo(&fakeRepo{Owner: "acme", Name: "retired", Archived: true})
+
+	sel := selection{Orgs: []string{"acme"}}
+	repos, err := sel.resolve(t.Context())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(repos) != 7 || slices.Contains(repos, "acme/retired") {
+		t.Fatalf("repos = %v, want 7 unarchived", repos)
+	}
+
+	cfg := config{MinIssues: 5, StaleDays: 90, LinkCheck: true, LabelLatencyWindowDays: 30}
+	out := scan(t.Context(), sel, repos, cfg)
+	if out.Summary.Total != 7 {
+		t.Fatalf("summary = %+v", out.Summary)
+	}
+	var rotting repoScore
+	for _, rs := range out.Repos {
+		if rs.Error != "" {
+			t.Fatalf("%s: %s", rs.Name, rs.Error)
+		}
+		want := scoreIssues(rs.Name, gh.openIssues(rs.fullName), cfg, time.Now())
+		if rs.HealthScore != want.HealthScore || rs.StaleCount != want.StaleCount || rs.UnlabeledCount != want.UnlabeledCount {
+			t.Errorf("%s: got score %d (stale %d, unlabeled %d), want %d (%d, %d)", rs.Name, rs.HealthScore, rs.StaleCount, rs.UnlabeledCount, want.HealthScore, want.StaleCount, want.UnlabeledCount)
+		}
+		if rs.Name == "rotting" {
+			rotting = rs
+		}
+	}
+	if rotting.TotalOpen != 3 {
+		t.Errorf("rotting: totalOpen = %d, want PRs excluded", rotting.TotalOpen)
+	}
+	if len(rotting.TriageQueue) != 1 || rotting.TriageQueue[0].Number != 1 {
+		t.Fatalf("rotting triage = %+v, want only #1 (dead #99)", rotting.TriageQueue)
+	}
+
+	md, err := renderMarkdown(out)
+	if err != nil || !strings.Contains(md, "| rotting |") {
+		t.Errorf("report missing rotting repo (err %v):\n%s", err, md)
+	}
+
+	p := buildPlan(out, cfg, now)
+	if len(p.Actions) != 1 || p.Actions[0].ID != "acme/rotting#1:close" {
+		t.Fatalf("plan = %+v", p.Actions)
+	}
+	if results, _ := applyPlan(p, true, runBackground, now); results[0].Status != "planned" || len(gh.writeLog()) != 0 {
+		t.Fatalf("dry run wrote %v", gh.writeLog())
+	}
+	results, exps := applyPlan(p, false, runBackground, now)
+	if results[0].Status != "applied" || gh.issue("acme/rotting", 1).State != "closed" {
+		t.Fatalf("apply: %+v, writes %v", results, gh.writeLog())
+	}
+
+	again := scan(t.Context(), sel, []string{"acme/rotting"}, cfg)
+	rep, pending := verifyRemediations(exps, again.Repos)
+	if rep == nil || rep.Held != 1 || len(pending) != 0 {
+		t.Errorf("verification = %+v, pending %v", rep, pending)
+	}
+	if again.Repos[0].TotalOpen != 2 {
+		t.Errorf("rescan totalOpen = %d", again.Repos[0].TotalOpen)
+	}
+}
+
+func TestFakeGitHubPagination(t *testing.T) {
+	gh := newFakeGitHub(t)
+	genFixture(gh, "big", 7, 1, 1, time.Now())
+	r := gh.repos["big/repo-00"]
+	for n := 2; n <= 250; n++ {
+		r.Issues = append(r.Issues, &fakeIssue{Number: n, State: "open", CreatedAt: time.Now(), UpdatedAt: time.Now()})
+	}
+	issues, err := ghListIssues(t.Context(), "big", "repo-00", "number", 1000)
+	if err != nil || len(issues) != 250 {
+		t.Fatalf("got %d issues, err %v", len(issues), err)
+	}
+	if _, err := ghListIssues(t.Context(), "big", "missing", "number", 10); err == nil || !strings.Contains(err.Error(), "HTTP 404") {
+		t.Errorf("missing repo: err = %v", err)
+	}
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+)
+
+var triageEffortFlag = flag.Bool("triage-effort", false, "estimate the minutes of triage each repo's backlog needs (per-item costs from -config triageEffort)")
+
+// triageCosts are the minutes one item of each kind takes to triage. An
+// issue that is unlabeled, unanswered and stale costs all three: labeling,
+// replying and deciding whether it's still wanted are separate jobs.
+type triageCosts struct {
+	UnlabeledMinutes  int `json:"unlabeledMinutes,omitempty"`
+	NoResponseMinutes int `json:"noResponseMinutes,omitempty"`
+	StaleMinutes      int `json:"staleMinutes,omitempty"`
+}
+
+// defaultTriageCosts fill in any cost the config leaves at zero.
+var defaultTriageCosts = triageCosts{UnlabeledMinutes: 2, NoResponseMinutes: 5, StaleMinutes: 3}
+
+func (c triageCosts) withDefaults() triageCosts {
+	if c.UnlabeledMinutes == 0 {
+		c.UnlabeledMinutes = defaultTriageCosts.UnlabeledMinutes
+	}
+	if c.NoResponseMinutes == 0 {
+		c.NoResponseMinutes = defaultTriageCosts.NoResponseMinutes
+	}
+	if c.StaleMinutes == 0 {
+		c.StaleMinutes = defaultTriageCosts.StaleMinutes
+	}
+	return c
+}
+
+func validateTriageCosts(c *triageCosts) error {
+	if c != nil && (c.UnlabeledMinutes < 0 || c.NoResponseMinutes < 0 || c.StaleMinutes < 0) {
+		return fmt.Errorf("triageEffort: minutes must not be negative")
+	}
+	return nil
+}
+
+// triageEffort is the estimated time to work through a repo's backlog.
+type triageEffort struct {
+	Minutes    int `json:"minutes"`
+	Unlabeled  int `json:"unlabeled"`
+	NoResponse int `json:"noResponse"`
+	Stale      int `json:"stale"`
+}
+
+// estimateTriageEffort prices a scored repo's backlog. Unlabeled and stale
+// counts come from the score; noResponse counts open issues no maintainer
+// other than the author has commented on.
+func estimateTriageEffort(rs repoScore, issues []issue, costs triageCosts) *triageEffort {
+	costs = costs.withDefaults()
+	te := &triageEffort{Unlabeled: rs.UnlabeledCount, Stale: rs.StaleCount}
+	for _, is := range issues {
+		if firstResponseAt(is).IsZero() {
+			te.NoResponse++
+		}
+	}
+	te.Minutes = te.Unlabeled*costs.UnlabeledMinutes + te.NoResponse*costs.NoResponseMinutes + te.Stale*costs.StaleMinutes
+	return te
+}
+
+// formatMinutes renders a duration in minutes as "45m", "2h" or "3h 20m".
+func formatMinutes(m int) string {
+	switch h := m / 60; {
+	case h == 0:
+		return fmt.Sprintf("%dm", m)
+	case m%60 == 0:
+		return fmt.Sprintf("%dh", h)
+	default:
+		return fmt.Sprintf("%dh %dm", h, m%60)
+	}
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEstimateTriageEffort(t *testing.T) {
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	old := now.AddDate(0, 0, -200)
+	reply := comment{Author: actor{Login: "maint"}, AuthorAssociation: "MEMBER", CreatedAt: old}
+	issues := []issue{
+		// Unlabeled, unanswered and stale: every cost applies.
+		{Number: 1, Author: actor{Login: "u"}, CreatedAt: old, UpdatedAt: old},
+		{Number: 2, Author: actor{Login: "u"}, CreatedAt: old, UpdatedAt: now, Labels: []label{{Name: "bug"}}, Comments: []comment{reply}},
+		// The author's own follow-up is not a response.
+		{Number: 3, Author: actor{Login: "u"}, CreatedAt: old, UpdatedAt: now, Labels: []label{{Name: "bug"}}, Comments: []comment{{Author: actor{Login: "u"}, AuthorAssociation: "MEMBER", CreatedAt: now}}},
+	}
+	cfg := config{MinIssues: 1, StaleDays: 90, TriageEffort: &triageCosts{StaleMinutes: 10}}
+	rs := scoreIssues("api", issues, cfg, now)
+	te := estimateTriageEffort(rs, issues, *cfg.TriageEffort)
+	// Defaults fill the unset costs: 1×2 unlabeled + 2×5 unanswered + 1×10 stale.
+	if te.Unlabeled != 1 || te.NoResponse != 2 || te.Stale != 1 || te.Minutes != 22 {
+		t.Errorf("effort = %+v", te)
+	}
+	if err := validateTriageCosts(&triageCosts{StaleMinutes: -1}); err == nil {
+		t.Error("negative costs should be rejected")
+	}
+}
+
+func TestTriageEffortReported(t *testing.T) {
+	out := sampleReport()
+	out.Config.TriageEffort = &triageCosts{}
+	out.Repos[0].TriageEffort = &triageEffort{Minutes: 200}
+	out.Repos[1].TriageEffort = &triageEffort{Minutes: 45}
+	out.Summary = summarize(out.Repos)
+	if out.Summary.TriageMinutes != 245 {
+		t.Fatalf("summary = %+v", out.Summary)
+	}
+	md, err := renderMarkdown(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"est. triage 4h 5m", "| Triage |", "| neglected | 35 | critical | 0 | 0 | 0 | 3h 20m |", "| fine | 100 | healthy | 0 | 0 | 0 |  |"} {
+		if !strings.Contains(md, want) {
+			t.Errorf("missing %q:\n%s", want, md)
+		}
+	}
+	if got := formatMinutes(120) + " " + formatMinutes(7); got != "2h 7m" {
+		t.Errorf("formatMinutes = %q", got)
+	}
+}
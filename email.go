@@ -0,0 +1,116 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"log/slog"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/smtp"
+	"net/textproto"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var emailTo = flag.String("email-to", "", "comma-separated addresses to email the report digest to (SMTP settings from -config)")
+
+// smtpConfig is the mail relay used for -email-to. The password comes from
+// $FAB_BACKLOG_SMTP_PASSWORD so it stays out of config files.
+type smtpConfig struct {
+	Host     string `json:"host"`
+	Port     int    `json:"port,omitempty"`
+	Username string `json:"username,omitempty"`
+	From     string `json:"from"`
+}
+
+func (s *smtpConfig) validate() error {
+	if s.Host == "" || s.From == "" {
+		return fmt.Errorf("smtp: host and from required")
+	}
+	return nil
+}
+
+func (s *smtpConfig) addr() string {
+	port := s.Port
+	if port == 0 {
+		port = 587
+	}
+	return net.JoinHostPort(s.Host, strconv.Itoa(port))
+}
+
+// smtpSend is swapped out in tests.
+var smtpSend = smtp.SendMail
+
+func digestSubject(out output) string {
+	s := out.Summary
+	return fmt.Sprintf("Backlog health: %s — %d critical, %d warning", out.Org, s.Critical, s.Warning)
+}
+
+// buildEmail renders a multipart/alternative message with the Markdown
+// report as the plain-text part and the HTML report as the rich part.
+func buildEmail(from string, to []string, subject, text, html string, now time.Time) ([]byte, error) {
+	var b bytes.Buffer
+	mw := multipart.NewWriter(&b)
+	fmt.Fprintf(&b, "From: %s\r\nTo: %s\r\nSubject: %s\r\nDate: %s\r\nMIME-Version: 1.0\r\nContent-Type: multipart/alternative; boundary=%s\r\n\r\n",
+		from, strings.Join(to, ", "), mime.QEncoding.Encode("utf-8", subject), now.Format(time.RFC1123Z), mw.Boundary())
+	for _, part := range []struct{ contentType, body string }{
+		{"text/plain; charset=utf-8", text},
+		{"text/html; charset=utf-8", html},
+	} {
+		w, err := mw.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, err
+		}
+		qp := quotedprintable.NewWriter(w)
+		if _, err := qp.Write([]byte(part.body)); err != nil {
+			return nil, err
+		}
+		if err := qp.Close(); err != nil {
+			return nil, err
+		}
+	}
+	if err := mw.Close(); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+func sendDigest(s *smtpConfig, to []string, out output, now time.Time) error {
+	text, err := renderMarkdown(out)
+	if err != nil {
+		return err
+	}
+	html, err := renderHTML(out)
+	if err != nil {
+		return err
+	}
+	msg, err := buildEmail(s.From, to, digestSubject(out), text, html, now)
+	if err != nil {
+		return err
+	}
+	var auth smtp.Auth
+	if s.Username != "" {
+		auth = smtp.PlainAuth("", s.Username, os.Getenv("FAB_BACKLOG_SMTP_PASSWORD"), s.Host)
+	}
+	return smtpSend(s.addr(), auth, s.From, to, msg)
+}
+
+func emailDigest(out output, cfg config) {
+	to := splitList(*emailTo)
+	if len(to) == 0 {
+		return
+	}
+	if err := sendDigest(cfg.SMTP, to, out, time.Now()); err != nil {
+		slog.Error("email digest failed", "error", err)
+	} else {
+		slog.Info("email digest sent", "to", len(to))
+	}
+}
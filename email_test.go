@@ -0,0 +1,61 @@
+package main
+
+import (
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/mail"
+	"net/smtp"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSendDigest(t *testing.T) {
+	var gotAddr string
+	var gotTo []string
+	var gotMsg []byte
+	smtpSend = func(addr string, a smtp.Auth, from string, to []string, msg []byte) error {
+		gotAddr, gotTo, gotMsg = addr, to, msg
+		return nil
+	}
+	defer func() { smtpSend = smtp.SendMail }()
+
+	s := &smtpConfig{Host: "mail.example.com", From: "backlog@example.com"}
+	if err := sendDigest(s, []string{"a@example.com", "b@example.com"}, sampleReport(), time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	if gotAddr != "mail.example.com:587" || len(gotTo) != 2 {
+		t.Errorf("addr = %q, to = %v", gotAddr, gotTo)
+	}
+
+	m, err := mail.ReadMessage(strings.NewReader(string(gotMsg)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	subject, _ := new(mime.WordDecoder).DecodeHeader(m.Header.Get("Subject"))
+	if subject != "Backlog health: misty-step — 1 critical, 1 warning" {
+		t.Errorf("subject = %q", subject)
+	}
+	_, params, _ := mime.ParseMediaType(m.Header.Get("Content-Type"))
+	mr := multipart.NewReader(m.Body, params["boundary"])
+	var types, bodies []string
+	for {
+		p, err := mr.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, _ := io.ReadAll(p) // multipart decodes quoted-printable
+		types = append(types, p.Header.Get("Content-Type"))
+		bodies = append(bodies, string(body))
+	}
+	if len(types) != 2 || !strings.HasPrefix(types[0], "text/plain") || !strings.HasPrefix(types[1], "text/html") {
+		t.Fatalf("parts = %v", types)
+	}
+	if !strings.Contains(bodies[0], "| neglected | 35 | critical |") || !strings.Contains(bodies[1], "<td>neglected</td>") {
+		t.Errorf("bodies = %q", bodies)
+	}
+}
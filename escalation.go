@@ -0,0 +1,96 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+)
+
+// escalationStep is one rung of the ladder: repos critical for at least Runs
+// consecutive runs are reported to the step's destination, which is exactly
+// one of Slack, Teams or Webhook. Lower rungs keep hearing about a repo after
+// it escalates, so the owning team is never dropped from the loop.
+type escalationStep struct {
+	Runs    int    `json:"runs"`
+	Name    string `json:"name"`
+	Slack   string `json:"slack,omitempty"`
+	Teams   string `json:"teams,omitempty"`
+	Webhook string `json:"webhook,omitempty"`
+}
+
+func (s escalationStep) validate() error {
+	if s.Runs <= 0 {
+		return fmt.Errorf("escalation %q: runs must be positive", s.Name)
+	}
+	n := 0
+	for _, u := range []string{s.Slack, s.Teams, s.Webhook} {
+		if u != "" {
+			n++
+		}
+	}
+	if n != 1 {
+		return fmt.Errorf("escalation %q: set exactly one of slack, teams or webhook", s.Name)
+	}
+	return nil
+}
+
+// escalatedRepos returns the repos that have reached step, in report order.
+func escalatedRepos(out output, step escalationStep) []repoScore {
+	var hit []repoScore
+	for _, rs := range out.Repos {
+		if rs.CriticalRuns >= step.Runs && rs.Exemption == nil {
+			hit = append(hit, rs)
+		}
+	}
+	return hit
+}
+
+func escalationLines(step escalationStep, repos []repoScore) (title string, lines []string) {
+	title = fmt.Sprintf("Escalation: %s", step.Name)
+	lines = append(lines, fmt.Sprintf("%d repos critical for %d+ consecutive runs", len(repos), step.Runs))
+	for _, rs := range repos {
+		lines = append(lines, fmt.Sprintf("%s — %d, critical for %d runs", rs.fullName, rs.HealthScore, rs.CriticalRuns))
+	}
+	return title, lines
+}
+
+func escalationMessage(step escalationStep, repos []repoScore) (url string, msg any) {
+	title, lines := escalationLines(step, repos)
+	switch {
+	case step.Slack != "":
+		return step.Slack, map[string]any{"text": "*" + title + "*\n" + lines[0] + "\n• " + strings.Join(lines[1:], "\n• ")}
+	case step.Teams != "":
+		body := []map[string]any{{"type": "TextBlock", "text": title, "weight": "Bolder", "size": "Medium"}}
+		for _, l := range lines {
+			body = append(body, map[string]any{"type": "TextBlock", "text": l, "wrap": true, "spacing": "None"})
+		}
+		return step.Teams, adaptiveCard(body)
+	default:
+		type entry struct {
+			Repo         string `json:"repo"`
+			HealthScore  int    `json:"healthScore"`
+			CriticalRuns int    `json:"criticalRuns"`
+		}
+		entries := make([]entry, 0, len(repos))
+		for _, rs := range repos {
+			entries = append(entries, entry{rs.fullName, rs.HealthScore, rs.CriticalRuns})
+		}
+		return step.Webhook, map[string]any{"escalation": step.Name, "runs": step.Runs, "repos": entries}
+	}
+}
+
+// escalate sends each step its repos. Steps with nothing to report stay quiet.
+func escalate(out output, steps []escalationStep) {
+	for _, step := range steps {
+		repos := escalatedRepos(out, step)
+		if len(repos) == 0 {
+			continue
+		}
+		url, msg := escalationMessage(step, repos)
+		if err := postJSON(url, msg, nil); err != nil {
+			slog.Error("escalation failed", "step", step.Name, "error", err)
+		} else {
+			slog.Info("escalation sent", "step", step.Name, "repos", len(repos))
+		}
+	}
+}
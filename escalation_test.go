@@ -0,0 +1,60 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestEscalate(t *testing.T) {
+	got := map[string]string{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var m map[string]any
+		_ = json.NewDecoder(r.Body).Decode(&m)
+		if text, ok := m["text"].(string); ok {
+			got[r.URL.Path] = text
+		}
+	}))
+	defer srv.Close()
+
+	out := output{Repos: []repoScore{
+		{fullName: "org/a", HealthScore: 20, CriticalRuns: 6},
+		{fullName: "org/b", HealthScore: 30, CriticalRuns: 3},
+		{fullName: "org/c", HealthScore: 35, CriticalRuns: 1},
+		{fullName: "org/d", HealthScore: 90},
+	}}
+	escalate(out, []escalationStep{
+		{Runs: 1, Name: "team", Slack: srv.URL + "/team"},
+		{Runs: 3, Name: "managers", Slack: srv.URL + "/em"},
+		{Runs: 6, Name: "directors", Slack: srv.URL + "/dir"},
+		{Runs: 10, Name: "vp", Slack: srv.URL + "/vp"},
+	})
+	for path, want := range map[string][]string{
+		"/team": {"3 repos critical for 1+", "org/a", "org/b", "org/c"},
+		"/em":   {"2 repos critical for 3+", "org/b — 30, critical for 3 runs"},
+		"/dir":  {"*Escalation: directors*", "org/a — 20, critical for 6 runs"},
+	} {
+		for _, w := range want {
+			if !strings.Contains(got[path], w) {
+				t.Errorf("%s: missing %q in %q", path, w, got[path])
+			}
+		}
+	}
+	if strings.Contains(got["/dir"], "org/b") {
+		t.Errorf("directors should only see repos at their rung: %q", got["/dir"])
+	}
+	if _, ok := got["/vp"]; ok {
+		t.Error("empty rung should not be notified")
+	}
+}
+
+func TestEscalationStepValidate(t *testing.T) {
+	if err := (escalationStep{Runs: 1, Name: "x"}).validate(); err == nil {
+		t.Error("step without destination should be rejected")
+	}
+	if err := (escalationStep{Runs: 0, Name: "x", Slack: "u"}).validate(); err == nil {
+		t.Error("step with zero runs should be rejected")
+	}
+}
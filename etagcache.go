@@ -0,0 +1,25 @@
+package main
+
+import (
+	"flag"
+
+	ghscan "github.com/misty-step/fab-backlog/pkg/scan"
+)
+
+var etagCacheDir = flag.String("etag-cache", "", "cache GitHub responses with their ETags in this directory and revalidate them with conditional requests, so repos whose issues haven't changed cost no rate limit")
+
+// etagCache is the -etag-cache layer; nil when caching is off.
+var etagCache *ghscan.ETagCache
+
+// withETagCache wraps run in the -etag-cache cache, if one was asked for.
+// focus goes without: it promises a fresh read of the repos it names.
+func withETagCache(run ghscan.Runner, cmd string) (ghscan.Runner, error) {
+	if *etagCacheDir == "" || cmd == "focus" {
+		return run, nil
+	}
+	var err error
+	if etagCache, err = ghscan.NewETagCache(*etagCacheDir); err != nil {
+		return nil, err
+	}
+	return etagCache.Wrap(run), nil
+}
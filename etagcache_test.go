@@ -0,0 +1,68 @@
+package main
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestETagCacheSkipsUnchangedRepos(t *testing.T) {
+	gh := newFakeGitHub(t)
+	now := time.Now()
+	genFixture(gh, "acme", 5, 3, 6, now)
+	prevDir, prevCache := *etagCacheDir, etagCache
+	*etagCacheDir = t.TempDir()
+	t.Cleanup(func() { *etagCacheDir, etagCache = prevDir, prevCache })
+
+	fake := runCmd
+	repos := []string{"acme/repo-00", "acme/repo-01", "acme/repo-02"}
+	cfg := config{MinIssues: 1, StaleDays: 90}
+	scanOnce := func() output {
+		// Each run opens the cache afresh, as separate invocations would.
+		run, err := withETagCache(fake, "scan")
+		if err != nil {
+			t.Fatal(err)
+		}
+		runCmd = run
+		return scan(t.Context(), selection{Orgs: []string{"acme"}}, repos, cfg)
+	}
+
+	first := scanOnce()
+	if etagCache.Hits() != 0 {
+		t.Fatalf("cold cache hit %d times", etagCache.Hits())
+	}
+	second := scanOnce()
+	if etagCache.Hits() != len(repos) || etagCache.Misses() != 0 {
+		t.Errorf("warm cache: %d hits, %d misses; want every repo served from cache", etagCache.Hits(), etagCache.Misses())
+	}
+	if contentHash(first) != contentHash(second) {
+		t.Error("cached scan differs from the fetched one")
+	}
+
+	// A new issue in repo-01 changes its latest issue, so only it is
+	// refetched.
+	gh.mu.Lock()
+	gh.repos["acme/repo-01"].Issues = append(gh.repos["acme/repo-01"].Issues, &fakeIssue{Number: 99, Title: "new", State: "open", CreatedAt: now, UpdatedAt: now, Author: "user1"})
+	gh.mu.Unlock()
+	third := scanOnce()
+	if etagCache.Hits() != len(repos)-1 || etagCache.Misses() != 1 {
+		t.Errorf("after a change: %d hits, %d misses", etagCache.Hits(), etagCache.Misses())
+	}
+	open := func(out output) int {
+		i := slices.IndexFunc(out.Repos, func(rs repoScore) bool { return rs.Name == "repo-01" })
+		return out.Repos[i].TotalOpen
+	}
+	if open(third) != open(second)+1 {
+		t.Errorf("repo-01 open = %d, was %d; the new issue was missed", open(third), open(second))
+	}
+}
+
+func TestFocusSkipsETagCache(t *testing.T) {
+	prevDir, prevCache := *etagCacheDir, etagCache
+	*etagCacheDir = t.TempDir()
+	t.Cleanup(func() { *etagCacheDir, etagCache = prevDir, prevCache })
+	etagCache = nil
+	if _, err := withETagCache(runCmd, "focus"); err != nil || etagCache != nil {
+		t.Errorf("focus opened the ETag cache: %v", err)
+	}
+}
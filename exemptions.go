@@ -0,0 +1,136 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"time"
+)
+
+var exemptionsURL = flag.String("exemptions-url", "", "central exemptions service to fetch at scan time, merged with the config's exemptions (bearer token from $FAB_BACKLOG_EXEMPTIONS_TOKEN)")
+
+// exemption waives enforcement for matching repos until Expires: they are
+// still scored and reported, but don't count towards the gate and are
+// never escalated or paged. Repo is owner/name and may be a glob.
+type exemption struct {
+	Repo    repoPattern `json:"repo"`
+	Reason  string      `json:"reason"`
+	Expires string      `json:"expires,omitempty"` // YYYY-MM-DD, inclusive; empty never expires
+	Source  string      `json:"source,omitempty"`
+}
+
+func (e exemption) validate() error {
+	if err := e.Repo.validate(); err != nil {
+		return fmt.Errorf("exemption: %w", err)
+	}
+	if e.Reason == "" {
+		return fmt.Errorf("exemption %q: reason required", e.Repo)
+	}
+	if e.Expires != "" {
+		if _, err := time.Parse("2006-01-02", e.Expires); err != nil {
+			return fmt.Errorf("exemption %q: expires: %w", e.Repo, err)
+		}
+	}
+	return nil
+}
+
+func (e exemption) activeAt(now time.Time) bool {
+	if e.Expires == "" {
+		return true
+	}
+	end, _ := time.Parse("2006-01-02", e.Expires)
+	return now.Before(end.AddDate(0, 0, 1))
+}
+
+// fetchExemptions GETs the central list. The service answers with a JSON
+// array of exemptions or an object holding one under "exemptions".
+func fetchExemptions(url, token string) ([]exemption, error) {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := notifyClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GET %s: %s", req.URL.Host, resp.Status)
+	}
+	var list []exemption
+	if body = bytes.TrimSpace(body); len(body) > 0 && body[0] == '{' {
+		var wrapped struct {
+			Exemptions []exemption `json:"exemptions"`
+		}
+		err = json.Unmarshal(body, &wrapped)
+		list = wrapped.Exemptions
+	} else {
+		err = json.Unmarshal(body, &list)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("parse exemptions from %s: %w", req.URL.Host, err)
+	}
+	for i := range list {
+		if err := list[i].validate(); err != nil {
+			return nil, fmt.Errorf("exemptions from %s: %w", req.URL.Host, err)
+		}
+		list[i].Source = req.URL.Host
+	}
+	return list, nil
+}
+
+// activeExemptions merges the configured exemptions with the central ones
+// and drops those expired by now. A central fetch error still returns the
+// configured exemptions.
+func activeExemptions(local []exemption, url string, now time.Time) ([]exemption, error) {
+	all := append([]exemption(nil), local...)
+	var err error
+	if url != "" {
+		var central []exemption
+		if central, err = fetchExemptions(url, os.Getenv("FAB_BACKLOG_EXEMPTIONS_TOKEN")); err == nil {
+			all = append(all, central...)
+		}
+	}
+	active := all[:0]
+	for _, e := range all {
+		if e.activeAt(now) {
+			active = append(active, e)
+		}
+	}
+	return active, err
+}
+
+// markExempt attaches to each repo the first exemption matching it.
+func markExempt(repos []repoScore, exs []exemption) {
+	for i := range repos {
+		for _, e := range exs {
+			if e.Repo.matches(repos[i].fullName) {
+				repos[i].Exemption = &e
+				break
+			}
+		}
+	}
+}
+
+// enforced returns the repos without an exemption.
+func enforced(repos []repoScore) []repoScore {
+	var out []repoScore
+	for _, rs := range repos {
+		if rs.Exemption == nil {
+			out = append(out, rs)
+		}
+	}
+	return out
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestActiveExemptions(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer s3cret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(`{"exemptions": [
+			{"repo": "acme/legacy-*", "reason": "sunset Q3", "expires": "2025-09-30"},
+			{"repo": "acme/old", "reason": "gone", "expires": "2025-01-01"}
+		]}`))
+	}))
+	defer srv.Close()
+	t.Setenv("FAB_BACKLOG_EXEMPTIONS_TOKEN", "s3cret")
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	local := []exemption{{Repo: "acme/api", Reason: "migration"}}
+	exs, err := activeExemptions(local, srv.URL, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(exs) != 2 || exs[1].Source == "" {
+		t.Fatalf("exemptions = %+v, want local plus the unexpired central one", exs)
+	}
+	repos := []repoScore{
+		{Name: "legacy-ui", Status: "critical", fullName: "acme/legacy-ui"},
+		{Name: "api", Status: "critical", fullName: "Acme/API"},
+		{Name: "web", Status: "critical", fullName: "acme/web"},
+	}
+	markExempt(repos, exs)
+	if repos[0].Exemption == nil || repos[1].Exemption == nil || repos[2].Exemption != nil {
+		t.Errorf("marked = %+v", repos)
+	}
+	if g := evaluateGate(summarize(enforced(repos)), "", 0); g.Passed || g.Reasons[0] != "1 repos critical, max 0" {
+		t.Errorf("gate = %+v, want only the unexempt repo counted", g)
+	}
+
+	t.Setenv("FAB_BACKLOG_EXEMPTIONS_TOKEN", "")
+	if exs, err := activeExemptions(local, srv.URL, now); err == nil || len(exs) != 1 {
+		t.Errorf("failed fetch: %+v, %v; want the local exemptions and an error", exs, err)
+	}
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"strings"
+)
+
+var ignoreFile = flag.String("ignore-file", ".fabignore", "file listing repos to skip, one glob per line (owner/name or name)")
+
+// loadIgnoreFile reads repo patterns from path: one per line, blank lines
+// and # comments skipped. The default file is optional; one named with
+// -ignore-file must exist.
+func loadIgnoreFile(p string, required bool) ([]string, error) {
+	f, err := os.Open(p)
+	if errors.Is(err, fs.ErrNotExist) && !required {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read ignore file: %w", err)
+	}
+	defer f.Close()
+	var patterns []string
+	sc := bufio.NewScanner(f)
+	for n := 1; sc.Scan(); n++ {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if _, err := path.Match(strings.ToLower(line), ""); err != nil {
+			return nil, fmt.Errorf("%s line %d: bad pattern %q", p, n, line)
+		}
+		patterns = append(patterns, line)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("read ignore file: %w", err)
+	}
+	return patterns, nil
+}
+
+// ignoredRepo reports whether owner/name matches any pattern. Patterns
+// with a slash match the full name, others the bare name under any owner;
+// both case-insensitively, as GitHub names are.
+func ignoredRepo(full string, patterns []string) bool {
+	full = strings.ToLower(full)
+	_, name := splitRepo("", full)
+	for _, p := range patterns {
+		p = strings.ToLower(p)
+		target := name
+		if strings.Contains(p, "/") {
+			target = full
+		}
+		if ok, _ := path.Match(p, target); ok {
+			return true
+		}
+	}
+	return false
+}
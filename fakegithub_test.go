@@ -0,0 +1,766 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeGitHub is an in-memory GitHub serving the REST and GraphQL subset the
+// scanner uses. Tests point runCmd at its gh method, which plays the part of the gh
+// CLI against it, so the whole pipeline runs without network or credentials.
+type fakeGitHub struct {
+	t   *testing.T
+	srv *httptest.Server
+
+	mu     sync.Mutex
+	repos  map[string]*fakeRepo // "owner/name"
+	writes []string             // "METHOD path" of every mutating request
+}
+
+type fakeRepo struct {
+	Owner, Name         string
+	Archived            bool
+	Private             bool
+	Topics              []string
+	PushedAt, UpdatedAt time.Time
+	Labels              []string // defined labels; those on issues exist too
+	Issues              []*fakeIssue
+	Files               map[string]string // default-branch contents by path
+}
+
+type fakeIssue struct {
+	Number    int
+	Title     string
+	Body      string
+	State     string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	Labels    []string
+	Author    string
+	IsPR      bool
+}
+
+// newFakeGitHub starts the server and swaps runCmd for the duration of t.
+func newFakeGitHub(t *testing.T) *fakeGitHub {
+	f := &fakeGitHub{t: t, repos: map[string]*fakeRepo{}}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /orgs/{org}/repos", f.listRepos)
+	mux.HandleFunc("GET /repos/{owner}/{repo}", f.getRepo)
+	mux.HandleFunc("GET /repos/{owner}/{repo}/issues", f.listIssues)
+	mux.HandleFunc("GET /repos/{owner}/{repo}/issues/{number}", f.getIssue)
+	mux.HandleFunc("PATCH /repos/{owner}/{repo}/issues/{number}", f.patchIssue)
+	mux.HandleFunc("POST /repos/{owner}/{repo}/issues/{number}/labels", f.addLabels)
+	mux.HandleFunc("DELETE /repos/{owner}/{repo}/issues/{number}/labels/{label}", f.removeLabel)
+	mux.HandleFunc("POST /repos/{owner}/{repo}/issues/{number}/comments", f.addComment)
+	mux.HandleFunc("GET /repos/{owner}/{repo}/labels/{name}", f.getLabel)
+	mux.HandleFunc("GET /repos/{owner}/{repo}/contents/{path...}", f.getContents)
+	mux.HandleFunc("PATCH /repos/{owner}/{repo}/labels/{name}", f.patchLabel)
+	mux.HandleFunc("POST /graphql", f.graphql)
+	mux.HandleFunc("GET /rate_limit", f.rateLimit)
+	f.srv = httptest.NewServer(etags(mux))
+	t.Cleanup(f.srv.Close)
+
+	prev := runCmd
+	runCmd = f.gh
+	t.Cleanup(func() { runCmd = prev })
+	return f
+}
+
+func (f *fakeGitHub) addRepo(r *fakeRepo) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.repos[r.Owner+"/"+r.Name] = r
+}
+
+// openIssues converts a repo's open issues to the scanner's type, for
+// computing expected scores.
+func (f *fakeGitHub) openIssues(full string) []issue {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var out []issue
+	for _, is := range f.repos[full].Issues {
+		if is.State == "open" && !is.IsPR {
+			out = append(out, is.gh())
+		}
+	}
+	return out
+}
+
+func (f *fakeGitHub) issue(full string, number int) *fakeIssue {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if r := f.repos[full]; r != nil {
+		for _, is := range r.Issues {
+			if is.Number == number {
+				return is
+			}
+		}
+	}
+	return nil
+}
+
+func (f *fakeGitHub) writeLog() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return slices.Clone(f.writes)
+}
+
+func (is *fakeIssue) gh() issue {
+	out := issue{Number: is.Number, Title: is.Title, Body: is.Body, CreatedAt: is.CreatedAt, UpdatedAt: is.UpdatedAt, Author: actor{Login: is.Author}}
+	for _, l := range is.Labels {
+		out.Labels = append(out.Labels, label{Name: l})
+	}
+	return out
+}
+
+// genFixture adds repos with deterministic pseudo-random backlogs: issue
+// ages up to two years, and per-repo odds of an issue being idle or
+// unlabeled, so a handful of repos spans the health bands.
+func genFixture(f *fakeGitHub, owner string, seed int64, repos, maxIssues int, now time.Time) {
+	rng := rand.New(rand.NewSource(seed))
+	labels := []string{"bug", "enhancement", "good first issue", "docs"}
+	for r := 0; r < repos; r++ {
+		repo := &fakeRepo{Owner: owner, Name: fmt.Sprintf("repo-%02d", r)}
+		staleBias, labelBias := rng.Float64(), rng.Float64()
+		for n := 1; n <= 1+rng.Intn(maxIssues); n++ {
+			// Half-hour offsets keep every timestamp well clear of the
+			// stale-days boundary, whenever the scan reads the clock.
+			created := now.Add(-time.Duration(rng.Intn(730*24))*time.Hour - 30*time.Minute)
+			updated := now.Add(-time.Duration(rng.Intn(30*24))*time.Hour - 30*time.Minute)
+			if rng.Float64() < staleBias {
+				updated = created
+			}
+			if updated.Before(created) {
+				updated = created
+			}
+			is := &fakeIssue{Number: n, Title: fmt.Sprintf("issue %d", n), State: "open", CreatedAt: created, UpdatedAt: updated, Author: "user" + strconv.Itoa(rng.Intn(5))}
+			if rng.Float64() < labelBias {
+				is.Labels = []string{labels[rng.Intn(len(labels))]}
+			}
+			repo.Issues = append(repo.Issues, is)
+		}
+		f.addRepo(repo)
+	}
+}
+
+// etags gives every successful GET an ETag of its body and answers a
+// matching If-None-Match with 304, as GitHub does.
+func etags(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "GET" {
+			h.ServeHTTP(w, r)
+			return
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, r)
+		maps.Copy(w.Header(), rec.Header())
+		if rec.Code == http.StatusOK {
+			etag := fmt.Sprintf(`"%x"`, sha256.Sum256(rec.Body.Bytes()))
+			w.Header().Set("ETag", etag)
+			if r.Header.Get("If-None-Match") == etag {
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+		}
+		w.WriteHeader(rec.Code)
+		_, _ = w.Write(rec.Body.Bytes())
+	})
+}
+
+// REST handlers.
+
+func (f *fakeGitHub) notFound(w http.ResponseWriter) {
+	w.WriteHeader(http.StatusNotFound)
+	fmt.Fprint(w, `{"message":"Not Found"}`)
+}
+
+func (f *fakeGitHub) record(r *http.Request) {
+	f.writes = append(f.writes, r.Method+" "+r.URL.Path)
+}
+
+// rateLimit reports a fixed, healthy quota; the fake doesn't meter calls.
+func (f *fakeGitHub) rateLimit(w http.ResponseWriter, r *http.Request) {
+	bucket := `{"limit":5000,"used":10,"remaining":4990,"reset":1791892800}`
+	fmt.Fprintf(w, `{"resources":{"core":%s,"graphql":%s,"search":{"limit":30,"used":0,"remaining":30,"reset":1791892800}}}`, bucket, bucket)
+}
+
+// page writes one page of items with a Link header for the next, the way
+// GitHub paginates.
+func page[T any](w http.ResponseWriter, r *http.Request, items []T) {
+	perPage, _ := strconv.Atoi(r.URL.Query().Get("per_page"))
+	if perPage <= 0 {
+		perPage = 30
+	}
+	n, _ := strconv.Atoi(r.URL.Query().Get("page"))
+	if n <= 0 {
+		n = 1
+	}
+	start, end := min((n-1)*perPage, len(items)), min(n*perPage, len(items))
+	if end < len(items) {
+		next := *r.URL
+		q := next.Query()
+		q.Set("page", strconv.Itoa(n+1))
+		q.Set("per_page", strconv.Itoa(perPage))
+		next.RawQuery = q.Encode()
+		w.Header().Set("Link", fmt.Sprintf(`<%s>; rel="next"`, next.String()))
+	}
+	chunk := items[start:end]
+	if chunk == nil {
+		chunk = []T{}
+	}
+	_ = json.NewEncoder(w).Encode(chunk)
+}
+
+type restRepo struct {
+	Name      string    `json:"name"`
+	Archived  bool      `json:"archived"`
+	PushedAt  time.Time `json:"pushed_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func (f *fakeGitHub) listRepos(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var out []restRepo
+	for _, repo := range f.repos {
+		if strings.EqualFold(repo.Owner, r.PathValue("org")) {
+			out = append(out, restRepo{repo.Name, repo.Archived, repo.PushedAt, repo.UpdatedAt})
+		}
+	}
+	slices.SortFunc(out, func(a, b restRepo) int { return strings.Compare(a.Name, b.Name) })
+	page(w, r, out)
+}
+
+func (f *fakeGitHub) getRepo(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	repo := f.repoFor(r)
+	if repo == nil {
+		f.notFound(w)
+		return
+	}
+	_ = json.NewEncoder(w).Encode(map[string]any{"name": repo.Name, "archived": repo.Archived, "private": repo.Private, "topics": repo.Topics})
+}
+
+type restIssue struct {
+	Number      int       `json:"number"`
+	Title       string    `json:"title"`
+	Body        string    `json:"body"`
+	State       string    `json:"state"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+	User        actor     `json:"user"`
+	Labels      []label   `json:"labels"`
+	PullRequest *struct{} `json:"pull_request,omitempty"`
+}
+
+func (is *fakeIssue) rest() restIssue {
+	out := restIssue{Number: is.Number, Title: is.Title, Body: is.Body, State: is.State, CreatedAt: is.CreatedAt, UpdatedAt: is.UpdatedAt, User: actor{Login: is.Author}, Labels: []label{}}
+	for _, l := range is.Labels {
+		out.Labels = append(out.Labels, label{Name: l})
+	}
+	if is.IsPR {
+		out.PullRequest = &struct{}{}
+	}
+	return out
+}
+
+func (f *fakeGitHub) repoFor(r *http.Request) *fakeRepo {
+	return f.repos[r.PathValue("owner")+"/"+r.PathValue("repo")]
+}
+
+func (f *fakeGitHub) issueFor(r *http.Request) *fakeIssue {
+	repo := f.repoFor(r)
+	n, _ := strconv.Atoi(r.PathValue("number"))
+	if repo == nil {
+		return nil
+	}
+	for _, is := range repo.Issues {
+		if is.Number == n {
+			return is
+		}
+	}
+	return nil
+}
+
+func (f *fakeGitHub) listIssues(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	repo := f.repoFor(r)
+	if repo == nil {
+		f.notFound(w)
+		return
+	}
+	state := r.URL.Query().Get("state")
+	if state == "" {
+		state = "open"
+	}
+	lbl := r.URL.Query().Get("labels")
+	var out []restIssue
+	for _, is := range repo.Issues {
+		if (state == "all" || is.State == state) && (lbl == "" || slices.ContainsFunc(is.Labels, func(l string) bool { return strings.EqualFold(l, lbl) })) {
+			out = append(out, is.rest())
+		}
+	}
+	if r.URL.Query().Get("sort") == "updated" {
+		slices.SortStableFunc(out, func(a, b restIssue) int { return b.UpdatedAt.Compare(a.UpdatedAt) })
+	}
+	page(w, r, out)
+}
+
+func (repo *fakeRepo) hasLabel(name string) bool {
+	match := func(l string) bool { return strings.EqualFold(l, name) }
+	if slices.ContainsFunc(repo.Labels, match) {
+		return true
+	}
+	for _, is := range repo.Issues {
+		if slices.ContainsFunc(is.Labels, match) {
+			return true
+		}
+	}
+	return false
+}
+
+func (f *fakeGitHub) getLabel(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	repo := f.repoFor(r)
+	if repo == nil || !repo.hasLabel(r.PathValue("name")) {
+		f.notFound(w)
+		return
+	}
+	fmt.Fprintf(w, `{"name":%q}`, r.PathValue("name"))
+}
+
+func (f *fakeGitHub) getContents(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	repo := f.repoFor(r)
+	content, ok := "", false
+	if repo != nil {
+		content, ok = repo.Files[r.PathValue("path")]
+	}
+	if !ok {
+		f.notFound(w)
+		return
+	}
+	_ = json.NewEncoder(w).Encode(map[string]string{"type": "file", "encoding": "base64", "content": base64.StdEncoding.EncodeToString([]byte(content))})
+}
+
+// patchLabel renames a label everywhere, refusing names already taken as
+// GitHub does.
+func (f *fakeGitHub) patchLabel(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	repo, old := f.repoFor(r), r.PathValue("name")
+	if repo == nil || !repo.hasLabel(old) {
+		f.notFound(w)
+		return
+	}
+	var body struct {
+		NewName string `json:"new_name"`
+	}
+	_ = json.NewDecoder(r.Body).Decode(&body)
+	if !strings.EqualFold(old, body.NewName) && repo.hasLabel(body.NewName) {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		fmt.Fprint(w, `{"message":"Validation Failed"}`)
+		return
+	}
+	f.record(r)
+	rename := func(ls []string) {
+		for i, l := range ls {
+			if strings.EqualFold(l, old) {
+				ls[i] = body.NewName
+			}
+		}
+	}
+	rename(repo.Labels)
+	for _, is := range repo.Issues {
+		rename(is.Labels)
+	}
+	fmt.Fprintf(w, `{"name":%q}`, body.NewName)
+}
+
+func (f *fakeGitHub) getIssue(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	is := f.issueFor(r)
+	if is == nil {
+		f.notFound(w)
+		return
+	}
+	_ = json.NewEncoder(w).Encode(is.rest())
+}
+
+func (f *fakeGitHub) patchIssue(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	is := f.issueFor(r)
+	if is == nil {
+		f.notFound(w)
+		return
+	}
+	f.record(r)
+	var body struct {
+		State string `json:"state"`
+	}
+	_ = json.NewDecoder(r.Body).Decode(&body)
+	if body.State != "" {
+		is.State = body.State
+	}
+	_ = json.NewEncoder(w).Encode(is.rest())
+}
+
+func (f *fakeGitHub) addLabels(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	is := f.issueFor(r)
+	if is == nil {
+		f.notFound(w)
+		return
+	}
+	f.record(r)
+	var body struct {
+		Labels []string `json:"labels"`
+	}
+	_ = json.NewDecoder(r.Body).Decode(&body)
+	for _, l := range body.Labels {
+		if !slices.Contains(is.Labels, l) {
+			is.Labels = append(is.Labels, l)
+		}
+	}
+	_ = json.NewEncoder(w).Encode(is.rest().Labels)
+}
+
+func (f *fakeGitHub) removeLabel(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	is := f.issueFor(r)
+	if is == nil {
+		f.notFound(w)
+		return
+	}
+	f.record(r)
+	is.Labels = slices.DeleteFunc(is.Labels, func(l string) bool { return strings.EqualFold(l, r.PathValue("label")) })
+	_ = json.NewEncoder(w).Encode(is.rest().Labels)
+}
+
+func (f *fakeGitHub) addComment(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.issueFor(r) == nil {
+		f.notFound(w)
+		return
+	}
+	f.record(r)
+	w.WriteHeader(http.StatusCreated)
+	fmt.Fprint(w, `{}`)
+}
+
+// graphql answers every query with an empty search: enough for the
+// optional GraphQL metrics to run and report nothing.
+func (f *fakeGitHub) graphql(w http.ResponseWriter, r *http.Request) {
+	fmt.Fprint(w, `{"data":{"search":{"pageInfo":{"hasNextPage":false,"endCursor":null},"nodes":[]}}}`)
+}
+
+// gh plays the gh CLI: it turns the gh invocations the scanner makes into
+// requests against the fake server and prints what gh would.
+func (f *fakeGitHub) gh(_ context.Context, bin string, args ...string) ([]byte, error) {
+	fail := func(err error) ([]byte, error) {
+		return nil, fmt.Errorf("%s %s: %w", bin, strings.Join(args, " "), err)
+	}
+	if bin != "gh" || len(args) < 2 {
+		return fail(fmt.Errorf("fake gh: unsupported command"))
+	}
+	out, err := f.ghCommand(args)
+	if err != nil {
+		return fail(err)
+	}
+	return out, nil
+}
+
+func ghFlag(args []string, name string) string {
+	if i := slices.Index(args, name); i >= 0 && i+1 < len(args) {
+		return args[i+1]
+	}
+	return ""
+}
+
+func (f *fakeGitHub) ghCommand(args []string) ([]byte, error) {
+	switch args[0] + " " + args[1] {
+	case "repo list":
+		var repos []restRepo
+		if err := f.getAll("/orgs/"+args[2]+"/repos", &repos); err != nil {
+			return nil, err
+		}
+		type ghRepo struct {
+			Name       string    `json:"name"`
+			IsArchived bool      `json:"isArchived"`
+			PushedAt   time.Time `json:"pushedAt"`
+			UpdatedAt  time.Time `json:"updatedAt"`
+		}
+		out := []ghRepo{}
+		for _, r := range repos {
+			if !r.Archived || !slices.Contains(args, "--no-archived") {
+				out = append(out, ghRepo{r.Name, r.Archived, r.PushedAt, r.UpdatedAt})
+			}
+		}
+		return json.Marshal(out)
+	case "search issues":
+		// Only the org-wide activity search the delta scans make.
+		since, err := time.Parse(time.RFC3339, strings.TrimPrefix(ghFlag(args, "--updated"), ">="))
+		if err != nil {
+			return nil, fmt.Errorf("fake gh search: unsupported --updated %q", ghFlag(args, "--updated"))
+		}
+		limit, _ := strconv.Atoi(ghFlag(args, "--limit"))
+		type hit struct {
+			Repository struct {
+				NameWithOwner string `json:"nameWithOwner"`
+			} `json:"repository"`
+		}
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		out := []hit{}
+		for full, repo := range f.repos {
+			if !strings.EqualFold(repo.Owner, ghFlag(args, "--owner")) {
+				continue
+			}
+			for _, is := range repo.Issues {
+				if !is.UpdatedAt.Before(since) && (limit == 0 || len(out) < limit) {
+					var h hit
+					h.Repository.NameWithOwner = full
+					out = append(out, h)
+				}
+			}
+		}
+		return json.Marshal(out)
+	case "issue list":
+		var issues []restIssue
+		q := url.Values{"state": {ghFlag(args, "--state")}}
+		if err := f.getAll("/repos/"+ghFlag(args, "--repo")+"/issues?"+q.Encode(), &issues); err != nil {
+			return nil, err
+		}
+		limit, _ := strconv.Atoi(ghFlag(args, "--limit"))
+		out := []map[string]any{}
+		for _, is := range issues {
+			if is.PullRequest != nil {
+				continue // gh issue list excludes PRs
+			}
+			if limit > 0 && len(out) == limit {
+				break
+			}
+			all := map[string]any{"number": is.Number, "title": is.Title, "body": is.Body, "state": strings.ToUpper(is.State), "createdAt": is.CreatedAt, "updatedAt": is.UpdatedAt, "labels": is.Labels, "author": is.User, "comments": []any{}, "reactionGroups": []any{}}
+			picked := map[string]any{}
+			for _, field := range strings.Split(ghFlag(args, "--json"), ",") {
+				picked[field] = all[field]
+			}
+			out = append(out, picked)
+		}
+		return json.Marshal(out)
+	case "issue view":
+		var is restIssue
+		if err := f.getOne("/repos/"+ghFlag(args, "--repo")+"/issues/"+args[2], &is); err != nil {
+			return nil, err
+		}
+		return json.Marshal(map[string]any{"number": is.Number, "title": is.Title, "body": is.Body, "createdAt": is.CreatedAt, "updatedAt": is.UpdatedAt, "labels": is.Labels})
+	case "issue close":
+		if _, err := f.do("PATCH", "/repos/"+ghFlag(args, "--repo")+"/issues/"+args[2], map[string]string{"state": "closed"}); err != nil {
+			return nil, err
+		}
+		if c := ghFlag(args, "--comment"); c != "" {
+			_, err := f.do("POST", "/repos/"+ghFlag(args, "--repo")+"/issues/"+args[2]+"/comments", map[string]string{"body": c})
+			return nil, err
+		}
+		return nil, nil
+	case "issue edit":
+		path := "/repos/" + ghFlag(args, "--repo") + "/issues/" + args[2] + "/labels"
+		if l := ghFlag(args, "--add-label"); l != "" {
+			if _, err := f.do("POST", path, map[string][]string{"labels": {l}}); err != nil {
+				return nil, err
+			}
+		}
+		if l := ghFlag(args, "--remove-label"); l != "" {
+			if _, err := f.do("DELETE", path+"/"+url.PathEscape(l), nil); err != nil {
+				return nil, err
+			}
+		}
+		return nil, nil
+	case "issue comment":
+		_, err := f.do("POST", "/repos/"+ghFlag(args, "--repo")+"/issues/"+args[2]+"/comments", map[string]string{"body": ghFlag(args, "--body")})
+		return nil, err
+	}
+	if args[0] == "api" {
+		return f.ghAPI(args[1:])
+	}
+	return nil, fmt.Errorf("fake gh: unsupported command %q", args[0]+" "+args[1])
+}
+
+func (f *fakeGitHub) ghAPI(args []string) ([]byte, error) {
+	var path string
+	method := ""
+	paginate, silent, include := false, false, false
+	fields := map[string]string{}
+	var headers []string
+	for i := 0; i < len(args); i++ {
+		switch a := args[i]; a {
+		case "--paginate":
+			paginate = true
+		case "--silent":
+			silent = true
+		case "-i":
+			include = true
+		case "-H":
+			headers = append(headers, args[i+1])
+			i++
+		case "-X":
+			method = args[i+1]
+			i++
+		case "-f":
+			k, v, _ := strings.Cut(args[i+1], "=")
+			fields[k] = v
+			i++
+		default:
+			if strings.HasPrefix(a, "-") {
+				return nil, fmt.Errorf("fake gh api: unsupported flag %s", a)
+			}
+			path = "/" + strings.TrimPrefix(a, "/")
+		}
+	}
+	if path == "/graphql" {
+		return f.do("POST", path, fields)
+	}
+	if method != "" && method != "GET" {
+		out, err := f.do(method, path, fields)
+		if silent {
+			out = nil
+		}
+		return out, err
+	}
+	var out bytes.Buffer
+	for next := path; next != ""; {
+		resp, err := f.request("GET", next, nil, headers...)
+		if err != nil {
+			return nil, err
+		}
+		if include {
+			out.WriteString("HTTP/1.1 200 OK\r\n")
+			_ = resp.header.Write(&out)
+			out.WriteString("\r\n")
+		}
+		out.Write(resp.body)
+		next = ""
+		if paginate {
+			next = nextLink(resp.header.Get("Link"), f.srv.URL)
+		}
+	}
+	if silent {
+		return nil, nil
+	}
+	return out.Bytes(), nil
+}
+
+type fakeResponse struct {
+	header http.Header
+	body   []byte
+}
+
+func (f *fakeGitHub) request(method, path string, body any, headers ...string) (fakeResponse, error) {
+	var r io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return fakeResponse{}, err
+		}
+		r = bytes.NewReader(data)
+	}
+	req, err := http.NewRequest(method, f.srv.URL+path, r)
+	if err != nil {
+		return fakeResponse{}, err
+	}
+	for _, h := range headers {
+		k, v, _ := strings.Cut(h, ":")
+		req.Header.Set(k, strings.TrimSpace(v))
+	}
+	resp, err := f.srv.Client().Do(req)
+	if err != nil {
+		return fakeResponse{}, err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fakeResponse{}, err
+	}
+	if resp.StatusCode/100 != 2 {
+		// The same shape as gh's own error output.
+		var msg struct{ Message string }
+		_ = json.Unmarshal(data, &msg)
+		return fakeResponse{}, fmt.Errorf("%s (HTTP %d)", msg.Message, resp.StatusCode)
+	}
+	return fakeResponse{resp.Header, data}, nil
+}
+
+func (f *fakeGitHub) do(method, path string, body any) ([]byte, error) {
+	resp, err := f.request(method, path, body)
+	return resp.body, err
+}
+
+// getOne decodes a single object.
+func (f *fakeGitHub) getOne(path string, v any) error {
+	out, err := f.do("GET", path, nil)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(out, v)
+}
+
+// getAll follows pagination and decodes every page into v.
+func (f *fakeGitHub) getAll(path string, v any) error {
+	out, err := f.ghAPI([]string{"--paginate", path + pageSep(path) + "per_page=100"})
+	if err != nil {
+		return err
+	}
+	dec := json.NewDecoder(bytes.NewReader(out))
+	var all []json.RawMessage
+	for dec.More() {
+		var p []json.RawMessage
+		if err := dec.Decode(&p); err != nil {
+			return err
+		}
+		all = append(all, p...)
+	}
+	data, _ := json.Marshal(all)
+	return json.Unmarshal(data, v)
+}
+
+func pageSep(path string) string {
+	if strings.Contains(path, "?") {
+		return "&"
+	}
+	return "?"
+}
+
+func nextLink(header, base string) string {
+	for _, part := range strings.Split(header, ",") {
+		if strings.Contains(part, `rel="next"`) {
+			u := strings.TrimSpace(part[strings.Index(part, "<")+1 : strings.Index(part, ">")])
+			return strings.TrimPrefix(u, base)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,168 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// publicFeed configures the redacted summary `serve` publishes for public
+// dashboards. It is an allowlist: only the listed fields leave the process,
+// whatever the internal report grows.
+type publicFeed struct {
+	// Fields are dotted paths into the report; a path through a list
+	// applies to every element, so "repos.status" keeps each repo's status.
+	Fields []string `json:"fields,omitempty"`
+	// ExcludeRepos are globs, as in .fabignore, of repos left out entirely.
+	ExcludeRepos []string `json:"excludeRepos,omitempty"`
+	// AllowOrigin is the CORS origin allowed to read the feed; default "*".
+	AllowOrigin   string `json:"allowOrigin,omitempty"`
+	MaxAgeSeconds int    `json:"maxAgeSeconds,omitempty"`
+}
+
+var defaultFeedFields = []string{
+	"generatedAt", "org", "summary.total", "summary.healthy", "summary.warning", "summary.critical",
+	"repos.name", "repos.healthScore", "repos.status", "repos.totalOpen", "repos.staleCount", "repos.unlabeledCount",
+}
+
+const defaultFeedMaxAge = 300
+
+func (p publicFeed) fields() []string {
+	if len(p.Fields) > 0 {
+		return p.Fields
+	}
+	return defaultFeedFields
+}
+
+func (p publicFeed) allowOrigin() string {
+	if p.AllowOrigin != "" {
+		return p.AllowOrigin
+	}
+	return "*"
+}
+
+func (p publicFeed) maxAge() int {
+	if p.MaxAgeSeconds > 0 {
+		return p.MaxAgeSeconds
+	}
+	return defaultFeedMaxAge
+}
+
+func (p publicFeed) validate() error {
+	for _, f := range p.Fields {
+		if f == "" || strings.HasPrefix(f, ".") || strings.HasSuffix(f, ".") || strings.Contains(f, "..") {
+			return fmt.Errorf("publicFeed: invalid field path %q", f)
+		}
+	}
+	if p.MaxAgeSeconds < 0 {
+		return fmt.Errorf("publicFeed: maxAgeSeconds must not be negative")
+	}
+	return nil
+}
+
+// build renders the feed for a report: excluded repos dropped, then every
+// field not on the allowlist.
+func (p publicFeed) build(report output) ([]byte, error) {
+	repos := make([]repoScore, 0, len(report.Repos))
+	for _, rs := range report.Repos {
+		if !ignoredRepo(rs.fullName, p.ExcludeRepos) {
+			repos = append(repos, rs)
+		}
+	}
+	report.Repos = repos
+	if len(p.ExcludeRepos) > 0 {
+		report.Summary = summarize(repos)
+	}
+	raw, err := json.Marshal(report)
+	if err != nil {
+		return nil, err
+	}
+	var doc any
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		return nil, err
+	}
+	paths := make([][]string, 0, len(p.fields()))
+	for _, f := range p.fields() {
+		paths = append(paths, strings.Split(f, "."))
+	}
+	return json.Marshal(project(doc, paths))
+}
+
+// project keeps only the parts of a decoded JSON value named by paths.
+func project(v any, paths [][]string) any {
+	switch v := v.(type) {
+	case []any:
+		out := make([]any, 0, len(v))
+		for _, e := range v {
+			out = append(out, project(e, paths))
+		}
+		return out
+	case map[string]any:
+		sub := map[string][][]string{}
+		whole := map[string]bool{}
+		for _, p := range paths {
+			if len(p) == 1 {
+				whole[p[0]] = true
+			} else {
+				sub[p[0]] = append(sub[p[0]], p[1:])
+			}
+		}
+		out := map[string]any{}
+		for k, e := range v {
+			switch {
+			case whole[k]:
+				out[k] = e
+			case sub[k] != nil:
+				out[k] = project(e, sub[k])
+			}
+		}
+		return out
+	}
+	// A path that runs past a scalar keeps nothing of it.
+	return nil
+}
+
+// feedHandler serves the latest feed with CORS and caching headers. Feeds
+// are immutable once published, so the ETag lets dashboards revalidate
+// for free.
+func (d *daemon) feedHandler(w http.ResponseWriter, r *http.Request) {
+	p := *d.cfg.PublicFeed
+	h := w.Header()
+	h.Set("Access-Control-Allow-Origin", p.allowOrigin())
+	if p.allowOrigin() != "*" {
+		h.Set("Vary", "Origin")
+	}
+	if r.Method == http.MethodOptions {
+		h.Set("Access-Control-Allow-Methods", "GET, OPTIONS")
+		h.Set("Access-Control-Allow-Headers", "If-None-Match")
+		h.Set("Access-Control-Max-Age", "86400")
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	d.mu.RLock()
+	feed, etag := d.feed, d.feedETag
+	d.mu.RUnlock()
+	h.Set("Content-Type", "application/json")
+	if feed == nil {
+		h.Set("Cache-Control", "no-store")
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_ = writeJSON(w, map[string]any{"ok": false, "error": "first scan in progress"})
+		return
+	}
+	h.Set("Cache-Control", "public, max-age="+strconv.Itoa(p.maxAge()))
+	h.Set("ETag", etag)
+	if r.Header.Get("If-None-Match") == etag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	_, _ = w.Write(feed)
+}
+
+func feedETag(feed []byte) string {
+	sum := sha256.Sum256(feed)
+	return `"` + hex.EncodeToString(sum[:8]) + `"`
+}
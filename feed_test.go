@@ -0,0 +1,96 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func feedReport() output {
+	return output{
+		GeneratedAt: "2025-06-01T00:00:00Z",
+		Org:         "acme",
+		Config:      config{MinIssues: 5, SMTP: &smtpConfig{Host: "mail.internal"}},
+		Repos: []repoScore{
+			{Name: "api", fullName: "acme/api", HealthScore: 35, Status: "critical", TotalOpen: 40, Recommendations: []string{"label 12 issues"}},
+			{Name: "secret-infra", fullName: "acme/secret-infra", HealthScore: 100, Status: "healthy"},
+		},
+		Summary: summary{Total: 2, Healthy: 1, Critical: 1},
+	}
+}
+
+func TestPublicFeedRedacts(t *testing.T) {
+	raw, err := publicFeed{}.build(feedReport())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, leak := range []string{"config", "mail.internal", "recommendations", "label 12 issues"} {
+		if strings.Contains(string(raw), leak) {
+			t.Errorf("feed leaks %q: %s", leak, raw)
+		}
+	}
+	if !strings.Contains(string(raw), `"healthScore":35`) || !strings.Contains(string(raw), `"summary":{"critical":1`) {
+		t.Errorf("feed = %s", raw)
+	}
+
+	p := publicFeed{Fields: []string{"summary.total", "repos.status"}, ExcludeRepos: []string{"secret-*"}}
+	raw, err = p.build(feedReport())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got map[string]any
+	_ = json.Unmarshal(raw, &got)
+	want := map[string]any{"summary": map[string]any{"total": 1.0}, "repos": []any{map[string]any{"status": "critical"}}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("feed = %s", raw)
+	}
+	if err := (publicFeed{Fields: []string{"repos..name"}}).validate(); err == nil {
+		t.Error("malformed path should be rejected")
+	}
+}
+
+func TestPublicFeedHandler(t *testing.T) {
+	d := newDaemon("", selection{}, config{PublicFeed: &publicFeed{AllowOrigin: "https://status.example.org", MaxAgeSeconds: 60}}, time.Hour, time.Hour)
+	srv := httptest.NewServer(d.handler())
+	defer srv.Close()
+	get := func(method string, header map[string]string) *http.Response {
+		req, _ := http.NewRequest(method, srv.URL+"/public/feed.json", nil)
+		for k, v := range header {
+			req.Header.Set(k, v)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		return resp
+	}
+	if resp := get("GET", nil); resp.StatusCode != http.StatusServiceUnavailable || resp.Header.Get("Cache-Control") != "no-store" {
+		t.Errorf("before first scan: %d %q", resp.StatusCode, resp.Header.Get("Cache-Control"))
+	}
+
+	d.publish(t.Context(), feedReport().Repos, time.Now())
+	resp := get("GET", nil)
+	if resp.StatusCode != http.StatusOK || resp.Header.Get("Access-Control-Allow-Origin") != "https://status.example.org" ||
+		resp.Header.Get("Cache-Control") != "public, max-age=60" || resp.Header.Get("Vary") != "Origin" {
+		t.Fatalf("feed: %d %v", resp.StatusCode, resp.Header)
+	}
+	etag := resp.Header.Get("ETag")
+	if resp := get("GET", map[string]string{"If-None-Match": etag}); etag == "" || resp.StatusCode != http.StatusNotModified {
+		t.Errorf("revalidation with %q: %d", etag, resp.StatusCode)
+	}
+	if resp := get("OPTIONS", nil); resp.StatusCode != http.StatusNoContent || !strings.Contains(resp.Header.Get("Access-Control-Allow-Methods"), "GET") {
+		t.Errorf("preflight: %d %v", resp.StatusCode, resp.Header)
+	}
+
+	d = &daemon{}
+	rec := httptest.NewRecorder()
+	d.handler().ServeHTTP(rec, httptest.NewRequest("GET", "/public/feed.json", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("feed served without being configured: %d", rec.Code)
+	}
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+)
+
+var (
+	failOn      = flag.String("fail-on", "", "exit non-zero if any repo is at this status or worse: warning or critical")
+	maxCritical = flag.Int("max-critical", -1, "exit non-zero if more than N repos are critical (-1 disables)")
+)
+
+// exitGateFailed is the exit code when a scan succeeds but trips a gate,
+// kept apart from 1 (scan failed) and 2 (bad usage) so CI can tell them apart.
+const exitGateFailed = 3
+
+// gateResult is the report's gate section, present when any gate is set.
+// An observed gate is reported but doesn't set the exit code.
+type gateResult struct {
+	Passed   bool     `json:"passed"`
+	Observed bool     `json:"observed,omitempty"`
+	Reasons  []string `json:"reasons,omitempty"`
+}
+
+func validateGate(failOn string) error {
+	switch failOn {
+	case "", "warning", "critical":
+		return nil
+	}
+	return fmt.Errorf("-fail-on must be warning or critical, got %q", failOn)
+}
+
+// evaluateGate checks the summary against the configured thresholds. It
+// returns nil when no gate is configured.
+func evaluateGate(s summary, failOn string, maxCritical int) *gateResult {
+	if failOn == "" && maxCritical < 0 {
+		return nil
+	}
+	g := &gateResult{}
+	switch failOn {
+	case "critical":
+		if s.Critical > 0 {
+			g.Reasons = append(g.Reasons, fmt.Sprintf("%d repos critical", s.Critical))
+		}
+	case "warning":
+		if n := s.Warning + s.Critical; n > 0 {
+			g.Reasons = append(g.Reasons, fmt.Sprintf("%d repos warning or critical", n))
+		}
+	}
+	if maxCritical >= 0 && s.Critical > maxCritical {
+		g.Reasons = append(g.Reasons, fmt.Sprintf("%d repos critical, max %d", s.Critical, maxCritical))
+	}
+	g.Passed = len(g.Reasons) == 0
+	return g
+}
+
+// gateTruncated fails g when the scan was cut short: repos that weren't
+// scanned can't be shown to pass, so a partial run never clears the gate.
+func gateTruncated(g *gateResult, truncated bool) *gateResult {
+	if g == nil || !truncated {
+		return g
+	}
+	g.Reasons = append(g.Reasons, "scan truncated before every repo was scored")
+	g.Passed = false
+	return g
+}
@@ -0,0 +1,47 @@
+package main
+
+import "testing"
+
+func TestEvaluateGate(t *testing.T) {
+	s := summary{Total: 5, Healthy: 3, Warning: 1, Critical: 1}
+	tests := []struct {
+		failOn      string
+		maxCritical int
+		want        *bool
+	}{
+		{"", -1, nil},
+		{"critical", -1, ptr(false)},
+		{"warning", -1, ptr(false)},
+		{"", 1, ptr(true)},
+		{"", 0, ptr(false)},
+	}
+	for _, tt := range tests {
+		g := evaluateGate(s, tt.failOn, tt.maxCritical)
+		switch {
+		case tt.want == nil && g != nil:
+			t.Errorf("failOn=%q max=%d: want no gate, got %+v", tt.failOn, tt.maxCritical, g)
+		case tt.want != nil && (g == nil || g.Passed != *tt.want):
+			t.Errorf("failOn=%q max=%d: got %+v, want passed=%v", tt.failOn, tt.maxCritical, g, *tt.want)
+		}
+	}
+	if g := evaluateGate(summary{Healthy: 4, Warning: 1}, "critical", -1); !g.Passed {
+		t.Errorf("warnings should pass a critical gate: %+v", g)
+	}
+	if validateGate("healthy") == nil {
+		t.Error("unknown -fail-on value should be rejected")
+	}
+}
+
+func ptr[T any](v T) *T { return &v }
+
+func TestGateTruncated(t *testing.T) {
+	if g := gateTruncated(&gateResult{Passed: true}, true); g.Passed || len(g.Reasons) != 1 {
+		t.Errorf("truncated scan should fail the gate: %+v", g)
+	}
+	if g := gateTruncated(&gateResult{Passed: true}, false); !g.Passed {
+		t.Errorf("complete scan should keep the verdict: %+v", g)
+	}
+	if g := gateTruncated(nil, true); g != nil {
+		t.Errorf("no gate configured should stay nil, got %+v", g)
+	}
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+)
+
+var gistFlag = flag.Bool("gist", false, "keep the latest report in a secret gist, created on the first run and updated after, for a shareable URL")
+
+// gistDescription identifies the org's report gist among the user's gists.
+func gistDescription(org string) string {
+	return "fab-backlog report: " + org
+}
+
+type gistFile struct {
+	Content string `json:"content"`
+}
+
+// publishGist creates a secret gist described as description holding
+// files, or replaces the files of the user's existing one, and returns its
+// URL.
+func publishGist(run func(string, ...string) ([]byte, error), description string, files map[string]string) (string, error) {
+	stdout, err := run("gh", "api", "gists", "--paginate", "--jq", ".[] | select(.description == "+strconv.Quote(description)+") | .id")
+	if err != nil {
+		return "", err
+	}
+	id, _, _ := strings.Cut(strings.TrimSpace(string(stdout)), "\n")
+
+	body := map[string]any{"description": description}
+	fs := map[string]gistFile{}
+	for name, content := range files {
+		fs[name] = gistFile{Content: content}
+	}
+	body["files"] = fs
+	method, path := "PATCH", "gists/"+id
+	if id == "" {
+		method, path = "POST", "gists"
+		body["public"] = false
+	}
+	data, err := json.Marshal(body)
+	if err != nil {
+		return "", err
+	}
+	in, err := tempFile("fab-backlog-gist-*.json", data)
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(in)
+	stdout, err = run("gh", "api", "-X", method, path, "--input", in, "--jq", ".html_url")
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(stdout)), nil
+}
+
+// publishReportGist keeps the report's gist up to date when -gist is set.
+func publishReportGist(out output) {
+	if !*gistFlag {
+		return
+	}
+	files, err := reportFiles(out)
+	if err != nil {
+		slog.Error("failed to render report", "error", err)
+		return
+	}
+	url, err := publishGist(runBackground, gistDescription(out.Org), files)
+	if err != nil {
+		slog.Error("failed to publish gist", "description", gistDescription(out.Org), "error", err)
+		return
+	}
+	slog.Info("report gist published", "url", url)
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestPublishGist(t *testing.T) {
+	files := map[string]string{"report.md": "# Backlog health\n", "report.json": "{}\n"}
+	for _, tc := range []struct {
+		existing, method, path string
+	}{
+		{"", "POST", "gists"},
+		{"abc123\n", "PATCH", "gists/abc123"},
+	} {
+		var calls [][]string
+		var body map[string]any
+		run := func(bin string, args ...string) ([]byte, error) {
+			calls = append(calls, args)
+			if args[1] == "gists" && args[2] == "--paginate" {
+				return []byte(tc.existing), nil
+			}
+			data, err := os.ReadFile(args[5])
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := json.Unmarshal(data, &body); err != nil {
+				t.Fatal(err)
+			}
+			return []byte("https://gist.github.com/abc123\n"), nil
+		}
+		url, err := publishGist(run, gistDescription("acme"), files)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if url != "https://gist.github.com/abc123" || len(calls) != 2 {
+			t.Fatalf("url %q, calls %v", url, calls)
+		}
+		if !strings.Contains(calls[0][4], `select(.description == "fab-backlog report: acme")`) {
+			t.Errorf("lookup = %v", calls[0])
+		}
+		if calls[1][2] != tc.method || calls[1][3] != tc.path {
+			t.Errorf("existing %q: %v, want %s %s", tc.existing, calls[1], tc.method, tc.path)
+		}
+		fs, _ := body["files"].(map[string]any)
+		if body["description"] != "fab-backlog report: acme" || len(fs) != 2 {
+			t.Errorf("body = %v", body)
+		}
+		// New gists are secret; updates leave visibility alone.
+		if public, ok := body["public"]; tc.existing == "" && public != false || tc.existing != "" && ok {
+			t.Errorf("existing %q: public = %v", tc.existing, body["public"])
+		}
+	}
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"fmt"
+)
+
+// repoGroup tags the repos matching any of its owner/name patterns, e.g.
+// platform or deprecated-track. A repo may carry several tags.
+type repoGroup struct {
+	Name  string       `json:"name"`
+	Repos repoPatterns `json:"repos"`
+	// Gate, when set, judges the group's repos by its own thresholds
+	// instead of -fail-on and -max-critical; an empty gate never fails.
+	Gate *groupGate `json:"gate,omitempty"`
+}
+
+type groupGate struct {
+	FailOn      string `json:"failOn,omitempty"`
+	MaxCritical *int   `json:"maxCritical,omitempty"`
+}
+
+func (g groupGate) maxCritical() int {
+	if g.MaxCritical == nil {
+		return -1
+	}
+	return *g.MaxCritical
+}
+
+// groupRollup is one group's slice of the backlog, summarised as the
+// report summary is.
+type groupRollup struct {
+	Name string `json:"name"`
+	summary
+	// HealthScore is the mean health score of the group's scored repos.
+	HealthScore float64     `json:"healthScore"`
+	Repos       []string    `json:"repos"`
+	Gate        *gateResult `json:"gate,omitempty"`
+}
+
+func validateGroups(groups []repoGroup) error {
+	seen := map[string]bool{}
+	for i, g := range groups {
+		if g.Name == "" {
+			return fmt.Errorf("group #%d: name required", i+1)
+		}
+		if seen[g.Name] {
+			return fmt.Errorf("group %s: defined twice", g.Name)
+		}
+		seen[g.Name] = true
+		if len(g.Repos) == 0 {
+			return fmt.Errorf("group %s: repos required", g.Name)
+		}
+		if err := g.Repos.validate(); err != nil {
+			return fmt.Errorf("group %s: %w", g.Name, err)
+		}
+		if g.Gate != nil {
+			if err := validateGate(g.Gate.FailOn); err != nil {
+				return fmt.Errorf("group %s: %w", g.Name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// tagGroups sets each repo's group tags, in config order.
+func tagGroups(repos []repoScore, groups []repoGroup) {
+	for i, rs := range repos {
+		repos[i].Groups = nil
+		for _, g := range groups {
+			if g.Repos.matches(rs.fullName) {
+				repos[i].Groups = append(repos[i].Groups, g.Name)
+			}
+		}
+	}
+}
+
+// rollupGroups summarises the tagged repos per group, in config order.
+// Groups matching no scanned repo are left out.
+func rollupGroups(repos []repoScore, groups []repoGroup) []groupRollup {
+	var out []groupRollup
+	for _, g := range groups {
+		mine := inGroup(repos, g.Name)
+		if len(mine) == 0 {
+			continue
+		}
+		r := groupRollup{Name: g.Name}
+		r.summary, r.HealthScore, r.Repos = rollupOf(mine)
+		out = append(out, r)
+	}
+	return out
+}
+
+func inGroup(repos []repoScore, name string) []repoScore {
+	var out []repoScore
+	for _, rs := range repos {
+		for _, tag := range rs.Groups {
+			if tag == name {
+				out = append(out, rs)
+				break
+			}
+		}
+	}
+	return out
+}
+
+// evaluateGroupGates gates repos, the enforced ones, by their groups'
+// thresholds and the rest by failOn and maxCritical. Each gated group's
+// verdict is recorded on its rollup, and a group failing fails the whole.
+// It returns nil when no gate applies.
+func evaluateGroupGates(out *output, repos []repoScore, groups []repoGroup, failOn string, maxCritical int) *gateResult {
+	var rest []repoScore
+	for _, rs := range repos {
+		if !gatedByGroup(rs, groups) {
+			rest = append(rest, rs)
+		}
+	}
+	g := evaluateGate(summarize(rest), failOn, maxCritical)
+	for _, grp := range groups {
+		if grp.Gate == nil {
+			continue
+		}
+		gr := evaluateGate(summarize(inGroup(repos, grp.Name)), grp.Gate.FailOn, grp.Gate.maxCritical())
+		if gr == nil {
+			continue
+		}
+		for i := range out.Groups {
+			if out.Groups[i].Name == grp.Name {
+				out.Groups[i].Gate = gr
+			}
+		}
+		if g == nil {
+			g = &gateResult{Passed: true}
+		}
+		for _, r := range gr.Reasons {
+			g.Reasons = append(g.Reasons, grp.Name+": "+r)
+		}
+		g.Passed = len(g.Reasons) == 0
+	}
+	return g
+}
+
+func gatedByGroup(rs repoScore, groups []repoGroup) bool {
+	for _, g := range groups {
+		if g.Gate != nil && g.Repos.matches(rs.fullName) {
+			return true
+		}
+	}
+	return false
+}
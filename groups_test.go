@@ -0,0 +1,109 @@
+package main
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGroupRollups(t *testing.T) {
+	cfg := config{Groups: []repoGroup{
+		{Name: "platform", Repos: []string{"acme/api", "acme/infra-*"}},
+		{Name: "frontend", Repos: []string{"acme/web", "acme/api"}},
+		{Name: "mobile", Repos: []string{"acme/ios"}},
+	}}
+	repos := []repoScore{
+		{Name: "api", fullName: "acme/api", HealthScore: 40, Status: "critical"},
+		{Name: "infra-dns", fullName: "Acme/Infra-DNS", HealthScore: 90, Status: "healthy"},
+		{Name: "web", fullName: "acme/web", HealthScore: 70, Status: "warning"},
+		{Name: "cli", fullName: "acme/cli", HealthScore: 80, Status: "healthy"},
+	}
+	out := assemble("acme", cfg, repos, time.Now())
+
+	tags := map[string][]string{}
+	for _, rs := range out.Repos {
+		tags[rs.Name] = rs.Groups
+	}
+	if !slices.Equal(tags["api"], []string{"platform", "frontend"}) || !slices.Equal(tags["infra-dns"], []string{"platform"}) || tags["cli"] != nil {
+		t.Errorf("tags = %v", tags)
+	}
+	if len(out.Groups) != 2 {
+		t.Fatalf("groups = %+v, want platform and frontend; mobile matched nothing", out.Groups)
+	}
+	plat, front := out.Groups[0], out.Groups[1]
+	if plat.Name != "platform" || plat.HealthScore != 65 || plat.Critical != 1 || plat.Healthy != 1 || !slices.Equal(plat.Repos, []string{"api", "infra-dns"}) {
+		t.Errorf("platform = %+v", plat)
+	}
+	if front.Name != "frontend" || front.HealthScore != 55 || !slices.Equal(front.Repos, []string{"api", "web"}) {
+		t.Errorf("frontend = %+v", front)
+	}
+}
+
+func TestGroupGates(t *testing.T) {
+	zero := 0
+	groups := []repoGroup{
+		{Name: "platform", Repos: []string{"acme/api", "acme/db"}, Gate: &groupGate{MaxCritical: &zero}},
+		{Name: "deprecated-track", Repos: []string{"acme/legacy"}, Gate: &groupGate{}},
+		{Name: "frontend", Repos: []string{"acme/web"}},
+	}
+	gate := func(repos []repoScore, failOn string) (*gateResult, output) {
+		tagGroups(repos, groups)
+		out := output{Repos: repos, Groups: rollupGroups(repos, groups)}
+		return evaluateGroupGates(&out, repos, groups, failOn, -1), out
+	}
+
+	// The legacy repo is critical, but its group never fails, and the
+	// global gate only sees repos outside gated groups.
+	g, out := gate([]repoScore{
+		{Name: "api", fullName: "acme/api", Status: "healthy"},
+		{Name: "legacy", fullName: "acme/legacy", Status: "critical"},
+		{Name: "web", fullName: "acme/web", Status: "warning"},
+	}, "critical")
+	if g == nil || !g.Passed {
+		t.Errorf("gate = %+v, want passed", g)
+	}
+	if out.Groups[0].Gate == nil || !out.Groups[0].Gate.Passed || out.Groups[1].Gate != nil || out.Groups[2].Gate != nil {
+		t.Errorf("group gates = %+v", out.Groups)
+	}
+
+	g, out = gate([]repoScore{
+		{Name: "db", fullName: "acme/db", Status: "critical"},
+		{Name: "web", fullName: "acme/web", Status: "critical"},
+	}, "critical")
+	if g == nil || g.Passed || !slices.Equal(g.Reasons, []string{"1 repos critical", "platform: 1 repos critical, max 0"}) {
+		t.Errorf("gate = %+v", g)
+	}
+	if out.Groups[0].Gate == nil || out.Groups[0].Gate.Passed {
+		t.Errorf("platform gate = %+v", out.Groups[0].Gate)
+	}
+
+	// A group's own thresholds gate the run even without -fail-on.
+	if g, _ := gate([]repoScore{{Name: "web", fullName: "acme/web", Status: "critical"}}, ""); g == nil || !g.Passed {
+		t.Errorf("gate = %+v, want platform's gate, passed", g)
+	}
+	if g := evaluateGroupGates(&output{}, []repoScore{{Name: "web", fullName: "acme/web", Status: "critical"}}, groups[2:], "", -1); g != nil {
+		t.Errorf("gate = %+v with no thresholds anywhere", g)
+	}
+}
+
+func TestValidateGroups(t *testing.T) {
+	for _, tc := range []struct {
+		groups []repoGroup
+		want   string
+	}{
+		{[]repoGroup{{Repos: []string{"acme/api"}}}, "name required"},
+		{[]repoGroup{{Name: "web"}}, "repos required"},
+		{[]repoGroup{{Name: "web", Repos: []string{"web"}}}, "must be owner/name"},
+		{[]repoGroup{{Name: "web", Repos: []string{"acme/["}}}, "bad pattern"},
+		{[]repoGroup{{Name: "web", Repos: []string{"acme/web"}}, {Name: "web", Repos: []string{"acme/site"}}}, "defined twice"},
+		{[]repoGroup{{Name: "web", Repos: []string{"acme/web"}, Gate: &groupGate{FailOn: "sometimes"}}}, "-fail-on must be"},
+	} {
+		if err := validateGroups(tc.groups); err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("validateGroups(%+v) = %v, want %q", tc.groups, err, tc.want)
+		}
+	}
+	if err := validateGroups([]repoGroup{{Name: "web", Repos: []string{"acme/web*"}, Gate: &groupGate{FailOn: "warning"}}}); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+var (
+	historyFile    = flag.String("history-file", "", "keep past runs in this JSON file; shorthand for the file storage driver")
+	smoothingAlpha = flag.Float64("smoothing-alpha", 0, "weight of the newest run in an exponential moving average of each repo's score, 0-1 (0 disables; needs -history-file)")
+)
+
+// history is the state carried from one run to the next, saved with each
+// stored run. Repos are keyed by lower-cased owner/name.
+type history struct {
+	Repos map[string]*repoHistory `json:"repos"`
+	// Policies counts the runs each policy has spent in observe mode.
+	Policies map[string]int `json:"policies,omitempty"`
+}
+
+type repoHistory struct {
+	Status        string    `json:"status"`
+	HealthScore   int       `json:"healthScore"`
+	SmoothedScore *float64  `json:"smoothedScore,omitempty"`
+	CriticalRuns  int       `json:"criticalRuns"`
+	UpdatedAt     time.Time `json:"updatedAt"`
+	// Stale lists the repo's stale issue numbers, to spot resurrections.
+	Stale []int `json:"stale,omitempty"`
+}
+
+// record folds this run's results into h and sets each repo's CriticalRuns.
+// A repo that failed to scan or wasn't scored keeps its previous state; any non-critical
+// result resets the streak. It returns the repos whose status changed since
+// the previous run; repos seen for the first time aren't changes.
+//
+// With alpha > 0 each repo also gets a SmoothedScore, an exponential moving
+// average seeded with the first raw score, and its status is classified from
+// that instead, so a repo hovering at a threshold doesn't flap. An overdue
+// security issue still forces critical.
+func (h *history) record(repos []repoScore, alpha float64, now time.Time) []statusChange {
+	var changes []statusChange
+	for i := range repos {
+		rs := &repos[i]
+		key := strings.ToLower(rs.fullName)
+		prev := h.Repos[key]
+		if rs.Error != "" || rs.Status == unscored {
+			if prev != nil {
+				rs.CriticalRuns = prev.CriticalRuns
+			}
+			continue
+		}
+		var smoothed *float64
+		if alpha > 0 {
+			ema := float64(rs.HealthScore)
+			if prev != nil && prev.SmoothedScore != nil {
+				ema = alpha*ema + (1-alpha)**prev.SmoothedScore
+			}
+			smoothed = &ema
+			reported := math.Round(ema*10) / 10
+			rs.SmoothedScore = &reported
+			if rs.SecurityOverdueCount == 0 {
+				rs.Status = statusFor(ema)
+				if b := rs.ScoreBreakdown; b != nil && rs.Status != statusFor(float64(rs.HealthScore)) {
+					b.Override = fmt.Sprintf("%s: status follows the smoothed score %.1f", rs.Status, reported)
+				}
+			}
+		}
+		runs := 0
+		if rs.Status == "critical" {
+			runs = 1
+			if prev != nil {
+				runs = prev.CriticalRuns + 1
+			}
+		}
+		rs.CriticalRuns = runs
+		if prev != nil && prev.Status != rs.Status {
+			changes = append(changes, statusChange{Repo: rs.fullName, From: prev.Status, To: rs.Status, HealthScore: rs.HealthScore, Exempt: rs.Exemption != nil})
+		}
+		stale := rs.staleIssues
+		if rs.reused && prev != nil {
+			stale = prev.Stale
+		}
+		h.Repos[key] = &repoHistory{Status: rs.Status, HealthScore: rs.HealthScore, SmoothedScore: smoothed, CriticalRuns: runs, UpdatedAt: now.UTC(), Stale: stale}
+	}
+	return changes
+}
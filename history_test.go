@@ -0,0 +1,67 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestHistoryRecord(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.json")
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	runs := [][]repoScore{
+		{{fullName: "org/a", Status: "critical"}, {fullName: "org/b", Status: "critical"}},
+		{{fullName: "org/a", Status: "critical"}, {fullName: "org/b", Status: "warning"}},
+		{{fullName: "Org/A", Error: "HTTP 502"}, {fullName: "org/b", Status: "critical"}},
+		{{fullName: "org/a", Status: "critical"}, {fullName: "org/b", Status: "critical"}},
+	}
+	var last []repoScore
+	var changes [][]statusChange
+	store := &fileStorage{path: path}
+	for i, repos := range runs {
+		h, err := latestHistory(store)
+		if err != nil {
+			t.Fatal(err)
+		}
+		at := now.AddDate(0, 0, i)
+		changes = append(changes, h.record(repos, 0, at))
+		if err := store.SaveRun(newRun(output{Repos: repos}, h, at)); err != nil {
+			t.Fatal(err)
+		}
+		last = repos
+	}
+	// a: critical, critical, (error keeps 2), critical → 3. b resets on warning.
+	if last[0].CriticalRuns != 3 || last[1].CriticalRuns != 2 {
+		t.Errorf("critical runs = %d, %d; want 3, 2", last[0].CriticalRuns, last[1].CriticalRuns)
+	}
+	if len(changes[0]) != 0 || len(changes[1]) != 1 || changes[1][0].To != "warning" || len(changes[2]) != 1 || changes[2][0].To != "critical" || len(changes[3]) != 0 {
+		t.Errorf("changes = %+v", changes)
+	}
+}
+
+func TestHistorySmoothing(t *testing.T) {
+	h := &history{Repos: map[string]*repoHistory{}}
+	now := time.Now()
+	var got []string
+	// A repo bouncing across the warning/critical line at 40.
+	for _, score := range []int{50, 35, 50, 35, 35, 35} {
+		repos := []repoScore{{fullName: "org/a", HealthScore: score, Status: statusFor(float64(score))}}
+		h.record(repos, 0.3, now)
+		got = append(got, repos[0].Status)
+	}
+	want := []string{"warning", "warning", "warning", "warning", "warning", "critical"}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("statuses = %v, want %v", got, want)
+		}
+	}
+	if s := h.Repos["org/a"].SmoothedScore; s == nil || *s >= 40 {
+		t.Errorf("smoothed = %v", s)
+	}
+
+	sec := []repoScore{{fullName: "org/b", HealthScore: 100, Status: "critical", SecurityOverdueCount: 1}}
+	h.record(sec, 0.3, now)
+	if sec[0].Status != "critical" {
+		t.Error("security override must survive smoothing")
+	}
+}
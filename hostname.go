@@ -0,0 +1,78 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+)
+
+var hostnameFlag = flag.String("hostname", "", "GitHub Enterprise Server or self-managed GitLab host to scan, e.g. github.acme.com; defaults to $GH_HOST (or $GITLAB_HOST with -provider gitlab), else github.com or gitlab.com")
+
+// hostEnv is the variable the provider's CLI reads its host from, and the
+// host it uses without one.
+func hostEnv() (env, fallback string) {
+	if *providerFlag == "gitlab" {
+		return "GITLAB_HOST", "gitlab.com"
+	}
+	return "GH_HOST", "github.com"
+}
+
+// forgeHost is the host of the GitHub, GitLab or other instance being
+// scanned.
+func forgeHost() string {
+	if base := providerURL(); base != "" {
+		if u, err := url.Parse(base); err == nil {
+			return u.Host
+		}
+	}
+	if *hostnameFlag != "" {
+		return *hostnameFlag
+	}
+	env, fallback := hostEnv()
+	if h := os.Getenv(env); h != "" {
+		return h
+	}
+	return fallback
+}
+
+// applyHostname checks -hostname is a bare host and exports it as GH_HOST,
+// which every gh call inherits: gh api then goes to the host's /api/v3 and
+// /api/graphql, and -R owner/repo resolves there, with the host's own
+// credentials from gh auth login --hostname or GH_ENTERPRISE_TOKEN. With
+// -provider gitlab it is exported as GITLAB_HOST for glab instead.
+func applyHostname() error {
+	h := *hostnameFlag
+	if h == "" {
+		return nil
+	}
+	if strings.Contains(h, "://") || strings.ContainsAny(h, "/ ") {
+		return fmt.Errorf("-hostname must be a bare host like github.acme.com, got %q", h)
+	}
+	env, _ := hostEnv()
+	return os.Setenv(env, h)
+}
+
+// webURL links to path on the scanned instance's web UI.
+func webURL(path string) string {
+	if base := providerURL(); base != "" {
+		return strings.TrimSuffix(base, "/") + "/" + path
+	}
+	return "https://" + forgeHost() + "/" + path
+}
+
+// issuesURL links to the issue list of the repo named fullName.
+func issuesURL(fullName string) string {
+	switch *providerFlag {
+	case "gitlab":
+		return webURL(fullName + "/-/issues")
+	case "jira":
+		_, key := splitRepo("", fullName)
+		return webURL("browse/" + key)
+	case "azure":
+		org, project := splitRepo("", fullName)
+		return webURL(url.PathEscape(org) + "/" + url.PathEscape(project) + "/_workitems")
+	}
+	return webURL(fullName + "/issues")
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"os"
+	"slices"
+	"testing"
+)
+
+func TestGithubHost(t *testing.T) {
+	prev := *hostnameFlag
+	t.Cleanup(func() { *hostnameFlag = prev })
+
+	*hostnameFlag = ""
+	t.Setenv("GH_HOST", "")
+	if h := forgeHost(); h != "github.com" {
+		t.Errorf("default host = %s", h)
+	}
+	t.Setenv("GH_HOST", "github.acme.com")
+	if u := webURL("acme/api/issues"); u != "https://github.acme.com/acme/api/issues" {
+		t.Errorf("GH_HOST url = %s", u)
+	}
+
+	*hostnameFlag = "ghe.acme.com"
+	if err := applyHostname(); err != nil {
+		t.Fatal(err)
+	}
+	if h := os.Getenv("GH_HOST"); h != "ghe.acme.com" || forgeHost() != h {
+		t.Errorf("-hostname exported GH_HOST=%s, host %s", h, forgeHost())
+	}
+	for _, bad := range []string{"https://ghe.acme.com", "ghe.acme.com/api/v3"} {
+		*hostnameFlag = bad
+		if err := applyHostname(); err == nil {
+			t.Errorf("-hostname %s should fail", bad)
+		}
+	}
+}
+
+func TestExtractLinksOnEnterpriseHost(t *testing.T) {
+	prev := *hostnameFlag
+	t.Cleanup(func() { *hostnameFlag = prev })
+	*hostnameFlag = "github.acme.com"
+
+	body := "See https://github.acme.com/org/app/issues/3 and https://github.com/org/app/issues/4"
+	got := extractLinks(body, "org", "app")
+	want := []string{"org/app#3", "https://github.com/org/app/issues/4"}
+	if !slices.Equal(got, want) {
+		t.Errorf("extractLinks = %q, want %q", got, want)
+	}
+}
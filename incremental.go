@@ -0,0 +1,225 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var sinceLastRun = flag.Bool("since-last-run", false, "rescan only repos with pushes or issue activity since the last stored run and reuse its scores for the rest (needs -history-file or storage)")
+
+// deltaSearchLimit caps the org-wide search for recently updated issues. A
+// search that hits it can't tell which repos it missed, so every repo of
+// that owner counts as changed.
+const deltaSearchLimit = 1000
+
+// incrementalInfo says how much of a -since-last-run report was rescanned.
+type incrementalInfo struct {
+	// Since is when the run whose scores were reused was generated.
+	Since     string `json:"since"`
+	Rescanned int    `json:"rescanned"`
+	Reused    int    `json:"reused"`
+}
+
+// repoActivity records which repos changed since a point in time.
+type repoActivity struct {
+	changed  map[string]string // lowercased owner/name -> owner/name
+	listed   map[string]bool   // repos the owner listings covered
+	overflow map[string]bool   // lowercased owners whose issue search hit its cap
+}
+
+// changedRepo reports whether full may have changed: it was pushed to, had
+// an issue updated, or can't be vouched for because the listing or the
+// search didn't cover it.
+func (a repoActivity) changedRepo(full string) bool {
+	key := strings.ToLower(full)
+	owner, _ := splitRepo("", key)
+	return a.changed[key] != "" || !a.listed[key] || a.overflow[owner]
+}
+
+// recentActivity lists each owner's repos with their push and update times
+// and searches for their issues updated since, two calls per owner however
+// many repos they have.
+func recentActivity(ctx context.Context, owners []string, since time.Time) (repoActivity, error) {
+	a := repoActivity{changed: map[string]string{}, listed: map[string]bool{}, overflow: map[string]bool{}}
+	for _, owner := range owners {
+		repos, err := client.ListRepoActivity(ctx, owner)
+		if err != nil {
+			return a, err
+		}
+		for _, r := range repos {
+			full := owner + "/" + r.Name
+			a.listed[strings.ToLower(full)] = true
+			if r.PushedAt.After(since) || r.UpdatedAt.After(since) {
+				a.changed[strings.ToLower(full)] = full
+			}
+		}
+		hits, complete, err := ghChangedRepos(ctx, owner, since)
+		if err != nil {
+			return a, err
+		}
+		if !complete {
+			a.overflow[strings.ToLower(owner)] = true
+		}
+		for _, full := range hits {
+			a.changed[strings.ToLower(full)] = full
+		}
+	}
+	return a, nil
+}
+
+// ownersOf returns the distinct owners of full repo names, in first-seen
+// order.
+func ownersOf(repos []string) []string {
+	var owners []string
+	for _, full := range repos {
+		owner, _ := splitRepo("", full)
+		if !slices.ContainsFunc(owners, func(o string) bool { return strings.EqualFold(o, owner) }) {
+			owners = append(owners, owner)
+		}
+	}
+	return owners
+}
+
+// planIncremental splits repos into those to rescan and scores reused from
+// the newest stored run. Without a usable previous run, or if its config
+// differs, everything is rescanned and info is nil.
+func planIncremental(ctx context.Context, store Storage, repos []string, cfg config) (rescan []string, reused []repoScore, info *incrementalInfo) {
+	prev, err := latestRun(store)
+	if err != nil || prev == nil {
+		if err != nil {
+			slog.Error("failed to load the previous run; scanning everything", "driver", cfg.Storage.Driver, "error", err)
+		} else {
+			slog.Info("no previous run; scanning everything")
+		}
+		return repos, nil, nil
+	}
+	if !sameConfig(prev.Report.Config, cfg) {
+		slog.Info("config changed since the previous run; scanning everything", "previous_run", prev.ID)
+		return repos, nil, nil
+	}
+	activity, err := recentActivity(ctx, ownersOf(repos), prev.GeneratedAt)
+	if err != nil {
+		slog.Warn("activity check failed; scanning everything", "error", err)
+		return repos, nil, nil
+	}
+
+	byName := map[string]repoScore{}
+	for _, rs := range prev.Report.Repos {
+		byName[strings.ToLower(prevFullName(rs, prev.Report.Org))] = rs
+	}
+	since := prev.GeneratedAt.UTC().Format(time.RFC3339)
+	for _, full := range repos {
+		rs, ok := byName[strings.ToLower(full)]
+		if !ok || rs.Error != "" || activity.changedRepo(full) {
+			rescan = append(rescan, full)
+			continue
+		}
+		reused = append(reused, reuseScore(rs, full, prev.Report, since))
+	}
+	slog.Info("incremental scan", "previous_run", prev.ID, "rescan", len(rescan), "reused", len(reused))
+	return rescan, reused, &incrementalInfo{Since: since, Rescanned: len(rescan), Reused: len(reused)}
+}
+
+// latestRun returns the newest stored run, or nil if there is none.
+func latestRun(s Storage) (*storedRun, error) {
+	runs, err := s.ListRuns()
+	if err != nil || len(runs) == 0 {
+		return nil, err
+	}
+	r, err := s.GetRun(runs[0].ID)
+	if err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+// sameConfig reports whether a stored report was made with the settings
+// cfg would use, so its scores can stand in for a fresh scan.
+func sameConfig(stored, cfg config) bool {
+	a, errA := json.Marshal(stored)
+	b, errB := json.Marshal(cfg)
+	return errA == nil && errB == nil && string(a) == string(b)
+}
+
+// prevFullName recovers a stored repo's owner/name: reports name repos by
+// their bare name when the owner is the report's org.
+func prevFullName(rs repoScore, org string) string {
+	if strings.Contains(rs.Name, "/") {
+		return rs.Name
+	}
+	return org + "/" + rs.Name
+}
+
+// reuseScore restores a stored score with the parts of prev that were kept
+// at the report level, and dates it with the run it came from.
+func reuseScore(rs repoScore, full string, prev output, since string) repoScore {
+	rs.fullName = full
+	rs.reused = true
+	rs.CriticalRuns, rs.SmoothedScore, rs.Resurrections = 0, nil, nil
+	for _, s := range prev.SecurityBacklog {
+		if s.Repo == rs.Name {
+			rs.securityOverdue = append(rs.securityOverdue, s)
+		}
+	}
+	for _, w := range prev.MostWanted {
+		if w.Repo == rs.Name {
+			rs.wanted = append(rs.wanted, w)
+		}
+	}
+	if rs.Freshness == nil {
+		rs.Freshness = &freshness{Core: since}
+		if prev.Config.Deep {
+			rs.Freshness.Deep = since
+		}
+	}
+	return rs
+}
+
+// stampFresh dates the repos an incremental run rescanned.
+func stampFresh(repos []repoScore, deep bool, now time.Time) {
+	stamp := now.UTC().Format(time.RFC3339)
+	for i := range repos {
+		if !repos[i].reused {
+			repos[i].Freshness = &freshness{Core: stamp}
+			if deep {
+				repos[i].Freshness.Deep = stamp
+			}
+		}
+	}
+}
+
+// ghChangedRepos lists repos in org with issues updated since the given
+// time, using a single org-wide search. complete is false when the search
+// hit its cap.
+func ghChangedRepos(ctx context.Context, org string, since time.Time) (repos []string, complete bool, err error) {
+	args := []string{"search", "issues", "--owner", org,
+		"--updated", ">=" + since.UTC().Format(time.RFC3339),
+		"--json", "repository", "--limit", strconv.Itoa(deltaSearchLimit)}
+	stdout, err := runCmd(ctx, "gh", args...)
+	if err != nil {
+		return nil, false, err
+	}
+	var hits []struct {
+		Repository struct {
+			NameWithOwner string `json:"nameWithOwner"`
+		} `json:"repository"`
+	}
+	if err := json.Unmarshal(stdout, &hits); err != nil {
+		return nil, false, fmt.Errorf("parse gh search issues json: %w", err)
+	}
+	seen := map[string]bool{}
+	for _, h := range hits {
+		if name := h.Repository.NameWithOwner; name != "" && !seen[name] {
+			seen[name] = true
+			repos = append(repos, name)
+		}
+	}
+	return repos, len(hits) < deltaSearchLimit, nil
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestSinceLastRunReusesUntouchedRepos(t *testing.T) {
+	gh := newFakeGitHub(t)
+	genFixture(gh, "acme", 11, 3, 6, time.Now())
+	cfg := config{MinIssues: 1, StaleDays: 90, MostWanted: 5, Storage: &storageConfig{Driver: "memory"}}
+	store := openStorage(cfg.Storage)
+	repos := []string{"acme/repo-00", "acme/repo-01", "acme/repo-02"}
+	sel := selection{Orgs: []string{"acme"}}
+
+	prevAt := time.Now()
+	first := scan(t.Context(), sel, repos, cfg)
+	h, _ := latestHistory(store)
+	if err := store.SaveRun(newRun(first, h, prevAt)); err != nil {
+		t.Fatal(err)
+	}
+
+	// Nothing changed yet: everything is reused and the report is the same.
+	rescan, reused, _ := planIncremental(t.Context(), store, repos, cfg)
+	if len(rescan) != 0 || len(reused) != 3 {
+		t.Fatalf("untouched org: rescan %v, reused %d", rescan, len(reused))
+	}
+	if same := scanReusing(t.Context(), sel, rescan, reused, cfg); contentHash(same) != contentHash(first) {
+		t.Error("reusing every score should reproduce the previous report")
+	}
+
+	// An issue update in repo-01 and a push to repo-02 since the last run.
+	later := prevAt.Add(time.Minute)
+	gh.mu.Lock()
+	gh.repos["acme/repo-01"].Issues[0].UpdatedAt = later
+	gh.repos["acme/repo-02"].PushedAt = later
+	gh.mu.Unlock()
+
+	rescan, reused, info := planIncremental(t.Context(), store, repos, cfg)
+	if !slices.Equal(rescan, []string{"acme/repo-01", "acme/repo-02"}) || len(reused) != 1 || reused[0].Name != "repo-00" {
+		t.Fatalf("rescan %v, reused %d", rescan, len(reused))
+	}
+	if info == nil || info.Rescanned != 2 || info.Reused != 1 || info.Since != prevAt.UTC().Format(time.RFC3339) {
+		t.Errorf("info = %+v", info)
+	}
+
+	out := scanReusing(t.Context(), sel, rescan, reused, cfg)
+	stampFresh(out.Repos, false, later)
+	if len(out.Repos) != 3 || out.Summary.Total != 3 {
+		t.Fatalf("report covers %d repos", len(out.Repos))
+	}
+	for _, rs := range out.Repos {
+		want := later.UTC().Format(time.RFC3339)
+		if rs.Name == "repo-00" {
+			want = info.Since
+		}
+		if rs.Freshness == nil || rs.Freshness.Core != want {
+			t.Errorf("%s freshness = %+v, want core %s", rs.Name, rs.Freshness, want)
+		}
+	}
+	// Reused scores keep their recommendations rather than losing the ones
+	// that need the issues.
+	i := slices.IndexFunc(first.Repos, func(rs repoScore) bool { return rs.Name == "repo-00" })
+	j := slices.IndexFunc(out.Repos, func(rs repoScore) bool { return rs.Name == "repo-00" })
+	if !slices.Equal(first.Repos[i].Recommendations, out.Repos[j].Recommendations) {
+		t.Errorf("recommendations = %v, were %v", out.Repos[j].Recommendations, first.Repos[i].Recommendations)
+	}
+}
+
+func TestSinceLastRunFallsBackToFullScan(t *testing.T) {
+	newFakeGitHub(t)
+	cfg := config{MinIssues: 1, StaleDays: 90, Storage: &storageConfig{Driver: "memory"}}
+	store := openStorage(cfg.Storage)
+	repos := []string{"acme/api"}
+
+	if rescan, reused, info := planIncremental(t.Context(), store, repos, cfg); len(rescan) != 1 || reused != nil || info != nil {
+		t.Errorf("without a previous run: rescan %v, reused %v, info %v", rescan, reused, info)
+	}
+
+	prev := output{Org: "acme", Config: cfg, Repos: []repoScore{{Name: "api", Status: "healthy"}}}
+	if err := store.SaveRun(newRun(prev, &history{}, time.Now())); err != nil {
+		t.Fatal(err)
+	}
+	changed := cfg
+	changed.StaleDays = 30
+	if rescan, reused, info := planIncremental(t.Context(), store, repos, changed); len(rescan) != 1 || reused != nil || info != nil {
+		t.Errorf("after a config change: rescan %v, reused %v, info %v", rescan, reused, info)
+	}
+}
@@ -0,0 +1,146 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// intakePolicy is what a newly filed issue should contain. Sections are
+// headings the issue templates produce; each must be present and filled
+// in. Reproduction, a case-insensitive regular expression, must match the
+// body of issues carrying one of ReproductionLabels (any issue if empty).
+type intakePolicy struct {
+	RequireLabel       bool     `json:"requireLabel,omitempty"`
+	Sections           []string `json:"sections,omitempty"`
+	MinBodyLength      int      `json:"minBodyLength,omitempty"`
+	Reproduction       string   `json:"reproduction,omitempty"`
+	ReproductionLabels []string `json:"reproductionLabels,omitempty"`
+}
+
+func (p *intakePolicy) validate() error {
+	if _, err := regexp.Compile("(?i)" + p.Reproduction); err != nil {
+		return fmt.Errorf("intake: reproduction: %w", err)
+	}
+	return nil
+}
+
+var errCheckUsage = errors.New("usage: fab-backlog check issue owner/repo#NUMBER")
+
+var headingRe = regexp.MustCompile(`^\s*(?:#{1,6}\s+(.+?)\s*#*|\*\*(.+?)\*\*:?)\s*$`)
+
+// bodySections splits a markdown body by heading, keyed by lowercased
+// heading text. Issue forms fill skipped fields with "_No response_",
+// which counts as empty.
+func bodySections(body string) map[string]string {
+	sections := map[string]string{}
+	var current string
+	var content []string
+	flush := func() {
+		if current != "" {
+			text := strings.TrimSpace(strings.Join(content, "\n"))
+			if text == "_No response_" {
+				text = ""
+			}
+			sections[current] = text
+		}
+	}
+	for _, line := range strings.Split(body, "\n") {
+		if m := headingRe.FindStringSubmatch(line); m != nil {
+			flush()
+			current, content = strings.ToLower(strings.TrimSpace(m[1]+m[2])), nil
+			continue
+		}
+		content = append(content, line)
+	}
+	flush()
+	return sections
+}
+
+// checkIntake returns the ways is falls short of p; none means it passes.
+func checkIntake(is issue, p intakePolicy, cfg config) []string {
+	var reasons []string
+	if p.RequireLabel && len(is.Labels) == 0 {
+		reasons = append(reasons, "no labels")
+	}
+	if body := strings.TrimSpace(is.Body); len(body) < p.MinBodyLength {
+		reasons = append(reasons, fmt.Sprintf("description is shorter than %d characters", p.MinBodyLength))
+	}
+	sections := bodySections(is.Body)
+	for _, want := range p.Sections {
+		text, ok := sections[strings.ToLower(want)]
+		switch {
+		case !ok:
+			reasons = append(reasons, fmt.Sprintf("missing section %q", want))
+		case text == "":
+			reasons = append(reasons, fmt.Sprintf("section %q is empty", want))
+		}
+	}
+	if p.Reproduction != "" && (len(p.ReproductionLabels) == 0 || cfg.labelMatcher(p.ReproductionLabels...).any(is)) {
+		if !regexp.MustCompile("(?i)" + p.Reproduction).MatchString(is.Body) {
+			reasons = append(reasons, "no reproduction steps or example")
+		}
+	}
+	return reasons
+}
+
+// parseIssueRef accepts owner/repo#N, or repo#N resolved against org.
+func parseIssueRef(org, ref string) (owner, repo string, number int, err error) {
+	r, n, ok := strings.Cut(ref, "#")
+	if number, err = strconv.Atoi(n); !ok || err != nil || number <= 0 {
+		return "", "", 0, errCheckUsage
+	}
+	if owner, repo = splitRepo(org, r); owner == "" || repo == "" {
+		return "", "", 0, errCheckUsage
+	}
+	return owner, repo, number, nil
+}
+
+func ghViewIssue(ctx context.Context, owner, repo string, number int) (issue, error) {
+	var is issue
+	stdout, err := runCmd(ctx, "gh", "issue", "view", strconv.Itoa(number), "--repo", owner+"/"+repo, "--json", "number,title,body,labels,createdAt,updatedAt")
+	if err != nil {
+		return is, err
+	}
+	if err := json.Unmarshal(stdout, &is); err != nil {
+		return is, fmt.Errorf("parse gh issue view json: %w", err)
+	}
+	return is, nil
+}
+
+// runCheck implements `fab-backlog check issue owner/repo#N`. It exits
+// with exitGateFailed when the issue falls short of the intake policy.
+func runCheck(ctx context.Context, sel selection, args []string, cfg config) int {
+	fail := func(code int, err error) int {
+		slog.Error("check failed", "error", err)
+		emitJSON(map[string]any{"ok": false, "error": err.Error()})
+		return code
+	}
+	if len(args) != 2 || args[0] != "issue" {
+		return fail(2, errCheckUsage)
+	}
+	if cfg.Intake == nil {
+		return fail(2, errors.New("check issue requires an intake policy in -config"))
+	}
+	owner, repo, number, err := parseIssueRef(sel.primaryOrg(), args[1])
+	if err != nil {
+		return fail(2, err)
+	}
+	is, err := ghViewIssue(ctx, owner, repo, number)
+	if err != nil {
+		return fail(1, err)
+	}
+	reasons := checkIntake(is, *cfg.Intake, cfg)
+	ref := fmt.Sprintf("%s/%s#%d", owner, repo, number)
+	emitJSON(map[string]any{"ok": true, "issue": ref, "title": is.Title, "passed": len(reasons) == 0, "reasons": append([]string{}, reasons...)})
+	if len(reasons) > 0 {
+		slog.Error("issue does not meet the intake policy", "issue", ref, "reasons", reasons)
+		return exitGateFailed
+	}
+	return 0
+}
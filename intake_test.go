@@ -0,0 +1,76 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+const intakeBody = `### Describe the bug
+
+It crashes.
+
+### Steps to reproduce
+
+_No response_
+
+**Version**
+1.2.3
+`
+
+func TestBodySections(t *testing.T) {
+	got := bodySections(intakeBody)
+	want := map[string]string{"describe the bug": "It crashes.", "steps to reproduce": "", "version": "1.2.3"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("sections = %q", got)
+	}
+}
+
+func TestCheckIntake(t *testing.T) {
+	p := intakePolicy{
+		RequireLabel:       true,
+		Sections:           []string{"Describe the bug", "Steps to reproduce", "Expected behavior"},
+		MinBodyLength:      10,
+		Reproduction:       "(?m)```|^\\s*1\\.\\s",
+		ReproductionLabels: []string{"bug"},
+	}
+	is := issue{Body: intakeBody, Labels: []label{{Name: "bug"}}}
+	got := checkIntake(is, p, config{})
+	want := []string{`section "Steps to reproduce" is empty`, `missing section "Expected behavior"`}
+	if len(got) != 3 || got[0] != want[0] || got[1] != want[1] || got[2] != "no reproduction steps or example" {
+		t.Errorf("reasons = %q", got)
+	}
+	// Reproduction is only required of bugs.
+	is.Labels = []label{{Name: "feature"}}
+	if got := checkIntake(is, p, config{}); len(got) != 2 {
+		t.Errorf("feature reasons = %q", got)
+	}
+	if got := checkIntake(issue{Body: "short"}, intakePolicy{RequireLabel: true, MinBodyLength: 10}, config{}); len(got) != 2 {
+		t.Errorf("bare reasons = %q", got)
+	}
+}
+
+func TestParseIssueRef(t *testing.T) {
+	if o, r, n, err := parseIssueRef("acme", "api#12"); err != nil || o != "acme" || r != "api" || n != 12 {
+		t.Errorf("api#12 = %s %s %d %v", o, r, n, err)
+	}
+	if o, r, n, err := parseIssueRef("", "x/y#3"); err != nil || o != "x" || r != "y" || n != 3 {
+		t.Errorf("x/y#3 = %s %s %d %v", o, r, n, err)
+	}
+	for _, bad := range []string{"api", "api#x", "api#0", "#4"} {
+		if _, _, _, err := parseIssueRef("", bad); err == nil {
+			t.Errorf("%q should be rejected", bad)
+		}
+	}
+}
+
+func TestViewIssueAgainstFake(t *testing.T) {
+	gh := newFakeGitHub(t)
+	gh.addRepo(&fakeRepo{Owner: "acme", Name: "api", Issues: []*fakeIssue{{Number: 7, State: "open", Title: "crash", Body: intakeBody, Labels: []string{"bug"}}}})
+	is, err := ghViewIssue(t.Context(), "acme", "api", 7)
+	if err != nil || is.Title != "crash" || is.Body != intakeBody || len(is.Labels) != 1 {
+		t.Fatalf("issue = %+v, %v", is, err)
+	}
+	if _, err := ghViewIssue(t.Context(), "acme", "api", 8); err == nil {
+		t.Error("missing issue should fail")
+	}
+}
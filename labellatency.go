@@ -0,0 +1,127 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log/slog"
+	"time"
+
+	ghscan "github.com/misty-step/fab-backlog/pkg/scan"
+)
+
+var labelLatencyWindowDays = flag.Int("label-latency-window-days", 0, "measure median time to first label over issues created in the last N days (0 disables; --deep uses 90)")
+
+// labelLatencyMetrics measures triage speed: how long issues filed in the
+// window waited for their first label. It moves long before the binary
+// unlabeled count does.
+type labelLatencyMetrics struct {
+	WindowDays  int `json:"windowDays"`
+	Sampled     int `json:"sampled"`
+	Labeled     int `json:"labeled"`
+	Unlabeled   int `json:"unlabeled"`
+	MedianHours int `json:"medianHours"`
+}
+
+func (c config) labelLatencyWindow() int {
+	if c.LabelLatencyWindowDays > 0 {
+		return c.LabelLatencyWindowDays
+	}
+	if c.Deep {
+		return defaultResponseWindowDays
+	}
+	return 0
+}
+
+// firstLabelQuery pages through a repo's recent issues with only their first
+// LabeledEvent, so a hundred issues cost one call instead of a hundred
+// timeline requests.
+const firstLabelQuery = `query($q: String!, $endCursor: String) {
+  search(query: $q, type: ISSUE, first: 100, after: $endCursor) {
+    pageInfo { hasNextPage endCursor }
+    nodes {
+      ... on Issue {
+        createdAt
+        timelineItems(itemTypes: [LABELED_EVENT], first: 1) {
+          nodes { ... on LabeledEvent { createdAt } }
+        }
+      }
+    }
+  }
+}`
+
+// labelTiming is when an issue was created and first labeled (zero if never).
+type labelTiming struct {
+	CreatedAt      time.Time
+	FirstLabeledAt time.Time
+}
+
+type firstLabelPage struct {
+	Search struct {
+		Nodes []*struct {
+			CreatedAt     time.Time `json:"createdAt"`
+			TimelineItems *struct {
+				Nodes []struct {
+					CreatedAt time.Time `json:"createdAt"`
+				} `json:"nodes"`
+			} `json:"timelineItems"`
+		} `json:"nodes"`
+	} `json:"search"`
+}
+
+// ghFirstLabelTimings skips issues whose timeline didn't come back rather
+// than count them as never labeled.
+func ghFirstLabelTimings(ctx context.Context, owner, repo string, since time.Time) ([]labelTiming, []ghscan.GraphQLError, error) {
+	q := fmt.Sprintf("repo:%s/%s is:issue created:>=%s", owner, repo, since.Format("2006-01-02"))
+	pages, errs, err := ghscan.GraphQL[firstLabelPage](ctx, runCmd, firstLabelQuery, map[string]string{"q": q})
+	if err != nil {
+		return nil, errs, err
+	}
+	var timings []labelTiming
+	for _, page := range pages {
+		for _, n := range page.Search.Nodes {
+			if n == nil || n.TimelineItems == nil {
+				continue
+			}
+			t := labelTiming{CreatedAt: n.CreatedAt}
+			if len(n.TimelineItems.Nodes) > 0 {
+				t.FirstLabeledAt = n.TimelineItems.Nodes[0].CreatedAt
+			}
+			timings = append(timings, t)
+		}
+	}
+	return timings, errs, nil
+}
+
+func computeLabelLatency(timings []labelTiming, windowDays int) *labelLatencyMetrics {
+	lm := &labelLatencyMetrics{WindowDays: windowDays, Sampled: len(timings)}
+	var hours []int
+	for _, t := range timings {
+		if t.FirstLabeledAt.IsZero() {
+			lm.Unlabeled++
+			continue
+		}
+		lm.Labeled++
+		h := int(t.FirstLabeledAt.Sub(t.CreatedAt).Hours())
+		if h < 0 {
+			h = 0
+		}
+		hours = append(hours, h)
+	}
+	lm.MedianHours = median(hours)
+	return lm
+}
+
+func addLabelLatency(ctx context.Context, score *repoScore, owner, repo string, cfg config, now time.Time) {
+	window := cfg.labelLatencyWindow()
+	if window <= 0 {
+		return
+	}
+	timings, errs, err := ghFirstLabelTimings(ctx, owner, repo, now.AddDate(0, 0, -window))
+	if err != nil {
+		slog.Warn("first-label latency unavailable", "repo", repo, "error", err)
+		return
+	}
+	addAPIErrors(score, "firstLabel", errs)
+	score.FirstLabel = computeLabelLatency(timings, window)
+}
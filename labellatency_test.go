@@ -0,0 +1,20 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestComputeLabelLatency(t *testing.T) {
+	t0 := time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC)
+	timings := []labelTiming{
+		{CreatedAt: t0, FirstLabeledAt: t0},                     // labeled by the issue template
+		{CreatedAt: t0, FirstLabeledAt: t0.Add(5 * time.Hour)},  // triaged same day
+		{CreatedAt: t0, FirstLabeledAt: t0.Add(72 * time.Hour)}, // triaged after a weekend
+		{CreatedAt: t0}, // never labeled
+	}
+	lm := computeLabelLatency(timings, 90)
+	if lm.Sampled != 4 || lm.Labeled != 3 || lm.Unlabeled != 1 || lm.MedianHours != 5 {
+		t.Errorf("got %+v, want 4 sampled, 3 labeled, median 5h", lm)
+	}
+}
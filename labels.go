@@ -0,0 +1,118 @@
+package main
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// labelMatcher matches label names against the patterns used throughout
+// the config. A pattern is one of:
+//
+//	bug          exact name, case-insensitive
+//	prio:*       glob (path.Match syntax), case-insensitive
+//	/^p[0-2]$/   regular expression, case-insensitive
+//
+// A pattern naming an alias group, or any member of one, matches the whole
+// group, so "P1" and "prio:high" can be one priority tier across repos with
+// different taxonomies.
+type labelMatcher struct {
+	exact map[string]bool
+	globs []string
+	res   []*regexp.Regexp
+}
+
+func (c config) labelMatcher(patterns ...string) labelMatcher {
+	m := labelMatcher{exact: map[string]bool{}}
+	for _, p := range expandAliases(patterns, c.LabelAliases) {
+		switch kind, body := patternKind(p); kind {
+		case "regexp":
+			if re, err := regexp.Compile("(?i)" + body); err == nil {
+				m.res = append(m.res, re)
+			}
+		case "glob":
+			m.globs = append(m.globs, strings.ToLower(body))
+		default:
+			m.exact[strings.ToLower(body)] = true
+		}
+	}
+	return m
+}
+
+func (m labelMatcher) match(name string) bool {
+	name = strings.ToLower(name)
+	if m.exact[name] {
+		return true
+	}
+	for _, g := range m.globs {
+		if ok, _ := path.Match(g, name); ok {
+			return true
+		}
+	}
+	for _, re := range m.res {
+		if re.MatchString(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// any reports whether is carries a matching label.
+func (m labelMatcher) any(is issue) bool {
+	for _, l := range is.Labels {
+		if m.match(l.Name) {
+			return true
+		}
+	}
+	return false
+}
+
+func patternKind(p string) (kind, body string) {
+	if len(p) >= 2 && strings.HasPrefix(p, "/") && strings.HasSuffix(p, "/") {
+		return "regexp", p[1 : len(p)-1]
+	}
+	if strings.ContainsAny(p, "*?[") {
+		return "glob", p
+	}
+	return "exact", p
+}
+
+// expandAliases adds every member of each alias group a pattern names.
+func expandAliases(patterns []string, aliases map[string][]string) []string {
+	out := append([]string(nil), patterns...)
+	for group, members := range aliases {
+		all := append([]string{group}, members...)
+		if !overlaps(patterns, all) {
+			continue
+		}
+		out = append(out, all...)
+	}
+	return out
+}
+
+func overlaps(a, b []string) bool {
+	for _, x := range a {
+		for _, y := range b {
+			if strings.EqualFold(x, y) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// validatePattern rejects patterns that would silently never match.
+func validatePattern(p string) error {
+	switch kind, body := patternKind(p); kind {
+	case "regexp":
+		if _, err := regexp.Compile(body); err != nil {
+			return fmt.Errorf("label pattern %s: %w", p, err)
+		}
+	case "glob":
+		if _, err := path.Match(body, ""); err != nil {
+			return fmt.Errorf("label pattern %s: %w", p, err)
+		}
+	}
+	return nil
+}
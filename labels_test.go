@@ -0,0 +1,29 @@
+package main
+
+import "testing"
+
+func TestLabelMatcher(t *testing.T) {
+	cfg := config{LabelAliases: map[string][]string{"priority:high": {"prio:high", "P1"}}}
+	tests := []struct {
+		patterns []string
+		label    string
+		want     bool
+	}{
+		{[]string{"bug"}, "Bug", true},
+		{[]string{"bug"}, "bugfix", false},
+		{[]string{"prio:*"}, "Prio:Low", true},
+		{[]string{"/^p[0-2]$/"}, "P1", true},
+		{[]string{"/^p[0-2]$/"}, "P3", false},
+		{[]string{"P1"}, "prio:high", true},
+		{[]string{"priority:high"}, "p1", true},
+		{[]string{"P1"}, "priority:low", false},
+	}
+	for _, tt := range tests {
+		if got := cfg.labelMatcher(tt.patterns...).match(tt.label); got != tt.want {
+			t.Errorf("%v match %q = %v, want %v", tt.patterns, tt.label, got, tt.want)
+		}
+	}
+	if validatePattern("/[/") == nil || validatePattern("[") == nil {
+		t.Error("malformed regexp and glob patterns should be rejected")
+	}
+}
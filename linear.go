@@ -0,0 +1,199 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+var linearSources stringList
+
+func init() {
+	flag.Var(&linearSources, "linear", "Linear team key, or project:NAME, to score next to the repos (repeatable); the API key is read from FAB_BACKLOG_LINEAR_API_KEY")
+}
+
+// linearEndpoint is Linear's GraphQL API.
+var linearEndpoint = "https://api.linear.app/graphql"
+
+var linearClient = &http.Client{Timeout: 30 * time.Second, Transport: httpTransport}
+
+// linearSourceName marks Linear entries among the repos.
+const linearSourceName = "linear"
+
+const linearIssuesQuery = `query($filter: IssueFilter, $after: String) {
+  issues(first: 100, after: $after, filter: $filter, orderBy: createdAt) {
+    nodes {
+      number title createdAt updatedAt
+      team { key }
+      state { type }
+      labels { nodes { name } }
+      creator { name }
+      assignee { name }
+    }
+    pageInfo { hasNextPage endCursor }
+  }
+}`
+
+// validateLinear checks -linear is only given to scan, with an API key.
+// Linear is queried over HTTP, not through gh, so a run that records or
+// replays gh calls can't capture it.
+func validateLinear(cmd string) error {
+	if len(linearSources) == 0 {
+		return nil
+	}
+	if cmd != "scan" {
+		return fmt.Errorf("-linear only applies to scan")
+	}
+	if *fromFile != "" || *recordDir != "" {
+		return fmt.Errorf("-linear can't be combined with -from-file or -record-dir: Linear's API isn't recorded or replayed")
+	}
+	if os.Getenv("FAB_BACKLOG_LINEAR_API_KEY") == "" {
+		return fmt.Errorf("-linear needs an API key in FAB_BACKLOG_LINEAR_API_KEY")
+	}
+	return nil
+}
+
+// linearFilter selects the open issues of source: a team by key, or a
+// project by name. Completed and canceled issues are closed.
+func linearFilter(source string) map[string]any {
+	open := map[string]any{"type": map[string]any{"nin": []string{"completed", "canceled"}}}
+	if name, ok := strings.CutPrefix(source, "project:"); ok {
+		return map[string]any{"project": map[string]any{"name": map[string]any{"eq": name}}, "state": open}
+	}
+	return map[string]any{"team": map[string]any{"key": map[string]any{"eq": source}}, "state": open}
+}
+
+type linearIssue struct {
+	Number    int       `json:"number"`
+	Title     string    `json:"title"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+	Team      struct {
+		Key string `json:"key"`
+	} `json:"team"`
+	State struct {
+		Type string `json:"type"`
+	} `json:"state"`
+	Labels struct {
+		Nodes []label `json:"nodes"`
+	} `json:"labels"`
+	Creator  *struct{ Name string } `json:"creator"`
+	Assignee *struct{ Name string } `json:"assignee"`
+}
+
+// issue converts a Linear issue. One still in the Triage state has its
+// labels dropped, so it counts as unlabeled: untriaged.
+func (li linearIssue) issue() issue {
+	is := issue{Number: li.Number, Title: li.Title, CreatedAt: li.CreatedAt, UpdatedAt: li.UpdatedAt}
+	if li.State.Type != "triage" {
+		is.Labels = li.Labels.Nodes
+	}
+	if li.Creator != nil {
+		is.Author.Login = li.Creator.Name
+	}
+	if li.Assignee != nil {
+		is.Assignees = []actor{{Login: li.Assignee.Name}}
+	}
+	return is
+}
+
+// fetchLinearIssues returns up to limit open issues of source. Issue
+// numbers are only unique within a team, so a project whose issues come
+// from more than one team is rejected rather than scored with ENG-12 and
+// OPS-12 both as #12.
+func fetchLinearIssues(ctx context.Context, key, source string, limit int) ([]issue, error) {
+	var issues []issue
+	var team string
+	var after *string
+	for len(issues) < limit {
+		body, err := json.Marshal(map[string]any{"query": linearIssuesQuery, "variables": map[string]any{"filter": linearFilter(source), "after": after}})
+		if err != nil {
+			return nil, err
+		}
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, linearEndpoint, bytes.NewReader(body))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", key)
+		resp, err := linearClient.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		var page struct {
+			Data struct {
+				Issues struct {
+					Nodes    []linearIssue `json:"nodes"`
+					PageInfo struct {
+						HasNextPage bool   `json:"hasNextPage"`
+						EndCursor   string `json:"endCursor"`
+					} `json:"pageInfo"`
+				} `json:"issues"`
+			} `json:"data"`
+			Errors []struct {
+				Message string `json:"message"`
+			} `json:"errors"`
+		}
+		err = json.NewDecoder(resp.Body).Decode(&page)
+		resp.Body.Close()
+		switch {
+		case len(page.Errors) > 0:
+			return nil, fmt.Errorf("linear: %s", page.Errors[0].Message)
+		case resp.StatusCode != http.StatusOK:
+			return nil, fmt.Errorf("linear: %s", resp.Status)
+		case err != nil:
+			return nil, fmt.Errorf("parse linear issues: %w", err)
+		}
+		for _, li := range page.Data.Issues.Nodes {
+			if team == "" {
+				team = li.Team.Key
+			} else if li.Team.Key != team {
+				return nil, fmt.Errorf("linear: %s spans teams %s and %s, whose issue numbers overlap; score each team with -linear KEY instead", source, team, li.Team.Key)
+			}
+			issues = append(issues, li.issue())
+		}
+		info := page.Data.Issues.PageInfo
+		if !info.HasNextPage {
+			break
+		}
+		after = &info.EndCursor
+	}
+	return issues[:min(len(issues), limit)], nil
+}
+
+// scoreLinear scores each -linear source as of now with the repos' formula,
+// as an entry named "linear:SOURCE" among them. Its unlabeledCount counts
+// untriaged issues: those in the Triage state or without labels. A team key
+// or project name Linear doesn't know matches no issues, so scores empty.
+func scoreLinear(ctx context.Context, org string, cfg config, now time.Time) []repoScore {
+	key := os.Getenv("FAB_BACKLOG_LINEAR_API_KEY")
+	var scores []repoScore
+	for _, source := range linearSources {
+		if ctx.Err() != nil {
+			break
+		}
+		name := linearSourceName + ":" + source
+		slog.Info("analysing linear issues", "source", source)
+		issues, err := fetchLinearIssues(ctx, key, source, issueLimit)
+		if err != nil {
+			slog.Warn("linear analysis error", "source", source, "error", err)
+			scores = append(scores, repoScore{Name: name, Source: linearSourceName, Error: err.Error(), fullName: org + "/" + name})
+			continue
+		}
+		rs := scoreIssues(name, withoutIgnored(openAt(issues, now), cfg), cfg, now)
+		rs.Source, rs.fullName = linearSourceName, org+"/"+name
+		slog.Info("linear analysis complete", "source", source, "health_score", rs.HealthScore, "status", rs.Status, "total_open", rs.TotalOpen, "stale_count", rs.StaleCount)
+		if streamed != nil {
+			streamed(rs)
+		}
+		scores = append(scores, rs)
+	}
+	return scores
+}
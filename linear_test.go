@@ -0,0 +1,147 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeLinear serves two pages of team ENG's issues: #1 stale and labeled,
+// #2 labeled but still in Triage.
+func fakeLinear(t *testing.T) {
+	t.Helper()
+	old := time.Now().AddDate(0, 0, -200).UTC().Format(time.RFC3339)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Authorization"); got != "lin_api_test" {
+			t.Errorf("Authorization = %q", got)
+		}
+		var req struct {
+			Variables struct {
+				Filter struct {
+					Team struct{ Key struct{ Eq string } }
+				} `json:"filter"`
+				After *string `json:"after"`
+			} `json:"variables"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Error(err)
+			return
+		}
+		if key := req.Variables.Filter.Team.Key.Eq; key != "ENG" {
+			fmt.Fprint(w, `{"data":{"issues":{"nodes":[],"pageInfo":{"hasNextPage":false}}}}`)
+			return
+		}
+		if req.Variables.After == nil {
+			fmt.Fprintf(w, `{"data":{"issues":{"nodes":[{"number":1,"title":"Crash","createdAt":%q,"updatedAt":%q,"state":{"type":"unstarted"},"labels":{"nodes":[{"name":"bug"}]},"creator":{"name":"ana"}}],"pageInfo":{"hasNextPage":true,"endCursor":"c1"}}}}`, old, old)
+			return
+		}
+		if *req.Variables.After != "c1" {
+			t.Errorf("after = %q", *req.Variables.After)
+		}
+		now := time.Now().UTC().Format(time.RFC3339)
+		fmt.Fprintf(w, `{"data":{"issues":{"nodes":[{"number":2,"title":"Slow","createdAt":%q,"updatedAt":%q,"state":{"type":"triage"},"labels":{"nodes":[{"name":"perf"}]},"assignee":{"name":"bo"}}],"pageInfo":{"hasNextPage":false}}}}`, now, now)
+	}))
+	t.Cleanup(srv.Close)
+	prev := linearEndpoint
+	t.Cleanup(func() { linearEndpoint = prev })
+	linearEndpoint = srv.URL
+}
+
+func TestFetchLinearIssues(t *testing.T) {
+	fakeLinear(t)
+	issues, err := fetchLinearIssues(t.Context(), "lin_api_test", "ENG", 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(issues) != 2 {
+		t.Fatalf("issues = %+v", issues)
+	}
+	if issues[0].Author.Login != "ana" || len(issues[0].Labels) != 1 {
+		t.Errorf("#1 = %+v", issues[0])
+	}
+	if len(issues[1].Labels) != 0 || len(issues[1].Assignees) != 1 {
+		t.Errorf("#2, in Triage, = %+v", issues[1])
+	}
+	if issues, err := fetchLinearIssues(t.Context(), "lin_api_test", "ENG", 1); err != nil || len(issues) != 1 {
+		t.Errorf("limit 1 = %+v, %v", issues, err)
+	}
+}
+
+func TestFetchLinearRejectsProjectAcrossTeams(t *testing.T) {
+	now := time.Now().UTC().Format(time.RFC3339)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"data":{"issues":{"nodes":[`+
+			`{"number":12,"title":"Crash","createdAt":%[1]q,"updatedAt":%[1]q,"team":{"key":"ENG"},"state":{"type":"started"},"labels":{"nodes":[]}},`+
+			`{"number":12,"title":"Outage","createdAt":%[1]q,"updatedAt":%[1]q,"team":{"key":"OPS"},"state":{"type":"started"},"labels":{"nodes":[]}}`+
+			`],"pageInfo":{"hasNextPage":false}}}}`, now)
+	}))
+	t.Cleanup(srv.Close)
+	prev := linearEndpoint
+	t.Cleanup(func() { linearEndpoint = prev })
+	linearEndpoint = srv.URL
+
+	_, err := fetchLinearIssues(t.Context(), "lin_api_test", "project:Q3 Launch", 10)
+	if err == nil || !strings.Contains(err.Error(), "ENG and OPS") {
+		t.Errorf("err = %v, want the project rejected for spanning teams", err)
+	}
+}
+
+func TestLinearFilter(t *testing.T) {
+	f := linearFilter("project:Q3 Launch")
+	if _, ok := f["team"]; ok {
+		t.Errorf("project filter = %v", f)
+	}
+	if got := f["project"].(map[string]any)["name"].(map[string]any)["eq"]; got != "Q3 Launch" {
+		t.Errorf("project name = %v", got)
+	}
+	if f["state"] == nil {
+		t.Error("filter should drop completed and canceled issues")
+	}
+}
+
+func TestScanLinear(t *testing.T) {
+	fakeLinear(t)
+	t.Setenv("FAB_BACKLOG_LINEAR_API_KEY", "lin_api_test")
+	prev := linearSources
+	t.Cleanup(func() { linearSources = prev })
+	linearSources = stringList{"ENG"}
+
+	out := scan(t.Context(), selection{Orgs: []string{"acme"}}, nil, config{MinIssues: 1, StaleDays: 90})
+	if len(out.Repos) != 1 {
+		t.Fatalf("scored %+v", out.Repos)
+	}
+	rs := out.Repos[0]
+	if rs.Name != "linear:ENG" || rs.Source != "linear" || rs.fullName != "acme/linear:ENG" {
+		t.Errorf("entry = %+v", rs)
+	}
+	if rs.Error != "" || rs.TotalOpen != 2 || rs.StaleCount != 1 || rs.UnlabeledCount != 1 {
+		t.Errorf("score = %+v", rs)
+	}
+}
+
+func TestValidateLinear(t *testing.T) {
+	prev := linearSources
+	t.Cleanup(func() { linearSources = prev })
+	linearSources = stringList{"ENG"}
+	t.Setenv("FAB_BACKLOG_LINEAR_API_KEY", "")
+	if err := validateLinear("scan"); err == nil {
+		t.Error("-linear without an API key should fail")
+	}
+	t.Setenv("FAB_BACKLOG_LINEAR_API_KEY", "lin_api_test")
+	if err := validateLinear("scan"); err != nil {
+		t.Error(err)
+	}
+	if err := validateLinear("focus"); err == nil {
+		t.Error("-linear with focus should fail")
+	}
+	prevRecord := *recordDir
+	t.Cleanup(func() { *recordDir = prevRecord })
+	*recordDir = t.TempDir()
+	if err := validateLinear("scan"); err == nil {
+		t.Error("-linear with -record-dir should fail")
+	}
+}
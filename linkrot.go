@@ -0,0 +1,197 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"net"
+	"net/http"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+var linkCheck = flag.Bool("link-check", false, "verify URLs and issue/PR references in stale issues and queue those whose context has rotted for closing")
+
+// maxLinksPerIssue bounds the requests a single link-heavy issue can cost.
+const maxLinksPerIssue = 10
+
+// triageItem is a suggested action on one issue, with the evidence for it.
+// Action is a plan action kind; Source names the analysis that suggested it.
+type triageItem struct {
+	Number  int      `json:"number"`
+	Title   string   `json:"title"`
+	Action  string   `json:"action"`
+	Label   string   `json:"label,omitempty"`
+	Comment string   `json:"comment,omitempty"`
+	Source  string   `json:"source"`
+	Reasons []string `json:"reasons"`
+}
+
+var (
+	urlPattern = regexp.MustCompile(`https?://[^\s<>()\[\]"'` + "`" + `]+`)
+	ghPath     = regexp.MustCompile(`^([\w.-]+)/([\w.-]+)/(?:issues|pull)/(\d+)`)
+	refPattern = regexp.MustCompile(`(?:^|[\s(])([\w.-]+/[\w.-]+)?#(\d+)\b`)
+)
+
+// extractLinks returns the distinct links in an issue body. GitHub issue and
+// PR links, full or shorthand, become "owner/repo#N" references so they can
+// be checked through the API, where private repos are visible.
+func extractLinks(body, owner, repo string) []string {
+	seen := map[string]bool{}
+	var links []string
+	add := func(l string) {
+		if !seen[l] && len(links) < maxLinksPerIssue {
+			seen[l] = true
+			links = append(links, l)
+		}
+	}
+	for _, u := range urlPattern.FindAllString(body, -1) {
+		u = strings.TrimRight(u, ".,;:!?")
+		if path, ok := strings.CutPrefix(u, webURL("")); ok {
+			if m := ghPath.FindStringSubmatch(path); m != nil {
+				add(m[1] + "/" + m[2] + "#" + m[3])
+				continue
+			}
+		}
+		add(u)
+	}
+	// URLs are blanked first so their fragments aren't read as references.
+	for _, m := range refPattern.FindAllStringSubmatch(urlPattern.ReplaceAllString(body, " "), -1) {
+		target := m[1]
+		if target == "" {
+			target = owner + "/" + repo
+		}
+		add(target + "#" + m[2])
+	}
+	return links
+}
+
+// linkStatus is the outcome of checking one link.
+type linkStatus int
+
+const (
+	linkAlive linkStatus = iota
+	linkDead
+	linkUnknown
+)
+
+// linkChecker checks links once per run, however many issues cite them.
+type linkChecker struct {
+	client *http.Client
+	mu     sync.Mutex
+	cache  map[string]linkStatus
+}
+
+func newLinkChecker() *linkChecker {
+	return &linkChecker{client: &http.Client{Timeout: 10 * time.Second, Transport: httpTransport}, cache: map[string]linkStatus{}}
+}
+
+func (lc *linkChecker) check(ctx context.Context, link string) linkStatus {
+	lc.mu.Lock()
+	st, ok := lc.cache[link]
+	lc.mu.Unlock()
+	if ok {
+		return st
+	}
+	if strings.HasPrefix(link, "http") {
+		st = lc.checkURL(ctx, link)
+	} else {
+		st = checkRef(ctx, link)
+	}
+	lc.mu.Lock()
+	lc.cache[link] = st
+	lc.mu.Unlock()
+	return st
+}
+
+func (lc *linkChecker) checkURL(ctx context.Context, u string) linkStatus {
+	resp, err := lc.do(ctx, http.MethodHead, u)
+	if err == nil && (resp.StatusCode == http.StatusMethodNotAllowed || resp.StatusCode == http.StatusForbidden) {
+		// Plenty of servers refuse HEAD but answer GET.
+		resp.Body.Close()
+		resp, err = lc.do(ctx, http.MethodGet, u)
+	}
+	if err != nil {
+		var dnsErr *net.DNSError
+		if errors.As(err, &dnsErr) && dnsErr.IsNotFound {
+			return linkDead
+		}
+		return linkUnknown
+	}
+	resp.Body.Close()
+	switch {
+	case resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusGone:
+		return linkDead
+	case resp.StatusCode < 400:
+		return linkAlive
+	}
+	return linkUnknown
+}
+
+func (lc *linkChecker) do(ctx context.Context, method, u string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	return lc.client.Do(req)
+}
+
+// checkRef checks an owner/repo#N reference; the issues endpoint serves PRs too.
+func checkRef(ctx context.Context, ref string) linkStatus {
+	i := strings.LastIndex(ref, "#")
+	_, err := runCmd(ctx, "gh", "api", "--silent", fmt.Sprintf("repos/%s/issues/%s", ref[:i], ref[i+1:]))
+	switch {
+	case err == nil:
+		return linkAlive
+	case strings.Contains(err.Error(), "HTTP 404") || strings.Contains(err.Error(), "HTTP 410"):
+		return linkDead
+	}
+	return linkUnknown
+}
+
+// rotReasons checks links and returns a reason per dead one when the dead
+// outnumber the live: an issue whose context is mostly gone is a strong
+// close candidate, one broken link among many is not.
+func rotReasons(links []string, check func(string) linkStatus) []string {
+	var dead []string
+	alive := 0
+	for _, l := range links {
+		switch check(l) {
+		case linkDead:
+			dead = append(dead, l)
+		case linkAlive:
+			alive++
+		}
+	}
+	if len(dead) == 0 || len(dead) <= alive {
+		return nil
+	}
+	reasons := make([]string, len(dead))
+	for i, l := range dead {
+		reasons[i] = "dead link: " + l
+	}
+	return reasons
+}
+
+// addLinkRot queues stale issues whose links have rotted for closing.
+func addLinkRot(ctx context.Context, score *repoScore, owner, repo string, issues []issue, cfg config, lc *linkChecker, now time.Time) {
+	if !cfg.LinkCheck {
+		return
+	}
+	stale := now.AddDate(0, 0, -cfg.StaleDays)
+	for _, is := range issues {
+		if !is.UpdatedAt.Before(stale) {
+			continue
+		}
+		links := extractLinks(is.Body, owner, repo)
+		if len(links) == 0 {
+			continue
+		}
+		if reasons := rotReasons(links, func(link string) linkStatus { return lc.check(ctx, link) }); reasons != nil {
+			score.TriageQueue = append(score.TriageQueue, triageItem{Number: is.Number, Title: is.Title, Action: "close", Source: "link-check", Reasons: reasons})
+		}
+	}
+}
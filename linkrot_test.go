@@ -0,0 +1,29 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestExtractLinks(t *testing.T) {
+	body := "Broken since #12 and other/lib#7.\nSee https://github.com/org/app/pull/40, https://example.com/docs#setup. and (https://old.example.org/x)"
+	got := extractLinks(body, "org", "app")
+	want := []string{"org/app#40", "https://example.com/docs#setup", "https://old.example.org/x", "org/app#12", "other/lib#7"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("extractLinks =\n%q\nwant\n%q", got, want)
+	}
+}
+
+func TestRotReasons(t *testing.T) {
+	status := map[string]linkStatus{"a": linkDead, "b": linkDead, "c": linkAlive, "d": linkUnknown}
+	check := func(l string) linkStatus { return status[l] }
+	if got := rotReasons([]string{"a", "b", "c", "d"}, check); len(got) != 2 {
+		t.Errorf("mostly dead: got %v, want 2 reasons", got)
+	}
+	if got := rotReasons([]string{"a", "c"}, check); got != nil {
+		t.Errorf("one dead among live links should not flag: %v", got)
+	}
+	if got := rotReasons([]string{"d"}, check); got != nil {
+		t.Errorf("unknown links should not flag: %v", got)
+	}
+}
@@ -0,0 +1,5 @@
+package main
+
+import "flag"
+
+var localeFlag = flag.String("locale", "", "number and date formatting for the Markdown and HTML reports, e.g. en-US, de-DE, ja-JP (default: unlocalised, ISO 8601 dates)")
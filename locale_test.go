@@ -0,0 +1,29 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRenderLocalised(t *testing.T) {
+	out := sampleReport()
+	out.GeneratedAt = "2025-03-04T05:06:07Z"
+	out.Repos[0].TotalOpen = 12345
+	out.HallOfShame = []shameIssue{{Repo: "misty-step/meh", Number: 3, Title: "ancient", AgeYears: 4.25}}
+	out.Config.Locale = "fr-FR"
+	md, err := renderMarkdown(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	html, err := renderHTML(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, doc := range []string{md, html} {
+		for _, want := range []string{"generated 04/03/2025 05:06 UTC", "12\u202f345", "(4,2 years)"} {
+			if !strings.Contains(doc, want) {
+				t.Errorf("missing %q:\n%s", want, doc)
+			}
+		}
+	}
+}
@@ -0,0 +1,265 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+
+	ghscan "github.com/misty-step/fab-backlog/pkg/scan"
+)
+
+var (
+	clientID  = flag.String("client-id", "", "with login, the client ID of the OAuth app or GitHub App to authorize through the device flow; a GitHub App's gives a fine-grained token limited to its permissions")
+	withToken = flag.Bool("with-token", false, "with login, store a token read from stdin, such as a fine-grained personal access token, instead of running the device flow")
+)
+
+// loginScopes are requested from OAuth apps; GitHub Apps ignore them and
+// grant their own permissions.
+const loginScopes = "repo read:org"
+
+// keyringService names fab-backlog's entries in the OS keyring, one per host.
+const keyringService = "fab-backlog"
+
+var loginClient = &http.Client{Timeout: 30 * time.Second, Transport: httpTransport}
+
+// validateLogin checks the login flags are only given to login.
+func validateLogin(cmd string) error {
+	if cmd == "login" {
+		if *clientID != "" && *withToken {
+			return fmt.Errorf("-client-id and -with-token are alternatives: pick one")
+		}
+		return nil
+	}
+	if *clientID != "" || *withToken {
+		return fmt.Errorf("-client-id and -with-token only apply to login")
+	}
+	return nil
+}
+
+// postForm posts form to u and decodes the JSON answer into v.
+func postForm(ctx context.Context, hc *http.Client, u string, form url.Values, v any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	resp, err := hc.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("POST %s: %s", u, resp.Status)
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+// deviceLogin runs the OAuth device flow against the instance at base: it
+// asks for a code, tells the user where to enter it, and polls until they
+// have authorized the app, returning the token.
+func deviceLogin(ctx context.Context, hc *http.Client, base, clientID string, prompt io.Writer) (string, error) {
+	var code struct {
+		DeviceCode      string `json:"device_code"`
+		UserCode        string `json:"user_code"`
+		VerificationURI string `json:"verification_uri"`
+		Interval        int    `json:"interval"`
+	}
+	if err := postForm(ctx, hc, base+"login/device/code", url.Values{"client_id": {clientID}, "scope": {loginScopes}}, &code); err != nil {
+		return "", fmt.Errorf("request device code: %w", err)
+	}
+	if code.DeviceCode == "" {
+		return "", fmt.Errorf("request device code: no code returned; is the device flow enabled for client %s?", clientID)
+	}
+	fmt.Fprintf(prompt, "Open %s and enter the code %s\n", code.VerificationURI, code.UserCode)
+
+	interval := time.Duration(code.Interval) * time.Second
+	poll := url.Values{
+		"client_id":   {clientID},
+		"device_code": {code.DeviceCode},
+		"grant_type":  {"urn:ietf:params:oauth:grant-type:device_code"},
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return "", ctx.Err()
+		case <-time.After(interval):
+		}
+		var tok struct {
+			AccessToken string `json:"access_token"`
+			Error       string `json:"error"`
+			Description string `json:"error_description"`
+			Interval    int    `json:"interval"`
+		}
+		if err := postForm(ctx, hc, base+"login/oauth/access_token", poll, &tok); err != nil {
+			return "", fmt.Errorf("poll for token: %w", err)
+		}
+		switch tok.Error {
+		case "":
+			return tok.AccessToken, nil
+		case "authorization_pending":
+		case "slow_down":
+			interval += 5 * time.Second
+			if tok.Interval > 0 {
+				interval = time.Duration(tok.Interval) * time.Second
+			}
+		default:
+			return "", fmt.Errorf("device flow: %s: %s", tok.Error, tok.Description)
+		}
+	}
+}
+
+// keyringCmd runs a keyring CLI with stdin as its input.
+var keyringCmd = func(stdin, bin string, args ...string) ([]byte, error) {
+	cmd := exec.Command(bin, args...)
+	cmd.Stdin = strings.NewReader(stdin)
+	out, err := cmd.Output()
+	var exit *exec.ExitError
+	if errors.As(err, &exit) && len(exit.Stderr) > 0 {
+		err = fmt.Errorf("%s: %s", bin, strings.TrimSpace(string(exit.Stderr)))
+	}
+	return out, err
+}
+
+// storeToken keeps token for host in the OS keyring. As with SQLite storage,
+// fab-backlog shells out rather than linking a keyring library: macOS's
+// security and libsecret's secret-tool elsewhere. Neither sees the token on
+// its command line.
+func storeToken(goos, host, token string) error {
+	switch goos {
+	case "darwin":
+		_, err := keyringCmd(fmt.Sprintf("add-generic-password -U -s %s -a %s -w %s\n", keyringService, host, token), "security", "-i")
+		return err
+	case "windows":
+		return errors.New("no keyring support on Windows yet: set GH_TOKEN instead")
+	}
+	_, err := keyringCmd(token, "secret-tool", "store", "--label", keyringService+" ("+host+")", "service", keyringService, "host", host)
+	return err
+}
+
+// loadToken reads the token login stored for host, "" if there is none.
+func loadToken(goos, host string) (string, error) {
+	var out []byte
+	var err error
+	switch goos {
+	case "darwin":
+		out, err = keyringCmd("", "security", "find-generic-password", "-s", keyringService, "-a", host, "-w")
+	case "windows":
+		return "", nil
+	default:
+		out, err = keyringCmd("", "secret-tool", "lookup", "service", keyringService, "host", host)
+	}
+	return strings.TrimSpace(string(out)), err
+}
+
+// ghInstalled reports whether gh is on the PATH.
+var ghInstalled = func() bool {
+	_, err := exec.LookPath("gh")
+	return err == nil
+}
+
+// validateGH checks that a run on a machine without gh only asks for what
+// ghscan.GitHubAPI can do: scan or focus named orgs and repos for the core
+// score. Offline commands and replays never run gh.
+func validateGH(c command, sel selection, cfg config) error {
+	if *providerFlag != "github" || c.offline || *fromFile != "" || ghInstalled() {
+		return nil
+	}
+	switch {
+	case c.name != "scan" && c.name != "focus":
+		return fmt.Errorf("%s needs gh, which isn't installed: only scan and focus read GitHub's API directly", c.name)
+	case len(sel.Orgs) == 0 && len(sel.Repos) == 0:
+		return fmt.Errorf("without gh, orgs aren't discovered: pass -org or -repo")
+	case *recordDir != "" || *etagCacheDir != "":
+		return fmt.Errorf("-record-dir and -etag-cache capture gh calls, and gh isn't installed")
+	}
+	if on := githubOnly(cfg); len(on) > 0 {
+		return fmt.Errorf("without gh only the core score is computed: %s needs gh", strings.Join(on, ", "))
+	}
+	return nil
+}
+
+// restAPIURL is the base of the REST API on host.
+func restAPIURL(host string) string {
+	if host == "github.com" {
+		return "https://api.github.com"
+	}
+	return "https://" + host + "/api/v3"
+}
+
+// useStoredToken hands gh the token login stored for the scanned host,
+// unless the environment already has one for it, so a scan works without
+// gh auth login. Without gh installed, the core reads go to the REST API
+// directly with that token instead.
+func useStoredToken() {
+	if *providerFlag != "github" {
+		return
+	}
+	host := forgeHost()
+	vars := []string{"GH_TOKEN", "GITHUB_TOKEN"}
+	if host != "github.com" {
+		vars = []string{"GH_ENTERPRISE_TOKEN", "GITHUB_ENTERPRISE_TOKEN"}
+	}
+	var token string
+	for _, v := range vars {
+		if token = os.Getenv(v); token != "" {
+			break
+		}
+	}
+	if token == "" {
+		stored, err := loadToken(runtime.GOOS, host)
+		if err != nil || stored == "" {
+			slog.Debug("no stored token", "host", host, "error", err)
+		} else {
+			token = stored
+			os.Setenv(vars[0], token)
+		}
+	}
+	if !ghInstalled() {
+		slog.Info("gh not installed; reading the REST API directly", "host", host)
+		client = ghscan.GitHubAPI{BaseURL: restAPIURL(host), Token: token, HTTP: providerClient()}
+	}
+}
+
+// runLogin implements `fab-backlog login`.
+func runLogin(ctx context.Context, args []string) int {
+	if len(args) > 0 {
+		return commandFailed(2, "invalid login command", errors.New("usage: fab-backlog login [-client-id ID | -with-token] [-hostname HOST]"))
+	}
+	host := forgeHost()
+	var token string
+	if *withToken {
+		data, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return commandFailed(1, "failed to read token", err)
+		}
+		if token = strings.TrimSpace(string(data)); token == "" {
+			return commandFailed(2, "failed to read token", errors.New("-with-token read an empty token from stdin"))
+		}
+	} else {
+		if *clientID == "" {
+			return commandFailed(2, "invalid login command", errors.New("login needs -client-id for the device flow, or -with-token to store an existing token"))
+		}
+		var err error
+		if token, err = deviceLogin(ctx, loginClient, webURL(""), *clientID, os.Stderr); err != nil {
+			return commandFailed(1, "login failed", err)
+		}
+	}
+	if err := storeToken(runtime.GOOS, host, token); err != nil {
+		return commandFailed(1, "failed to store token", err)
+	}
+	slog.Info("token stored in keyring", "host", host)
+	emitJSON(map[string]any{"ok": true, "host": host})
+	return 0
+}
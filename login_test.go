@@ -0,0 +1,175 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	ghscan "github.com/misty-step/fab-backlog/pkg/scan"
+)
+
+// fakeDeviceFlow answers the device flow endpoints, keeping the user
+// pending for the given number of polls before granting or denying.
+func fakeDeviceFlow(t *testing.T, pending int, outcome string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil || r.Form.Get("client_id") != "Iv1.abc" {
+			t.Errorf("%s: form %v, err %v", r.URL.Path, r.Form, err)
+		}
+		switch r.URL.Path {
+		case "/login/device/code":
+			w.Write([]byte(`{"device_code":"dev1","user_code":"ABCD-1234","verification_uri":"https://github.com/login/device","expires_in":900,"interval":0}`))
+		case "/login/oauth/access_token":
+			if r.Form.Get("device_code") != "dev1" {
+				t.Errorf("polled with %v", r.Form)
+			}
+			switch {
+			case pending > 0:
+				pending--
+				w.Write([]byte(`{"error":"authorization_pending"}`))
+			case outcome == "":
+				w.Write([]byte(`{"access_token":"ghu_token","token_type":"bearer"}`))
+			default:
+				w.Write([]byte(`{"error":"` + outcome + `","error_description":"The user has denied your application access."}`))
+			}
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+}
+
+func TestDeviceLogin(t *testing.T) {
+	srv := fakeDeviceFlow(t, 2, "")
+	defer srv.Close()
+	var prompt strings.Builder
+	token, err := deviceLogin(context.Background(), srv.Client(), srv.URL+"/", "Iv1.abc", &prompt)
+	if err != nil || token != "ghu_token" {
+		t.Fatalf("token %q, err %v", token, err)
+	}
+	if !strings.Contains(prompt.String(), "https://github.com/login/device") || !strings.Contains(prompt.String(), "ABCD-1234") {
+		t.Errorf("prompt = %q", prompt.String())
+	}
+
+	denied := fakeDeviceFlow(t, 0, "access_denied")
+	defer denied.Close()
+	if _, err := deviceLogin(context.Background(), denied.Client(), denied.URL+"/", "Iv1.abc", &prompt); err == nil || !strings.Contains(err.Error(), "access_denied") {
+		t.Errorf("denied: err = %v", err)
+	}
+}
+
+func TestTokenKeyring(t *testing.T) {
+	prev := keyringCmd
+	t.Cleanup(func() { keyringCmd = prev })
+	var calls []string
+	stored := map[string]string{}
+	keyringCmd = func(stdin, bin string, args ...string) ([]byte, error) {
+		call := bin + " " + strings.Join(args, " ")
+		calls = append(calls, call)
+		if strings.Contains(call, "lookup") || strings.Contains(call, "find-generic-password") {
+			return []byte(stored[bin] + "\n"), nil
+		}
+		stored[bin] = stdin
+		return nil, nil
+	}
+
+	if err := storeToken("linux", "github.com", "ghu_token"); err != nil {
+		t.Fatal(err)
+	}
+	if tok, err := loadToken("linux", "github.com"); err != nil || tok != "ghu_token" {
+		t.Errorf("linux token %q, err %v", tok, err)
+	}
+	if err := storeToken("darwin", "github.acme.com", "ghu_token"); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(stored["security"], "-a github.acme.com -w ghu_token") {
+		t.Errorf("security stdin = %q", stored["security"])
+	}
+	for _, c := range calls {
+		if strings.Contains(c, "ghu_token") {
+			t.Errorf("token on a command line: %s", c)
+		}
+	}
+	if err := storeToken("windows", "github.com", "ghu_token"); err == nil {
+		t.Error("windows should be unsupported")
+	}
+}
+
+func TestUseStoredToken(t *testing.T) {
+	prev, prevHost, prevInstalled := keyringCmd, *hostnameFlag, ghInstalled
+	t.Cleanup(func() { keyringCmd, *hostnameFlag, ghInstalled = prev, prevHost, prevInstalled })
+	keyringCmd = func(string, string, ...string) ([]byte, error) { return []byte("ghu_stored\n"), nil }
+	ghInstalled = func() bool { return true }
+	*hostnameFlag = ""
+	for _, v := range []string{"GH_HOST", "GH_TOKEN", "GITHUB_TOKEN", "GH_ENTERPRISE_TOKEN", "GITHUB_ENTERPRISE_TOKEN"} {
+		t.Setenv(v, "")
+	}
+
+	useStoredToken()
+	if got := os.Getenv("GH_TOKEN"); got != "ghu_stored" {
+		t.Errorf("GH_TOKEN = %q", got)
+	}
+	t.Setenv("GH_TOKEN", "")
+	t.Setenv("GITHUB_TOKEN", "ghp_env")
+	useStoredToken()
+	if got := os.Getenv("GH_TOKEN"); got != "" {
+		t.Errorf("an environment token should win, GH_TOKEN = %q", got)
+	}
+	*hostnameFlag = "github.acme.com"
+	useStoredToken()
+	if got := os.Getenv("GH_ENTERPRISE_TOKEN"); got != "ghu_stored" {
+		t.Errorf("GH_ENTERPRISE_TOKEN = %q", got)
+	}
+}
+
+func TestWithoutGH(t *testing.T) {
+	prev, prevHost, prevInstalled, prevClient := keyringCmd, *hostnameFlag, ghInstalled, client
+	t.Cleanup(func() { keyringCmd, *hostnameFlag, ghInstalled, client = prev, prevHost, prevInstalled, prevClient })
+	keyringCmd = func(string, string, ...string) ([]byte, error) { return []byte("ghu_stored\n"), nil }
+	ghInstalled = func() bool { return false }
+	*hostnameFlag = "github.acme.com"
+	for _, v := range []string{"GH_HOST", "GH_TOKEN", "GITHUB_TOKEN", "GH_ENTERPRISE_TOKEN", "GITHUB_ENTERPRISE_TOKEN"} {
+		t.Setenv(v, "")
+	}
+
+	useStoredToken()
+	api, ok := client.(ghscan.GitHubAPI)
+	if !ok || api.Token != "ghu_stored" || api.BaseURL != "https://github.acme.com/api/v3" {
+		t.Errorf("client = %+v, want the REST API with the stored token", client)
+	}
+
+	scan := command{name: "scan"}
+	sel := selection{Orgs: []string{"acme"}}
+	if err := validateGH(scan, sel, config{}); err != nil {
+		t.Errorf("core scan: %v", err)
+	}
+	if err := validateGH(command{name: "triage"}, sel, config{}); err == nil {
+		t.Error("triage without gh should fail")
+	}
+	if err := validateGH(scan, selection{}, config{}); err == nil {
+		t.Error("org discovery without gh should fail")
+	}
+	if err := validateGH(scan, sel, config{Deep: true}); err == nil {
+		t.Error("-deep without gh should fail")
+	}
+	if err := validateGH(command{name: "report", offline: true}, selection{}, config{}); err != nil {
+		t.Errorf("offline commands don't need gh: %v", err)
+	}
+}
+
+func TestValidateLogin(t *testing.T) {
+	prevID, prevWith := *clientID, *withToken
+	t.Cleanup(func() { *clientID, *withToken = prevID, prevWith })
+	*clientID = "Iv1.abc"
+	if err := validateLogin("login"); err != nil {
+		t.Error(err)
+	}
+	if err := validateLogin("scan"); err == nil {
+		t.Error("-client-id with scan should fail")
+	}
+	*withToken = true
+	if err := validateLogin("login"); err == nil {
+		t.Error("-client-id with -with-token should fail")
+	}
+}
@@ -1,48 +1,196 @@
 package main
 
 import (
-	"bytes"
+	"cmp"
+	"context"
 	"encoding/json"
 	"flag"
 	"fmt"
+	"io"
 	"log/slog"
 	"os"
-	"os/exec"
+	"os/signal"
+	"slices"
 	"sort"
 	"strings"
+	"syscall"
 	"time"
+
+	ghscan "github.com/misty-step/fab-backlog/pkg/scan"
+	"github.com/misty-step/fab-backlog/pkg/score"
 )
 
 var (
-	org       = flag.String("org", "misty-step", "GitHub org/owner to scan")
+	org       = flag.String("org", "", "GitHub org/owner to scan (comma-separated for several); default: the authenticated account's organizations")
 	minIssues = flag.Int("min-issues", 5, "minimum issues threshold for health score")
 	staleDays = flag.Int("stale-days", 90, "stale threshold in days")
 	quiet     = flag.Bool("quiet", false, "suppress info/warn logs (only errors shown)")
 	jsonLogs  = flag.Bool("json-logs", false, "emit logs as JSON (default: text)")
+	deep      = flag.Bool("deep", false, "fetch every open issue and include per-issue drill-down metrics")
+
+	unlabeledGrace   = flag.Int("unlabeled-grace-hours", 0, "ignore issues younger than this many hours in the unlabeled metric")
+	securityLabel    = flag.String("security-label", "security", "label marking security issues")
+	securityMaxDays  = flag.Int("security-max-days", 0, "force a repo critical when a security issue is open longer than this (0 disables)")
+	hallOfShameN     = flag.Int("hall-of-shame", 0, "list the N oldest open issues across the org (0 disables)")
+	mostWantedN      = flag.Int("most-wanted", 0, "list the N most 👍-reacted open issues across the org (0 disables)")
+	onboardingLabels = flag.String("onboarding-labels", "good first issue,help wanted", "comma-separated labels counted as onboarding-friendly")
+)
+
+// issueLimit caps the number of open issues fetched per repo in a normal scan.
+// Deep scans lift it so large backlogs are measured in full.
+const (
+	issueLimit     = 100
+	deepIssueLimit = 10000
 )
 
 type output struct {
-	GeneratedAt string      `json:"generatedAt"`
-	Org         string      `json:"org"`
-	Config      config      `json:"config"`
-	Repos       []repoScore `json:"repos"`
-	Summary     summary     `json:"summary"`
+	GeneratedAt     string             `json:"generatedAt"`
+	Meta            *reportMeta        `json:"meta,omitempty"`
+	Org             string             `json:"org"`
+	Orgs            []string           `json:"orgs,omitempty"`
+	Scopes          []string           `json:"scopes"`
+	Config          config             `json:"config"`
+	Repos           []repoScore        `json:"repos"`
+	SecurityBacklog []securityIssue    `json:"securityBacklog,omitempty"`
+	MostWanted      []wantedIssue      `json:"mostWanted,omitempty"`
+	HallOfShame     []shameIssue       `json:"hallOfShame,omitempty"`
+	TeamRollups     []teamRollup       `json:"teamRollups,omitempty"`
+	Ownership       []ownerRollup      `json:"ownership,omitempty"`
+	Groups          []groupRollup      `json:"groups,omitempty"`
+	Remediation     *remediationReport `json:"remediation,omitempty"`
+	Observing       []observedPolicy   `json:"observing,omitempty"`
+	Applied         []applyResult      `json:"applied,omitempty"`
+	Summary         summary            `json:"summary"`
+	Truncated       bool               `json:"truncated,omitempty"`
+	Incremental     *incrementalInfo   `json:"incremental,omitempty"`
+	Gate            *gateResult        `json:"gate,omitempty"`
 }
 
 type config struct {
-	MinIssues int `json:"minIssues"`
-	StaleDays int `json:"staleDays"`
+	MinIssues              int                      `json:"minIssues"`
+	StaleDays              int                      `json:"staleDays"`
+	UnlabeledGraceHours    int                      `json:"unlabeledGraceHours,omitempty"`
+	OnboardingLabels       []string                 `json:"onboardingLabels"`
+	IgnoreLabels           []string                 `json:"ignoreLabels,omitempty"`
+	StaleExcludeTitles     []string                 `json:"staleExcludeTitles,omitempty"`
+	RepoTopics             bool                     `json:"repoTopics,omitempty"`
+	Topics                 map[string]repoOverrides `json:"topics,omitempty"`
+	LabelAliases           map[string][]string      `json:"labelAliases,omitempty"`
+	SLOs                   []slo                    `json:"slos,omitempty"`
+	SecurityLabel          string                   `json:"securityLabel,omitempty"`
+	SecurityMaxDays        int                      `json:"securityMaxDays,omitempty"`
+	MostWanted             int                      `json:"mostWanted,omitempty"`
+	HallOfShame            int                      `json:"hallOfShame,omitempty"`
+	ResponseWindowDays     int                      `json:"responseWindowDays,omitempty"`
+	LabelLatencyWindowDays int                      `json:"labelLatencyWindowDays,omitempty"`
+	ContributorWindowDays  int                      `json:"contributorWindowDays,omitempty"`
+	QAWindowDays           int                      `json:"qaWindowDays,omitempty"`
+	AutomationBots         []string                 `json:"automationBots,omitempty"`
+	CodeDebt               bool                     `json:"codeDebt,omitempty"`
+	CodeDebtAuthors        []string                 `json:"codeDebtAuthors,omitempty"`
+	TriageEffort           *triageCosts             `json:"triageEffort,omitempty"`
+	SecurityAlerts         bool                     `json:"securityAlerts,omitempty"`
+	ProjectCoverage        *projectCoverage         `json:"projectCoverage,omitempty"`
+	LinkCheck              bool                     `json:"linkCheck,omitempty"`
+	TeamRollups            bool                     `json:"teamRollups,omitempty"`
+	CodeOwners             bool                     `json:"codeOwners,omitempty"`
+	DuplicateThreshold     float64                  `json:"duplicateThreshold,omitempty"`
+	Escalation             []escalationStep         `json:"escalation,omitempty"`
+	SMTP                   *smtpConfig              `json:"smtp,omitempty"`
+	Observe                map[string]int           `json:"observe,omitempty"`
+	Storage                *storageConfig           `json:"storage,omitempty"`
+	Scopes                 []string                 `json:"scopes,omitempty"`
+	StalePolicy            *stalePolicy             `json:"stalePolicy,omitempty"`
+	LabelRules             []labelRule              `json:"labelRules,omitempty"`
+	Intake                 *intakePolicy            `json:"intake,omitempty"`
+	PublicFeed             *publicFeed              `json:"publicFeed,omitempty"`
+	SupportSLA             *supportSLA              `json:"supportSLA,omitempty"`
+	OOO                    []oooPeriod              `json:"ooo,omitempty"`
+	Exemptions             []exemption              `json:"exemptions,omitempty"`
+	Ramps                  []rampPlan               `json:"ramps,omitempty"`
+	Groups                 []repoGroup              `json:"groups,omitempty"`
+	Locale                 string                   `json:"locale,omitempty"`
+	Deep                   bool                     `json:"deep,omitempty"`
+
+	// Policies in observe mode this run; set from Observe and the history.
+	observing map[string]bool
+	// Maintainer absences from OOO and -ooo-calendar.
+	away awaySchedule
 }
 
 type repoScore struct {
-	Name           string  `json:"name"`
-	TotalOpen      int     `json:"totalOpen"`
-	StaleCount     int     `json:"staleCount"`
-	StalePercent   float64 `json:"stalePercent"`
-	UnlabeledCount int     `json:"unlabeledCount"`
-	HealthScore    int     `json:"healthScore"`
-	Status         string  `json:"status"`
-	Error          string  `json:"error,omitempty"`
+	Name                 string               `json:"name"`
+	Source               string               `json:"source,omitempty"`
+	TotalOpen            int                  `json:"totalOpen"`
+	StaleCount           int                  `json:"staleCount"`
+	StalePercent         float64              `json:"stalePercent"`
+	StaleExempt          []int                `json:"staleExempt,omitempty"`
+	UnlabeledCount       int                  `json:"unlabeledCount"`
+	OnboardingCount      int                  `json:"onboardingCount"`
+	HealthScore          int                  `json:"healthScore"`
+	Overrides            *repoOverrides       `json:"overrides,omitempty"`
+	Annotations          []topicAnnotation    `json:"annotations,omitempty"`
+	ScoreBreakdown       *scoreBreakdown      `json:"scoreBreakdown,omitempty"`
+	SmoothedScore        *float64             `json:"smoothedScore,omitempty"`
+	Status               string               `json:"status"`
+	SLOBreaches          []sloBreach          `json:"sloBreaches,omitempty"`
+	Duplicates           []duplicate          `json:"duplicates,omitempty"`
+	Recommendations      []string             `json:"recommendations,omitempty"`
+	SecurityOverdueCount int                  `json:"securityOverdueCount,omitempty"`
+	FirstResponse        *responseMetrics     `json:"firstResponse,omitempty"`
+	SLA                  *slaCompliance       `json:"sla,omitempty"`
+	FirstLabel           *labelLatencyMetrics `json:"firstLabel,omitempty"`
+	Contributors         *contributorMetrics  `json:"contributors,omitempty"`
+	QA                   *qaMetrics           `json:"qa,omitempty"`
+	AutomationBacklog    *automationBacklog   `json:"automationBacklog,omitempty"`
+	CodeDebt             *codeDebt            `json:"codeDebt,omitempty"`
+	TriageEffort         *triageEffort        `json:"triageEffort,omitempty"`
+	Resurrections        *resurrections       `json:"resurrections,omitempty"`
+	SecurityAlerts       *securityAlerts      `json:"securityAlerts,omitempty"`
+	ProjectTracking      *projectTracking     `json:"projectTracking,omitempty"`
+	TriageQueue          []triageItem         `json:"triageQueue,omitempty"`
+	CodeOwners           *codeOwners          `json:"codeOwners,omitempty"`
+	Observed             []string             `json:"observed,omitempty"`
+	Exemption            *exemption           `json:"exemption,omitempty"`
+	Groups               []string             `json:"groups,omitempty"`
+	Ramp                 *rampStatus          `json:"ramp,omitempty"`
+	CriticalRuns         int                  `json:"criticalRuns,omitempty"`
+	Freshness            *freshness           `json:"freshness,omitempty"`
+	Deep                 *deepMetrics         `json:"deep,omitempty"`
+	APIErrors            []apiError           `json:"apiErrors,omitempty"`
+	Error                string               `json:"error,omitempty"`
+
+	// Retained for analyses that run after every repo has been scored.
+	fullName         string
+	issues           []issue
+	openIssues       []issue // before ignored labels and code debt are taken out
+	truncated        bool
+	staleDays        int // after topic and repo overrides
+	securityOverdue  []securityIssue
+	unlabeledPercent float64
+	staleIssues      []int
+	// Set on scores -since-last-run carried over from the previous run,
+	// with that run's mostWanted entries for the repo.
+	reused bool
+	wanted []wantedIssue
+}
+
+// deepMetrics is the per-repo drill-down emitted by --deep scans.
+type deepMetrics struct {
+	OldestIssueDays int           `json:"oldestIssueDays"`
+	MedianAgeDays   int           `json:"medianAgeDays"`
+	MedianIdleDays  int           `json:"medianIdleDays"`
+	Issues          []issueDetail `json:"issues"`
+}
+
+type issueDetail struct {
+	Number    int      `json:"number"`
+	Title     string   `json:"title"`
+	AgeDays   int      `json:"ageDays"`
+	IdleDays  int      `json:"idleDays"`
+	Stale     bool     `json:"stale"`
+	Unlabeled bool     `json:"unlabeled"`
+	Labels    []string `json:"labels,omitempty"`
 }
 
 type summary struct {
@@ -50,28 +198,34 @@ type summary struct {
 	Healthy  int `json:"healthy"`
 	Warning  int `json:"warning"`
 	Critical int `json:"critical"`
+	// Sum of the repos' triageEffort estimates.
+	TriageMinutes int `json:"triageMinutes,omitempty"`
+	// Sum of the repos' resurrection counts.
+	Resurrected int `json:"resurrected,omitempty"`
+	// Repos behind their ramp plans.
+	OffTrack int `json:"offTrack,omitempty"`
 }
 
-type issue struct {
-	Number    int       `json:"number"`
-	Title     string    `json:"title"`
-	CreatedAt time.Time `json:"createdAt"`
-	UpdatedAt time.Time `json:"updatedAt"`
-	Labels    []label   `json:"labels"`
-}
-
-type label struct {
-	Name string `json:"name"`
-}
-
-type repoInfo struct {
-	Name          string `json:"name"`
-	NameWithOwner string `json:"nameWithOwner"`
-	IsArchived    bool   `json:"isArchived"`
-}
+// The issue types are shared with the score package so library callers and
+// the CLI read the same gh JSON.
+type (
+	issue   = score.Issue
+	label   = score.Label
+	actor   = score.Actor
+	comment = score.Comment
+)
 
 func main() {
-	flag.Parse()
+	c, args, err := findCommand(os.Args[1:])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		flag.Usage()
+		os.Exit(2)
+	}
+	positional, err := parseArgs(flag.CommandLine, args)
+	if err != nil {
+		os.Exit(2)
+	}
 
 	// Configure slog based on --quiet and --json-logs flags.
 	logLevel := slog.LevelInfo
@@ -86,188 +240,852 @@ func main() {
 	}
 	slog.SetDefault(slog.New(handler))
 
-	slog.Info("fab-backlog starting", "org", *org, "min_issues", *minIssues, "stale_days", *staleDays)
+	// Ctrl-C or SIGTERM cancels in-flight gh calls; scans then report what
+	// they finished.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
 
-	out := output{
-		GeneratedAt: time.Now().UTC().Format(time.RFC3339),
-		Org:         *org,
-		Config:      config{MinIssues: *minIssues, StaleDays: *staleDays},
-		Repos:       []repoScore{},
+	cfg, err := buildConfig()
+	if err == nil {
+		err = validateGate(*failOn)
+	}
+	if err == nil {
+		err = validateRetry()
+	}
+	if err == nil {
+		err = validateRateLimit()
+	}
+	if err == nil && len(cfg.Escalation) > 0 && cfg.Storage == nil {
+		err = fmt.Errorf("escalation requires -history-file or storage")
+	}
+	if err == nil && len(cfg.Observe) > 0 && cfg.Storage == nil {
+		err = fmt.Errorf("observe requires -history-file or storage to count runs")
+	}
+	if err == nil && pagerDutyKey() != "" && cfg.Storage == nil {
+		err = fmt.Errorf("-pagerduty-routing-key requires -history-file or storage")
+	}
+	if err == nil && (*smoothingAlpha < 0 || *smoothingAlpha > 1) {
+		err = fmt.Errorf("-smoothing-alpha must be between 0 and 1")
+	}
+	if err == nil && *smoothingAlpha > 0 && cfg.Storage == nil {
+		err = fmt.Errorf("-smoothing-alpha requires -history-file or storage")
+	}
+	if err == nil && *sinceLastRun && cfg.Storage == nil {
+		err = fmt.Errorf("-since-last-run requires -history-file or storage")
+	}
+	if err == nil && *skipUnchanged && cfg.Storage == nil {
+		err = fmt.Errorf("-skip-publish-if-unchanged requires -history-file or storage")
+	}
+	if err == nil && *tenantsFile != "" && c.name != "serve" {
+		err = fmt.Errorf("-tenants only applies to serve")
+	}
+	if err == nil && *svgDir != "" && c.name != "trend" {
+		err = fmt.Errorf("-svg-dir only applies to trend")
+	}
+	if err == nil && *siteDir != "" && c.name != "publish" {
+		err = fmt.Errorf("-dir only applies to publish")
+	}
+	if err == nil && *badgeDir != "" && c.name != "scan" && c.name != "focus" {
+		err = fmt.Errorf("-badge-dir only applies to scan and focus")
+	}
+	if err == nil && *commitReport != "" && c.name != "scan" && c.name != "focus" {
+		err = fmt.Errorf("-commit-report only applies to scan and focus")
+	}
+	if err == nil && *gistFlag && c.name != "scan" && c.name != "focus" {
+		err = fmt.Errorf("-gist only applies to scan and focus")
+	}
+	if err == nil && *commitReport != "" && strings.Count(*commitReport, "/") != 1 {
+		err = fmt.Errorf("-commit-report must be owner/repo, got %q", *commitReport)
+	}
+	if err == nil && *fromFile != "" && (*recordDir != "" || *etagCacheDir != "") {
+		err = fmt.Errorf("-from-file replays recorded responses; it can't be combined with -record-dir or -etag-cache")
+	}
+	if err == nil {
+		err = pinClock()
+	}
+	if err == nil {
+		err = pinAsOf()
+	}
+	if err == nil {
+		err = validateFormat(c.name)
+	}
+	if err == nil {
+		err = validateOutput(c.name)
+	}
+	if err == nil {
+		err = validateOuts(c.name)
+	}
+	if err == nil {
+		err = validateDemo(c.name)
+	}
+	if err == nil {
+		err = validateUpload(c.name)
+	}
+	if err == nil {
+		err = applyHostname()
+	}
+	if err == nil {
+		err = validateLogin(c.name)
+	}
+	if err == nil {
+		err = applyCABundle()
+	}
+	if err == nil {
+		err = validateProvider(c, currentSelection(), cfg)
+	}
+	if err == nil {
+		err = validateGH(c, currentSelection(), cfg)
+	}
+	if err == nil {
+		err = validateLinear(c.name)
+	}
+	if err == nil {
+		err = loadTemplate(c.name)
+	}
+	if err == nil && tableByDefault(c.name) {
+		*formatFlag = "table"
+	}
+	if err == nil && *asOf != "" && c.name == "serve" {
+		err = fmt.Errorf("-as-of doesn't apply to serve")
+	}
+	if err == nil && *asOf != "" && cfg.Storage != nil {
+		err = fmt.Errorf("-as-of reports aren't stored, so they can't rewrite history: drop -history-file or storage")
 	}
+	if err == nil && *asOf != "" && *applyStaleLabel != "" && !*dryRun {
+		err = fmt.Errorf("-as-of with -apply-stale-label requires -dry-run")
+	}
+	if err == nil && *emailTo != "" && cfg.SMTP == nil {
+		err = fmt.Errorf("-email-to requires smtp settings in -config")
+	}
+	if err == nil {
+		cfg.away, err = loadAway(cfg.OOO, *oooCalendar)
+	}
+	if err != nil {
+		slog.Error("invalid config", "error", err)
+		emitJSON(map[string]any{"ok": false, "error": err.Error()})
+		os.Exit(2)
+	}
+	var run ghscan.Runner
+	if *fromFile != "" {
+		run, err = withReplay(runCmd)
+	} else if run, err = withETagCache(withThrottle(runCmd), c.name); err == nil {
+		run, err = withRecording(run)
+	}
+	if err != nil {
+		slog.Error("failed to set up gh calls", "etag_cache", *etagCacheDir, "record_dir", *recordDir, "from_file", *fromFile, "error", err)
+		emitJSON(map[string]any{"ok": false, "error": err.Error()})
+		os.Exit(2)
+	}
+	runCmd = withRetries(run)
+	applyProvider()
+	sel := currentSelection()
+	if sel.Ignore, err = loadIgnoreFile(*ignoreFile, flagSet("ignore-file")); err != nil {
+		slog.Error("invalid ignore file", "error", err)
+		emitJSON(map[string]any{"ok": false, "error": err.Error()})
+		os.Exit(2)
+	}
+	if !c.offline && *fromFile == "" {
+		useStoredToken()
+	}
+	// Tenants bring their own selections.
+	if len(sel.Orgs) == 0 && len(sel.Repos) == 0 && !c.offline && *tenantsFile == "" {
+		if sel.Orgs, err = discoverOrgs(ctx, *allOrgs, os.Stdin, os.Stderr); err != nil {
+			slog.Error("no organization to scan", "error", err)
+			emitJSON(map[string]any{"ok": false, "error": err.Error()})
+			os.Exit(2)
+		}
+	}
+	code := c.run(ctx, sel, positional, cfg)
+	stop()
+	os.Exit(code)
+}
+
+// runScan implements the default command: scan the selection (or, for
+// focus, the repos named in args), then store, publish and gate the report.
+func runScan(ctx context.Context, sel selection, args []string, cfg config, focus bool) int {
+	primary := sel.primaryOrg()
+	var err error
+	scanCtx, cancel := withScanTimeout(ctx)
+	defer cancel()
+	slog.Info("fab-backlog starting", "org", primary, "min_issues", cfg.MinIssues, "stale_days", cfg.StaleDays, "deep", cfg.Deep, "focus", focus)
 
-	slog.Info("scanning repos", "org", *org)
-	repos, err := ghListRepos(*org)
+	var repos []string
+	if focus {
+		if len(args) == 0 {
+			slog.Error("focus requires at least one repo")
+			emitJSON(map[string]any{"ok": false, "error": "focus requires at least one repo"})
+			return 2
+		}
+		repos = dedupeRepos(primary, args)
+	} else {
+		repos, err = sel.resolve(scanCtx)
+		if err != nil {
+			slog.Error("failed to list repos", "orgs", sel.Orgs, "error", err)
+			emitJSON(map[string]any{"ok": false, "error": "failed to list repos: " + err.Error()})
+			return 1
+		}
+	}
+	if *showPlan {
+		emitJSON(buildScanPlan(sel, repos, cfg))
+		return 0
+	}
+	sink, err := openReportSink()
+	if err != nil {
+		slog.Error("failed to open output", "path", *outputFile, "error", err)
+		emitJSON(map[string]any{"ok": false, "error": err.Error()})
+		return 1
+	}
+	defer sink.discard()
+	streamRepos(sink)
+	outs, err := openOutputs(outFlags)
 	if err != nil {
-		slog.Error("failed to list repos", "org", *org, "error", err)
-		emitJSON(map[string]any{"ok": false, "error": "failed to list repos: " + err.Error()})
-		os.Exit(1)
+		slog.Error("failed to open output", "error", err)
+		emitJSON(map[string]any{"ok": false, "error": err.Error()})
+		return 1
 	}
-	slog.Info("repo scan complete", "org", *org, "count", len(repos))
+	defer outs.discard()
 
-	for _, repo := range repos {
-		slog.Info("analysing repo", "repo", repo)
-		rs := computeRepoScore(repo, *org, *minIssues, *staleDays)
-		if rs.Error != "" {
-			slog.Warn("repo analysis error", "repo", repo, "error", rs.Error)
+	var store Storage
+	var hist *history
+	var prevHash string
+	if cfg.Storage != nil {
+		store = openStorage(cfg.Storage)
+		if hist, err = latestHistory(store); err != nil {
+			slog.Error("failed to load history", "driver", cfg.Storage.Driver, "error", err)
+		}
+		if prevHash, err = previousContentHash(store); err != nil {
+			slog.Error("failed to load previous report", "driver", cfg.Storage.Driver, "error", err)
+		}
+	}
+	var observed []observedPolicy
+	cfg.observing, observed = observing(cfg.Observe, hist)
+
+	var reused []repoScore
+	var incremental *incrementalInfo
+	if *sinceLastRun && !focus {
+		repos, reused, incremental = planIncremental(scanCtx, store, repos, cfg)
+	}
+	out := scanReusing(scanCtx, sel, repos, reused, cfg)
+	if incremental != nil {
+		out.Incremental = incremental
+		stampFresh(out.Repos, cfg.Deep, clock())
+	}
+	out.Observing = observed
+	exemptions, err := activeExemptions(cfg.Exemptions, *exemptionsURL, clock())
+	if err != nil {
+		slog.Error("failed to fetch central exemptions", "error", err)
+	}
+	markExempt(out.Repos, exemptions)
+	if *remediationLog != "" {
+		rep, err := verifyRemediationLog(*remediationLog, out.Repos)
+		if err != nil {
+			slog.Error("remediation verification failed", "path", *remediationLog, "error", err)
+		} else {
+			out.Remediation = rep
+			slog.Info("remediation verified", "checked", rep.Checked, "held", rep.Held, "reverted", rep.Reverted)
+		}
+	}
+	// A truncated run is missing repos, which history would read as
+	// recoveries and reset streaks over, so it isn't recorded.
+	if out.Truncated {
+		hist = nil
+	}
+	var changes []statusChange
+	if hist != nil {
+		markResurrections(out.Repos, hist, *applyStaleLabel)
+		changes = hist.record(out.Repos, *smoothingAlpha, time.Now())
+		hist.countObserved(cfg.observing)
+		out.Summary = summarize(out.Repos)
+		out.Groups = rollupGroups(out.Repos, cfg.Groups)
+	}
+	if *planOut != "" {
+		p := buildPlan(out, cfg, clock())
+		if err := writePlan(*planOut, p); err != nil {
+			slog.Error("failed to write plan", "path", *planOut, "error", err)
+			return 1
+		}
+		slog.Info("plan written", "path", *planOut, "actions", len(p.Actions))
+	}
+	if *applyStaleLabel != "" {
+		if exps := runStaleLabeling(ctx, &out, cfg, *applyStaleLabel, *dryRun, clock()); len(exps) > 0 && *remediationLog != "" {
+			if err := recordExpectations(*remediationLog, exps); err != nil {
+				slog.Error("failed to record remediation expectations", "path", *remediationLog, "error", err)
+			}
+		}
+	}
+	// Exempt repos are reported but don't count towards the gate.
+	gated := summarize(enforced(out.Repos))
+	out.Gate = gateRamps(evaluateGroupGates(&out, enforced(out.Repos), cfg.Groups, *failOn, *maxCritical), gated, *failOffTrack)
+	out.Gate = gateTruncated(out.Gate, out.Truncated)
+	if out.Gate != nil && cfg.observing["gate"] {
+		out.Gate.Observed = true
+	}
+	hash := contentHash(out)
+	warnReplayMisses()
+	out.Meta = &reportMeta{ContentHash: hash, Unchanged: prevHash == hash, AsOf: *asOf}
+	if !*deterministic {
+		out.Meta.API = currentAPIUsage(ctx)
+	}
+	if hist != nil {
+		if err := store.SaveRun(newRun(out, hist, time.Now())); err != nil {
+			slog.Error("failed to save run", "driver", cfg.Storage.Driver, "error", err)
+		}
+	}
+	// Storage keeps the real names so history still lines up; everything
+	// published from here on is pseudonymized.
+	if *demo {
+		if out, err = pseudonymize(out); err != nil {
+			slog.Error("failed to pseudonymize report", "error", err)
+			emitJSON(map[string]any{"ok": false, "error": err.Error()})
+			return 1
+		}
+	}
+	if *outputDir != "" {
+		path, err := writeArtifact(*outputDir, *keepRuns, out, time.Now())
+		if err != nil {
+			slog.Error("failed to write report", "dir", *outputDir, "error", err)
+			return 1
+		}
+		slog.Info("report written", "path", path)
+	}
+	if outs.writesReport() {
+		if err := outs.write(out); err != nil {
+			slog.Error("failed to write report", "error", err)
+			return 1
+		}
+	} else if *outputDir == "" {
+		if err := emitReport(sink, out); err != nil {
+			sink.discard()
+			slog.Error("failed to write report", "path", *outputFile, "template", *templateFile, "error", err)
+			return 1
+		}
+		if err := sink.close(); err != nil {
+			slog.Error("failed to write report", "path", *outputFile, "error", err)
+			return 1
+		}
+		if sink.file != nil {
+			slog.Info("report written", "path", *outputFile)
+		}
+	}
+	if *badgeDir != "" {
+		if err := writeBadges(*badgeDir, out); err != nil {
+			slog.Error("failed to write badges", "dir", *badgeDir, "error", err)
+			return 1
+		}
+	}
+	if err := archiveReport(out, time.Now()); err != nil {
+		slog.Error("failed to upload report", "url", *uploadURL, "error", err)
+		return 1
+	}
+	switch {
+	case out.Truncated:
+		// A partial report would post passing checks and notifications
+		// for repos that were never scanned.
+		slog.Warn("scan truncated; skipping publication")
+	case *skipUnchanged && out.Meta.Unchanged:
+		slog.Info("report unchanged since the previous run; skipping publication", "content_hash", out.Meta.ContentHash)
+	default:
+		notify(out)
+		outs.notify(out)
+		emailDigest(out, cfg)
+		publishReportIssue(out)
+		publishCheckRuns(out)
+		publishReportCommit(out)
+		publishReportGist(out)
+	}
+	// Escalation and paging follow run-to-run streaks and transitions, so
+	// they run even when the report itself hasn't changed.
+	escalate(out, cfg.Escalation)
+	pageRegressions(changes)
+	if out.Gate != nil && !out.Gate.Passed {
+		if out.Gate.Observed {
+			slog.Warn("backlog gate would fail (observe mode)", "reasons", out.Gate.Reasons)
 		} else {
-			slog.Info("repo analysis complete", "repo", repo, "health_score", rs.HealthScore, "status", rs.Status, "total_open", rs.TotalOpen, "stale_count", rs.StaleCount)
+			slog.Error("backlog gate failed", "reasons", out.Gate.Reasons)
+			return exitGateFailed
 		}
-		out.Repos = append(out.Repos, rs)
 	}
+	return 0
+}
+
+// parseArgs parses fs from args, allowing flags to be interspersed with
+// positional arguments (e.g. `focus repoA repoB --deep`). It returns the
+// positional arguments in order.
+func parseArgs(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		args = fs.Args()
+		if len(args) == 0 {
+			return positional, nil
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+}
+
+// scan analyses repos and assembles the sorted report. Repos may be given as
+// bare names (resolved against org) or as owner/name.
+func scan(ctx context.Context, sel selection, repos []string, cfg config) output {
+	return scanReusing(ctx, sel, repos, nil, cfg)
+}
+
+// scanReusing scans repos and reports them alongside scores reused from an
+// earlier run.
+func scanReusing(ctx context.Context, sel selection, repos []string, reused []repoScore, cfg config) output {
+	now := clock()
+	if *deterministic {
+		repos = byName(repos)
+	}
+	org := sel.primaryOrg()
+	if streamed != nil {
+		for _, rs := range reused {
+			streamed(rs)
+		}
+	}
+	scored := append(scoreRepos(ctx, org, repos, cfg, now), scoreLinear(ctx, org, cfg, now)...)
+	out := assemble(org, cfg, append(scored, reused...), now)
+	if len(sel.Orgs) > 1 {
+		out.Orgs = sel.Orgs
+	}
+	if ctx.Err() != nil {
+		out.Truncated = true
+		slog.Warn("scan cut short; reporting the repos finished so far", "finished", len(out.Repos), "selected", len(repos)+len(reused), "reason", context.Cause(ctx))
+		return out
+	}
+	addOrgSections(ctx, &out, cfg, now)
+	return out
+}
 
-	sort.Slice(out.Repos, func(i, j int) bool {
-		if out.Repos[i].Error != "" && out.Repos[j].Error == "" {
-			return false
+// scoreRepos fetches and scores repos as of now, -concurrency at a time. Each worker
+// fills only its repo's slot, so results keep the input order whatever the
+// scheduling. Once ctx is done no new repo starts, and repos still in
+// flight are dropped rather than reported half-fetched.
+func scoreRepos(ctx context.Context, org string, repos []string, cfg config, now time.Time) []repoScore {
+	slots := make([]*repoScore, len(repos))
+	links := newLinkChecker()
+	parallelEach(len(repos), *concurrency, func(i int) {
+		if ctx.Err() != nil {
+			return
 		}
-		if out.Repos[j].Error != "" && out.Repos[i].Error == "" {
-			return true
+		if rs := scoreRepo(ctx, org, repos[i], cfg, links, now); ctx.Err() == nil {
+			slots[i] = rs
+			if rs != nil && streamed != nil {
+				streamed(*rs)
+			}
 		}
-		return out.Repos[i].HealthScore < out.Repos[j].HealthScore
 	})
+	scored := make([]repoScore, 0, len(repos))
+	for _, rs := range slots {
+		if rs != nil {
+			scored = append(scored, *rs)
+		}
+	}
+	return scored
+}
 
-	for _, r := range out.Repos {
-		if r.Error != "" {
-			continue
+// scoreRepo fetches and scores one repo as of now, returning nil if it
+// opted out.
+func scoreRepo(ctx context.Context, org, repo string, cfg config, links *linkChecker, now time.Time) *repoScore {
+	slog.Info("analysing repo", "repo", repo)
+	owner, name := splitRepo(org, repo)
+	ctx = ghscan.WithRepo(ctx, owner+"/"+name)
+	overrides, err := ghRepoOverrides(ctx, owner, name)
+	if err != nil {
+		slog.Warn("repo config ignored", "repo", repo, "path", repoConfigPath, "error", err)
+	}
+	if overrides != nil && overrides.OptOut {
+		slog.Info("repo opted out", "repo", repo, "path", repoConfigPath)
+		return nil
+	}
+	// Topics refine the central config; the repo's file has the last word.
+	tc := repoTopicConfig(ctx, cfg, owner, name)
+	if tc.optOut {
+		slog.Info("repo opted out", "repo", repo, "source", "topics")
+		return nil
+	}
+	rcfg := overrides.apply(tc.cfg)
+	rs := computeRepoScore(ctx, name, owner, rcfg, now)
+	rs.Overrides = overrides
+	rs.Annotations, rs.Exemption = tc.annotations, tc.exemption
+	addLinkRot(ctx, &rs, owner, name, rs.issues, rcfg, links, now)
+	addStalePolicy(ctx, &rs, owner, name, rcfg, now)
+	addCodeOwners(ctx, &rs, owner, name, rcfg)
+	if owner != org {
+		rs.Name = owner + "/" + name
+		for i := range rs.securityOverdue {
+			rs.securityOverdue[i].Repo = rs.Name
 		}
-		switch r.Status {
-		case "healthy":
-			out.Summary.Healthy++
-		case "warning":
-			out.Summary.Warning++
-		case "critical":
-			out.Summary.Critical++
+	}
+	if rs.Error != "" {
+		slog.Warn("repo analysis error", "repo", repo, "error", rs.Error)
+	} else {
+		slog.Info("repo analysis complete", "repo", repo, "health_score", rs.HealthScore, "status", rs.Status, "total_open", rs.TotalOpen, "stale_count", rs.StaleCount)
+	}
+	return &rs
+}
+
+// assemble builds the report from scored repos: org-wide sections, the
+// worst-first ordering and the summary counts.
+func assemble(org string, cfg config, repos []repoScore, now time.Time) output {
+	out := output{
+		GeneratedAt: now.UTC().Format(time.RFC3339),
+		Scopes:      cfg.scopes(),
+		Org:         org,
+		Config:      cfg,
+		Repos:       repos,
+	}
+	if out.Repos == nil {
+		out.Repos = []repoScore{}
+	}
+
+	for i, rs := range out.Repos {
+		out.SecurityBacklog = append(out.SecurityBacklog, rs.securityOverdue...)
+		if rs.Error == "" && rs.Status != unscored && !rs.reused {
+			rcfg := cfg
+			rcfg.StaleDays = cmp.Or(rs.staleDays, cfg.StaleDays)
+			out.Repos[i].Recommendations = recommend(rs, rs.staleCandidates(), rcfg, now)
 		}
-		out.Summary.Total++
+	}
+	addRampStatus(out.Repos, cfg.Ramps, now)
+	tagGroups(out.Repos, cfg.Groups)
+	out.MostWanted = mostWanted(out.Repos, cfg.MostWanted, now)
+	if cfg.CodeOwners {
+		out.Ownership = rollupOwners(out.Repos)
 	}
 
+	// Worst first, then unscored repos, then ones that failed to scan.
+	rank := func(rs repoScore) int {
+		switch {
+		case rs.Error != "":
+			return 2
+		case rs.Status == unscored:
+			return 1
+		}
+		return 0
+	}
+	sort.SliceStable(out.Repos, func(i, j int) bool {
+		if ri, rj := rank(out.Repos[i]), rank(out.Repos[j]); ri != rj {
+			return ri < rj
+		}
+		if out.Repos[i].HealthScore != out.Repos[j].HealthScore || !*deterministic {
+			return out.Repos[i].HealthScore < out.Repos[j].HealthScore
+		}
+		return strings.ToLower(out.Repos[i].Name) < strings.ToLower(out.Repos[j].Name)
+	})
+
+	out.Summary = summarize(out.Repos)
+	out.Groups = rollupGroups(out.Repos, cfg.Groups)
 	slog.Info("completed",
 		"total", out.Summary.Total,
 		"healthy", out.Summary.Healthy,
 		"warning", out.Summary.Warning,
 		"critical", out.Summary.Critical,
 	)
+	return out
+}
 
-	emitJSON(out)
+// splitList splits a comma-separated flag value, dropping empty entries.
+func splitList(s string) []string {
+	out := []string{}
+	for _, part := range strings.Split(s, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			out = append(out, part)
+		}
+	}
+	return out
+}
+
+// splitRepo resolves "name" against org and passes "owner/name" through.
+func splitRepo(org, repo string) (owner, name string) {
+	if i := strings.Index(repo, "/"); i >= 0 {
+		return repo[:i], repo[i+1:]
+	}
+	return org, repo
 }
 
 func emitJSON(v any) {
-	enc := json.NewEncoder(os.Stdout)
+	_ = writeJSON(os.Stdout, v)
+}
+
+func writeJSON(w io.Writer, v any) error {
+	enc := json.NewEncoder(w)
 	enc.SetEscapeHTML(false)
-	_ = enc.Encode(v)
+	return enc.Encode(v)
+}
+
+func ghListRepos(ctx context.Context, org string) ([]string, error) {
+	return client.ListRepos(ctx, org)
 }
 
-func ghListRepos(org string) ([]string, error) {
-	if strings.TrimSpace(org) == "" {
-		return nil, fmt.Errorf("org required")
+// issueFields lists the gh issue fields a scan needs. Comments are costly,
+// so they are only requested when a metric consumes them.
+func issueFields(cfg config) string {
+	fields := ghscan.IssueFields
+	if len(cfg.SLOs) > 0 || cfg.StalePolicy != nil || cfg.TriageEffort != nil {
+		fields += ",author,comments"
+	} else if cfg.CodeDebt {
+		fields += ",author"
 	}
-	args := []string{"repo", "list", org, "--limit", "100", "--json", "name,isArchived"}
-	stdout, err := runCmd("gh", args...)
-	if err != nil {
-		return nil, err
+	if len(cfg.SLOs) > 0 && len(cfg.away) > 0 {
+		fields += ",assignees"
 	}
-	var repos []repoInfo
-	if err := json.Unmarshal(stdout, &repos); err != nil {
-		return nil, fmt.Errorf("parse gh repo list json: %w", err)
+	if cfg.MostWanted > 0 {
+		fields += ",reactionGroups"
 	}
-	var names []string
-	for _, r := range repos {
-		if !r.IsArchived {
-			names = append(names, r.Name)
-		}
+	if cfg.LinkCheck {
+		fields += ",body"
 	}
-	return names, nil
+	return fields
 }
 
-func ghListIssues(owner, repo string) ([]issue, error) {
-	args := []string{"issue", "list", "--repo", owner + "/" + repo, "--state", "open", "--json", "number,title,createdAt,updatedAt,labels", "--limit", "100"}
-	stdout, err := runCmd("gh", args...)
-	if err != nil {
-		return nil, err
+func ghListIssues(ctx context.Context, owner, repo, fields string, limit int) ([]issue, error) {
+	return client.ListIssues(ctx, owner, repo, fields, limit)
+}
+
+// ghAPIList GETs a paginated REST list endpoint via `gh api --paginate` and
+// returns the items of every page.
+func ghAPIList[T any](ctx context.Context, path string) ([]T, error) {
+	return ghscan.APIList[T](ctx, runCmd, path)
+}
+
+func computeRepoScore(ctx context.Context, repoName, org string, cfg config, now time.Time) repoScore {
+	limit := issueLimit
+	if cfg.Deep {
+		limit = deepIssueLimit
+	}
+	// Open PRs don't depend on the issues, so fetch them at the same time.
+	var waitPRs func() ([]pullRequest, error)
+	if len(cfg.AutomationBots) > 0 && cfg.inScope("prs") {
+		waitPRs = async(func() ([]pullRequest, error) { return ghListOpenPRs(ctx, org, repoName, automationLimit) })
+	}
+	score := repoScore{Name: repoName, Status: unscored}
+	if cfg.inScope("issues") {
+		issues, err := ghListIssues(ctx, org, repoName, issueFields(cfg), limit)
+		if err != nil {
+			return repoScore{Name: repoName, Error: err.Error(), fullName: org + "/" + repoName}
+		}
+		truncated := len(issues) >= limit
+		open := openAt(issues, now)
+		issues = withoutIgnored(open, cfg)
+		var debt []issue
+		if cfg.CodeDebt {
+			issues, debt = splitCodeDebt(issues, cfg.codeDebtAuthors())
+		}
+		score = scoreIssues(repoName, issues, cfg, now)
+		score.issues = issues
+		score.openIssues = open
+		score.truncated = truncated
+		score.staleDays = cfg.StaleDays
+		score.staleIssues = staleNumbers(score.staleCandidates(), cfg.StaleDays, now)
+		if cfg.CodeDebt {
+			score.CodeDebt = computeCodeDebt(debt, cfg.StaleDays, now)
+		}
+		if cfg.TriageEffort != nil {
+			score.TriageEffort = estimateTriageEffort(score, issues, *cfg.TriageEffort)
+		}
+	}
+	score.fullName = org + "/" + repoName
+	// Optional metrics each cost extra calls. A failure is logged and leaves
+	// the core score intact.
+	if cfg.inScope("issues") {
+		addResponseMetrics(ctx, &score, org, repoName, cfg, limit, now)
+		addSLACompliance(ctx, &score, org, repoName, cfg, limit, now)
+		addLabelLatency(ctx, &score, org, repoName, cfg, now)
+		addContributorMetrics(ctx, &score, org, repoName, cfg, now)
+		addProjectCoverage(ctx, &score, org, repoName, cfg, limit)
+	}
+	if cfg.inScope("discussions") {
+		addQAMetrics(ctx, &score, org, repoName, cfg, now)
 	}
-	var issues []issue
-	if err := json.Unmarshal(stdout, &issues); err != nil {
-		return nil, fmt.Errorf("parse gh issue list json: %w", err)
+	if waitPRs != nil {
+		prs, err := waitPRs()
+		addAutomationBacklog(&score, repoName, cfg, prs, err, now)
 	}
-	return issues, nil
+	addSecurityAlerts(ctx, &score, org, repoName, cfg, now)
+	return score
 }
 
-func computeRepoScore(repoName, org string, minIssues, staleDays int) repoScore {
-	score := repoScore{Name: repoName}
-	issues, err := ghListIssues(org, repoName)
-	if err != nil {
-		score.Error = err.Error()
-		return score
-	}
-	score.TotalOpen = len(issues)
-	if score.TotalOpen == 0 {
-		score.StaleCount, score.StalePercent, score.UnlabeledCount = 0, 0, 0
-		score.HealthScore, score.Status = 100, "healthy"
-		return score
+// scoreIssues computes a repo's metrics and health from its open issues as
+// of now.
+func scoreIssues(repoName string, issues []issue, cfg config, now time.Time) repoScore {
+	rs := repoScore{Name: repoName}
+	neverStale := cfg.neverStale()
+	m := score.Compute(withoutStaleLabel(issues), score.Options{MinIssues: cfg.MinIssues, StaleDays: cfg.StaleDays, UnlabeledGraceHours: cfg.UnlabeledGraceHours, NeverStale: neverStale}, now)
+	b := m.Breakdown
+	rs.TotalOpen, rs.StaleCount, rs.StalePercent = m.TotalOpen, m.StaleCount, m.StalePercent
+	rs.UnlabeledCount, rs.unlabeledPercent = m.UnlabeledCount, m.UnlabeledPercent
+	rs.HealthScore, rs.Status, rs.ScoreBreakdown = m.HealthScore, m.Status, &b
+	rs.StaleExempt = matchingNumbers(issues, neverStale)
+	if rs.TotalOpen == 0 {
+		if cfg.Deep {
+			rs.Deep = &deepMetrics{Issues: []issueDetail{}}
+		}
+		return rs
 	}
-	staleThreshold := time.Now().AddDate(0, 0, -staleDays)
+	onboarding := cfg.labelMatcher(cfg.OnboardingLabels...)
 	for _, issue := range issues {
-		if issue.UpdatedAt.Before(staleThreshold) {
-			score.StaleCount++
+		if onboarding.any(issue) {
+			rs.OnboardingCount++
 		}
-		if len(issue.Labels) == 0 {
-			score.UnlabeledCount++
+	}
+	// An overdue security issue is critical no matter how healthy the rest
+	// of the backlog looks.
+	rs.securityOverdue = overdueSecurityIssues(repoName, issues, cfg.labelMatcher(cfg.SecurityLabel), cfg.SecurityMaxDays, now)
+	if rs.SecurityOverdueCount = len(rs.securityOverdue); rs.SecurityOverdueCount > 0 && rs.Status != "critical" {
+		if cfg.observing["securityOverride"] {
+			rs.Observed = append(rs.Observed, "securityOverride: would be critical")
+		} else {
+			rs.Status = "critical"
+			b.Override = fmt.Sprintf("critical: %s open longer than %d days", plural(rs.SecurityOverdueCount, "security issue"), cfg.SecurityMaxDays)
 		}
 	}
-	score.StalePercent = float64(score.StaleCount) / float64(score.TotalOpen) * 100
-	unlabeledPercent := float64(score.UnlabeledCount) / float64(score.TotalOpen) * 100
-	score.HealthScore = computeHealthScore(score.TotalOpen, score.StalePercent, unlabeledPercent, minIssues)
-	if score.HealthScore >= 70 {
-		score.Status = "healthy"
-	} else if score.HealthScore >= 40 {
-		score.Status = "warning"
-	} else {
-		score.Status = "critical"
+	rs.SLOBreaches = computeSLOBreaches(issues, cfg, now)
+	rs.Duplicates = findDuplicates(issues, cfg.DuplicateThreshold)
+	if cfg.Deep {
+		rs.Deep = computeDeepMetrics(issues, cfg.StaleDays, now)
+		for i, d := range rs.Deep.Issues {
+			if slices.Contains(rs.StaleExempt, d.Number) {
+				rs.Deep.Issues[i].Stale = false
+			}
+		}
 	}
-	return score
+	return rs
 }
 
-func computeHealthScore(totalOpen int, stalePercent, unlabeledPercent float64, minIssues int) int {
-	if totalOpen == 0 {
-		return 100
+// withoutIgnored drops issues carrying any of the config's ignored labels,
+// leaving them out of every metric.
+func withoutIgnored(issues []issue, cfg config) []issue {
+	if len(cfg.IgnoreLabels) == 0 {
+		return issues
 	}
-	score := 50
-	if totalOpen >= minIssues {
-		score += 20
+	ignored := cfg.labelMatcher(cfg.IgnoreLabels...)
+	kept := make([]issue, 0, len(issues))
+	for _, is := range issues {
+		if !ignored.any(is) {
+			kept = append(kept, is)
+		}
 	}
-	if stalePercent < 30.0 {
-		score += 15
+	return kept
+}
+
+// hasAnyLabel reports whether is carries any of names exactly, compared
+// case-insensitively since repos disagree on "Good First Issue" casing.
+// Configured labels go through labelMatcher instead.
+func hasAnyLabel(is issue, names []string) bool {
+	for _, l := range is.Labels {
+		for _, n := range names {
+			if strings.EqualFold(l.Name, n) {
+				return true
+			}
+		}
 	}
-	if unlabeledPercent < 20.0 {
-		score += 15
+	return false
+}
+
+func computeDeepMetrics(issues []issue, staleDays int, now time.Time) *deepMetrics {
+	dm := &deepMetrics{Issues: make([]issueDetail, 0, len(issues))}
+	ages := make([]int, 0, len(issues))
+	idles := make([]int, 0, len(issues))
+	for _, is := range issues {
+		d := issueDetail{
+			Number:    is.Number,
+			Title:     is.Title,
+			AgeDays:   daysBetween(is.CreatedAt, now),
+			IdleDays:  daysBetween(is.UpdatedAt, now),
+			Stale:     is.UpdatedAt.Before(now.AddDate(0, 0, -staleDays)),
+			Unlabeled: len(is.Labels) == 0,
+		}
+		for _, l := range is.Labels {
+			d.Labels = append(d.Labels, l.Name)
+		}
+		if d.AgeDays > dm.OldestIssueDays {
+			dm.OldestIssueDays = d.AgeDays
+		}
+		ages = append(ages, d.AgeDays)
+		idles = append(idles, d.IdleDays)
+		dm.Issues = append(dm.Issues, d)
+	}
+	// Most idle first: that's the order a cleanup sprint works through them.
+	sort.SliceStable(dm.Issues, func(i, j int) bool { return dm.Issues[i].IdleDays > dm.Issues[j].IdleDays })
+	dm.MedianAgeDays = median(ages)
+	dm.MedianIdleDays = median(idles)
+	return dm
+}
+
+func daysBetween(from, to time.Time) int {
+	if from.IsZero() || to.Before(from) {
+		return 0
 	}
-	if score > 100 {
-		score = 100
+	return int(to.Sub(from).Hours() / 24)
+}
+
+func median(xs []int) int {
+	if len(xs) == 0 {
+		return 0
 	}
-	if score < 0 {
-		score = 0
+	s := append([]int(nil), xs...)
+	sort.Ints(s)
+	m := len(s) / 2
+	if len(s)%2 == 0 {
+		return (s[m-1] + s[m]) / 2
 	}
-	return score
+	return s[m]
 }
 
-func IsStale(updatedAt time.Time, staleDays int) bool {
-	return updatedAt.Before(time.Now().AddDate(0, 0, -staleDays))
+func computeHealthScore(totalOpen int, stalePercent, unlabeledPercent float64, minIssues int) int {
+	return breakdownFor(totalOpen, stalePercent, unlabeledPercent, minIssues).Total()
 }
 
-func runCmd(bin string, args ...string) ([]byte, error) {
-	cmd := exec.Command(bin, args...)
-	cmd.Env = os.Environ()
-	var stdout, stderr bytes.Buffer
-	cmd.Stdout = &stdout
-	cmd.Stderr = &stderr
-	if err := cmd.Run(); err != nil {
-		msg := strings.TrimSpace(stderr.String())
-		if msg == "" {
-			msg = strings.TrimSpace(stdout.String())
+func summarize(repos []repoScore) summary {
+	var s summary
+	for _, r := range repos {
+		if r.Error != "" || r.Status == unscored {
+			continue
 		}
-		if msg == "" {
-			msg = err.Error()
+		switch r.Status {
+		case "healthy":
+			s.Healthy++
+		case "warning":
+			s.Warning++
+		case "critical":
+			s.Critical++
+		}
+		s.Total++
+		if r.TriageEffort != nil {
+			s.TriageMinutes += r.TriageEffort.Minutes
+		}
+		if r.Resurrections != nil {
+			s.Resurrected += r.Resurrections.Count
+		}
+		if r.Ramp != nil && r.Ramp.Status == rampOffTrack {
+			s.OffTrack++
 		}
-		return nil, fmt.Errorf("%s %s: %s", bin, strings.Join(args, " "), msg)
 	}
-	return stdout.Bytes(), nil
+	return s
+}
+
+// IsStale reports whether an issue last updated at updatedAt has gone
+// staleDays without activity as of now.
+func IsStale(updatedAt time.Time, staleDays int, now time.Time) bool {
+	return updatedAt.Before(now.AddDate(0, 0, -staleDays))
+}
+
+// runCmd is the seam every GitHub call goes through; tests swap it for a
+// fake gh backed by an in-memory GitHub.
+var runCmd = ghscan.Exec
+
+// client is the seam the core reads go through: repo listings, issue lists
+// and repo files. It runs gh through runCmd, so retries, throttling,
+// caching and recording apply; tests may swap in a ghscan.Fake.
+var client ghscan.Client = ghscan.GH{Run: func(ctx context.Context, bin string, args ...string) ([]byte, error) {
+	return runCmd(ctx, bin, args...)
+}}
+
+// runBackground is runCmd for work that must finish even after a scan was
+// cut short: applying plans, publishing results and storage.
+func runBackground(bin string, args ...string) ([]byte, error) {
+	return runCmd(context.Background(), bin, args...)
 }
@@ -2,13 +2,14 @@ package main
 
 import (
 	"encoding/json"
+	"flag"
 	"testing"
 	"time"
 )
 
 func TestComputeHealthScore(t *testing.T) {
 	tests := []struct {
-		totalOpen, minIssues, want int
+		totalOpen, minIssues, want     int
 		stalePercent, unlabeledPercent float64
 	}{
 		{0, 5, 100, 0, 0},
@@ -29,17 +30,18 @@ func TestComputeHealthScore(t *testing.T) {
 }
 
 func TestIsStale(t *testing.T) {
-	now := time.Now()
-	if IsStale(now, 90) != false {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	if IsStale(now, 90, now) != false {
 		t.Error("today should not be stale")
 	}
-	if IsStale(now.AddDate(0, 0, -89), 90) != false {
+	if IsStale(now.AddDate(0, 0, -89), 90, now) != false {
 		t.Error("89 days ago should not be stale")
 	}
-	if IsStale(now.AddDate(0, 0, -90), 90) != true {
-		t.Error("90 days ago should be stale")
+	cutoff := now.AddDate(0, 0, -90)
+	if IsStale(cutoff, 90, now) != false || IsStale(cutoff.Add(-time.Nanosecond), 90, now) != true {
+		t.Error("staleness should start just past 90 days")
 	}
-	if IsStale(now.AddDate(-1, 0, 0), 90) != true {
+	if IsStale(now.AddDate(-1, 0, 0), 90, now) != true {
 		t.Error("1 year ago should be stale")
 	}
 }
@@ -66,3 +68,63 @@ func TestEdgeCases(t *testing.T) {
 		t.Error("negative should not produce negative score")
 	}
 }
+
+func TestParseArgsInterspersed(t *testing.T) {
+	fs := flag.NewFlagSet("focus", flag.ContinueOnError)
+	deep := fs.Bool("deep", false, "")
+	got, err := parseArgs(fs, []string{"repoA", "--deep", "repoB"})
+	if err != nil {
+		t.Fatalf("parseArgs: %v", err)
+	}
+	if !*deep || len(got) != 2 || got[0] != "repoA" || got[1] != "repoB" {
+		t.Errorf("parseArgs = %v (deep=%v), want [repoA repoB] deep=true", got, *deep)
+	}
+}
+
+func TestComputeDeepMetrics(t *testing.T) {
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	issues := []issue{
+		{Number: 1, CreatedAt: now.AddDate(0, 0, -400), UpdatedAt: now.AddDate(0, 0, -200), Labels: []label{{Name: "bug"}}},
+		{Number: 2, CreatedAt: now.AddDate(0, 0, -10), UpdatedAt: now.AddDate(0, 0, -1)},
+		{Number: 3, CreatedAt: now.AddDate(0, 0, -30), UpdatedAt: now.AddDate(0, 0, -30)},
+	}
+	dm := computeDeepMetrics(issues, 90, now)
+	if dm.OldestIssueDays != 400 || dm.MedianAgeDays != 30 || dm.MedianIdleDays != 30 {
+		t.Errorf("got oldest=%d medianAge=%d medianIdle=%d, want 400/30/30", dm.OldestIssueDays, dm.MedianAgeDays, dm.MedianIdleDays)
+	}
+	if len(dm.Issues) != 3 || dm.Issues[0].Number != 1 || !dm.Issues[0].Stale || dm.Issues[0].Unlabeled {
+		t.Errorf("issue 1 should sort first as stale and labeled: %+v", dm.Issues)
+	}
+	if !dm.Issues[2].Unlabeled || dm.Issues[2].Stale {
+		t.Errorf("issue 2 should be last, unlabeled and fresh: %+v", dm.Issues[2])
+	}
+}
+
+func TestHasAnyLabel(t *testing.T) {
+	names := splitList("good first issue, help wanted,")
+	if len(names) != 2 {
+		t.Fatalf("splitList = %q, want 2 entries", names)
+	}
+	if !hasAnyLabel(issue{Labels: []label{{Name: "Good First Issue"}}}, names) {
+		t.Error("label match should be case-insensitive")
+	}
+	if hasAnyLabel(issue{Labels: []label{{Name: "bug"}}}, names) {
+		t.Error("bug is not an onboarding label")
+	}
+}
+
+func TestScoreIssuesUnlabeledGrace(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	issues := []issue{
+		{Number: 1, CreatedAt: now.Add(-time.Hour), UpdatedAt: now},
+		{Number: 2, CreatedAt: now.Add(-72 * time.Hour), UpdatedAt: now},
+		{Number: 3, CreatedAt: now.Add(-72 * time.Hour), UpdatedAt: now, Labels: []label{{Name: "bug"}}},
+	}
+	if got := scoreIssues("r", issues, config{MinIssues: 5, StaleDays: 90}, now); got.UnlabeledCount != 2 {
+		t.Errorf("without grace: unlabeledCount = %d, want 2", got.UnlabeledCount)
+	}
+	got := scoreIssues("r", issues, config{MinIssues: 5, StaleDays: 90, UnlabeledGraceHours: 48}, now)
+	if got.UnlabeledCount != 1 || got.TotalOpen != 3 {
+		t.Errorf("with grace: unlabeledCount = %d totalOpen = %d, want 1 and 3", got.UnlabeledCount, got.TotalOpen)
+	}
+}
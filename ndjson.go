@@ -0,0 +1,92 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"sync"
+)
+
+// streamed is set under -format ndjson to write each repo the moment it is
+// scored, from whichever worker scored it.
+var streamed func(repoScore)
+
+// streamErr is the first error writing a streamed repo line; once set, no
+// more are written.
+var streamErr error
+
+// repoRecord is an NDJSON line for one repo: its report entry, tagged.
+type repoRecord struct {
+	Type string `json:"type"` // "repo"
+	repoScore
+}
+
+// summaryRecord is the last NDJSON line: the whole report, repos included.
+// Repo lines are as scored; the repos here are final, with what is only
+// known once every repo is in.
+type summaryRecord struct {
+	Type string `json:"type"` // "summary"
+	output
+}
+
+// validateFormat checks -format against the command it is given to.
+func validateFormat(cmd string) error {
+	switch cmd {
+	case "report":
+		return nil // checked when rendering
+	case "scan", "focus":
+		switch *formatFlag {
+		case "", "json", "ndjson", "table":
+			return nil
+		}
+		return fmt.Errorf("-format must be json, ndjson or table, got %q", *formatFlag)
+	case "trend":
+		switch *formatFlag {
+		case "", "table", "json":
+			return nil
+		}
+		return fmt.Errorf("-format must be table or json, got %q", *formatFlag)
+	}
+	if *formatFlag != "" {
+		return fmt.Errorf("-format doesn't apply to %s", cmd)
+	}
+	return nil
+}
+
+// streamRepos turns on streaming to w for -format ndjson.
+func streamRepos(w io.Writer) {
+	if *formatFlag != "ndjson" {
+		return
+	}
+	var mu sync.Mutex
+	streamErr = nil
+	streamed = func(rs repoScore) {
+		mu.Lock()
+		defer mu.Unlock()
+		if streamErr == nil {
+			streamErr = writeJSON(w, repoRecord{Type: "repo", repoScore: rs})
+		}
+	}
+}
+
+// emitReport writes the finished report to w: whole, as a table, through
+// -template, or under -format ndjson as the closing summary line. It fails
+// if a template fails to render or a write, streamed lines included, fails.
+func emitReport(w io.Writer, out output) error {
+	var err error
+	switch {
+	case *formatFlag == "table":
+		_, err = io.WriteString(w, renderTable(out, colorTable()))
+	case customTmpl != nil:
+		var doc string
+		if doc, err = renderTemplate(out); err == nil {
+			_, err = io.WriteString(w, doc)
+		}
+	case streamed == nil:
+		err = writeJSON(w, out)
+	case streamErr != nil:
+		err = streamErr
+	default:
+		err = writeJSON(w, summaryRecord{Type: "summary", output: out})
+	}
+	return err
+}
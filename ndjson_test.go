@@ -0,0 +1,114 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestNDJSONStream(t *testing.T) {
+	gh := newFakeGitHub(t)
+	genFixture(gh, "acme", 3, 4, 6, time.Now())
+	var (
+		mu    sync.Mutex
+		lines []string
+	)
+	prev := streamed
+	t.Cleanup(func() { streamed = prev })
+	streamed = func(rs repoScore) {
+		b, err := json.Marshal(repoRecord{Type: "repo", repoScore: rs})
+		if err != nil {
+			t.Error(err)
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		lines = append(lines, string(b))
+	}
+	reused := []repoScore{{Name: "repo-99", HealthScore: 100, Status: "healthy", reused: true}}
+	sel := selection{Orgs: []string{"acme"}}
+	out := scanReusing(t.Context(), sel, []string{"repo-00", "repo-01", "repo-02", "repo-03"}, reused, config{MinIssues: 1, StaleDays: 90})
+
+	var names []string
+	for _, l := range lines {
+		var rec struct {
+			Type, Name string
+		}
+		if err := json.Unmarshal([]byte(l), &rec); err != nil || rec.Type != "repo" {
+			t.Fatalf("bad repo line %s: %v", l, err)
+		}
+		names = append(names, rec.Name)
+	}
+	slices.Sort(names)
+	if !slices.Equal(names, []string{"repo-00", "repo-01", "repo-02", "repo-03", "repo-99"}) {
+		t.Errorf("streamed %v", names)
+	}
+
+	b, err := json.Marshal(summaryRecord{Type: "summary", output: out})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var sum map[string]any
+	if err := json.Unmarshal(b, &sum); err != nil {
+		t.Fatal(err)
+	}
+	if sum["type"] != "summary" || sum["summary"] == nil || sum["org"] != "acme" {
+		t.Errorf("summary line %s", b)
+	}
+	if repos, _ := sum["repos"].([]any); len(repos) != len(out.Repos) {
+		t.Errorf("summary line has %d repos, want the %d final ones", len(repos), len(out.Repos))
+	}
+	if strings.Contains(string(b), "\n") {
+		t.Error("summary isn't a single line")
+	}
+}
+
+func TestValidateFormat(t *testing.T) {
+	prev := *formatFlag
+	t.Cleanup(func() { *formatFlag = prev })
+	for _, tc := range []struct {
+		cmd, format string
+		ok          bool
+	}{
+		{"scan", "", true},
+		{"scan", "ndjson", true},
+		{"focus", "json", true},
+		{"scan", "html", false},
+		{"report", "sla", true},
+		{"triage", "ndjson", false},
+		{"triage", "", true},
+	} {
+		*formatFlag = tc.format
+		if err := validateFormat(tc.cmd); (err == nil) != tc.ok {
+			t.Errorf("%s -format %q: err %v", tc.cmd, tc.format, err)
+		}
+	}
+}
+
+// brokenWriter fails every write, like a closed pipe.
+type brokenWriter struct{}
+
+func (brokenWriter) Write([]byte) (int, error) { return 0, errors.New("broken pipe") }
+
+func TestEmitReportWriteErrors(t *testing.T) {
+	prevFormat, prevStreamed := *formatFlag, streamed
+	t.Cleanup(func() { *formatFlag, streamed = prevFormat, prevStreamed })
+	out := output{Org: "acme", Repos: []repoScore{{Name: "a", HealthScore: 90, Status: "healthy"}}}
+	for _, format := range []string{"json", "table"} {
+		*formatFlag = format
+		if err := emitReport(brokenWriter{}, out); err == nil {
+			t.Errorf("-format %s: a failed write should be an error", format)
+		}
+	}
+
+	*formatFlag = "ndjson"
+	streamRepos(brokenWriter{})
+	streamed(out.Repos[0])
+	if err := emitReport(io.Discard, out); err == nil {
+		t.Error("a failed repo line should fail the report")
+	}
+}
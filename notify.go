@@ -0,0 +1,143 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+)
+
+var notifySlack = flag.String("notify-slack", "", "Slack incoming webhook URL to post the scan summary to")
+
+// worstRepoCount is how many repos a notification lists by name.
+const worstRepoCount = 5
+
+var notifyClient = &http.Client{Timeout: 30 * time.Second, Transport: httpTransport}
+
+// postJSON POSTs v as JSON and treats any non-2xx response as an error.
+func postJSON(url string, v any, headers map[string]string) error {
+	body, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return postBody(url, body, headers)
+}
+
+func postBody(url string, body []byte, headers map[string]string) error {
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	resp, err := notifyClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("POST %s: %s: %s", req.URL.Host, resp.Status, strings.TrimSpace(string(msg)))
+	}
+	return nil
+}
+
+// worstRepos returns up to n scored repos, worst first. The report is
+// already in that order with unscored and errored repos last.
+func worstRepos(out output, n int) []repoScore {
+	var worst []repoScore
+	for _, rs := range out.Repos {
+		if rs.Error != "" || rs.Status == unscored || len(worst) == n {
+			break
+		}
+		worst = append(worst, rs)
+	}
+	return worst
+}
+
+func countErrors(out output) int {
+	n := 0
+	for _, rs := range out.Repos {
+		if rs.Error != "" {
+			n++
+		}
+	}
+	return n
+}
+
+func summaryLine(out output) string {
+	s := out.Summary
+	line := fmt.Sprintf("%d repos: %d healthy, %d warning, %d critical", s.Total, s.Healthy, s.Warning, s.Critical)
+	if n := countErrors(out); n > 0 {
+		line += fmt.Sprintf(" (%d failed to scan)", n)
+	}
+	if s.TriageMinutes > 0 {
+		line += fmt.Sprintf("; est. triage %s", formatMinutes(s.TriageMinutes))
+	}
+	return line
+}
+
+// slackMessage renders the summary as Slack mrkdwn.
+func slackMessage(out output) map[string]any {
+	var b strings.Builder
+	fmt.Fprintf(&b, "*Backlog health: %s*\n%s", out.Org, summaryLine(out))
+	if worst := worstRepos(out, worstRepoCount); len(worst) > 0 {
+		b.WriteString("\n\n*Worst repos*")
+		for _, rs := range worst {
+			fmt.Fprintf(&b, "\n• %s — %d (%s)", rs.Name, rs.HealthScore, rs.Status)
+		}
+	}
+	return map[string]any{"text": b.String()}
+}
+
+// notifiers are the sinks a report is delivered to.
+type notifiers struct {
+	Webhook string `json:"webhook,omitempty"`
+	Slack   string `json:"slack,omitempty"`
+	Teams   string `json:"teams,omitempty"`
+	Discord string `json:"discord,omitempty"`
+}
+
+// notify delivers the report to every sink configured by flags. A failed
+// delivery is logged but doesn't fail the scan.
+func notify(out output) {
+	notifiers{Webhook: *webhookURL, Slack: *notifySlack, Teams: *notifyTeams, Discord: *notifyDiscord}.send(slog.Default(), out)
+}
+
+func (n notifiers) send(log *slog.Logger, out output) {
+	if n.Webhook != "" {
+		if err := postWebhook(n.Webhook, webhookSecret(), out); err != nil {
+			log.Error("webhook delivery failed", "error", err)
+		} else {
+			log.Info("webhook delivered")
+		}
+	}
+	if n.Slack != "" {
+		if err := postJSON(n.Slack, slackMessage(out), nil); err != nil {
+			log.Error("slack notification failed", "error", err)
+		} else {
+			log.Info("slack notification sent")
+		}
+	}
+	if n.Teams != "" {
+		if err := postJSON(n.Teams, teamsMessage(out), nil); err != nil {
+			log.Error("teams notification failed", "error", err)
+		} else {
+			log.Info("teams notification sent")
+		}
+	}
+	if n.Discord != "" {
+		if err := postJSON(n.Discord, discordMessage(out), nil); err != nil {
+			log.Error("discord notification failed", "error", err)
+		} else {
+			log.Info("discord notification sent")
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func sampleReport() output {
+	return output{
+		Org: "misty-step",
+		Repos: []repoScore{
+			{Name: "neglected", HealthScore: 35, Status: "critical"},
+			{Name: "meh", HealthScore: 55, Status: "warning"},
+			{Name: "fine", HealthScore: 100, Status: "healthy"},
+			{Name: "broken", Error: "gh: HTTP 500"},
+		},
+		Summary: summary{Total: 3, Healthy: 1, Warning: 1, Critical: 1},
+	}
+}
+
+func TestSlackMessage(t *testing.T) {
+	var got map[string]string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("content type = %q", r.Header.Get("Content-Type"))
+		}
+		_ = json.NewDecoder(r.Body).Decode(&got)
+	}))
+	defer srv.Close()
+
+	if err := postJSON(srv.URL, slackMessage(sampleReport()), nil); err != nil {
+		t.Fatal(err)
+	}
+	text := got["text"]
+	for _, want := range []string{"*Backlog health: misty-step*", "3 repos: 1 healthy, 1 warning, 1 critical (1 failed to scan)", "• neglected — 35 (critical)", "• fine — 100 (healthy)"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("message missing %q:\n%s", want, text)
+		}
+	}
+	if strings.Contains(text, "broken") {
+		t.Errorf("errored repos should not be listed as worst:\n%s", text)
+	}
+}
+
+func TestPostJSONError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "invalid_token", http.StatusForbidden)
+	}))
+	defer srv.Close()
+	if err := postJSON(srv.URL, map[string]string{}, nil); err == nil || !strings.Contains(err.Error(), "invalid_token") {
+		t.Errorf("want error with response body, got %v", err)
+	}
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+)
+
+// observablePolicies can be introduced in observe mode: for the configured
+// number of runs they are evaluated and reported but don't change any
+// status or exit code, so teams see what a new policy would do before it
+// can break their CI.
+var observablePolicies = map[string]string{
+	"securityOverride": "an overdue security issue makes a repo critical",
+	"gate":             "-fail-on and -max-critical set the exit code",
+}
+
+// observedPolicy is a policy still in observe mode, including this run.
+type observedPolicy struct {
+	Name     string `json:"name"`
+	RunsLeft int    `json:"runsLeft"`
+}
+
+func validateObserve(observe map[string]int) error {
+	for name, runs := range observe {
+		if _, ok := observablePolicies[name]; !ok {
+			return fmt.Errorf("observe: unknown policy %q", name)
+		}
+		if runs < 0 {
+			return fmt.Errorf("observe %q: runs must not be negative", name)
+		}
+	}
+	return nil
+}
+
+// observing returns the policies still in observe mode given how many runs
+// h has already observed. Without history every configured policy is
+// observed, so a lost history file can't suddenly start failing builds.
+func observing(observe map[string]int, h *history) (map[string]bool, []observedPolicy) {
+	on := map[string]bool{}
+	var list []observedPolicy
+	for name, runs := range observe {
+		seen := 0
+		if h != nil {
+			seen = h.Policies[name]
+		}
+		if seen < runs {
+			on[name] = true
+			list = append(list, observedPolicy{Name: name, RunsLeft: runs - seen})
+		}
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return on, list
+}
+
+// countObserved records one more observed run for each policy in on.
+func (h *history) countObserved(on map[string]bool) {
+	if len(on) > 0 && h.Policies == nil {
+		h.Policies = map[string]int{}
+	}
+	for name := range on {
+		h.Policies[name]++
+	}
+}
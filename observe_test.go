@@ -0,0 +1,43 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestObservingCountsDown(t *testing.T) {
+	h := &history{Repos: map[string]*repoHistory{}}
+	observe := map[string]int{"gate": 2, "securityOverride": 1}
+	var left [][]observedPolicy
+	for i := 0; i < 3; i++ {
+		on, list := observing(observe, h)
+		left = append(left, list)
+		h.countObserved(on)
+	}
+	if len(left[0]) != 2 || left[0][0] != (observedPolicy{"gate", 2}) || left[0][1] != (observedPolicy{"securityOverride", 1}) {
+		t.Errorf("run 1 = %+v", left[0])
+	}
+	if len(left[1]) != 1 || left[1][0] != (observedPolicy{"gate", 1}) || len(left[2]) != 0 {
+		t.Errorf("runs 2-3 = %+v", left[1:])
+	}
+	if on, _ := observing(observe, nil); !on["gate"] || !on["securityOverride"] {
+		t.Error("without history every policy should be observed")
+	}
+	if err := validateObserve(map[string]int{"nope": 1}); err == nil {
+		t.Error("unknown policy should be rejected")
+	}
+}
+
+func TestScoreIssuesObservedSecurityOverride(t *testing.T) {
+	now := time.Now()
+	issues := []issue{{Number: 1, CreatedAt: now.AddDate(0, 0, -60), UpdatedAt: now, Labels: []label{{Name: "security"}}}}
+	cfg := config{MinIssues: 1, StaleDays: 90, SecurityLabel: "security", SecurityMaxDays: 30}
+	if rs := scoreIssues("a", issues, cfg, now); rs.Status != "critical" {
+		t.Fatalf("enforced status = %s", rs.Status)
+	}
+	cfg.observing = map[string]bool{"securityOverride": true}
+	rs := scoreIssues("a", issues, cfg, now)
+	if rs.Status != "healthy" || len(rs.Observed) != 1 || rs.SecurityOverdueCount != 1 {
+		t.Errorf("observed: status = %s, observed = %v", rs.Status, rs.Observed)
+	}
+}
@@ -0,0 +1,225 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+var oooCalendar = flag.String("ooo-calendar", "", "iCalendar (.ics) file or URL of maintainer absences; each event's summary starts with the GitHub login, e.g. \"alice: vacation\"")
+
+// oooPeriod is one maintainer absence from the config file. From and To
+// are dates (YYYY-MM-DD), both inclusive.
+type oooPeriod struct {
+	Login string `json:"login"`
+	From  string `json:"from"`
+	To    string `json:"to"`
+}
+
+func (p oooPeriod) span() (span, error) {
+	from, err := time.Parse("2006-01-02", p.From)
+	if err != nil {
+		return span{}, fmt.Errorf("ooo %q: from: %w", p.Login, err)
+	}
+	to, err := time.Parse("2006-01-02", p.To)
+	if err != nil {
+		return span{}, fmt.Errorf("ooo %q: to: %w", p.Login, err)
+	}
+	if to.Before(from) {
+		return span{}, fmt.Errorf("ooo %q: to is before from", p.Login)
+	}
+	return span{from, to.AddDate(0, 0, 1)}, nil
+}
+
+func (p oooPeriod) validate() error {
+	if p.Login == "" {
+		return fmt.Errorf("ooo: login required")
+	}
+	_, err := p.span()
+	return err
+}
+
+// span is a half-open interval [start, end).
+type span struct{ start, end time.Time }
+
+// awaySchedule holds each maintainer's absences, keyed by lower-cased login.
+// Response clocks pause while everyone an issue is assigned to is away, so
+// a vacation doesn't count against a repo's responsiveness.
+type awaySchedule map[string][]span
+
+func (a awaySchedule) add(login string, s span) {
+	key := strings.ToLower(strings.TrimPrefix(login, "@"))
+	a[key] = append(a[key], s)
+}
+
+// away reports whether login is out of office at t.
+func (a awaySchedule) away(login string, t time.Time) bool {
+	for _, s := range a[strings.ToLower(login)] {
+		if !t.Before(s.start) && t.Before(s.end) {
+			return true
+		}
+	}
+	return false
+}
+
+// allAway reports whether every one of people is out of office at t. Nobody
+// assigned means nobody to excuse.
+func (a awaySchedule) allAway(people []actor, t time.Time) bool {
+	if len(people) == 0 {
+		return false
+	}
+	for _, p := range people {
+		if !a.away(p.Login, t) {
+			return false
+		}
+	}
+	return true
+}
+
+// awayDuring returns how much of [from, to) every one of people was away
+// for. Between consecutive absence boundaries nobody's status changes, so
+// checking one instant per piece is enough.
+func (a awaySchedule) awayDuring(people []actor, from, to time.Time) time.Duration {
+	if len(a) == 0 || len(people) == 0 || !to.After(from) {
+		return 0
+	}
+	cuts := []time.Time{from, to}
+	for _, p := range people {
+		for _, s := range a[strings.ToLower(p.Login)] {
+			for _, t := range []time.Time{s.start, s.end} {
+				if t.After(from) && t.Before(to) {
+					cuts = append(cuts, t)
+				}
+			}
+		}
+	}
+	sort.Slice(cuts, func(i, j int) bool { return cuts[i].Before(cuts[j]) })
+	var total time.Duration
+	for i := 1; i < len(cuts); i++ {
+		if cuts[i].After(cuts[i-1]) && a.allAway(people, cuts[i-1]) {
+			total += cuts[i].Sub(cuts[i-1])
+		}
+	}
+	return total
+}
+
+// loadAway merges the config's ooo periods with the -ooo-calendar events.
+func loadAway(periods []oooPeriod, calendar string) (awaySchedule, error) {
+	a := awaySchedule{}
+	for _, p := range periods {
+		s, err := p.span()
+		if err != nil {
+			return nil, err
+		}
+		a.add(p.Login, s)
+	}
+	if calendar == "" {
+		return a, nil
+	}
+	data, err := readCalendar(calendar)
+	if err != nil {
+		return nil, fmt.Errorf("read ooo calendar: %w", err)
+	}
+	if err := parseICS(bytes.NewReader(data), a); err != nil {
+		return nil, fmt.Errorf("parse ooo calendar: %w", err)
+	}
+	return a, nil
+}
+
+func readCalendar(src string) ([]byte, error) {
+	if !strings.HasPrefix(src, "http://") && !strings.HasPrefix(src, "https://") {
+		return os.ReadFile(src)
+	}
+	resp, err := notifyClient.Get(src)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GET %s: %s", resp.Request.URL.Host, resp.Status)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+// parseICS adds each VEVENT to a under the login its SUMMARY starts with.
+// Only DTSTART, DTEND and SUMMARY are read; an all-day event's DTEND is
+// already exclusive, and an event without one lasts its start day.
+func parseICS(r io.Reader, a awaySchedule) error {
+	var lines []string
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		line := strings.TrimRight(sc.Text(), "\r")
+		// Long lines are folded onto continuation lines that start with
+		// whitespace.
+		if n := len(lines); n > 0 && (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) {
+			lines[n-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	if err := sc.Err(); err != nil {
+		return err
+	}
+	var in bool
+	var login string
+	var start, end time.Time
+	for _, line := range lines {
+		name, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		prop, _, _ := strings.Cut(name, ";")
+		switch strings.ToUpper(prop) {
+		case "BEGIN":
+			if strings.EqualFold(value, "VEVENT") {
+				in, login, start, end = true, "", time.Time{}, time.Time{}
+			}
+		case "SUMMARY":
+			if f := strings.Fields(value); in && len(f) > 0 {
+				login = strings.TrimRight(f[0], ":,")
+			}
+		case "DTSTART", "DTEND":
+			if !in {
+				continue
+			}
+			t, err := parseICSTime(value)
+			if err != nil {
+				return err
+			}
+			if strings.EqualFold(prop, "DTSTART") {
+				start = t
+			} else {
+				end = t
+			}
+		case "END":
+			if !in || !strings.EqualFold(value, "VEVENT") {
+				continue
+			}
+			in = false
+			if login == "" || start.IsZero() {
+				continue
+			}
+			if end.IsZero() {
+				end = start.AddDate(0, 0, 1)
+			}
+			a.add(login, span{start, end})
+		}
+	}
+	return nil
+}
+
+func parseICSTime(v string) (time.Time, error) {
+	for _, layout := range []string{"20060102T150405Z", "20060102T150405", "20060102"} {
+		if t, err := time.Parse(layout, v); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("unsupported date %q", v)
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAwayDuring(t *testing.T) {
+	a, err := loadAway([]oooPeriod{
+		{Login: "alice", From: "2025-06-02", To: "2025-06-06"},
+		{Login: "Bob", From: "2025-06-04", To: "2025-06-10"},
+	}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	day := func(d int) time.Time { return time.Date(2025, 6, d, 0, 0, 0, 0, time.UTC) }
+	alice, both := []actor{{Login: "Alice"}}, []actor{{Login: "alice"}, {Login: "bob"}}
+	if got := a.awayDuring(alice, day(1), day(10)); got != 5*24*time.Hour {
+		t.Errorf("alice away %v, want 5 days", got)
+	}
+	// Only the days both were out pause the clock.
+	if got := a.awayDuring(both, day(1), day(10)); got != 3*24*time.Hour {
+		t.Errorf("both away %v, want 3 days", got)
+	}
+	if got := a.awayDuring(nil, day(1), day(10)); got != 0 {
+		t.Errorf("unassigned away %v, want 0", got)
+	}
+	if !a.allAway(both, day(5)) || a.allAway(both, day(8)) {
+		t.Error("allAway wrong around the overlap")
+	}
+	if _, err := loadAway([]oooPeriod{{Login: "x", From: "2025-06-05", To: "2025-06-01"}}, ""); err == nil {
+		t.Error("reversed period should be rejected")
+	}
+}
+
+func TestParseICS(t *testing.T) {
+	ics := strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"BEGIN:VEVENT",
+		"SUMMARY:alice: summer",
+		"  vacation",
+		"DTSTART;VALUE=DATE:20250602",
+		"DTEND;VALUE=DATE:20250607",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"SUMMARY:@bob conference",
+		"DTSTART:20250604T090000Z",
+		"END:VEVENT",
+		"END:VCALENDAR",
+	}, "\r\n")
+	a := awaySchedule{}
+	if err := parseICS(strings.NewReader(ics), a); err != nil {
+		t.Fatal(err)
+	}
+	day := func(d int) time.Time { return time.Date(2025, 6, d, 12, 0, 0, 0, time.UTC) }
+	if !a.away("alice", day(6)) || a.away("alice", day(7)) {
+		t.Errorf("alice = %+v", a["alice"])
+	}
+	if !a.away("bob", day(4)) || a.away("bob", day(5)) {
+		t.Errorf("bob = %+v", a["bob"])
+	}
+}
+
+func TestOOOPausesResponseClocks(t *testing.T) {
+	now := time.Date(2025, 6, 20, 0, 0, 0, 0, time.UTC)
+	away, _ := loadAway([]oooPeriod{{Login: "maint", From: "2025-06-10", To: "2025-06-30"}}, "")
+	assigned := []actor{{Login: "maint"}}
+	issues := []issue{
+		{Number: 1, CreatedAt: now.AddDate(0, 0, -12), Assignees: assigned, Labels: []label{{Name: "bug"}}},
+		{Number: 2, CreatedAt: now.AddDate(0, 0, -12), Labels: []label{{Name: "bug"}}},
+	}
+	cfg := config{SLOs: []slo{{Label: "bug", FirstResponseDays: 5}}, away: away}
+	got := computeSLOBreaches(issues, cfg, now)
+	if len(got) != 1 || got[0].Number != 2 || got[0].ActualDays != 12 {
+		t.Errorf("breaches = %+v, want only the unassigned issue", got)
+	}
+	if rm := computeResponseMetrics(issues, 30, away, now); rm.Unresponded != 1 || rm.Excused != 1 {
+		t.Errorf("response metrics = %+v", rm)
+	}
+}
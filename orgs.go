@@ -0,0 +1,100 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+var allOrgs = flag.Bool("all-orgs", false, "without -org, scan every organization the authenticated account belongs to")
+
+// ghAuthOrgs lists the organizations of the account gh is logged in as.
+// App installation tokens have no user, so for them it falls back to the
+// owners of the installation's repos.
+func ghAuthOrgs(ctx context.Context) ([]string, error) {
+	type org struct {
+		Login string `json:"login"`
+	}
+	var logins []string
+	orgs, err := ghAPIList[org](ctx, "user/orgs")
+	if err == nil {
+		for _, o := range orgs {
+			logins = append(logins, o.Login)
+		}
+		return logins, nil
+	}
+	stdout, ierr := runCmd(ctx, "gh", "api", "--paginate", "installation/repositories", "--jq", ".repositories[].owner.login")
+	if ierr != nil {
+		return nil, fmt.Errorf("list organizations: %w", err)
+	}
+	for _, l := range strings.Fields(string(stdout)) {
+		logins = append(logins, l)
+	}
+	return dedupeFold(logins), nil
+}
+
+// discoverOrgs picks the orgs to scan when none were given: all of them
+// with all, the only one if there is just one, otherwise whichever the
+// user chooses when stdin is a terminal.
+func discoverOrgs(ctx context.Context, all bool, in *os.File, prompt io.Writer) ([]string, error) {
+	orgs, err := ghAuthOrgs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case len(orgs) == 0:
+		return nil, fmt.Errorf("the authenticated account belongs to no organizations; pass -org")
+	case all || len(orgs) == 1:
+		return orgs, nil
+	}
+	if st, err := in.Stat(); err != nil || st.Mode()&os.ModeCharDevice == 0 {
+		return nil, fmt.Errorf("several organizations available (%s); pass -org or -all-orgs", strings.Join(orgs, ", "))
+	}
+	return promptOrgs(orgs, in, prompt)
+}
+
+// promptOrgs asks for a comma-separated list of numbers or "all".
+func promptOrgs(orgs []string, in io.Reader, out io.Writer) ([]string, error) {
+	for i, o := range orgs {
+		fmt.Fprintf(out, "%3d) %s\n", i+1, o)
+	}
+	fmt.Fprint(out, "Scan which organizations? [numbers, comma-separated, or all]: ")
+	line, err := bufio.NewReader(in).ReadString('\n')
+	if err != nil && line == "" {
+		return nil, fmt.Errorf("read organization choice: %w", err)
+	}
+	line = strings.TrimSpace(line)
+	if strings.EqualFold(line, "all") {
+		return orgs, nil
+	}
+	var picked []string
+	for _, f := range splitList(line) {
+		n, err := strconv.Atoi(f)
+		if err != nil || n < 1 || n > len(orgs) {
+			return nil, fmt.Errorf("invalid choice %q", f)
+		}
+		picked = append(picked, orgs[n-1])
+	}
+	if len(picked) == 0 {
+		return nil, fmt.Errorf("no organization chosen")
+	}
+	return dedupeFold(picked), nil
+}
+
+// dedupeFold drops case-insensitive repeats, keeping the first spelling.
+func dedupeFold(names []string) []string {
+	seen := map[string]bool{}
+	var out []string
+	for _, n := range names {
+		if key := strings.ToLower(n); !seen[key] {
+			seen[key] = true
+			out = append(out, n)
+		}
+	}
+	return out
+}
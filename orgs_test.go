@@ -0,0 +1,55 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestPromptOrgs(t *testing.T) {
+	orgs := []string{"acme", "beta", "gamma"}
+	cases := []struct {
+		in   string
+		want []string
+		err  bool
+	}{
+		{"2\n", []string{"beta"}, false},
+		{"3, 1, 3\n", []string{"gamma", "acme"}, false},
+		{"ALL\n", orgs, false},
+		{"4\n", nil, true},
+		{"\n", nil, true},
+	}
+	for _, c := range cases {
+		var prompt strings.Builder
+		got, err := promptOrgs(orgs, strings.NewReader(c.in), &prompt)
+		if (err != nil) != c.err || !reflect.DeepEqual(got, c.want) {
+			t.Errorf("%q: got %v, %v; want %v", c.in, got, err, c.want)
+		}
+		if !strings.Contains(prompt.String(), "  2) beta") {
+			t.Errorf("prompt = %q", prompt.String())
+		}
+	}
+}
+
+func TestGhAuthOrgs(t *testing.T) {
+	prev := runCmd
+	t.Cleanup(func() { runCmd = prev })
+	runCmd = func(_ context.Context, name string, args ...string) ([]byte, error) {
+		return []byte(`[{"login":"acme"}][{"login":"beta"}]`), nil
+	}
+	if got, err := ghAuthOrgs(t.Context()); err != nil || !reflect.DeepEqual(got, []string{"acme", "beta"}) {
+		t.Errorf("user orgs = %v, %v", got, err)
+	}
+
+	runCmd = func(_ context.Context, name string, args ...string) ([]byte, error) {
+		if args[len(args)-1] == "user/orgs" {
+			return nil, errors.New("Resource not accessible by integration (HTTP 403)")
+		}
+		return []byte("acme\nAcme\nbeta\n"), nil
+	}
+	if got, err := ghAuthOrgs(t.Context()); err != nil || !reflect.DeepEqual(got, []string{"acme", "beta"}) {
+		t.Errorf("installation owners = %v, %v", got, err)
+	}
+}
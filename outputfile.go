@@ -0,0 +1,103 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+var outputFile = flag.String("output", "", "write the report to this file instead of stdout, replacing it atomically once complete (- is stdout)")
+
+// atomicFile is written through a temp file beside path and renamed over
+// it on commit, so readers see the old file or the complete new one, never
+// a partial write.
+type atomicFile struct {
+	path string
+	tmp  *os.File
+}
+
+func createAtomic(path string) (*atomicFile, error) {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp*")
+	if err != nil {
+		return nil, fmt.Errorf("write %s: %w", path, err)
+	}
+	return &atomicFile{path: path, tmp: tmp}, nil
+}
+
+func (f *atomicFile) Write(p []byte) (int, error) {
+	return f.tmp.Write(p)
+}
+
+// commit moves the finished file into place.
+func (f *atomicFile) commit() error {
+	defer os.Remove(f.tmp.Name())
+	if err := f.tmp.Close(); err != nil {
+		return fmt.Errorf("write %s: %w", f.path, err)
+	}
+	if err := os.Chmod(f.tmp.Name(), 0o644); err != nil {
+		return fmt.Errorf("write %s: %w", f.path, err)
+	}
+	if err := os.Rename(f.tmp.Name(), f.path); err != nil {
+		return fmt.Errorf("write %s: %w", f.path, err)
+	}
+	return nil
+}
+
+// abort drops the temp file, leaving path as it was. It is a no-op after
+// commit.
+func (f *atomicFile) abort() {
+	f.tmp.Close()
+	os.Remove(f.tmp.Name())
+}
+
+// reportSink is where a command writes its report: stdout, or the -output
+// file.
+type reportSink struct {
+	io.Writer
+	file *atomicFile
+}
+
+// openReportSink opens the -output file, failing before any work is done
+// if it can't be written.
+func openReportSink() (*reportSink, error) {
+	if *outputFile == "" || *outputFile == "-" {
+		return &reportSink{Writer: os.Stdout}, nil
+	}
+	f, err := createAtomic(*outputFile)
+	if err != nil {
+		return nil, err
+	}
+	return &reportSink{Writer: f, file: f}, nil
+}
+
+// close publishes a complete report. Until then a -output file is left
+// untouched.
+func (s *reportSink) close() error {
+	if s.file == nil {
+		return nil
+	}
+	return s.file.commit()
+}
+
+// discard abandons the report, on any path that doesn't reach close.
+func (s *reportSink) discard() {
+	if s.file != nil {
+		s.file.abort()
+	}
+}
+
+// validateOutput checks -output against the command and -output-dir.
+func validateOutput(cmd string) error {
+	if *outputFile == "" {
+		return nil
+	}
+	switch {
+	case *outputDir != "":
+		return fmt.Errorf("-output and -output-dir are mutually exclusive")
+	case cmd != "scan" && cmd != "focus" && cmd != "report":
+		return fmt.Errorf("-output doesn't apply to %s", cmd)
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestReportSinkIsAtomic(t *testing.T) {
+	prev := *outputFile
+	t.Cleanup(func() { *outputFile = prev })
+	path := filepath.Join(t.TempDir(), "report.json")
+	if err := os.WriteFile(path, []byte("old\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	*outputFile = path
+
+	sink, err := openReportSink()
+	if err != nil {
+		t.Fatal(err)
+	}
+	fmt.Fprintln(sink, "half a rep")
+	if data, _ := os.ReadFile(path); string(data) != "old\n" {
+		t.Errorf("readers saw %q mid-write", data)
+	}
+	sink.discard()
+	if data, _ := os.ReadFile(path); string(data) != "old\n" {
+		t.Errorf("discarded write replaced the file: %q", data)
+	}
+
+	sink, err = openReportSink()
+	if err != nil {
+		t.Fatal(err)
+	}
+	fmt.Fprintln(sink, "new")
+	if err := sink.close(); err != nil {
+		t.Fatal(err)
+	}
+	sink.discard() // the deferred call after a successful close
+	if data, _ := os.ReadFile(path); string(data) != "new\n" {
+		t.Errorf("file = %q", data)
+	}
+	if entries, _ := os.ReadDir(filepath.Dir(path)); len(entries) != 1 {
+		t.Errorf("temp files left behind: %v", entries)
+	}
+
+	*outputFile = filepath.Join(t.TempDir(), "missing", "report.json")
+	if _, err := openReportSink(); err == nil {
+		t.Error("an unwritable -output should fail up front")
+	}
+	*outputFile = "-"
+	if sink, err := openReportSink(); err != nil || sink.file != nil {
+		t.Errorf("-output - should be stdout: %+v, %v", sink, err)
+	}
+}
+
+func TestReportCommandOutput(t *testing.T) {
+	prevOut, prevFormat := *outputFile, *formatFlag
+	t.Cleanup(func() { *outputFile, *formatFlag = prevOut, prevFormat })
+	dir := t.TempDir()
+	in := filepath.Join(dir, "report.json")
+	data, _ := json.Marshal(output{Org: "acme", Repos: []repoScore{{Name: "api", HealthScore: 55, Status: "warning"}}})
+	if err := os.WriteFile(in, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	*outputFile, *formatFlag = filepath.Join(dir, "report.md"), "markdown"
+	if code := runReport([]string{in}, config{}); code != 0 {
+		t.Fatalf("exit %d", code)
+	}
+	if md, _ := os.ReadFile(*outputFile); !strings.Contains(string(md), "| api | 55 | warning |") {
+		t.Errorf("report.md = %s", md)
+	}
+}
+
+func TestValidateOutput(t *testing.T) {
+	prevOut, prevDir := *outputFile, *outputDir
+	t.Cleanup(func() { *outputFile, *outputDir = prevOut, prevDir })
+	*outputFile = "report.json"
+	for cmd, ok := range map[string]bool{"scan": true, "focus": true, "report": true, "serve": false, "triage": false} {
+		if err := validateOutput(cmd); (err == nil) != ok {
+			t.Errorf("%s: err %v", cmd, err)
+		}
+	}
+	*outputDir = "reports"
+	if err := validateOutput("scan"); err == nil {
+		t.Error("-output with -output-dir should be rejected")
+	}
+}
@@ -0,0 +1,237 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"slices"
+	"strings"
+)
+
+var outFlags outputSpecs
+
+func init() {
+	flag.Var(&outFlags, "out", "deliver the report as FORMAT=DEST (repeatable) instead of to stdout: json, markdown, html, sla or template (-template) to a file, - (stdout) or step-summary; slack, teams, discord or webhook to a URL")
+}
+
+// stepSummary is the -out destination for the GitHub Actions job summary.
+const stepSummary = "step-summary"
+
+// renderers are the -out formats written to files.
+var renderers = map[string]func(output) (string, error){
+	"json":     renderJSON,
+	"markdown": renderMarkdown,
+	"html":     renderHTML,
+	"sla":      renderSLA,
+	"template": renderTemplate,
+}
+
+// notifyFormats are the -out formats posted to a webhook URL.
+var notifyFormats = []string{"slack", "teams", "discord", "webhook"}
+
+func renderJSON(out output) (string, error) {
+	var b bytes.Buffer
+	err := writeJSON(&b, out)
+	return b.String(), err
+}
+
+// outputSpec is one -out: a format and where it goes.
+type outputSpec struct {
+	Format, Dest string
+}
+
+func (s outputSpec) notification() bool {
+	return slices.Contains(notifyFormats, s.Format)
+}
+
+// outputSpecs is the repeatable -out flag.
+type outputSpecs []outputSpec
+
+func (l *outputSpecs) String() string {
+	parts := make([]string, len(*l))
+	for i, s := range *l {
+		parts[i] = s.Format + "=" + s.Dest
+	}
+	return strings.Join(parts, ",")
+}
+
+func (l *outputSpecs) Set(v string) error {
+	format, dest, ok := strings.Cut(v, "=")
+	if !ok || dest == "" {
+		return fmt.Errorf("want FORMAT=DEST, got %q", v)
+	}
+	s := outputSpec{Format: format, Dest: dest}
+	isURL := strings.HasPrefix(dest, "https://") || strings.HasPrefix(dest, "http://")
+	switch {
+	case s.notification():
+		if !isURL {
+			return fmt.Errorf("%s needs a webhook URL, got %q", format, dest)
+		}
+	case renderers[format] != nil:
+		if isURL {
+			return fmt.Errorf("%s is written to a file, - or %s, not a URL", format, stepSummary)
+		}
+	default:
+		return fmt.Errorf("unknown format %q: want json, markdown, html, sla, template, slack, teams, discord or webhook", format)
+	}
+	*l = append(*l, s)
+	return nil
+}
+
+// validateOuts checks -out against the command and the other output flags.
+func validateOuts(cmd string) error {
+	if len(outFlags) == 0 {
+		return nil
+	}
+	switch {
+	case cmd != "scan" && cmd != "focus" && cmd != "report":
+		return fmt.Errorf("-out doesn't apply to %s", cmd)
+	case *outputFile != "":
+		return fmt.Errorf("-out and -output are mutually exclusive")
+	case *formatFlag != "":
+		return fmt.Errorf("-out names each format itself; drop -format")
+	}
+	seen := map[string]bool{}
+	for _, s := range outFlags {
+		if s.notification() {
+			if cmd == "report" {
+				return fmt.Errorf("-out %s only applies to scans", s.Format)
+			}
+			continue
+		}
+		if s.Format == "template" && *templateFile == "" {
+			return fmt.Errorf("-out template=%s needs -template FILE", s.Dest)
+		}
+		if seen[s.Dest] {
+			return fmt.Errorf("-out: %s is written twice", s.Dest)
+		}
+		seen[s.Dest] = true
+		if s.Dest == stepSummary && os.Getenv("GITHUB_STEP_SUMMARY") == "" {
+			return fmt.Errorf("-out %s=%s: $GITHUB_STEP_SUMMARY isn't set; it is only available in GitHub Actions", s.Format, stepSummary)
+		}
+	}
+	return nil
+}
+
+// openOutput is one -out destination, its file opened before the scan.
+type openOutput struct {
+	outputSpec
+	w    io.Writer
+	file *atomicFile
+}
+
+// outputs are the -out destinations of a run.
+type outputs []openOutput
+
+// openOutputs creates each -out file, failing before any work is done if
+// one can't be written. Files are replaced atomically once complete; the
+// step summary is appended to, as GitHub expects.
+func openOutputs(specs []outputSpec) (outputs, error) {
+	var outs outputs
+	for _, s := range specs {
+		o := openOutput{outputSpec: s}
+		switch {
+		case s.notification():
+		case s.Dest == "-":
+			o.w = os.Stdout
+		case s.Dest == stepSummary:
+		default:
+			f, err := createAtomic(s.Dest)
+			if err != nil {
+				outs.discard()
+				return nil, err
+			}
+			o.w, o.file = f, f
+		}
+		outs = append(outs, o)
+	}
+	return outs, nil
+}
+
+// writesReport reports whether any destination takes the report itself,
+// replacing stdout; notifications alone leave stdout alone.
+func (outs outputs) writesReport() bool {
+	return slices.ContainsFunc(outs, func(o openOutput) bool { return !o.notification() })
+}
+
+// write renders the report into every file destination and publishes
+// them. A destination that fails doesn't stop the others; the errors are
+// returned together.
+func (outs outputs) write(out output) error {
+	var errs []error
+	for _, o := range outs {
+		if o.notification() {
+			continue
+		}
+		doc, err := renderers[o.Format](out)
+		if err == nil {
+			err = o.publish(doc)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("-out %s=%s: %w", o.Format, o.Dest, err))
+			continue
+		}
+		if o.Dest != "-" {
+			slog.Info("report written", "format", o.Format, "path", o.Dest)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (o openOutput) publish(doc string) error {
+	if o.Dest == stepSummary {
+		return appendFile(os.Getenv("GITHUB_STEP_SUMMARY"), doc)
+	}
+	if _, err := io.WriteString(o.w, doc); err != nil {
+		return err
+	}
+	if o.file != nil {
+		return o.file.commit()
+	}
+	return nil
+}
+
+func appendFile(path, doc string) error {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := io.WriteString(f, doc); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// notify posts the report to every notification destination.
+func (outs outputs) notify(out output) {
+	for _, o := range outs {
+		var n notifiers
+		switch o.Format {
+		case "slack":
+			n.Slack = o.Dest
+		case "teams":
+			n.Teams = o.Dest
+		case "discord":
+			n.Discord = o.Dest
+		case "webhook":
+			n.Webhook = o.Dest
+		default:
+			continue
+		}
+		n.send(slog.Default(), out)
+	}
+}
+
+// discard abandons any files not yet published.
+func (outs outputs) discard() {
+	for _, o := range outs {
+		if o.file != nil {
+			o.file.abort()
+		}
+	}
+}
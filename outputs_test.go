@@ -0,0 +1,112 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestOutputSpecsSet(t *testing.T) {
+	var l outputSpecs
+	for _, v := range []string{"json=report.json", "markdown=step-summary", "slack=https://hooks.slack.test/x", "html=-"} {
+		if err := l.Set(v); err != nil {
+			t.Fatalf("%s: %v", v, err)
+		}
+	}
+	if l.String() != "json=report.json,markdown=step-summary,slack=https://hooks.slack.test/x,html=-" {
+		t.Errorf("String() = %s", l.String())
+	}
+	for _, bad := range []string{"json", "json=", "csv=out.csv", "slack=alerts.txt", "markdown=https://example.test/report"} {
+		if err := l.Set(bad); err == nil {
+			t.Errorf("%q should be rejected", bad)
+		}
+	}
+}
+
+func TestValidateOuts(t *testing.T) {
+	prevOuts, prevFile, prevFormat := outFlags, *outputFile, *formatFlag
+	t.Cleanup(func() { outFlags, *outputFile, *formatFlag = prevOuts, prevFile, prevFormat })
+	t.Setenv("GITHUB_STEP_SUMMARY", "")
+
+	outFlags = outputSpecs{{"json", "a.json"}, {"slack", "https://hooks.slack.test/x"}}
+	if err := validateOuts("scan"); err != nil {
+		t.Errorf("scan: %v", err)
+	}
+	for cmd, want := range map[string]string{"runs": "doesn't apply", "report": "only applies to scans"} {
+		if err := validateOuts(cmd); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("%s: err %v, want %q", cmd, err, want)
+		}
+	}
+	outFlags = outputSpecs{{"json", "a.json"}, {"markdown", "a.json"}}
+	if err := validateOuts("scan"); err == nil {
+		t.Error("two formats into one file should be rejected")
+	}
+	outFlags = outputSpecs{{"markdown", stepSummary}}
+	if err := validateOuts("scan"); err == nil {
+		t.Error("step-summary outside Actions should be rejected")
+	}
+	outFlags = outputSpecs{{"json", "a.json"}}
+	*formatFlag = "ndjson"
+	if err := validateOuts("scan"); err == nil {
+		t.Error("-out with -format should be rejected")
+	}
+	*formatFlag, *outputFile = "", "b.json"
+	if err := validateOuts("scan"); err == nil {
+		t.Error("-out with -output should be rejected")
+	}
+}
+
+func TestOutputsFanOut(t *testing.T) {
+	dir := t.TempDir()
+	summaryPath := filepath.Join(dir, "summary.md")
+	if err := os.WriteFile(summaryPath, []byte("## Earlier step\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("GITHUB_STEP_SUMMARY", summaryPath)
+	var posted map[string]string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&posted)
+	}))
+	defer srv.Close()
+
+	jsonPath := filepath.Join(dir, "report.json")
+	outs, err := openOutputs([]outputSpec{{"json", jsonPath}, {"markdown", stepSummary}, {"slack", srv.URL}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer outs.discard()
+	if !outs.writesReport() {
+		t.Error("json and markdown destinations take the report")
+	}
+	if _, err := os.Stat(jsonPath); err == nil {
+		t.Error("the report file should only appear once complete")
+	}
+	report := sampleReport()
+	if err := outs.write(report); err != nil {
+		t.Fatal(err)
+	}
+	outs.notify(report)
+
+	var got output
+	if data, err := os.ReadFile(jsonPath); err != nil || json.Unmarshal(data, &got) != nil || got.Org != "misty-step" {
+		t.Errorf("report.json = %+v, %v", got, err)
+	}
+	md, _ := os.ReadFile(summaryPath)
+	if !strings.HasPrefix(string(md), "## Earlier step\n") || !strings.Contains(string(md), "| neglected | 35 | critical |") {
+		t.Errorf("step summary should be appended to:\n%s", md)
+	}
+	if !strings.Contains(posted["text"], "*Backlog health: misty-step*") {
+		t.Errorf("slack got %v", posted)
+	}
+
+	if outs, _ := openOutputs([]outputSpec{{"slack", srv.URL}}); outs.writesReport() {
+		t.Error("notifications alone should leave the report on stdout")
+	}
+	if _, err := openOutputs([]outputSpec{{"json", filepath.Join(dir, "missing", "r.json")}}); err == nil {
+		t.Error("an unwritable destination should fail up front")
+	}
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+)
+
+var pagerDutyKeyFlag = flag.String("pagerduty-routing-key", "", "PagerDuty Events API v2 routing key; pages when a repo becomes critical (default $FAB_BACKLOG_PAGERDUTY_KEY, needs -history-file)")
+
+// pagerDutyURL is swapped out in tests.
+var pagerDutyURL = "https://events.pagerduty.com/v2/enqueue"
+
+func pagerDutyKey() string {
+	if *pagerDutyKeyFlag != "" {
+		return *pagerDutyKeyFlag
+	}
+	return os.Getenv("FAB_BACKLOG_PAGERDUTY_KEY")
+}
+
+// statusChange is a repo whose status differs from the previous run's.
+type statusChange struct {
+	Repo        string
+	From, To    string
+	HealthScore int
+	Exempt      bool
+}
+
+// pagerDutyEvent triggers an incident when a repo falls into critical and
+// resolves it when the repo recovers. The dedup key ties the two together,
+// and keeps a repo to one open incident.
+func pagerDutyEvent(key string, c statusChange) (map[string]any, bool) {
+	ev := map[string]any{
+		"routing_key": key,
+		"dedup_key":   "fab-backlog/" + strings.ToLower(c.Repo),
+	}
+	switch {
+	case c.To == "critical":
+		ev["event_action"] = "trigger"
+		ev["payload"] = map[string]any{
+			"summary":        fmt.Sprintf("Backlog collapse: %s is critical (score %d, was %s)", c.Repo, c.HealthScore, c.From),
+			"source":         c.Repo,
+			"severity":       "error",
+			"component":      "backlog",
+			"custom_details": map[string]any{"healthScore": c.HealthScore, "previousStatus": c.From},
+		}
+	case c.From == "critical":
+		ev["event_action"] = "resolve"
+	default:
+		return nil, false
+	}
+	return ev, true
+}
+
+func pageRegressions(changes []statusChange) {
+	key := pagerDutyKey()
+	if key == "" {
+		return
+	}
+	for _, c := range changes {
+		// An exempt repo isn't paged, but an incident opened before the
+		// exemption still resolves.
+		if c.Exempt && c.To == "critical" {
+			continue
+		}
+		ev, ok := pagerDutyEvent(key, c)
+		if !ok {
+			continue
+		}
+		if err := postJSON(pagerDutyURL, ev, nil); err != nil {
+			slog.Error("pagerduty event failed", "repo", c.Repo, "error", err)
+		} else {
+			slog.Info("pagerduty event sent", "repo", c.Repo, "action", ev["event_action"])
+		}
+	}
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestPageRegressions(t *testing.T) {
+	var events []map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var ev map[string]any
+		_ = json.NewDecoder(r.Body).Decode(&ev)
+		events = append(events, ev)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer srv.Close()
+	defer func(u string) { pagerDutyURL = u }(pagerDutyURL)
+	pagerDutyURL = srv.URL
+	*pagerDutyKeyFlag = "rk"
+	defer func() { *pagerDutyKeyFlag = "" }()
+
+	pageRegressions([]statusChange{
+		{Repo: "org/a", From: "warning", To: "critical", HealthScore: 35},
+		{Repo: "org/b", From: "critical", To: "warning", HealthScore: 50},
+		{Repo: "org/c", From: "healthy", To: "warning", HealthScore: 60},
+	})
+	if len(events) != 2 {
+		t.Fatalf("events = %+v", events)
+	}
+	if events[0]["event_action"] != "trigger" || events[0]["dedup_key"] != "fab-backlog/org/a" || events[0]["routing_key"] != "rk" {
+		t.Errorf("trigger = %+v", events[0])
+	}
+	if events[1]["event_action"] != "resolve" || events[1]["dedup_key"] != "fab-backlog/org/b" {
+		t.Errorf("resolve = %+v", events[1])
+	}
+}
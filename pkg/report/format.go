@@ -0,0 +1,124 @@
+// Package report formats values the way fab-backlog's Markdown and HTML
+// reports print them: locale-aware numbers and dates, and Markdown table
+// cells that can't break their row.
+//
+// A Format's Funcs plug straight into text/template or html/template:
+//
+//	f, err := report.Lookup("de-DE")
+//	t := template.Must(template.New("r").Funcs(f.Funcs()).Parse(`{{num .Total}}`))
+package report
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Format is how a locale writes numbers and dates in reports.
+type Format struct {
+	group   string // thousands separator
+	decimal string
+	date    string // time layout for timestamps
+}
+
+// ISO is the default: no grouping and unambiguous ISO 8601 dates.
+var ISO = Format{group: "", decimal: ".", date: "2006-01-02 15:04 MST"}
+
+// locales maps BCP 47 tags to their conventional formats. Bare languages
+// pick their most common region.
+var locales = map[string]Format{
+	"en-us": {",", ".", "01/02/2006 3:04 PM MST"},
+	"en-gb": {",", ".", "02/01/2006 15:04 MST"},
+	"en-in": {",", ".", "02/01/2006 15:04 MST"},
+	"de-de": {".", ",", "02.01.2006 15:04 MST"},
+	"de-ch": {"’", ".", "02.01.2006 15:04 MST"},
+	"fr-fr": {"\u202f", ",", "02/01/2006 15:04 MST"},
+	"es-es": {".", ",", "02/01/2006 15:04 MST"},
+	"it-it": {".", ",", "02/01/2006 15:04 MST"},
+	"nl-nl": {".", ",", "02-01-2006 15:04 MST"},
+	"pt-br": {".", ",", "02/01/2006 15:04 MST"},
+	"sv-se": {"\u00a0", ",", "2006-01-02 15:04 MST"},
+	"pl-pl": {"\u00a0", ",", "02.01.2006 15:04 MST"},
+	"ja-jp": {",", ".", "2006/01/02 15:04 MST"},
+	"zh-cn": {",", ".", "2006/01/02 15:04 MST"},
+	"ko-kr": {",", ".", "2006. 01. 02. 15:04 MST"},
+}
+
+var localeLanguages = map[string]string{
+	"en": "en-us", "de": "de-de", "fr": "fr-fr", "es": "es-es", "it": "it-it", "nl": "nl-nl",
+	"pt": "pt-br", "sv": "sv-se", "pl": "pl-pl", "ja": "ja-jp", "zh": "zh-cn", "ko": "ko-kr",
+}
+
+// Lookup resolves a tag such as "de-DE", "de_DE" or "de". The empty tag is
+// ISO.
+func Lookup(tag string) (Format, error) {
+	if tag == "" {
+		return ISO, nil
+	}
+	key := strings.ToLower(strings.ReplaceAll(tag, "_", "-"))
+	if full, ok := localeLanguages[key]; ok {
+		key = full
+	}
+	if f, ok := locales[key]; ok {
+		return f, nil
+	}
+	return Format{}, fmt.Errorf("unsupported locale %q", tag)
+}
+
+// Integer formats n with the locale's thousands separator.
+func (f Format) Integer(n int) string {
+	s := strconv.Itoa(n)
+	neg := strings.HasPrefix(s, "-")
+	s = strings.TrimPrefix(s, "-")
+	if f.group != "" {
+		for i := len(s) - 3; i > 0; i -= 3 {
+			s = s[:i] + f.group + s[i:]
+		}
+	}
+	if neg {
+		s = "-" + s
+	}
+	return s
+}
+
+// Float formats x with prec digits after the decimal separator.
+func (f Format) Float(x float64, prec int) string {
+	s := strconv.FormatFloat(x, 'f', prec, 64)
+	whole, frac, _ := strings.Cut(s, ".")
+	n, _ := strconv.Atoi(whole)
+	out := f.Integer(n)
+	if n == 0 && strings.HasPrefix(whole, "-") {
+		out = "-" + out
+	}
+	if frac != "" {
+		out += f.decimal + frac
+	}
+	return out
+}
+
+// Timestamp reformats an RFC 3339 timestamp, passing anything else through
+// unchanged.
+func (f Format) Timestamp(s string) string {
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return s
+	}
+	return t.Format(f.date)
+}
+
+// Funcs are the template helpers that format through f: num, decimal and
+// date.
+func (f Format) Funcs() map[string]any {
+	return map[string]any{
+		"num":     f.Integer,
+		"decimal": f.Float,
+		"date":    f.Timestamp,
+	}
+}
+
+// MarkdownCell keeps a value from breaking out of its table cell.
+func MarkdownCell(s string) string {
+	s = strings.ReplaceAll(s, "|", `\|`)
+	return strings.Join(strings.Fields(s), " ")
+}
@@ -0,0 +1,35 @@
+package report
+
+import "testing"
+
+func TestLocaleFormats(t *testing.T) {
+	de, err := Lookup("de_DE")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := de.Integer(1234567); got != "1.234.567" {
+		t.Errorf("de integer = %q", got)
+	}
+	if got := de.Float(-1234.5, 1); got != "-1.234,5" {
+		t.Errorf("de float = %q", got)
+	}
+	if got := de.Timestamp("2025-03-04T05:06:07Z"); got != "04.03.2025 05:06 UTC" {
+		t.Errorf("de date = %q", got)
+	}
+	us, _ := Lookup("en")
+	if got := us.Timestamp("2025-03-04T15:06:07Z"); got != "03/04/2025 3:06 PM UTC" {
+		t.Errorf("en date = %q", got)
+	}
+	if got := ISO.Integer(1234) + " " + ISO.Float(-0.25, 1); got != "1234 -0.2" {
+		t.Errorf("iso = %q", got)
+	}
+	if _, err := Lookup("xx-YY"); err == nil {
+		t.Error("unknown locale should be rejected")
+	}
+}
+
+func TestMarkdownCell(t *testing.T) {
+	if got := MarkdownCell("a|b\nc"); got != `a\|b c` {
+		t.Errorf("MarkdownCell = %q", got)
+	}
+}
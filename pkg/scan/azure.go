@@ -0,0 +1,204 @@
+package scan
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/misty-step/fab-backlog/pkg/score"
+)
+
+// AzureDevOps is the Client for Azure DevOps Boards, in the cloud at
+// https://dev.azure.com or on a server, at BaseURL. The org is the
+// organization (a server's collection) and projects stand in for repos.
+// Work items of every type are issues, open until their state is Closed,
+// Done, Completed or Removed, and their tags count as labels. Token is a
+// personal access token, sent with basic auth.
+type AzureDevOps struct {
+	BaseURL string
+	Token   string
+	HTTP    *http.Client
+}
+
+// azureAPIVersion is the REST API version requested; servers from 2019 on
+// serve it.
+const azureAPIVersion = "5.0"
+
+// azurePageSize is the most projects, and work items, fetched per request.
+const azurePageSize = 200
+
+// azureFields are the work item fields a scan reads.
+const azureFields = "System.Title,System.CreatedDate,System.ChangedDate,System.Tags,System.CreatedBy,System.AssignedTo"
+
+// azureOpenQuery selects a project's open work items, newest first.
+const azureOpenQuery = "SELECT [System.Id] FROM WorkItems WHERE [System.TeamProject] = @project AND [System.State] NOT IN ('Closed', 'Done', 'Completed', 'Removed') ORDER BY [System.CreatedDate] DESC"
+
+type azureProject struct {
+	Name           string    `json:"name"`
+	State          string    `json:"state"`
+	LastUpdateTime time.Time `json:"lastUpdateTime"`
+}
+
+type azureIdentity struct {
+	UniqueName string `json:"uniqueName"`
+}
+
+type azureWorkItem struct {
+	ID     int `json:"id"`
+	Fields struct {
+		Title      string         `json:"System.Title"`
+		Created    time.Time      `json:"System.CreatedDate"`
+		Changed    time.Time      `json:"System.ChangedDate"`
+		Tags       string         `json:"System.Tags"`
+		CreatedBy  azureIdentity  `json:"System.CreatedBy"`
+		AssignedTo *azureIdentity `json:"System.AssignedTo"`
+	} `json:"fields"`
+}
+
+// issue converts a work item, stale by its changed date. Tags come as one
+// "a; b" string.
+func (wi azureWorkItem) issue() score.Issue {
+	f := wi.Fields
+	is := score.Issue{Number: wi.ID, Title: f.Title, CreatedAt: f.Created, UpdatedAt: f.Changed, Author: score.Actor{Login: f.CreatedBy.UniqueName}}
+	for tag := range strings.SplitSeq(f.Tags, ";") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			is.Labels = append(is.Labels, score.Label{Name: tag})
+		}
+	}
+	if f.AssignedTo != nil {
+		is.Assignees = []score.Actor{{Login: f.AssignedTo.UniqueName}}
+	}
+	return is
+}
+
+func (a AzureDevOps) header() http.Header {
+	header := http.Header{}
+	if a.Token != "" {
+		header.Set("Authorization", "Basic "+base64.StdEncoding.EncodeToString([]byte(":"+a.Token)))
+	}
+	return header
+}
+
+// url is path under org's _apis, with query and the API version.
+func (a AzureDevOps) url(org, path string, query url.Values) string {
+	if query == nil {
+		query = url.Values{}
+	}
+	query.Set("api-version", azureAPIVersion)
+	return strings.TrimSuffix(a.BaseURL, "/") + "/" + url.PathEscape(org) + "/" + path + "?" + query.Encode()
+}
+
+// projects lists org's well-formed projects, up to limit when positive.
+func (a AzureDevOps) projects(ctx context.Context, org string, limit int) ([]azureProject, error) {
+	if strings.TrimSpace(org) == "" {
+		return nil, fmt.Errorf("organization required")
+	}
+	var projects []azureProject
+	for skip := 0; ; skip += azurePageSize {
+		query := url.Values{"$top": {strconv.Itoa(azurePageSize)}, "$skip": {strconv.Itoa(skip)}}
+		data, err := getJSON(ctx, a.HTTP, a.url(org, "_apis/projects", query), a.header())
+		if err != nil {
+			return nil, err
+		}
+		var page struct {
+			Value []azureProject `json:"value"`
+		}
+		if err := json.Unmarshal(data, &page); err != nil {
+			return nil, fmt.Errorf("parse azure devops projects: %w", err)
+		}
+		for _, p := range page.Value {
+			if p.State == "" || p.State == "wellFormed" {
+				projects = append(projects, p)
+			}
+		}
+		if len(page.Value) < azurePageSize || limit > 0 && len(projects) >= limit {
+			break
+		}
+	}
+	if limit > 0 && len(projects) > limit {
+		projects = projects[:limit]
+	}
+	return projects, nil
+}
+
+func (a AzureDevOps) ListRepos(ctx context.Context, org string) ([]string, error) {
+	projects, err := a.projects(ctx, org, 0)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(projects))
+	for _, p := range projects {
+		names = append(names, p.Name)
+	}
+	return names, nil
+}
+
+// ListRepoActivity reports each project's last update as both timestamps.
+func (a AzureDevOps) ListRepoActivity(ctx context.Context, org string) ([]RepoActivity, error) {
+	projects, err := a.projects(ctx, org, repoActivityLimit)
+	if err != nil {
+		return nil, err
+	}
+	act := make([]RepoActivity, 0, len(projects))
+	for _, p := range projects {
+		act = append(act, RepoActivity{Name: p.Name, PushedAt: p.LastUpdateTime, UpdatedAt: p.LastUpdateTime})
+	}
+	return act, nil
+}
+
+// ListIssues returns up to limit open work items of project, newest first:
+// a WIQL query finds their IDs, then they are fetched in batches.
+func (a AzureDevOps) ListIssues(ctx context.Context, org, project, fields string, limit int) ([]score.Issue, error) {
+	body, err := json.Marshal(map[string]string{"query": azureOpenQuery})
+	if err != nil {
+		return nil, err
+	}
+	base := url.PathEscape(project) + "/_apis/wit/"
+	data, err := postJSON(ctx, a.HTTP, a.url(org, base+"wiql", url.Values{"$top": {strconv.Itoa(limit)}}), a.header(), body)
+	if err != nil {
+		return nil, err
+	}
+	var result struct {
+		WorkItems []struct {
+			ID int `json:"id"`
+		} `json:"workItems"`
+	}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, fmt.Errorf("parse azure devops query: %w", err)
+	}
+	ids := make([]string, 0, len(result.WorkItems))
+	for _, wi := range result.WorkItems[:min(len(result.WorkItems), limit)] {
+		ids = append(ids, strconv.Itoa(wi.ID))
+	}
+	issues := make([]score.Issue, 0, len(ids))
+	for start := 0; start < len(ids); start += azurePageSize {
+		batch := ids[start:min(start+azurePageSize, len(ids))]
+		query := url.Values{"ids": {strings.Join(batch, ",")}, "fields": {azureFields}}
+		data, err := getJSON(ctx, a.HTTP, a.url(org, base+"workitems", query), a.header())
+		if err != nil {
+			return nil, err
+		}
+		var page struct {
+			Value []azureWorkItem `json:"value"`
+		}
+		if err := json.Unmarshal(data, &page); err != nil {
+			return nil, fmt.Errorf("parse azure devops work items: %w", err)
+		}
+		for _, wi := range page.Value {
+			issues = append(issues, wi.issue())
+		}
+	}
+	return issues, nil
+}
+
+// RepoFile always reports the file missing: a project's boards have no
+// files, so per-repo overrides don't apply.
+func (a AzureDevOps) RepoFile(ctx context.Context, org, project, path string) ([]byte, error) {
+	return nil, fmt.Errorf("%s: %w", path, ErrNotFound)
+}
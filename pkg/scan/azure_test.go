@@ -0,0 +1,74 @@
+package scan
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAzureDevOps(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user, token, ok := r.BasicAuth(); !ok || user != "" || token != "pat" {
+			t.Errorf("%s: basic auth %q %q", r.URL, user, token)
+		}
+		if r.URL.Query().Get("api-version") == "" {
+			t.Errorf("%s: no api-version", r.URL)
+		}
+		switch r.URL.Path {
+		case "/acme/_apis/projects":
+			w.Write([]byte(`{"count":3,"value":[{"name":"Fabrikam Web","state":"wellFormed","lastUpdateTime":"2025-03-04T10:00:00Z"},{"name":"New","state":"createPending"},{"name":"Ops","state":"wellFormed"}]}`))
+		case "/acme/Fabrikam Web/_apis/wit/wiql":
+			var body struct{ Query string }
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil || r.Method != http.MethodPost || !strings.Contains(body.Query, "NOT IN ('Closed'") {
+				t.Errorf("wiql %s %q, %v", r.Method, body.Query, err)
+			}
+			w.Write([]byte(`{"workItems":[{"id":42},{"id":7}]}`))
+		case "/acme/Fabrikam Web/_apis/wit/workitems":
+			if got := r.URL.Query().Get("ids"); got != "42,7" {
+				t.Errorf("ids = %q", got)
+			}
+			w.Write([]byte(`{"count":2,"value":[
+				{"id":42,"fields":{"System.Title":"Login fails","System.CreatedDate":"2025-01-02T10:00:00.12Z","System.ChangedDate":"2025-03-04T10:00:00Z","System.Tags":"bug; auth","System.CreatedBy":{"uniqueName":"ann@acme.com"},"System.AssignedTo":{"uniqueName":"bo@acme.com"}}},
+				{"id":7,"fields":{"System.Title":"Docs","System.CreatedDate":"2024-06-01T00:00:00Z","System.ChangedDate":"2024-06-01T00:00:00Z","System.CreatedBy":{"uniqueName":"ann@acme.com"}}}]}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+	a := AzureDevOps{BaseURL: srv.URL + "/", Token: "pat", HTTP: srv.Client()}
+	ctx := context.Background()
+
+	if names, err := a.ListRepos(ctx, "acme"); err != nil || !slices.Equal(names, []string{"Fabrikam Web", "Ops"}) {
+		t.Errorf("ListRepos = %v, %v", names, err)
+	}
+	act, err := a.ListRepoActivity(ctx, "acme")
+	if err != nil || len(act) != 2 || !act[0].UpdatedAt.Equal(time.Date(2025, 3, 4, 10, 0, 0, 0, time.UTC)) {
+		t.Errorf("ListRepoActivity = %+v, %v", act, err)
+	}
+	issues, err := a.ListIssues(ctx, "acme", "Fabrikam Web", IssueFields, 100)
+	if err != nil || len(issues) != 2 {
+		t.Fatalf("ListIssues = %+v, %v", issues, err)
+	}
+	is := issues[0]
+	if is.Number != 42 || is.Title != "Login fails" || is.Author.Login != "ann@acme.com" || len(is.Assignees) != 1 {
+		t.Errorf("issue = %+v", is)
+	}
+	if names := []string{is.Labels[0].Name, is.Labels[1].Name}; !slices.Equal(names, []string{"bug", "auth"}) {
+		t.Errorf("labels = %v", names)
+	}
+	if len(issues[1].Labels) != 0 || len(issues[1].Assignees) != 0 {
+		t.Errorf("untagged issue = %+v", issues[1])
+	}
+	if _, err := a.ListIssues(ctx, "acme", "Gone", IssueFields, 100); !errors.Is(err, ErrNotFound) {
+		t.Errorf("missing project: err %v", err)
+	}
+	if _, err := a.RepoFile(ctx, "acme", "Ops", ".github/fab-backlog.yml"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("RepoFile: err %v", err)
+	}
+}
@@ -0,0 +1,152 @@
+package scan
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/misty-step/fab-backlog/pkg/score"
+)
+
+// Client is the forge reads every scan is built on. GH makes them with the
+// gh CLI, GitLab with glab, and GitHubAPI, Gitea, Jira and AzureDevOps
+// over HTTP; Fake answers them from memory, so a scan can be tested end to
+// end without spawning processes.
+type Client interface {
+	// ListRepos returns the names of org's unarchived repos.
+	ListRepos(ctx context.Context, org string) ([]string, error)
+	// ListRepoActivity returns the activity timestamps of owner's
+	// unarchived repos.
+	ListRepoActivity(ctx context.Context, owner string) ([]RepoActivity, error)
+	// ListIssues returns up to limit open issues of owner/repo with the
+	// given comma-separated gh fields filled in.
+	ListIssues(ctx context.Context, owner, repo, fields string, limit int) ([]score.Issue, error)
+	// RepoFile returns a file from the default branch of owner/repo, or an
+	// error wrapping ErrNotFound if there is none.
+	RepoFile(ctx context.Context, owner, repo, path string) ([]byte, error)
+}
+
+// ErrNotFound is wrapped by errors for repos and files that don't exist.
+var ErrNotFound = errors.New("not found")
+
+// GH is the Client that shells out to gh through Run.
+type GH struct {
+	Run Runner
+}
+
+func (g GH) ListRepos(ctx context.Context, org string) ([]string, error) {
+	return ListRepos(ctx, g.Run, org)
+}
+
+func (g GH) ListRepoActivity(ctx context.Context, owner string) ([]RepoActivity, error) {
+	return ListRepoActivity(ctx, g.Run, owner)
+}
+
+func (g GH) ListIssues(ctx context.Context, owner, repo, fields string, limit int) ([]score.Issue, error) {
+	return ListIssues(ctx, g.Run, owner, repo, fields, limit)
+}
+
+func (g GH) RepoFile(ctx context.Context, owner, repo, path string) ([]byte, error) {
+	stdout, err := g.Run(ctx, "gh", "api", fmt.Sprintf("repos/%s/%s/contents/%s", owner, repo, path))
+	if err != nil {
+		if strings.Contains(err.Error(), "HTTP 404") {
+			return nil, fmt.Errorf("%s: %w", path, ErrNotFound)
+		}
+		return nil, err
+	}
+	return decodeContents(path, stdout)
+}
+
+// decodeContents decodes a GitHub contents API answer for path.
+func decodeContents(path string, data []byte) ([]byte, error) {
+	var file struct {
+		Content  string `json:"content"`
+		Encoding string `json:"encoding"`
+	}
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+	if file.Encoding != "base64" {
+		return nil, fmt.Errorf("%s: unsupported encoding %q", path, file.Encoding)
+	}
+	// GitHub wraps the base64 at 60 columns.
+	data, err := base64.StdEncoding.DecodeString(strings.ReplaceAll(file.Content, "\n", ""))
+	if err != nil {
+		return nil, fmt.Errorf("decode %s: %w", path, err)
+	}
+	return data, nil
+}
+
+// Fake is an in-memory Client. Populate Repos before use; it isn't safe to
+// change them while a scan reads them.
+type Fake struct {
+	Repos []FakeRepo
+}
+
+// FakeRepo is one repo a Fake serves.
+type FakeRepo struct {
+	Owner, Name string
+	Archived    bool
+	PushedAt    time.Time
+	UpdatedAt   time.Time
+	// Issues are the open issues, returned whatever fields are asked for.
+	Issues []score.Issue
+	// Files maps paths in the default branch to their content.
+	Files map[string]string
+}
+
+func (f *Fake) repo(owner, name string) (*FakeRepo, error) {
+	for i, r := range f.Repos {
+		if strings.EqualFold(r.Owner, owner) && strings.EqualFold(r.Name, name) {
+			return &f.Repos[i], nil
+		}
+	}
+	return nil, fmt.Errorf("%s/%s: %w", owner, name, ErrNotFound)
+}
+
+func (f *Fake) ListRepos(ctx context.Context, org string) ([]string, error) {
+	if strings.TrimSpace(org) == "" {
+		return nil, fmt.Errorf("org required")
+	}
+	var names []string
+	for _, r := range f.Repos {
+		if strings.EqualFold(r.Owner, org) && !r.Archived {
+			names = append(names, r.Name)
+		}
+	}
+	return names, nil
+}
+
+func (f *Fake) ListRepoActivity(ctx context.Context, owner string) ([]RepoActivity, error) {
+	var repos []RepoActivity
+	for _, r := range f.Repos {
+		if strings.EqualFold(r.Owner, owner) && !r.Archived {
+			repos = append(repos, RepoActivity{Name: r.Name, PushedAt: r.PushedAt, UpdatedAt: r.UpdatedAt})
+		}
+	}
+	return repos, nil
+}
+
+func (f *Fake) ListIssues(ctx context.Context, owner, repo, fields string, limit int) ([]score.Issue, error) {
+	r, err := f.repo(owner, repo)
+	if err != nil {
+		return nil, err
+	}
+	return append([]score.Issue(nil), r.Issues[:min(limit, len(r.Issues))]...), nil
+}
+
+func (f *Fake) RepoFile(ctx context.Context, owner, repo, path string) ([]byte, error) {
+	r, err := f.repo(owner, repo)
+	if err != nil {
+		return nil, err
+	}
+	content, ok := r.Files[path]
+	if !ok {
+		return nil, fmt.Errorf("%s: %w", path, ErrNotFound)
+	}
+	return []byte(content), nil
+}
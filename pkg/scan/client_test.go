@@ -0,0 +1,62 @@
+package scan
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/misty-step/fab-backlog/pkg/score"
+)
+
+func TestGHRepoFile(t *testing.T) {
+	content := base64.StdEncoding.EncodeToString([]byte("staleDays: 30\n"))
+	gh := GH{Run: func(ctx context.Context, bin string, args ...string) ([]byte, error) {
+		switch args[1] {
+		case "repos/acme/api/contents/.github/fab-backlog.yml":
+			return []byte(`{"encoding":"base64","content":"` + content[:8] + `\n` + content[8:] + `"}`), nil
+		case "repos/acme/web/contents/.github/fab-backlog.yml":
+			return nil, errors.New("gh: Not Found (HTTP 404)")
+		}
+		return nil, errors.New("gh: Server Error (HTTP 500)")
+	}}
+	ctx := context.Background()
+	if data, err := gh.RepoFile(ctx, "acme", "api", ".github/fab-backlog.yml"); err != nil || string(data) != "staleDays: 30\n" {
+		t.Errorf("data %q, err %v", data, err)
+	}
+	if _, err := gh.RepoFile(ctx, "acme", "web", ".github/fab-backlog.yml"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("404: err %v, want ErrNotFound", err)
+	}
+	if _, err := gh.RepoFile(ctx, "acme", "cli", ".github/fab-backlog.yml"); err == nil || errors.Is(err, ErrNotFound) {
+		t.Errorf("500: err %v, want a plain error", err)
+	}
+}
+
+func TestFake(t *testing.T) {
+	f := &Fake{Repos: []FakeRepo{
+		{Owner: "acme", Name: "api", Issues: []score.Issue{{Number: 1}, {Number: 2}, {Number: 3}}, Files: map[string]string{"README.md": "hi"}},
+		{Owner: "acme", Name: "old", Archived: true},
+		{Owner: "globex", Name: "web"},
+	}}
+	var c Client = f
+	ctx := context.Background()
+	if names, _ := c.ListRepos(ctx, "ACME"); !slices.Equal(names, []string{"api"}) {
+		t.Errorf("ListRepos = %v", names)
+	}
+	if issues, _ := c.ListIssues(ctx, "acme", "api", IssueFields, 2); len(issues) != 2 || issues[1].Number != 2 {
+		t.Errorf("ListIssues should honour the limit: %+v", issues)
+	}
+	if _, err := c.ListIssues(ctx, "acme", "gone", IssueFields, 10); !errors.Is(err, ErrNotFound) {
+		t.Errorf("missing repo: err %v", err)
+	}
+	if data, err := c.RepoFile(ctx, "acme", "api", "README.md"); err != nil || string(data) != "hi" {
+		t.Errorf("RepoFile = %q, %v", data, err)
+	}
+	if _, err := c.RepoFile(ctx, "globex", "web", "README.md"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("missing file: err %v", err)
+	}
+	if act, _ := c.ListRepoActivity(ctx, "globex"); len(act) != 1 || act[0].Name != "web" {
+		t.Errorf("ListRepoActivity = %+v", act)
+	}
+}
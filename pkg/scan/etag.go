@@ -0,0 +1,181 @@
+package scan
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/textproto"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync/atomic"
+)
+
+// ETagCache keeps GitHub responses on disk with their ETags, so a repeated
+// scan revalidates them with conditional requests. GitHub answers an
+// unchanged resource with 304 Not Modified, which is quick and doesn't
+// count against the rate limit.
+//
+// Plain REST GETs (gh api PATH) are revalidated directly. gh issue list is
+// GraphQL, which has no ETags, so its output is cached against the ETag of
+// the repo's most recently updated issue instead: any new, edited,
+// commented, labelled or closed issue changes it; a new reaction alone
+// doesn't.
+type ETagCache struct {
+	dir          string
+	hits, misses atomic.Int64
+}
+
+// NewETagCache opens a cache in dir, creating it if needed.
+func NewETagCache(dir string) (*ETagCache, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return &ETagCache{dir: dir}, nil
+}
+
+type cacheEntry struct {
+	ETag string `json:"etag"`
+	Body []byte `json:"body"`
+}
+
+// Wrap returns a Runner that serves the calls it can from the cache and
+// passes everything else to run.
+func (c *ETagCache) Wrap(run Runner) Runner {
+	return func(ctx context.Context, bin string, args ...string) ([]byte, error) {
+		switch {
+		case bin == "gh" && len(args) == 2 && args[0] == "api" && !strings.HasPrefix(args[1], "-") && args[1] != "graphql":
+			return c.get(ctx, run, args[1])
+		case bin == "gh" && len(args) >= 2 && args[0] == "issue" && args[1] == "list" && slices.Contains(args, "--repo"):
+			return c.issueList(ctx, run, args)
+		}
+		return run(ctx, bin, args...)
+	}
+}
+
+// Hits is the number of calls answered from the cache.
+func (c *ETagCache) Hits() int { return int(c.hits.Load()) }
+
+// Misses is the number of cacheable calls that had to be fetched.
+func (c *ETagCache) Misses() int { return int(c.misses.Load()) }
+
+func (c *ETagCache) get(ctx context.Context, run Runner, path string) ([]byte, error) {
+	key := "api " + path
+	cached := c.load(key)
+	etag, body, notModified, err := revalidate(ctx, run, path, cached)
+	if err != nil {
+		return nil, err
+	}
+	if notModified {
+		c.hits.Add(1)
+		return cached.Body, nil
+	}
+	c.misses.Add(1)
+	if etag != "" {
+		c.store(key, cacheEntry{ETag: etag, Body: body})
+	}
+	return body, nil
+}
+
+func (c *ETagCache) issueList(ctx context.Context, run Runner, args []string) ([]byte, error) {
+	key := strings.Join(args, "\x00")
+	cached := c.load(key)
+	repo := args[slices.Index(args, "--repo")+1]
+	probe := "repos/" + repo + "/issues?state=all&sort=updated&direction=desc&per_page=1"
+	etag, _, notModified, err := revalidate(ctx, run, probe, cached)
+	if err == nil && notModified {
+		c.hits.Add(1)
+		return cached.Body, nil
+	}
+	// A failed probe only costs the cache; the list itself decides.
+	c.misses.Add(1)
+	out, lerr := run(ctx, "gh", args...)
+	if lerr != nil {
+		return nil, lerr
+	}
+	if err == nil && etag != "" {
+		c.store(key, cacheEntry{ETag: etag, Body: out})
+	}
+	return out, nil
+}
+
+// revalidate GETs path, conditionally on cached's ETag when there is one,
+// and returns the response's ETag and body, or notModified.
+func revalidate(ctx context.Context, run Runner, path string, cached *cacheEntry) (etag string, body []byte, notModified bool, err error) {
+	args := []string{"api", "-i", path}
+	if cached != nil {
+		args = append(args, "-H", "If-None-Match: "+cached.ETag)
+	}
+	out, err := run(ctx, "gh", args...)
+	if err != nil {
+		// gh exits non-zero on any status above 299, 304 included.
+		if cached != nil && strings.Contains(err.Error(), "HTTP 304") {
+			return "", nil, true, nil
+		}
+		return "", nil, false, err
+	}
+	status, header, body := parseIncluded(out)
+	if status == http.StatusNotModified && cached != nil {
+		return "", nil, true, nil
+	}
+	return header.Get("ETag"), body, false, nil
+}
+
+// parseIncluded splits gh api -i output into the status code, headers and
+// body.
+func parseIncluded(out []byte) (int, http.Header, []byte) {
+	head, body, ok := bytes.Cut(out, []byte("\r\n\r\n"))
+	if !ok {
+		head, body, ok = bytes.Cut(out, []byte("\n\n"))
+	}
+	if !ok {
+		return 0, nil, out
+	}
+	r := textproto.NewReader(bufio.NewReader(io.MultiReader(bytes.NewReader(head), strings.NewReader("\r\n\r\n"))))
+	line, _ := r.ReadLine() // "HTTP/2.0 200 OK"
+	status := 0
+	if f := strings.Fields(line); len(f) >= 2 {
+		status, _ = strconv.Atoi(f[1])
+	}
+	mime, _ := r.ReadMIMEHeader()
+	return status, http.Header(mime), body
+}
+
+// path is where key is cached. Keys are per GH_HOST, so one cache can serve
+// github.com and an Enterprise Server without mixing their responses.
+func (c *ETagCache) path(key string) string {
+	if host := os.Getenv("GH_HOST"); host != "" {
+		key = host + "\x00" + key
+	}
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(c.dir, hex.EncodeToString(sum[:])+".json")
+}
+
+func (c *ETagCache) load(key string) *cacheEntry {
+	data, err := os.ReadFile(c.path(key))
+	if err != nil {
+		return nil
+	}
+	var e cacheEntry
+	if json.Unmarshal(data, &e) != nil || e.ETag == "" {
+		return nil
+	}
+	return &e
+}
+
+// store writes atomically, so parallel workers and interrupted runs never
+// leave a torn entry. A failed write only loses the entry.
+func (c *ETagCache) store(key string, e cacheEntry) {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return
+	}
+	writeAtomic(c.dir, filepath.Base(c.path(key)), data)
+}
@@ -0,0 +1,147 @@
+package scan
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// etagGitHub serves REST bodies with an ETag derived from their content and
+// answers a matching If-None-Match the way gh does: exit status 1 and
+// "gh: HTTP 304".
+type etagGitHub struct {
+	rest  map[string]string // path -> body
+	lists map[string]string // repo -> gh issue list output
+	calls []string
+}
+
+func (g *etagGitHub) run(ctx context.Context, bin string, args ...string) ([]byte, error) {
+	g.calls = append(g.calls, strings.Join(args, " "))
+	if args[0] == "issue" {
+		return []byte(g.lists[args[slices.Index(args, "--repo")+1]]), nil
+	}
+	path := args[2]
+	body, ok := g.rest[path]
+	if !ok {
+		return nil, errors.New("gh: Not Found (HTTP 404)")
+	}
+	etag := fmt.Sprintf(`W/"%x"`, sha256.Sum256([]byte(body)))
+	if i := slices.Index(args, "-H"); i >= 0 && args[i+1] == "If-None-Match: "+etag {
+		return nil, errors.New("gh api " + path + ": gh: HTTP 304")
+	}
+	return []byte("HTTP/2.0 200 OK\r\nContent-Type: application/json\r\nEtag: " + etag + "\r\n\r\n" + body), nil
+}
+
+func TestETagCacheRevalidatesRESTGets(t *testing.T) {
+	g := &etagGitHub{rest: map[string]string{"repos/acme/api/contents/x": `{"a":1}`}}
+	c, err := NewETagCache(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	run := c.Wrap(g.run)
+	for i := range 2 {
+		out, err := run(context.Background(), "gh", "api", "repos/acme/api/contents/x")
+		if err != nil || string(out) != `{"a":1}` {
+			t.Fatalf("call %d: out %q, err %v", i, out, err)
+		}
+	}
+	if c.Hits() != 1 || c.Misses() != 1 {
+		t.Errorf("hits %d, misses %d; want the second call served from cache", c.Hits(), c.Misses())
+	}
+	if !strings.Contains(g.calls[1], "If-None-Match") {
+		t.Errorf("second call wasn't conditional: %q", g.calls[1])
+	}
+
+	g.rest["repos/acme/api/contents/x"] = `{"a":2}`
+	if out, _ := run(context.Background(), "gh", "api", "repos/acme/api/contents/x"); string(out) != `{"a":2}` {
+		t.Errorf("changed resource served stale: %q", out)
+	}
+	if _, err := run(context.Background(), "gh", "api", "repos/acme/api/contents/missing"); err == nil {
+		t.Error("a 404 should come through as an error")
+	}
+}
+
+func TestETagCacheIssueListsFollowLatestIssue(t *testing.T) {
+	probe := "repos/acme/api/issues?state=all&sort=updated&direction=desc&per_page=1"
+	g := &etagGitHub{
+		rest:  map[string]string{probe: `[{"number":7,"updated_at":"2026-10-01T00:00:00Z"}]`},
+		lists: map[string]string{"acme/api": `[{"number":7}]`},
+	}
+	dir := t.TempDir()
+	list := []string{"issue", "list", "--repo", "acme/api", "--state", "open", "--json", IssueFields}
+	scan := func() string {
+		// A fresh cache per scan, as across runs.
+		c, err := NewETagCache(dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		g.calls = nil
+		out, err := c.Wrap(g.run)(context.Background(), "gh", list...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(out)
+	}
+
+	scan()
+	if got := scan(); got != `[{"number":7}]` || len(g.calls) != 1 {
+		t.Errorf("unchanged repo: out %q after calls %q; want only the probe", got, g.calls)
+	}
+
+	g.rest[probe] = `[{"number":8,"updated_at":"2026-10-02T00:00:00Z"}]`
+	g.lists["acme/api"] = `[{"number":7},{"number":8}]`
+	if got := scan(); got != `[{"number":7},{"number":8}]` || len(g.calls) != 2 {
+		t.Errorf("changed repo: out %q after calls %q; want probe and list", got, g.calls)
+	}
+}
+
+func TestETagCachePassesThroughOtherCalls(t *testing.T) {
+	g := &etagGitHub{}
+	c, err := NewETagCache(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, args := range [][]string{
+		{"api", "--paginate", "repos/acme/api/labels"},
+		{"api", "graphql", "-f", "query=q"},
+		{"search", "issues", "--owner", "acme"},
+	} {
+		g.calls = nil
+		_, _ = c.Wrap(g.run)(context.Background(), "gh", args...)
+		if len(g.calls) != 1 || g.calls[0] != strings.Join(args, " ") {
+			t.Errorf("%v became %q", args, g.calls)
+		}
+	}
+	if c.Hits()+c.Misses() != 0 {
+		t.Errorf("uncacheable calls were counted: %d hits, %d misses", c.Hits(), c.Misses())
+	}
+}
+
+func TestParseIncluded(t *testing.T) {
+	status, h, body := parseIncluded([]byte("HTTP/2.0 200 OK\nEtag: \"abc\"\nX-Ratelimit-Remaining: 4999\n\n[1,2]"))
+	if status != 200 || h.Get("ETag") != `"abc"` || string(body) != "[1,2]" {
+		t.Errorf("status %d, etag %q, body %q", status, h.Get("ETag"), body)
+	}
+}
+
+func TestETagCacheKeysByHost(t *testing.T) {
+	g := &etagGitHub{rest: map[string]string{"repos/acme/api/contents/x": `{"a":1}`}}
+	c, err := NewETagCache(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	run := c.Wrap(g.run)
+	for _, host := range []string{"", "github.acme.com"} {
+		t.Setenv("GH_HOST", host)
+		if _, err := run(context.Background(), "gh", "api", "repos/acme/api/contents/x"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if c.Hits() != 0 || c.Misses() != 2 {
+		t.Errorf("hits %d, misses %d; want each host cached separately", c.Hits(), c.Misses())
+	}
+}
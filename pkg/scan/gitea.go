@@ -0,0 +1,144 @@
+package scan
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/misty-step/fab-backlog/pkg/score"
+)
+
+// Gitea is the Client for Gitea and its forks, Forgejo and Codeberg. There
+// is no CLI to shell out to, so it calls the REST API at BaseURL (such as
+// https://codeberg.org) directly, with Token if set; public repos need none.
+type Gitea struct {
+	BaseURL string
+	Token   string
+	HTTP    *http.Client
+}
+
+// giteaPageSize is the largest page Gitea serves by default.
+const giteaPageSize = 50
+
+type giteaRepo struct {
+	Name      string    `json:"name"`
+	Archived  bool      `json:"archived"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type giteaIssue struct {
+	Number    int           `json:"number"`
+	Title     string        `json:"title"`
+	Body      string        `json:"body"`
+	CreatedAt time.Time     `json:"created_at"`
+	UpdatedAt time.Time     `json:"updated_at"`
+	Labels    []score.Label `json:"labels"`
+	User      score.Actor   `json:"user"`
+	Assignees []score.Actor `json:"assignees"`
+}
+
+// get GETs path under /api/v1.
+func (g Gitea) get(ctx context.Context, path string) ([]byte, error) {
+	header := http.Header{}
+	if g.Token != "" {
+		header.Set("Authorization", "token "+g.Token)
+	}
+	return getJSON(ctx, g.HTTP, strings.TrimSuffix(g.BaseURL, "/")+"/api/v1/"+path, header)
+}
+
+// giteaList GETs a list endpoint a page at a time until a short page or,
+// when limit is positive, until it has limit items.
+func giteaList[T any](ctx context.Context, g Gitea, path string, limit int) ([]T, error) {
+	sep := "?"
+	if strings.Contains(path, "?") {
+		sep = "&"
+	}
+	var items []T
+	for page := 1; ; page++ {
+		data, err := g.get(ctx, fmt.Sprintf("%s%slimit=%d&page=%d", path, sep, giteaPageSize, page))
+		if err != nil {
+			return nil, err
+		}
+		var batch []T
+		if err := json.Unmarshal(data, &batch); err != nil {
+			return nil, fmt.Errorf("parse %s: %w", path, err)
+		}
+		items = append(items, batch...)
+		if len(batch) < giteaPageSize || limit > 0 && len(items) >= limit {
+			break
+		}
+	}
+	if limit > 0 && len(items) > limit {
+		items = items[:limit]
+	}
+	return items, nil
+}
+
+// repos lists owner's repos, whether owner is an org or a user.
+func (g Gitea) repos(ctx context.Context, owner string, limit int) ([]giteaRepo, error) {
+	if strings.TrimSpace(owner) == "" {
+		return nil, fmt.Errorf("org required")
+	}
+	repos, err := giteaList[giteaRepo](ctx, g, "orgs/"+url.PathEscape(owner)+"/repos", limit)
+	if errors.Is(err, ErrNotFound) {
+		repos, err = giteaList[giteaRepo](ctx, g, "users/"+url.PathEscape(owner)+"/repos", limit)
+	}
+	return repos, err
+}
+
+func (g Gitea) ListRepos(ctx context.Context, org string) ([]string, error) {
+	repos, err := g.repos(ctx, org, 0)
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, r := range repos {
+		if !r.Archived {
+			names = append(names, r.Name)
+		}
+	}
+	return names, nil
+}
+
+// ListRepoActivity reports each repo's updated_at, which Gitea moves on
+// pushes, as both timestamps.
+func (g Gitea) ListRepoActivity(ctx context.Context, owner string) ([]RepoActivity, error) {
+	repos, err := g.repos(ctx, owner, repoActivityLimit)
+	if err != nil {
+		return nil, err
+	}
+	var act []RepoActivity
+	for _, r := range repos {
+		if !r.Archived {
+			act = append(act, RepoActivity{Name: r.Name, PushedAt: r.UpdatedAt, UpdatedAt: r.UpdatedAt})
+		}
+	}
+	return act, nil
+}
+
+// ListIssues returns open issues, pull requests excluded. Comments and
+// reactions are never filled in, whatever fields are asked for.
+func (g Gitea) ListIssues(ctx context.Context, owner, repo, fields string, limit int) ([]score.Issue, error) {
+	found, err := giteaList[giteaIssue](ctx, g, fmt.Sprintf("repos/%s/%s/issues?state=open&type=issues", url.PathEscape(owner), url.PathEscape(repo)), limit)
+	if err != nil {
+		return nil, err
+	}
+	issues := make([]score.Issue, 0, len(found))
+	for _, gi := range found {
+		issues = append(issues, score.Issue{Number: gi.Number, Title: gi.Title, Body: gi.Body, CreatedAt: gi.CreatedAt, UpdatedAt: gi.UpdatedAt, Labels: gi.Labels, Author: gi.User, Assignees: gi.Assignees})
+	}
+	return issues, nil
+}
+
+func (g Gitea) RepoFile(ctx context.Context, owner, repo, path string) ([]byte, error) {
+	data, err := g.get(ctx, fmt.Sprintf("repos/%s/%s/raw/%s", url.PathEscape(owner), url.PathEscape(repo), path))
+	if errors.Is(err, ErrNotFound) {
+		return nil, fmt.Errorf("%s: %w", path, ErrNotFound)
+	}
+	return data, err
+}
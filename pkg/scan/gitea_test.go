@@ -0,0 +1,70 @@
+package scan
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestGitea(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Authorization"); got != "token s3cret" {
+			t.Errorf("%s: Authorization %q", r.URL, got)
+		}
+		q := r.URL.Query()
+		switch r.URL.Path {
+		case "/api/v1/orgs/ann/repos":
+			http.NotFound(w, r)
+		case "/api/v1/users/ann/repos":
+			w.Write([]byte(`[{"name":"notes","updated_at":"2025-05-01T00:00:00Z"},{"name":"old","archived":true}]`))
+		case "/api/v1/repos/ann/notes/issues":
+			if q.Get("state") != "open" || q.Get("type") != "issues" {
+				t.Errorf("issues query %v", q)
+			}
+			n := 50
+			if q.Get("page") == "2" {
+				n = 10
+			}
+			var issues []string
+			for i := range n {
+				issues = append(issues, fmt.Sprintf(`{"number":%s%02d,"title":"t","created_at":"2025-01-01T00:00:00Z","labels":[{"name":"bug"}],"user":{"login":"ann"}}`, q.Get("page"), i))
+			}
+			w.Write([]byte("[" + strings.Join(issues, ",") + "]"))
+		case "/api/v1/repos/ann/notes/raw/.github/fab-backlog.yml":
+			w.Write([]byte("staleDays: 30\n"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+	g := Gitea{BaseURL: srv.URL + "/", Token: "s3cret", HTTP: srv.Client()}
+	ctx := context.Background()
+
+	if names, err := g.ListRepos(ctx, "ann"); err != nil || !slices.Equal(names, []string{"notes"}) {
+		t.Errorf("ListRepos = %v, %v", names, err)
+	}
+	if act, err := g.ListRepoActivity(ctx, "ann"); err != nil || len(act) != 1 || act[0].PushedAt.IsZero() {
+		t.Errorf("ListRepoActivity = %+v, %v", act, err)
+	}
+	issues, err := g.ListIssues(ctx, "ann", "notes", IssueFields, 55)
+	if err != nil || len(issues) != 55 {
+		t.Fatalf("ListIssues: %d issues, err %v", len(issues), err)
+	}
+	if is := issues[0]; is.Number != 100 || is.Labels[0].Name != "bug" || is.Author.Login != "ann" {
+		t.Errorf("issue = %+v", is)
+	}
+	if _, err := g.ListIssues(ctx, "ann", "gone", IssueFields, 10); !errors.Is(err, ErrNotFound) {
+		t.Errorf("missing repo: err %v", err)
+	}
+	if data, err := g.RepoFile(ctx, "ann", "notes", ".github/fab-backlog.yml"); err != nil || string(data) != "staleDays: 30\n" {
+		t.Errorf("RepoFile = %q, %v", data, err)
+	}
+	if _, err := g.RepoFile(ctx, "ann", "old", ".github/fab-backlog.yml"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("missing file: err %v", err)
+	}
+}
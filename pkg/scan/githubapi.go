@@ -0,0 +1,150 @@
+package scan
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/misty-step/fab-backlog/pkg/score"
+)
+
+// GitHubAPI is the Client for GitHub without the gh CLI: it calls the REST
+// API at BaseURL (https://api.github.com, or a server's
+// https://HOST/api/v3) directly, with Token if set. It makes the same core
+// reads as GH, so a machine with only a token can scan.
+type GitHubAPI struct {
+	BaseURL string
+	Token   string
+	HTTP    *http.Client
+}
+
+// githubPageSize is the largest page the REST API serves.
+const githubPageSize = 100
+
+type githubRepo struct {
+	Name      string    `json:"name"`
+	Archived  bool      `json:"archived"`
+	PushedAt  time.Time `json:"pushed_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type githubIssue struct {
+	Number      int             `json:"number"`
+	Title       string          `json:"title"`
+	Body        string          `json:"body"`
+	CreatedAt   time.Time       `json:"created_at"`
+	UpdatedAt   time.Time       `json:"updated_at"`
+	Labels      []score.Label   `json:"labels"`
+	User        score.Actor     `json:"user"`
+	Assignees   []score.Actor   `json:"assignees"`
+	PullRequest json.RawMessage `json:"pull_request"`
+}
+
+// get GETs path under BaseURL.
+func (g GitHubAPI) get(ctx context.Context, path string) ([]byte, error) {
+	header := http.Header{"X-Github-Api-Version": {"2022-11-28"}}
+	if g.Token != "" {
+		header.Set("Authorization", "Bearer "+g.Token)
+	}
+	return getJSON(ctx, g.HTTP, strings.TrimSuffix(g.BaseURL, "/")+"/"+path, header)
+}
+
+// githubList GETs a list endpoint a page at a time until a short page or,
+// when limit is positive, until keep has accepted limit items.
+func githubList[T any](ctx context.Context, g GitHubAPI, path string, limit int, keep func(T) bool) ([]T, error) {
+	sep := "?"
+	if strings.Contains(path, "?") {
+		sep = "&"
+	}
+	var items []T
+	for page := 1; ; page++ {
+		data, err := g.get(ctx, fmt.Sprintf("%s%sper_page=%d&page=%d", path, sep, githubPageSize, page))
+		if err != nil {
+			return nil, err
+		}
+		var batch []T
+		if err := json.Unmarshal(data, &batch); err != nil {
+			return nil, fmt.Errorf("parse %s: %w", path, err)
+		}
+		for _, item := range batch {
+			if keep(item) {
+				items = append(items, item)
+			}
+		}
+		if len(batch) < githubPageSize || limit > 0 && len(items) >= limit {
+			break
+		}
+	}
+	if limit > 0 && len(items) > limit {
+		items = items[:limit]
+	}
+	return items, nil
+}
+
+// repos lists owner's unarchived repos, whether owner is an org or a user.
+func (g GitHubAPI) repos(ctx context.Context, owner string, limit int) ([]githubRepo, error) {
+	if strings.TrimSpace(owner) == "" {
+		return nil, fmt.Errorf("org required")
+	}
+	unarchived := func(r githubRepo) bool { return !r.Archived }
+	repos, err := githubList(ctx, g, "orgs/"+url.PathEscape(owner)+"/repos", limit, unarchived)
+	if errors.Is(err, ErrNotFound) {
+		repos, err = githubList(ctx, g, "users/"+url.PathEscape(owner)+"/repos", limit, unarchived)
+	}
+	return repos, err
+}
+
+func (g GitHubAPI) ListRepos(ctx context.Context, org string) ([]string, error) {
+	repos, err := g.repos(ctx, org, 0)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(repos))
+	for _, r := range repos {
+		names = append(names, r.Name)
+	}
+	return names, nil
+}
+
+func (g GitHubAPI) ListRepoActivity(ctx context.Context, owner string) ([]RepoActivity, error) {
+	repos, err := g.repos(ctx, owner, repoActivityLimit)
+	if err != nil {
+		return nil, err
+	}
+	act := make([]RepoActivity, 0, len(repos))
+	for _, r := range repos {
+		act = append(act, RepoActivity{Name: r.Name, PushedAt: r.PushedAt, UpdatedAt: r.UpdatedAt})
+	}
+	return act, nil
+}
+
+// ListIssues returns open issues, pull requests excluded, newest first.
+// Comments and reactions are never filled in, whatever fields are asked for.
+func (g GitHubAPI) ListIssues(ctx context.Context, owner, repo, fields string, limit int) ([]score.Issue, error) {
+	found, err := githubList(ctx, g, fmt.Sprintf("repos/%s/%s/issues?state=open", url.PathEscape(owner), url.PathEscape(repo)), limit,
+		func(gi githubIssue) bool { return gi.PullRequest == nil })
+	if err != nil {
+		return nil, err
+	}
+	issues := make([]score.Issue, 0, len(found))
+	for _, gi := range found {
+		issues = append(issues, score.Issue{Number: gi.Number, Title: gi.Title, Body: gi.Body, CreatedAt: gi.CreatedAt, UpdatedAt: gi.UpdatedAt, Labels: gi.Labels, Author: gi.User, Assignees: gi.Assignees})
+	}
+	return issues, nil
+}
+
+func (g GitHubAPI) RepoFile(ctx context.Context, owner, repo, path string) ([]byte, error) {
+	data, err := g.get(ctx, fmt.Sprintf("repos/%s/%s/contents/%s", url.PathEscape(owner), url.PathEscape(repo), path))
+	if errors.Is(err, ErrNotFound) {
+		return nil, fmt.Errorf("%s: %w", path, ErrNotFound)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return decodeContents(path, data)
+}
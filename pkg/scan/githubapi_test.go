@@ -0,0 +1,75 @@
+package scan
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestGitHubAPI(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Authorization"); got != "Bearer ghp_test" {
+			t.Errorf("%s: Authorization %q", r.URL, got)
+		}
+		q := r.URL.Query()
+		switch r.URL.Path {
+		case "/orgs/ann/repos":
+			http.NotFound(w, r)
+		case "/users/ann/repos":
+			w.Write([]byte(`[{"name":"notes","pushed_at":"2025-05-01T00:00:00Z","updated_at":"2025-04-01T00:00:00Z"},{"name":"old","archived":true}]`))
+		case "/repos/ann/notes/issues":
+			if q.Get("state") != "open" {
+				t.Errorf("issues query %v", q)
+			}
+			// A full first page, with a pull request among the issues.
+			n := 100
+			if q.Get("page") == "2" {
+				n = 10
+			}
+			var items []string
+			for i := range n {
+				pr := ""
+				if i == 1 {
+					pr = `,"pull_request":{"url":"x"}`
+				}
+				items = append(items, fmt.Sprintf(`{"number":%s%02d,"title":"t","created_at":"2025-01-01T00:00:00Z","labels":[{"name":"bug"}],"user":{"login":"ann"}%s}`, q.Get("page"), i, pr))
+			}
+			w.Write([]byte("[" + strings.Join(items, ",") + "]"))
+		case "/repos/ann/notes/contents/.github/fab-backlog.yml":
+			w.Write([]byte(`{"encoding":"base64","content":"c3RhbGVEYXlz\nOiAzMAo="}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+	g := GitHubAPI{BaseURL: srv.URL + "/", Token: "ghp_test", HTTP: srv.Client()}
+	ctx := context.Background()
+
+	if names, err := g.ListRepos(ctx, "ann"); err != nil || !slices.Equal(names, []string{"notes"}) {
+		t.Errorf("ListRepos = %v, %v", names, err)
+	}
+	if act, err := g.ListRepoActivity(ctx, "ann"); err != nil || len(act) != 1 || act[0].PushedAt.Equal(act[0].UpdatedAt) {
+		t.Errorf("ListRepoActivity = %+v, %v", act, err)
+	}
+	issues, err := g.ListIssues(ctx, "ann", "notes", IssueFields, 105)
+	if err != nil || len(issues) != 105 {
+		t.Fatalf("ListIssues: %d issues, err %v", len(issues), err)
+	}
+	if is := issues[0]; is.Number != 100 || is.Labels[0].Name != "bug" || is.Author.Login != "ann" {
+		t.Errorf("issue = %+v", is)
+	}
+	if issues[1].Number == 101 {
+		t.Error("pull request listed as an issue")
+	}
+	if data, err := g.RepoFile(ctx, "ann", "notes", ".github/fab-backlog.yml"); err != nil || string(data) != "staleDays: 30\n" {
+		t.Errorf("RepoFile = %q, %v", data, err)
+	}
+	if _, err := g.RepoFile(ctx, "ann", "notes", ".github/missing.yml"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("missing file: err %v", err)
+	}
+}
@@ -0,0 +1,153 @@
+package scan
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/misty-step/fab-backlog/pkg/score"
+)
+
+// GitLab is the Client for GitLab groups and projects. Like GH it shells
+// out, to glab api, which supplies authentication and the host (GITLAB_HOST).
+// Groups stand in for orgs and projects for repos. Issues carry what the
+// core score needs; comments and reactions are never filled in, whatever
+// fields are asked for.
+type GitLab struct {
+	Run Runner
+}
+
+// gitlabPageSize is GitLab's largest page.
+const gitlabPageSize = 100
+
+type gitlabProject struct {
+	Path           string    `json:"path_with_namespace"`
+	Archived       bool      `json:"archived"`
+	LastActivityAt time.Time `json:"last_activity_at"`
+}
+
+type gitlabUser struct {
+	Username string `json:"username"`
+}
+
+type gitlabIssue struct {
+	IID         int          `json:"iid"`
+	Title       string       `json:"title"`
+	Description string       `json:"description"`
+	CreatedAt   time.Time    `json:"created_at"`
+	UpdatedAt   time.Time    `json:"updated_at"`
+	Labels      []string     `json:"labels"`
+	Author      gitlabUser   `json:"author"`
+	Assignees   []gitlabUser `json:"assignees"`
+}
+
+// glabList GETs a GitLab list endpoint a page at a time until a short page
+// or, when limit is positive, until it has limit items.
+func glabList[T any](ctx context.Context, run Runner, path string, limit int) ([]T, error) {
+	sep := "?"
+	if strings.Contains(path, "?") {
+		sep = "&"
+	}
+	var items []T
+	for page := 1; ; page++ {
+		stdout, err := run(ctx, "glab", "api", fmt.Sprintf("%s%sper_page=%d&page=%d", path, sep, gitlabPageSize, page))
+		if err != nil {
+			return nil, err
+		}
+		var batch []T
+		if err := json.Unmarshal(stdout, &batch); err != nil {
+			return nil, fmt.Errorf("parse glab api %s: %w", path, err)
+		}
+		items = append(items, batch...)
+		if len(batch) < gitlabPageSize || limit > 0 && len(items) >= limit {
+			break
+		}
+	}
+	if limit > 0 && len(items) > limit {
+		items = items[:limit]
+	}
+	return items, nil
+}
+
+// projects lists group's unarchived projects, subgroups' included.
+func (g GitLab) projects(ctx context.Context, group string, limit int) ([]gitlabProject, error) {
+	if strings.TrimSpace(group) == "" {
+		return nil, fmt.Errorf("group required")
+	}
+	return glabList[gitlabProject](ctx, g.Run, "groups/"+url.PathEscape(group)+"/projects?include_subgroups=true&archived=false", limit)
+}
+
+// projectName is a project's path within group, subgroups included, so
+// group/name is its full path, as owner/name is a GitHub repo's.
+func projectName(group string, p gitlabProject) string {
+	if len(p.Path) > len(group) && strings.EqualFold(p.Path[:len(group)+1], group+"/") {
+		return p.Path[len(group)+1:]
+	}
+	return p.Path
+}
+
+func (g GitLab) ListRepos(ctx context.Context, group string) ([]string, error) {
+	projects, err := g.projects(ctx, group, 0)
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, p := range projects {
+		if !p.Archived {
+			names = append(names, projectName(group, p))
+		}
+	}
+	return names, nil
+}
+
+// ListRepoActivity reports each project's last activity, which covers
+// pushes and issue changes alike, as both timestamps.
+func (g GitLab) ListRepoActivity(ctx context.Context, group string) ([]RepoActivity, error) {
+	projects, err := g.projects(ctx, group, repoActivityLimit)
+	if err != nil {
+		return nil, err
+	}
+	var repos []RepoActivity
+	for _, p := range projects {
+		if !p.Archived {
+			repos = append(repos, RepoActivity{Name: projectName(group, p), PushedAt: p.LastActivityAt, UpdatedAt: p.LastActivityAt})
+		}
+	}
+	return repos, nil
+}
+
+func (g GitLab) ListIssues(ctx context.Context, owner, repo, fields string, limit int) ([]score.Issue, error) {
+	found, err := glabList[gitlabIssue](ctx, g.Run, "projects/"+url.PathEscape(owner+"/"+repo)+"/issues?state=opened", limit)
+	if err != nil {
+		if strings.Contains(err.Error(), "404") {
+			return nil, fmt.Errorf("%s/%s: %w", owner, repo, ErrNotFound)
+		}
+		return nil, err
+	}
+	issues := make([]score.Issue, 0, len(found))
+	for _, gi := range found {
+		is := score.Issue{Number: gi.IID, Title: gi.Title, Body: gi.Description, CreatedAt: gi.CreatedAt, UpdatedAt: gi.UpdatedAt, Author: score.Actor{Login: gi.Author.Username}}
+		for _, l := range gi.Labels {
+			is.Labels = append(is.Labels, score.Label{Name: l})
+		}
+		for _, a := range gi.Assignees {
+			is.Assignees = append(is.Assignees, score.Actor{Login: a.Username})
+		}
+		issues = append(issues, is)
+	}
+	return issues, nil
+}
+
+func (g GitLab) RepoFile(ctx context.Context, owner, repo, path string) ([]byte, error) {
+	stdout, err := g.Run(ctx, "glab", "api", fmt.Sprintf("projects/%s/repository/files/%s/raw?ref=HEAD", url.PathEscape(owner+"/"+repo), url.PathEscape(path)))
+	if err != nil {
+		if strings.Contains(err.Error(), "404") {
+			return nil, fmt.Errorf("%s: %w", path, ErrNotFound)
+		}
+		return nil, err
+	}
+	return stdout, nil
+}
@@ -0,0 +1,68 @@
+package scan
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGitLab(t *testing.T) {
+	var calls []string
+	g := GitLab{Run: func(ctx context.Context, bin string, args ...string) ([]byte, error) {
+		calls = append(calls, bin+" "+strings.Join(args, " "))
+		path := args[1]
+		switch {
+		case strings.HasPrefix(path, "groups/acme/projects?include_subgroups=true&archived=false&per_page=100&page=1"):
+			return []byte(`[{"path_with_namespace":"acme/api","last_activity_at":"2025-05-01T00:00:00Z"},
+				{"path_with_namespace":"acme/platform/web"},
+				{"path_with_namespace":"acme/old","archived":true}]`), nil
+		case strings.HasPrefix(path, "projects/acme%2Fapi/issues?state=opened&per_page=100&page="):
+			page := path[strings.LastIndex(path, "=")+1:]
+			var issues []string
+			n := 100
+			if page == "2" {
+				n = 30
+			}
+			for i := range n {
+				issues = append(issues, fmt.Sprintf(`{"iid":%s%02d,"title":"t","created_at":"2025-01-01T00:00:00Z","labels":["bug"],"author":{"username":"ann"}}`, page, i))
+			}
+			return []byte("[" + strings.Join(issues, ",") + "]"), nil
+		case path == "projects/acme%2Fapi/repository/files/.github%2Ffab-backlog.yml/raw?ref=HEAD":
+			return []byte("staleDays: 30\n"), nil
+		}
+		return nil, errors.New("glab: 404 Not Found (HTTP 404)")
+	}}
+	ctx := context.Background()
+
+	names, err := g.ListRepos(ctx, "acme")
+	if err != nil || !slices.Equal(names, []string{"api", "platform/web"}) {
+		t.Errorf("ListRepos = %v, %v", names, err)
+	}
+	act, err := g.ListRepoActivity(ctx, "acme")
+	if err != nil || len(act) != 2 || !act[0].PushedAt.Equal(time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("ListRepoActivity = %+v, %v", act, err)
+	}
+
+	calls = nil
+	issues, err := g.ListIssues(ctx, "acme", "api", IssueFields, 120)
+	if err != nil || len(issues) != 120 || len(calls) != 2 {
+		t.Fatalf("ListIssues: %d issues in %d calls, err %v", len(issues), len(calls), err)
+	}
+	if is := issues[0]; is.Number != 100 || is.Labels[0].Name != "bug" || is.Author.Login != "ann" {
+		t.Errorf("issue = %+v", is)
+	}
+	if _, err := g.ListIssues(ctx, "acme", "gone", IssueFields, 10); !errors.Is(err, ErrNotFound) {
+		t.Errorf("missing project: err %v", err)
+	}
+
+	if data, err := g.RepoFile(ctx, "acme", "api", ".github/fab-backlog.yml"); err != nil || string(data) != "staleDays: 30\n" {
+		t.Errorf("RepoFile = %q, %v", data, err)
+	}
+	if _, err := g.RepoFile(ctx, "acme", "web", ".github/fab-backlog.yml"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("missing file: err %v", err)
+	}
+}
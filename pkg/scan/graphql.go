@@ -0,0 +1,81 @@
+package scan
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// GraphQLError is one entry of a GraphQL response's errors. Path leads to
+// the field GitHub couldn't resolve and returned as null, for example
+// ["search", "nodes", 3, "timelineItems"].
+type GraphQLError struct {
+	Type    string `json:"type,omitempty"`
+	Message string `json:"message"`
+	Path    []any  `json:"path,omitempty"`
+}
+
+// PathString is Path joined with dots.
+func (e GraphQLError) PathString() string {
+	parts := make([]string, len(e.Path))
+	for i, p := range e.Path {
+		parts[i] = fmt.Sprint(p)
+	}
+	return strings.Join(parts, ".")
+}
+
+// GraphQL runs a query with gh api graphql --paginate and decodes each
+// page's data into a T.
+//
+// GitHub answers a query it could only partly resolve with the data it has,
+// nulls where it failed, and an errors list; gh prints that and exits
+// non-zero. Such a response isn't treated as a failure: the pages are
+// returned with the errors, so a single inaccessible node doesn't cost the
+// rest. err is only set when no page carried data. Callers should expect
+// null nodes.
+//
+// gh stops paginating when it fails, so pages from a failed run may not be
+// all of them. errs then ends with an INCOMPLETE entry carrying gh's error.
+func GraphQL[T any](ctx context.Context, run Runner, query string, vars map[string]string) (pages []T, errs []GraphQLError, err error) {
+	args := []string{"api", "graphql", "--paginate", "-f", "query=" + query}
+	for _, k := range slices.Sorted(maps.Keys(vars)) {
+		args = append(args, "-f", k+"="+vars[k])
+	}
+	stdout, runErr := run(ctx, "gh", args...)
+	if runErr != nil && ctx.Err() != nil {
+		return nil, nil, runErr
+	}
+	dec := json.NewDecoder(bytes.NewReader(stdout))
+	for dec.More() {
+		var page struct {
+			Data   *T             `json:"data"`
+			Errors []GraphQLError `json:"errors"`
+		}
+		if err := dec.Decode(&page); err != nil {
+			if runErr != nil {
+				return nil, nil, runErr
+			}
+			return nil, nil, fmt.Errorf("parse gh api graphql: %w", err)
+		}
+		errs = append(errs, page.Errors...)
+		if page.Data != nil {
+			pages = append(pages, *page.Data)
+		}
+	}
+	if len(pages) == 0 {
+		switch {
+		case runErr != nil:
+			return nil, errs, runErr
+		case len(errs) > 0:
+			return nil, errs, fmt.Errorf("gh api graphql: %s", errs[0].Message)
+		}
+	}
+	if runErr != nil {
+		errs = append(errs, GraphQLError{Type: "INCOMPLETE", Message: "later pages may be missing: " + runErr.Error()})
+	}
+	return pages, errs, nil
+}
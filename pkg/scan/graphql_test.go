@@ -0,0 +1,87 @@
+package scan
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+)
+
+type numbersPage struct {
+	Search struct {
+		Nodes []*struct {
+			Number int `json:"number"`
+		} `json:"nodes"`
+	} `json:"search"`
+}
+
+func TestGraphQLKeepsPartialData(t *testing.T) {
+	// Two pages, the second with a node GitHub couldn't resolve; gh prints
+	// both and exits non-zero.
+	out := `{"data":{"search":{"nodes":[{"number":1},{"number":2}]}}}` +
+		`{"data":{"search":{"nodes":[{"number":3},null]}},"errors":[{"type":"FORBIDDEN","message":"Resource not accessible by integration","path":["search","nodes",1]}]}`
+	var gotArgs []string
+	run := func(ctx context.Context, bin string, args ...string) ([]byte, error) {
+		gotArgs = args
+		return []byte(out), errors.New("gh: Resource not accessible by integration")
+	}
+	pages, errs, err := GraphQL[numbersPage](context.Background(), run, "query", map[string]string{"q": "repo:acme/api"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var numbers []int
+	for _, p := range pages {
+		for _, n := range p.Search.Nodes {
+			if n != nil {
+				numbers = append(numbers, n.Number)
+			}
+		}
+	}
+	if !slices.Equal(numbers, []int{1, 2, 3}) {
+		t.Errorf("numbers = %v", numbers)
+	}
+	if len(errs) != 2 || errs[0].Type != "FORBIDDEN" || errs[0].PathString() != "search.nodes.1" {
+		t.Errorf("errs = %+v", errs)
+	}
+	// gh stopped there, so there may have been more pages.
+	if len(errs) == 2 && (errs[1].Type != "INCOMPLETE" || !strings.Contains(errs[1].Message, "Resource not accessible")) {
+		t.Errorf("errs[1] = %+v, want the run's failure", errs[1])
+	}
+	if want := []string{"api", "graphql", "--paginate", "-f", "query=query", "-f", "q=repo:acme/api"}; !slices.Equal(gotArgs, want) {
+		t.Errorf("args = %q", gotArgs)
+	}
+}
+
+func TestGraphQLFailsWithoutData(t *testing.T) {
+	for name, tc := range map[string]struct {
+		out    string
+		runErr error
+	}{
+		"errors only":   {`{"data":null,"errors":[{"message":"Something went wrong"}]}`, errors.New("gh: Something went wrong")},
+		"gh failed":     {"", errors.New("gh: HTTP 502")},
+		"unparseable":   {"<html>", errors.New("gh: HTTP 502")},
+		"exit 0 errors": {`{"errors":[{"message":"Parse error"}]}`, nil},
+	} {
+		run := func(ctx context.Context, bin string, args ...string) ([]byte, error) {
+			return []byte(tc.out), tc.runErr
+		}
+		if _, _, err := GraphQL[numbersPage](context.Background(), run, "query", nil); err == nil {
+			t.Errorf("%s: want an error", name)
+		}
+	}
+}
+
+func TestGraphQLFlagsCutShortPagination(t *testing.T) {
+	// The first page came back; fetching the next one failed.
+	run := func(ctx context.Context, bin string, args ...string) ([]byte, error) {
+		return []byte(`{"data":{"search":{"nodes":[{"number":1}]}}}`), errors.New("gh: HTTP 502")
+	}
+	pages, errs, err := GraphQL[numbersPage](context.Background(), run, "query", nil)
+	if err != nil || len(pages) != 1 {
+		t.Fatalf("pages = %+v, err = %v", pages, err)
+	}
+	if len(errs) != 1 || errs[0].Type != "INCOMPLETE" {
+		t.Errorf("errs = %+v, want the partial result flagged", errs)
+	}
+}
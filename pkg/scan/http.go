@@ -0,0 +1,54 @@
+package scan
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// getJSON GETs u with header through hc, for the providers reached over
+// HTTP rather than a CLI. A 404 comes back as an error wrapping
+// ErrNotFound, any other failure status with the response body.
+func getJSON(ctx context.Context, hc *http.Client, u string, header http.Header) ([]byte, error) {
+	return sendJSON(ctx, hc, http.MethodGet, u, header, nil)
+}
+
+// postJSON is getJSON for a POST of the JSON body, for APIs that query
+// that way.
+func postJSON(ctx context.Context, hc *http.Client, u string, header http.Header, body []byte) ([]byte, error) {
+	return sendJSON(ctx, hc, http.MethodPost, u, header, body)
+}
+
+func sendJSON(ctx context.Context, hc *http.Client, method, u string, header http.Header, body []byte) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, method, u, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header = header.Clone()
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if hc == nil {
+		hc = http.DefaultClient
+	}
+	resp, err := hc.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return nil, fmt.Errorf("%s %s: %w", method, u, ErrNotFound)
+	case resp.StatusCode != http.StatusOK:
+		return nil, fmt.Errorf("%s %s: %s: %s", method, u, resp.Status, strings.TrimSpace(string(data)))
+	}
+	return data, nil
+}
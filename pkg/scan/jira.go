@@ -0,0 +1,218 @@
+package scan
+
+import (
+	"cmp"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/misty-step/fab-backlog/pkg/score"
+)
+
+// Jira is the Client for a Jira site, Cloud or Data Center, at BaseURL.
+// Projects stand in for repos, named by key; every project on the site
+// belongs to whatever org the caller names it after. Issues are open when
+// their status isn't in the Done category, and their components count as
+// labels, prefixed "component:", so an issue is unlabeled only when it has
+// neither. With User set, Token is a Cloud API token sent with basic auth;
+// without, a Data Center personal access token sent as a bearer token.
+type Jira struct {
+	BaseURL     string
+	User, Token string
+	HTTP        *http.Client
+}
+
+// jiraPageSize is the most issues Jira returns per search page.
+const jiraPageSize = 100
+
+// jiraFields are the issue fields a scan reads.
+const jiraFields = "summary,created,updated,labels,components,reporter,assignee"
+
+// jiraTime parses Jira's timestamps, which have no colon in the offset.
+type jiraTime struct{ time.Time }
+
+func (t *jiraTime) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil || s == "" {
+		return err
+	}
+	parsed, err := time.Parse("2006-01-02T15:04:05.000-0700", s)
+	if err != nil {
+		return err
+	}
+	t.Time = parsed
+	return nil
+}
+
+type jiraUser struct {
+	AccountID string `json:"accountId"` // Cloud
+	Name      string `json:"name"`      // Data Center
+}
+
+func (u *jiraUser) actor() score.Actor {
+	if u == nil {
+		return score.Actor{}
+	}
+	return score.Actor{Login: cmp.Or(u.AccountID, u.Name)}
+}
+
+type jiraIssue struct {
+	Key    string `json:"key"`
+	Fields struct {
+		Summary    string   `json:"summary"`
+		Created    jiraTime `json:"created"`
+		Updated    jiraTime `json:"updated"`
+		Labels     []string `json:"labels"`
+		Components []struct {
+			Name string `json:"name"`
+		} `json:"components"`
+		Reporter *jiraUser `json:"reporter"`
+		Assignee *jiraUser `json:"assignee"`
+	} `json:"fields"`
+}
+
+// issue converts a Jira issue, numbered by its key's digits.
+func (ji jiraIssue) issue() score.Issue {
+	f := ji.Fields
+	number, _ := strconv.Atoi(ji.Key[strings.LastIndex(ji.Key, "-")+1:])
+	is := score.Issue{Number: number, Title: f.Summary, CreatedAt: f.Created.Time, UpdatedAt: f.Updated.Time, Author: f.Reporter.actor()}
+	for _, l := range f.Labels {
+		is.Labels = append(is.Labels, score.Label{Name: l})
+	}
+	for _, c := range f.Components {
+		is.Labels = append(is.Labels, score.Label{Name: "component:" + c.Name})
+	}
+	if f.Assignee != nil {
+		is.Assignees = []score.Actor{f.Assignee.actor()}
+	}
+	return is
+}
+
+func (j Jira) get(ctx context.Context, path string, query url.Values) ([]byte, error) {
+	header := http.Header{}
+	switch {
+	case j.User != "":
+		header.Set("Authorization", "Basic "+base64.StdEncoding.EncodeToString([]byte(j.User+":"+j.Token)))
+	case j.Token != "":
+		header.Set("Authorization", "Bearer "+j.Token)
+	}
+	u := strings.TrimSuffix(j.BaseURL, "/") + "/rest/api/" + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+	return getJSON(ctx, j.HTTP, u, header)
+}
+
+// ListRepos returns the keys of the site's projects, whatever org is.
+func (j Jira) ListRepos(ctx context.Context, org string) ([]string, error) {
+	data, err := j.get(ctx, "2/project", nil)
+	if err != nil {
+		return nil, err
+	}
+	var projects []struct {
+		Key      string `json:"key"`
+		Archived bool   `json:"archived"`
+	}
+	if err := json.Unmarshal(data, &projects); err != nil {
+		return nil, fmt.Errorf("parse jira projects: %w", err)
+	}
+	var keys []string
+	for _, p := range projects {
+		if !p.Archived {
+			keys = append(keys, p.Key)
+		}
+	}
+	return keys, nil
+}
+
+// ListRepoActivity isn't supported: Jira projects have no activity
+// timestamps to compare.
+func (j Jira) ListRepoActivity(ctx context.Context, owner string) ([]RepoActivity, error) {
+	return nil, errors.New("jira: project activity isn't supported")
+}
+
+// ListIssues returns up to limit open issues of project, newest first. It
+// searches with Cloud's /rest/api/3/search/jql, falling back to Data
+// Center's /rest/api/2/search where that doesn't exist.
+func (j Jira) ListIssues(ctx context.Context, owner, project, fields string, limit int) ([]score.Issue, error) {
+	jql := fmt.Sprintf("project = %s AND statusCategory != Done ORDER BY created DESC", strconv.Quote(project))
+	query := url.Values{"jql": {jql}, "fields": {jiraFields}, "maxResults": {strconv.Itoa(min(limit, jiraPageSize))}}
+	var found []jiraIssue
+	for len(found) < limit {
+		data, err := j.get(ctx, "3/search/jql", query)
+		if errors.Is(err, ErrNotFound) {
+			return j.listIssuesDC(ctx, project, jql, limit)
+		}
+		if err != nil {
+			return nil, searchError(err, project)
+		}
+		var page struct {
+			Issues        []jiraIssue `json:"issues"`
+			NextPageToken string      `json:"nextPageToken"`
+		}
+		if err := json.Unmarshal(data, &page); err != nil {
+			return nil, fmt.Errorf("parse jira search: %w", err)
+		}
+		found = append(found, page.Issues...)
+		if page.NextPageToken == "" {
+			break
+		}
+		query.Set("nextPageToken", page.NextPageToken)
+	}
+	return jiraIssues(found, limit), nil
+}
+
+// searchError reports a search for a project that doesn't exist, which Jira
+// rejects as a bad request naming it, as not found.
+func searchError(err error, project string) error {
+	if strings.Contains(err.Error(), "400 Bad Request") && strings.Contains(err.Error(), project) {
+		return fmt.Errorf("%s: %w", project, ErrNotFound)
+	}
+	return err
+}
+
+// listIssuesDC is ListIssues on Data Center, paged by offset.
+func (j Jira) listIssuesDC(ctx context.Context, project, jql string, limit int) ([]score.Issue, error) {
+	query := url.Values{"jql": {jql}, "fields": {jiraFields}, "maxResults": {strconv.Itoa(min(limit, jiraPageSize))}}
+	var found []jiraIssue
+	for len(found) < limit {
+		query.Set("startAt", strconv.Itoa(len(found)))
+		data, err := j.get(ctx, "2/search", query)
+		if err != nil {
+			return nil, searchError(err, project)
+		}
+		var page struct {
+			Issues []jiraIssue `json:"issues"`
+			Total  int         `json:"total"`
+		}
+		if err := json.Unmarshal(data, &page); err != nil {
+			return nil, fmt.Errorf("parse jira search: %w", err)
+		}
+		found = append(found, page.Issues...)
+		if len(page.Issues) == 0 || len(found) >= page.Total {
+			break
+		}
+	}
+	return jiraIssues(found, limit), nil
+}
+
+func jiraIssues(found []jiraIssue, limit int) []score.Issue {
+	issues := make([]score.Issue, 0, min(len(found), limit))
+	for _, ji := range found[:min(len(found), limit)] {
+		issues = append(issues, ji.issue())
+	}
+	return issues
+}
+
+// RepoFile always reports the file missing: Jira projects have no files,
+// so per-repo overrides don't apply.
+func (j Jira) RepoFile(ctx context.Context, owner, project, path string) ([]byte, error) {
+	return nil, fmt.Errorf("%s: %w", path, ErrNotFound)
+}
@@ -0,0 +1,88 @@
+package scan
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+const jiraIssueJSON = `{"key":"OPS-%s","fields":{"summary":"Disk full","created":"2025-01-02T10:00:00.000+0100","updated":"2025-03-04T10:00:00.000+0000",
+	"labels":["infra"],"components":[{"name":"Storage"}],"reporter":{"accountId":"5b10a"},"assignee":null}}`
+
+func TestJiraCloud(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user, token, ok := r.BasicAuth(); !ok || user != "ann@acme.com" || token != "tok" {
+			t.Errorf("%s: basic auth %q %q", r.URL, user, token)
+		}
+		q := r.URL.Query()
+		switch r.URL.Path {
+		case "/rest/api/2/project":
+			w.Write([]byte(`[{"key":"OPS"},{"key":"OLD","archived":true},{"key":"WEB"}]`))
+		case "/rest/api/3/search/jql":
+			if !strings.HasPrefix(q.Get("jql"), `project = "OPS" AND statusCategory != Done`) {
+				w.WriteHeader(http.StatusBadRequest)
+				w.Write([]byte(`{"errorMessages":["The value 'GONE' does not exist for the field 'project'."]}`))
+				return
+			}
+			if q.Get("nextPageToken") == "" {
+				w.Write([]byte(`{"issues":[` + strings.ReplaceAll(jiraIssueJSON, "%s", "12") + `],"nextPageToken":"p2"}`))
+				return
+			}
+			w.Write([]byte(`{"issues":[` + strings.ReplaceAll(jiraIssueJSON, "%s", "7") + `],"isLast":true}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+	j := Jira{BaseURL: srv.URL, User: "ann@acme.com", Token: "tok", HTTP: srv.Client()}
+	ctx := context.Background()
+
+	if keys, err := j.ListRepos(ctx, "acme"); err != nil || !slices.Equal(keys, []string{"OPS", "WEB"}) {
+		t.Errorf("ListRepos = %v, %v", keys, err)
+	}
+	issues, err := j.ListIssues(ctx, "acme", "OPS", IssueFields, 100)
+	if err != nil || len(issues) != 2 {
+		t.Fatalf("ListIssues = %+v, %v", issues, err)
+	}
+	is := issues[0]
+	if is.Number != 12 || is.Title != "Disk full" || is.Author.Login != "5b10a" || len(is.Assignees) != 0 {
+		t.Errorf("issue = %+v", is)
+	}
+	if !is.CreatedAt.Equal(time.Date(2025, 1, 2, 9, 0, 0, 0, time.UTC)) {
+		t.Errorf("created = %v", is.CreatedAt)
+	}
+	if names := []string{is.Labels[0].Name, is.Labels[1].Name}; !slices.Equal(names, []string{"infra", "component:Storage"}) {
+		t.Errorf("labels = %v", names)
+	}
+	if _, err := j.ListIssues(ctx, "acme", "GONE", IssueFields, 100); !errors.Is(err, ErrNotFound) {
+		t.Errorf("missing project: err %v", err)
+	}
+	if _, err := j.RepoFile(ctx, "acme", "OPS", ".github/fab-backlog.yml"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("RepoFile: err %v", err)
+	}
+}
+
+func TestJiraDataCenter(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Authorization"); got != "Bearer pat" {
+			t.Errorf("%s: Authorization %q", r.URL, got)
+		}
+		if r.URL.Path != "/rest/api/2/search" {
+			http.NotFound(w, r)
+			return
+		}
+		issue := strings.ReplaceAll(jiraIssueJSON, "%s", r.URL.Query().Get("startAt"))
+		w.Write([]byte(`{"startAt":0,"total":2,"issues":[` + issue + `]}`))
+	}))
+	defer srv.Close()
+	j := Jira{BaseURL: srv.URL + "/", Token: "pat", HTTP: srv.Client()}
+	issues, err := j.ListIssues(context.Background(), "acme", "OPS", IssueFields, 100)
+	if err != nil || len(issues) != 2 || issues[0].Number != 0 || issues[1].Number != 1 {
+		t.Errorf("ListIssues = %+v, %v", issues, err)
+	}
+}
@@ -0,0 +1,259 @@
+package scan
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Bucket is one GitHub rate-limit resource as reported by /rate_limit.
+type Bucket struct {
+	Limit     int       `json:"limit"`
+	Remaining int       `json:"remaining"`
+	Used      int       `json:"used"`
+	Reset     time.Time `json:"reset"`
+}
+
+// RateLimits fetches the current quota of every rate-limit resource, keyed
+// by resource name ("core", "graphql", "search", ...). The call itself
+// doesn't count against any of them.
+func RateLimits(ctx context.Context, run Runner) (map[string]Bucket, error) {
+	stdout, err := run(ctx, "gh", "api", "rate_limit")
+	if err != nil {
+		return nil, err
+	}
+	var resp struct {
+		Resources map[string]struct {
+			Limit     int   `json:"limit"`
+			Remaining int   `json:"remaining"`
+			Used      int   `json:"used"`
+			Reset     int64 `json:"reset"`
+		} `json:"resources"`
+	}
+	if err := json.Unmarshal(stdout, &resp); err != nil {
+		return nil, fmt.Errorf("parse gh api rate_limit json: %w", err)
+	}
+	buckets := make(map[string]Bucket, len(resp.Resources))
+	for name, r := range resp.Resources {
+		buckets[name] = Bucket{Limit: r.Limit, Remaining: r.Remaining, Used: r.Used, Reset: time.Unix(r.Reset, 0).UTC()}
+	}
+	return buckets, nil
+}
+
+// Resource names the rate-limit resource a gh command draws on, or "" for
+// commands that don't talk to the GitHub API. gh's list and view commands
+// are GraphQL queries under the hood; gh search uses the REST search API.
+func Resource(bin string, args []string) string {
+	if bin != "gh" || len(args) < 2 {
+		return ""
+	}
+	switch args[0] {
+	case "search":
+		return "search"
+	case "repo", "issue", "pr", "label", "project":
+		return "graphql"
+	case "api":
+		switch {
+		case args[1] == "rate_limit":
+			return ""
+		case args[1] == "graphql":
+			return "graphql"
+		case strings.HasPrefix(strings.TrimPrefix(args[1], "/"), "search/"):
+			return "search"
+		}
+		return "core"
+	}
+	return "core"
+}
+
+// RateLimited reports whether err is GitHub refusing a call for exceeding a
+// primary or secondary rate limit.
+func RateLimited(err error) bool {
+	msg := strings.ToLower(err.Error())
+	return strings.Contains(msg, "rate limit exceeded") || strings.Contains(msg, "secondary rate limit")
+}
+
+// refreshEvery is how many calls Throttle counts down its own estimate
+// before asking GitHub for the real quota again. Paginated calls cost more
+// than one request, so the estimate drifts high.
+const refreshEvery = 25
+
+// Throttle keeps a scan inside its rate-limit budget: once a resource's
+// remaining quota falls to Reserve, calls drawing on it wait for the
+// window to reset. It also counts the calls it lets through.
+type Throttle struct {
+	// Reserve is the quota left untouched for other tools sharing the
+	// token; 0 only waits once GitHub starts refusing calls.
+	Reserve int
+	// SecondaryWait is how long to back off after a secondary rate limit,
+	// which GitHub doesn't announce a reset time for.
+	SecondaryWait time.Duration
+	// OnWait, if set, is called before each wait.
+	OnWait func(resource string, wait time.Duration)
+	// OnEvent, if set, is also called before each wait, with a
+	// RateLimitPause event naming the repo the call's context was tagged
+	// with.
+	OnEvent func(Event)
+
+	run          Runner
+	mu           sync.Mutex
+	buckets      map[string]Bucket // nil until the first refresh
+	drawn        map[string]bool
+	sinceRefresh int
+	calls        int
+	waited       time.Duration
+}
+
+// NewThrottle wraps run; use its Run method as the Runner.
+func NewThrottle(run Runner, reserve int) *Throttle {
+	return &Throttle{Reserve: reserve, SecondaryWait: time.Minute, run: run}
+}
+
+// Run is a Runner that waits out exhausted quota before each API call and,
+// when GitHub refuses a read-only call for exceeding a rate limit anyway,
+// waits and repeats it once.
+func (t *Throttle) Run(ctx context.Context, bin string, args ...string) ([]byte, error) {
+	res := Resource(bin, args)
+	if res == "" {
+		return t.run(ctx, bin, args...)
+	}
+	if err := t.await(ctx, res); err != nil {
+		return nil, err
+	}
+	out, err := t.call(ctx, res, bin, args)
+	if err == nil || !RateLimited(err) || !ReadOnly(bin, args) {
+		return out, err
+	}
+	t.refresh(ctx)
+	t.mu.Lock()
+	exhausted := t.buckets[res].Remaining == 0
+	t.mu.Unlock()
+	if exhausted {
+		err = t.await(ctx, res)
+	} else {
+		err = t.sleep(ctx, res, t.SecondaryWait)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return t.call(ctx, res, bin, args)
+}
+
+func (t *Throttle) call(ctx context.Context, res, bin string, args []string) ([]byte, error) {
+	t.mu.Lock()
+	t.calls++
+	t.sinceRefresh++
+	if t.drawn == nil {
+		t.drawn = map[string]bool{}
+	}
+	t.drawn[res] = true
+	if b, ok := t.buckets[res]; ok && b.Remaining > 0 {
+		b.Remaining--
+		t.buckets[res] = b
+	}
+	t.mu.Unlock()
+	return t.run(ctx, bin, args...)
+}
+
+// await blocks until res has quota above the reserve, refreshing the
+// estimate first when it's missing or stale.
+func (t *Throttle) await(ctx context.Context, res string) error {
+	t.mu.Lock()
+	stale := t.buckets == nil || t.sinceRefresh >= refreshEvery
+	t.mu.Unlock()
+	if stale {
+		t.refresh(ctx)
+	}
+	t.mu.Lock()
+	b, ok := t.buckets[res]
+	t.mu.Unlock()
+	if !ok || b.Remaining > t.Reserve {
+		return nil
+	}
+	wait := time.Until(b.Reset)
+	if wait <= 0 {
+		return nil
+	}
+	if err := t.sleep(ctx, res, wait); err != nil {
+		return err
+	}
+	// The window has reset; make the next call fetch the new quota.
+	t.mu.Lock()
+	t.sinceRefresh = refreshEvery
+	t.mu.Unlock()
+	return nil
+}
+
+func (t *Throttle) sleep(ctx context.Context, res string, wait time.Duration) error {
+	if t.OnWait != nil {
+		t.OnWait(res, wait)
+	}
+	if t.OnEvent != nil {
+		t.OnEvent(Event{Kind: RateLimitPause, Repo: RepoFrom(ctx), Resource: res, Wait: wait})
+	}
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(wait):
+	}
+	t.mu.Lock()
+	t.waited += wait
+	t.mu.Unlock()
+	return nil
+}
+
+// refresh replaces the quota estimate with GitHub's figures. A host that
+// doesn't report rate limits (GHES with them disabled) leaves the estimate
+// empty, so nothing is throttled.
+func (t *Throttle) refresh(ctx context.Context) {
+	buckets, err := RateLimits(ctx, t.run)
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.sinceRefresh = 0
+	if err != nil {
+		if t.buckets == nil {
+			t.buckets = map[string]Bucket{}
+		}
+		return
+	}
+	t.buckets = buckets
+}
+
+// Calls is the number of API calls made through Run, repeats included.
+func (t *Throttle) Calls() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.calls
+}
+
+// Waited is the total time Run spent waiting for quota.
+func (t *Throttle) Waited() time.Duration {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.waited
+}
+
+// Limits fetches the current quota of the resources the calls so far drew
+// on, or of every resource if there were none.
+func (t *Throttle) Limits(ctx context.Context) (map[string]Bucket, error) {
+	buckets, err := RateLimits(ctx, t.run)
+	if err != nil {
+		return nil, err
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.buckets, t.sinceRefresh = buckets, 0
+	if len(t.drawn) == 0 {
+		return buckets, nil
+	}
+	drawn := make(map[string]Bucket, len(t.drawn))
+	for res := range t.drawn {
+		if b, ok := buckets[res]; ok {
+			drawn[res] = b
+		}
+	}
+	return drawn, nil
+}
@@ -0,0 +1,178 @@
+package scan
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+// quotaGitHub answers gh api rate_limit from its own figures and spends one
+// unit of the resource each other call draws on.
+type quotaGitHub struct {
+	mu        sync.Mutex
+	remaining map[string]int
+	reset     time.Time
+	calls     []string
+	fail      func(args []string) error
+}
+
+func (q *quotaGitHub) run(ctx context.Context, bin string, args ...string) ([]byte, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if len(args) == 2 && args[0] == "api" && args[1] == "rate_limit" {
+		if q.remaining == nil {
+			return nil, errors.New("gh: Not Found (HTTP 404)")
+		}
+		s := `{"resources":{`
+		first := true
+		for name, n := range q.remaining {
+			if !first {
+				s += ","
+			}
+			first = false
+			s += fmt.Sprintf(`%q:{"limit":5000,"remaining":%d,"used":%d,"reset":%d}`, name, n, 5000-n, q.reset.Unix())
+		}
+		return []byte(s + "}}"), nil
+	}
+	q.calls = append(q.calls, args[0])
+	if q.fail != nil {
+		if err := q.fail(args); err != nil {
+			return nil, err
+		}
+	}
+	if res := Resource(bin, args); q.remaining != nil && res != "" {
+		q.remaining[res]--
+	}
+	return []byte("ok"), nil
+}
+
+func TestResource(t *testing.T) {
+	for _, tc := range []struct {
+		args []string
+		want string
+	}{
+		{[]string{"issue", "list", "--repo", "acme/api"}, "graphql"},
+		{[]string{"repo", "list", "acme"}, "graphql"},
+		{[]string{"search", "issues", "--owner", "acme"}, "search"},
+		{[]string{"api", "graphql", "-f", "query=..."}, "graphql"},
+		{[]string{"api", "search/issues?q=org:acme"}, "search"},
+		{[]string{"api", "repos/acme/api/issues/1/timeline"}, "core"},
+		{[]string{"api", "rate_limit"}, ""},
+	} {
+		if got := Resource("gh", tc.args); got != tc.want {
+			t.Errorf("Resource(%v) = %q, want %q", tc.args, got, tc.want)
+		}
+	}
+	if got := Resource("sqlite3", []string{"db", "select 1"}); got != "" {
+		t.Errorf("non-gh commands draw on no quota, got %q", got)
+	}
+}
+
+func TestRateLimited(t *testing.T) {
+	for msg, want := range map[string]bool{
+		"gh: API rate limit exceeded for user ID 1. (HTTP 403)":                 true,
+		"gh: You have exceeded a secondary rate limit. Please wait. (HTTP 403)": true,
+		"gh: Resource not accessible by integration (HTTP 403)":                 false,
+	} {
+		if got := RateLimited(errors.New(msg)); got != want {
+			t.Errorf("RateLimited(%q) = %v, want %v", msg, got, want)
+		}
+	}
+}
+
+func TestRateLimits(t *testing.T) {
+	reset := time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)
+	q := &quotaGitHub{remaining: map[string]int{"core": 4990}, reset: reset}
+	got, err := RateLimits(context.Background(), q.run)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (Bucket{Limit: 5000, Remaining: 4990, Used: 10, Reset: reset}); got["core"] != want {
+		t.Errorf("core = %+v, want %+v", got["core"], want)
+	}
+}
+
+func TestThrottleWaitsAtReserve(t *testing.T) {
+	q := &quotaGitHub{remaining: map[string]int{"graphql": 12, "core": 5000}, reset: time.Now().Add(time.Second)}
+	th := NewThrottle(q.run, 10)
+	var waits []string
+	th.OnWait = func(res string, wait time.Duration) { waits = append(waits, res) }
+
+	ctx := context.Background()
+	for range 2 {
+		if _, err := th.Run(ctx, "gh", "issue", "list", "--repo", "acme/api"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := th.Run(ctx, "gh", "api", "repos/acme/api/issues/1/timeline"); err != nil {
+		t.Fatal(err)
+	}
+	if len(waits) != 0 {
+		t.Fatalf("waited with quota above the reserve: %v", waits)
+	}
+
+	// graphql is down to the reserve, so the next call waits for the reset.
+	ctx, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+	defer cancel()
+	if _, err := th.Run(ctx, "gh", "issue", "list", "--repo", "acme/web"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("err = %v, want the wait cut short by the context", err)
+	}
+	if len(waits) != 1 || waits[0] != "graphql" {
+		t.Errorf("waits = %v, want one on graphql", waits)
+	}
+	if th.Calls() != 3 {
+		t.Errorf("Calls() = %d, want 3", th.Calls())
+	}
+
+	limits, err := th.Limits(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(limits) != 2 || limits["graphql"].Remaining != 10 || limits["core"].Remaining != 4999 {
+		t.Errorf("limits = %+v, want graphql and core only", limits)
+	}
+}
+
+func TestThrottleRepeatsRateLimitedReads(t *testing.T) {
+	refused := 0
+	q := &quotaGitHub{remaining: map[string]int{"graphql": 4000}, reset: time.Now().Add(time.Hour)}
+	q.fail = func(args []string) error {
+		if refused == 0 {
+			refused++
+			return errors.New("gh: You have exceeded a secondary rate limit (HTTP 403)")
+		}
+		return nil
+	}
+	th := NewThrottle(q.run, 0)
+	th.SecondaryWait = time.Millisecond
+	out, err := th.Run(context.Background(), "gh", "issue", "list", "--repo", "acme/api")
+	if err != nil || string(out) != "ok" {
+		t.Fatalf("out %q, err %v", out, err)
+	}
+	if th.Calls() != 2 || th.Waited() != time.Millisecond {
+		t.Errorf("%d calls, waited %v; want 2 calls after one short wait", th.Calls(), th.Waited())
+	}
+
+	refused = 0
+	if _, err := th.Run(context.Background(), "gh", "issue", "comment", "1", "--body", "hi"); err == nil {
+		t.Error("a rate-limited write must not be repeated")
+	}
+}
+
+func TestThrottleWithoutRateLimits(t *testing.T) {
+	// Hosts with rate limiting disabled 404 on /rate_limit; nothing throttles.
+	q := &quotaGitHub{}
+	th := NewThrottle(q.run, 100)
+	th.OnWait = func(string, time.Duration) { t.Error("waited without known limits") }
+	for range 30 {
+		if _, err := th.Run(context.Background(), "gh", "search", "issues", "--owner", "acme"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if th.Calls() != 30 {
+		t.Errorf("Calls() = %d, want 30", th.Calls())
+	}
+}
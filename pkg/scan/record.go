@@ -0,0 +1,157 @@
+package scan
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync/atomic"
+)
+
+// Recording is one call and what it returned, as Record writes it and
+// Replay reads it back.
+type Recording struct {
+	Bin    string   `json:"bin"`
+	Args   []string `json:"args"`
+	Stdout string   `json:"stdout"`
+	Error  string   `json:"error,omitempty"`
+}
+
+// datePattern matches the dates and timestamps scans put into search
+// qualifiers and time windows. They depend on when a scan ran, so calls are
+// matched with them masked out.
+var datePattern = regexp.MustCompile(`\d{4}-\d{2}-\d{2}(T\d{2}:\d{2}:\d{2}(\.\d+)?(Z|[+-]\d{2}:\d{2})?)?`)
+
+// recordingKey identifies a call regardless of the dates in it.
+func recordingKey(bin string, args []string) string {
+	return datePattern.ReplaceAllString(bin+"\x00"+strings.Join(args, "\x00"), "DATE")
+}
+
+// Record returns a Runner that runs each call with run and writes each gh
+// call's output, or its error, to dir: one file per distinct call, so
+// parallel workers never contend and a repeated call keeps its latest
+// answer. Calls cut short by ctx aren't recorded.
+func Record(dir string, run Runner) (Runner, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return func(ctx context.Context, bin string, args ...string) ([]byte, error) {
+		out, err := run(ctx, bin, args...)
+		if bin != "gh" || ctx.Err() != nil {
+			return out, err
+		}
+		rec := Recording{Bin: bin, Args: args, Stdout: string(out)}
+		if err != nil {
+			rec.Error = err.Error()
+		}
+		if data, merr := json.MarshalIndent(rec, "", "  "); merr == nil {
+			sum := sha256.Sum256([]byte(recordingKey(bin, args)))
+			writeAtomic(dir, hex.EncodeToString(sum[:8])+".json", data)
+		}
+		return out, err
+	}, nil
+}
+
+// Replay answers calls from recordings instead of running them, so a scan
+// can be repeated, re-scored or tested without network access.
+type Replay struct {
+	calls  map[string]Recording
+	misses atomic.Int64
+}
+
+// LoadReplay reads recordings from path: a directory written by Record, or
+// a file holding a JSON array of them (handy as a checked-in fixture).
+func LoadReplay(path string) (*Replay, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	var recs []Recording
+	if info.IsDir() {
+		files, err := filepath.Glob(filepath.Join(path, "*.json"))
+		if err != nil {
+			return nil, err
+		}
+		for _, f := range files {
+			var rec Recording
+			data, err := os.ReadFile(f)
+			if err == nil {
+				err = json.Unmarshal(data, &rec)
+			}
+			if err != nil {
+				return nil, fmt.Errorf("read recording %s: %w", f, err)
+			}
+			recs = append(recs, rec)
+		}
+	} else {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(data, &recs); err != nil {
+			return nil, fmt.Errorf("parse recordings %s: %w", path, err)
+		}
+	}
+	if len(recs) == 0 {
+		return nil, fmt.Errorf("%s holds no recordings", path)
+	}
+	r := &Replay{calls: make(map[string]Recording, len(recs))}
+	for _, rec := range recs {
+		r.calls[recordingKey(rec.Bin, rec.Args)] = rec
+	}
+	return r, nil
+}
+
+// ErrNotRecorded is returned for a call the recordings don't cover.
+var ErrNotRecorded = errors.New("no recorded response")
+
+// Wrap returns a Runner that answers gh calls from the recordings and
+// passes other commands, such as sqlite3 for storage, to run. gh writes
+// are refused rather than replayed, since nothing would be written.
+func (r *Replay) Wrap(run Runner) Runner {
+	return func(ctx context.Context, bin string, args ...string) ([]byte, error) {
+		if bin != "gh" {
+			return run(ctx, bin, args...)
+		}
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		cmd := bin + " " + strings.Join(args, " ")
+		if !ReadOnly(bin, args) {
+			return nil, fmt.Errorf("%s: writes are disabled when replaying", cmd)
+		}
+		rec, ok := r.calls[recordingKey(bin, args)]
+		if !ok {
+			r.misses.Add(1)
+			return nil, fmt.Errorf("%s: %w", cmd, ErrNotRecorded)
+		}
+		if rec.Error != "" {
+			return []byte(rec.Stdout), errors.New(rec.Error)
+		}
+		return []byte(rec.Stdout), nil
+	}
+}
+
+// Misses is the number of calls the recordings didn't cover.
+func (r *Replay) Misses() int { return int(r.misses.Load()) }
+
+// writeAtomic writes name in dir through a temporary file, so concurrent
+// writers and interrupted runs never leave a torn file. A failed write only
+// loses the file.
+func writeAtomic(dir, name string, data []byte) {
+	tmp, err := os.CreateTemp(dir, ".tmp-*")
+	if err != nil {
+		return
+	}
+	_, werr := tmp.Write(data)
+	cerr := tmp.Close()
+	if werr != nil || cerr != nil || os.Rename(tmp.Name(), filepath.Join(dir, name)) != nil {
+		os.Remove(tmp.Name())
+	}
+}
@@ -0,0 +1,84 @@
+package scan
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRecordAndReplay(t *testing.T) {
+	live := func(ctx context.Context, bin string, args ...string) ([]byte, error) {
+		switch {
+		case bin != "gh":
+			return []byte("local"), nil
+		case args[0] == "search":
+			return []byte(`[{"number":1}]`), nil
+		case args[1] == "repos/acme/api/contents/.github/fab-backlog.json":
+			return nil, errors.New("gh: Not Found (HTTP 404)")
+		}
+		return []byte("[]"), nil
+	}
+	dir := t.TempDir()
+	rec, err := Record(dir, live)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	search := []string{"search", "issues", "--repo", "acme/api", "created:>=2026-09-15T10:00:00Z"}
+	_, _ = rec(ctx, "gh", search...)
+	_, _ = rec(ctx, "gh", "api", "repos/acme/api/contents/.github/fab-backlog.json")
+	_, _ = rec(ctx, "sqlite3", "runs.db", "select 1")
+	if files, _ := filepath.Glob(filepath.Join(dir, "*.json")); len(files) != 2 {
+		t.Fatalf("recorded %d files, want the 2 gh calls", len(files))
+	}
+
+	r, err := LoadReplay(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	run := r.Wrap(func(ctx context.Context, bin string, args ...string) ([]byte, error) {
+		return []byte("passed through"), nil
+	})
+	// A later scan's window starts at a later date but is the same call.
+	search[4] = "created:>=2026-10-15T09:30:00Z"
+	if out, err := run(ctx, "gh", search...); err != nil || string(out) != `[{"number":1}]` {
+		t.Errorf("search: out %q, err %v", out, err)
+	}
+	if _, err := run(ctx, "gh", "api", "repos/acme/api/contents/.github/fab-backlog.json"); err == nil || !strings.Contains(err.Error(), "HTTP 404") {
+		t.Errorf("recorded error should replay, got %v", err)
+	}
+	if _, err := run(ctx, "gh", "api", "repos/acme/web/labels"); !errors.Is(err, ErrNotRecorded) || r.Misses() != 1 {
+		t.Errorf("unrecorded call: err %v, misses %d", err, r.Misses())
+	}
+	if _, err := run(ctx, "gh", "issue", "comment", "1", "--body", "hi"); err == nil {
+		t.Error("writes must not replay")
+	}
+	if out, _ := run(ctx, "sqlite3", "runs.db", "select 1"); string(out) != "passed through" {
+		t.Errorf("non-gh commands should pass through, got %q", out)
+	}
+}
+
+func TestLoadReplayFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "scan.json")
+	data, _ := json.Marshal([]Recording{{Bin: "gh", Args: []string{"repo", "list", "acme"}, Stdout: "[]"}})
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	r, err := LoadReplay(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out, err := r.Wrap(nil)(context.Background(), "gh", "repo", "list", "acme"); err != nil || string(out) != "[]" {
+		t.Errorf("out %q, err %v", out, err)
+	}
+	if err := os.WriteFile(path, []byte("[]"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadReplay(path); err == nil {
+		t.Error("an empty recording should be rejected")
+	}
+}
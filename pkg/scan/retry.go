@@ -0,0 +1,133 @@
+package scan
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"slices"
+	"strings"
+	"time"
+)
+
+// RetryPolicy says how Retry treats a failing call.
+type RetryPolicy struct {
+	// Retries is how many times a transient failure is retried.
+	Retries int
+	// Backoff is the ceiling of the first wait; it doubles per retry up to
+	// MaxBackoff, and each wait is drawn uniformly below it (full jitter)
+	// so parallel workers don't retry in lockstep.
+	Backoff    time.Duration
+	MaxBackoff time.Duration
+	// Timeout bounds each attempt; 0 leaves attempts unbounded.
+	Timeout time.Duration
+	// OnRetry, if set, is called before each wait.
+	OnRetry func(attempt int, wait time.Duration, err error)
+	// OnEvent, if set, is also called before each wait, with a Retried
+	// event naming the repo the call's context was tagged with.
+	OnEvent func(Event)
+}
+
+// transientMarkers are the parts of gh error messages that mean the call
+// may well succeed if repeated.
+var transientMarkers = []string{
+	"HTTP 429", "HTTP 500", "HTTP 502", "HTTP 503", "HTTP 504",
+	"error connecting to", "connection reset", "connection refused",
+	"i/o timeout", "TLS handshake timeout", "unexpected EOF",
+}
+
+// Transient reports whether err looks like a blip rather than an answer:
+// a server error, a dropped connection or an attempt that timed out.
+func Transient(err error) bool {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	msg := err.Error()
+	for _, m := range transientMarkers {
+		if strings.Contains(msg, m) {
+			return true
+		}
+	}
+	return false
+}
+
+// ReadOnly reports whether a command only reads from GitHub, so repeating
+// it is harmless. Anything it doesn't recognise, including every write, is
+// treated as unsafe to repeat.
+func ReadOnly(bin string, args []string) bool {
+	if bin != "gh" || len(args) < 2 {
+		return false
+	}
+	switch args[0] {
+	case "search":
+		return true
+	case "repo", "issue", "pr":
+		return args[1] == "list" || args[1] == "view"
+	case "api":
+		if i := slices.IndexFunc(args, func(a string) bool { return a == "-X" || a == "--method" }); i >= 0 {
+			return i+1 < len(args) && strings.EqualFold(args[i+1], "GET")
+		}
+		if args[1] == "graphql" {
+			for _, a := range args {
+				if q, ok := strings.CutPrefix(a, "query="); ok {
+					return !strings.HasPrefix(strings.TrimSpace(q), "mutation")
+				}
+			}
+			return false
+		}
+		// gh api turns a request with fields into a POST.
+		return !slices.ContainsFunc(args, func(a string) bool {
+			return a == "-f" || a == "-F" || a == "--field" || a == "--raw-field" || a == "--input"
+		})
+	}
+	return false
+}
+
+// Retry wraps run so each attempt is bounded by p.Timeout and transient
+// failures of read-only commands are retried with backoff. Writes are
+// attempted once: a write that timed out may still have landed.
+func Retry(run Runner, p RetryPolicy) Runner {
+	return func(ctx context.Context, bin string, args ...string) ([]byte, error) {
+		retries := p.Retries
+		if !ReadOnly(bin, args) {
+			retries = 0
+		}
+		for attempt := 0; ; attempt++ {
+			out, err := attemptOnce(ctx, run, p.Timeout, bin, args)
+			if err == nil || attempt >= retries || ctx.Err() != nil || !Transient(err) {
+				return out, err
+			}
+			wait := backoff(p, attempt)
+			if p.OnRetry != nil {
+				p.OnRetry(attempt+1, wait, err)
+			}
+			if p.OnEvent != nil {
+				p.OnEvent(Event{Kind: Retried, Repo: RepoFrom(ctx), Attempt: attempt + 1, Wait: wait, Err: err})
+			}
+			select {
+			case <-ctx.Done():
+				return nil, err
+			case <-time.After(wait):
+			}
+		}
+	}
+}
+
+func attemptOnce(ctx context.Context, run Runner, timeout time.Duration, bin string, args []string) ([]byte, error) {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	return run(ctx, bin, args...)
+}
+
+func backoff(p RetryPolicy, attempt int) time.Duration {
+	ceiling := p.Backoff << attempt
+	if p.MaxBackoff > 0 && (ceiling > p.MaxBackoff || ceiling <= 0) {
+		ceiling = p.MaxBackoff
+	}
+	if ceiling <= 0 {
+		return 0
+	}
+	return rand.N(ceiling)
+}
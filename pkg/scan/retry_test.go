@@ -0,0 +1,96 @@
+package scan
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// flaky fails with err for the first n calls, then succeeds.
+func flaky(n int, err error, calls *int) Runner {
+	return func(ctx context.Context, bin string, args ...string) ([]byte, error) {
+		*calls++
+		if *calls <= n {
+			return nil, err
+		}
+		return []byte("ok"), nil
+	}
+}
+
+func TestRetryTransientReads(t *testing.T) {
+	var calls, retried int
+	p := RetryPolicy{Retries: 3, Backoff: time.Millisecond, MaxBackoff: 2 * time.Millisecond,
+		OnRetry: func(int, time.Duration, error) { retried++ }}
+	run := Retry(flaky(2, errors.New("gh: Bad Gateway (HTTP 502)"), &calls), p)
+	out, err := run(context.Background(), "gh", "issue", "list", "--repo", "acme/api")
+	if err != nil || string(out) != "ok" || calls != 3 || retried != 2 {
+		t.Fatalf("out %q, err %v after %d calls, %d retries", out, err, calls, retried)
+	}
+
+	calls = 0
+	_, err = Retry(flaky(5, errors.New("gh: Bad Gateway (HTTP 502)"), &calls), p)(context.Background(), "gh", "repo", "list", "acme")
+	if err == nil || calls != 4 {
+		t.Errorf("gave up after %d calls, err %v", calls, err)
+	}
+
+	calls = 0
+	_, err = Retry(flaky(1, errors.New("gh: Not Found (HTTP 404)"), &calls), p)(context.Background(), "gh", "api", "repos/acme/api")
+	if err == nil || calls != 1 {
+		t.Errorf("a 404 is an answer, not a blip: %d calls", calls)
+	}
+
+	calls = 0
+	_, err = Retry(flaky(1, errors.New("gh: HTTP 503"), &calls), p)(context.Background(), "gh", "issue", "comment", "1", "--body", "hi")
+	if err == nil || calls != 1 {
+		t.Errorf("writes must not be repeated: %d calls", calls)
+	}
+}
+
+func TestRetryPerCallTimeout(t *testing.T) {
+	calls := 0
+	hang := func(ctx context.Context, bin string, args ...string) ([]byte, error) {
+		calls++
+		if calls == 1 {
+			<-ctx.Done()
+			return nil, ctx.Err()
+		}
+		return []byte("ok"), nil
+	}
+	out, err := Retry(hang, RetryPolicy{Retries: 1, Timeout: 20 * time.Millisecond})(context.Background(), "gh", "search", "issues")
+	if err != nil || string(out) != "ok" || calls != 2 {
+		t.Fatalf("out %q, err %v after %d calls", out, err, calls)
+	}
+
+	// A cancelled scan stops retrying at once.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	calls = 0
+	_, err = Retry(flaky(5, errors.New("HTTP 500"), &calls), RetryPolicy{Retries: 5, Backoff: time.Hour})(ctx, "gh", "search", "issues")
+	if err == nil || calls != 1 {
+		t.Errorf("cancelled: %d calls, err %v", calls, err)
+	}
+}
+
+func TestReadOnly(t *testing.T) {
+	for _, tt := range []struct {
+		args []string
+		want bool
+	}{
+		{[]string{"issue", "list", "--repo", "a/b"}, true},
+		{[]string{"issue", "edit", "1"}, false},
+		{[]string{"api", "--paginate", "repos/a/b/labels"}, true},
+		{[]string{"api", "-X", "PATCH", "repos/a/b/labels/x"}, false},
+		{[]string{"api", "-X", "GET", "search/issues", "-f", "q=x"}, true},
+		{[]string{"api", "repos/a/b/labels/x", "-f", "new_name=y"}, false},
+		{[]string{"api", "graphql", "-f", "query=query { viewer { login } }"}, true},
+		{[]string{"api", "graphql", "-f", "query=mutation { addStar }"}, false},
+	} {
+		if got := ReadOnly("gh", tt.args); got != tt.want {
+			t.Errorf("ReadOnly(%v) = %v, want %v", tt.args, got, tt.want)
+		}
+	}
+	if ReadOnly("sqlite3", []string{"db", "select 1"}) {
+		t.Error("only gh commands are known to be read-only")
+	}
+}
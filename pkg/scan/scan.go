@@ -0,0 +1,149 @@
+// Package scan fetches repositories and issues from GitHub through the gh
+// CLI, which supplies authentication and host configuration, from GitLab
+// through glab, or from the REST APIs of a Gitea instance, a Jira site or
+// Azure DevOps.
+//
+// Every call takes a context, which cancels the gh process when done, and
+// a Runner, so callers can substitute a fake gh in tests or route commands
+// through their own wrapper:
+//
+//	repos, err := scan.ListRepos(ctx, scan.Exec, "misty-step")
+//	issues, err := scan.ListIssues(ctx, scan.Exec, "misty-step", repos[0], scan.IssueFields, 100)
+package scan
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/misty-step/fab-backlog/pkg/score"
+)
+
+// IssueFields are the gh issue fields score.Compute needs.
+const IssueFields = "number,title,createdAt,updatedAt,labels"
+
+// Runner runs a command and returns its stdout. It should give up once ctx
+// is done. A failed command may still return what it printed: gh prints a
+// GraphQL response that carries errors alongside data, then exits non-zero.
+type Runner func(ctx context.Context, bin string, args ...string) ([]byte, error)
+
+// Exec runs the command for real, killing it if ctx is done first. Its
+// error carries the command line and whatever the command printed, stderr
+// first; stdout is returned with it.
+func Exec(ctx context.Context, bin string, args ...string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, bin, args...)
+	cmd.Env = os.Environ()
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("%s %s: %w", bin, strings.Join(args, " "), ctx.Err())
+		}
+		msg := strings.TrimSpace(stderr.String())
+		if msg == "" {
+			msg = strings.TrimSpace(stdout.String())
+		}
+		if msg == "" {
+			msg = err.Error()
+		}
+		return stdout.Bytes(), fmt.Errorf("%s %s: %s", bin, strings.Join(args, " "), msg)
+	}
+	return stdout.Bytes(), nil
+}
+
+type repoInfo struct {
+	Name       string `json:"name"`
+	IsArchived bool   `json:"isArchived"`
+}
+
+// ListRepos returns the names of org's unarchived repos.
+func ListRepos(ctx context.Context, run Runner, org string) ([]string, error) {
+	if strings.TrimSpace(org) == "" {
+		return nil, fmt.Errorf("org required")
+	}
+	args := []string{"repo", "list", org, "--limit", "100", "--json", "name,isArchived"}
+	stdout, err := run(ctx, "gh", args...)
+	if err != nil {
+		return nil, err
+	}
+	var repos []repoInfo
+	if err := json.Unmarshal(stdout, &repos); err != nil {
+		return nil, fmt.Errorf("parse gh repo list json: %w", err)
+	}
+	var names []string
+	for _, r := range repos {
+		if !r.IsArchived {
+			names = append(names, r.Name)
+		}
+	}
+	return names, nil
+}
+
+// RepoActivity is when a repo last saw a push or a change to the repo
+// itself. Issue activity doesn't move either timestamp.
+type RepoActivity struct {
+	Name      string    `json:"name"`
+	PushedAt  time.Time `json:"pushedAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// repoActivityLimit caps ListRepoActivity. A repo beyond it has no known
+// activity, which callers should treat as changed.
+const repoActivityLimit = 1000
+
+// ListRepoActivity returns the activity timestamps of owner's unarchived
+// repos.
+func ListRepoActivity(ctx context.Context, run Runner, owner string) ([]RepoActivity, error) {
+	args := []string{"repo", "list", owner, "--no-archived", "--limit", strconv.Itoa(repoActivityLimit), "--json", "name,pushedAt,updatedAt"}
+	stdout, err := run(ctx, "gh", args...)
+	if err != nil {
+		return nil, err
+	}
+	var repos []RepoActivity
+	if err := json.Unmarshal(stdout, &repos); err != nil {
+		return nil, fmt.Errorf("parse gh repo list json: %w", err)
+	}
+	return repos, nil
+}
+
+// ListIssues returns up to limit open issues of owner/repo with the given
+// comma-separated gh fields filled in.
+func ListIssues(ctx context.Context, run Runner, owner, repo, fields string, limit int) ([]score.Issue, error) {
+	args := []string{"issue", "list", "--repo", owner + "/" + repo, "--state", "open", "--json", fields, "--limit", strconv.Itoa(limit)}
+	stdout, err := run(ctx, "gh", args...)
+	if err != nil {
+		return nil, err
+	}
+	var issues []score.Issue
+	if err := json.Unmarshal(stdout, &issues); err != nil {
+		return nil, fmt.Errorf("parse gh issue list json: %w", err)
+	}
+	return issues, nil
+}
+
+// APIList GETs a paginated REST list endpoint via `gh api --paginate` and
+// returns the items of every page.
+func APIList[T any](ctx context.Context, run Runner, path string) ([]T, error) {
+	stdout, err := run(ctx, "gh", "api", "--paginate", path)
+	if err != nil {
+		return nil, err
+	}
+	// --paginate emits one JSON array per page back to back.
+	var items []T
+	dec := json.NewDecoder(bytes.NewReader(stdout))
+	for dec.More() {
+		var page []T
+		if err := dec.Decode(&page); err != nil {
+			return nil, fmt.Errorf("parse gh api %s: %w", path, err)
+		}
+		items = append(items, page...)
+	}
+	return items, nil
+}
@@ -0,0 +1,69 @@
+package scan
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeGH answers each gh command from canned output keyed by its first two
+// arguments.
+func fakeGH(outputs map[string]string, calls *[]string) Runner {
+	return func(_ context.Context, bin string, args ...string) ([]byte, error) {
+		*calls = append(*calls, bin+" "+strings.Join(args, " "))
+		out, ok := outputs[strings.Join(args[:2], " ")]
+		if !ok {
+			return nil, errors.New("HTTP 404")
+		}
+		return []byte(out), nil
+	}
+}
+
+func TestListReposSkipsArchived(t *testing.T) {
+	var calls []string
+	run := fakeGH(map[string]string{"repo list": `[{"name":"api","isArchived":false},{"name":"old","isArchived":true}]`}, &calls)
+	repos, err := ListRepos(context.Background(), run, "acme")
+	if err != nil || !slices.Equal(repos, []string{"api"}) {
+		t.Fatalf("repos = %v, err %v", repos, err)
+	}
+	if _, err := ListRepos(context.Background(), run, " "); err == nil {
+		t.Error("empty org should be rejected")
+	}
+}
+
+func TestListIssues(t *testing.T) {
+	var calls []string
+	run := fakeGH(map[string]string{"issue list": `[{"number":7,"title":"crash","labels":[{"name":"bug"}],"createdAt":"2025-01-02T00:00:00Z"}]`}, &calls)
+	issues, err := ListIssues(context.Background(), run, "acme", "api", IssueFields, 50)
+	if err != nil || len(issues) != 1 || issues[0].Number != 7 || issues[0].Labels[0].Name != "bug" {
+		t.Fatalf("issues = %+v, err %v", issues, err)
+	}
+	if want := "gh issue list --repo acme/api --state open --json " + IssueFields + " --limit 50"; calls[0] != want {
+		t.Errorf("call = %q, want %q", calls[0], want)
+	}
+}
+
+func TestAPIListJoinsPages(t *testing.T) {
+	var calls []string
+	run := fakeGH(map[string]string{"api --paginate": `[{"id":1},{"id":2}][{"id":3}]`}, &calls)
+	items, err := APIList[struct{ ID int }](context.Background(), run, "repos/acme/api/labels")
+	if err != nil || len(items) != 3 || items[2].ID != 3 {
+		t.Fatalf("items = %+v, err %v", items, err)
+	}
+	if _, err := APIList[int](context.Background(), fakeGH(map[string]string{"api --paginate": `{"message":"nope"}`}, &calls), "x"); err == nil {
+		t.Error("a non-array page should fail to parse")
+	}
+}
+
+func TestExecStopsWhenContextDone(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err := Exec(ctx, "sleep", "5")
+	if !errors.Is(err, context.DeadlineExceeded) || time.Since(start) > 2*time.Second {
+		t.Fatalf("err = %v after %s", err, time.Since(start))
+	}
+}
@@ -0,0 +1,140 @@
+package scan
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/misty-step/fab-backlog/pkg/score"
+)
+
+// EventKind says what an Event reports.
+type EventKind string
+
+const (
+	// RepoStarted and RepoFinished bracket each repo a Scanner scores.
+	RepoStarted  EventKind = "repo-started"
+	RepoFinished EventKind = "repo-finished"
+	// Retried is a transient failure Retry is about to repeat.
+	Retried EventKind = "retry"
+	// RateLimitPause is a Throttle wait for quota.
+	RateLimitPause EventKind = "rate-limit-pause"
+)
+
+// Event is a progress report from a scan, for callers drawing their own
+// progress bars or exporting metrics rather than reading logs.
+type Event struct {
+	Kind EventKind
+	// Repo is owner/name. Retries and pauses carry it when the call was
+	// made under a context from WithRepo, as Scanner's are.
+	Repo string
+	// Total is how many repos the scan covers, on repo events.
+	Total int
+	// Elapsed is how long the repo took, on RepoFinished.
+	Elapsed time.Duration
+	// Attempt numbers a retry from 1.
+	Attempt int
+	// Wait is how long a retry or pause is about to sleep.
+	Wait time.Duration
+	// Resource is the rate-limit resource a pause waits on.
+	Resource string
+	// Err is why a repo failed, or the failure being retried.
+	Err error
+}
+
+type repoKey struct{}
+
+// WithRepo tags ctx with the repo calls made under it are for, so Retry and
+// Throttle events can name it.
+func WithRepo(ctx context.Context, repo string) context.Context {
+	return context.WithValue(ctx, repoKey{}, repo)
+}
+
+// RepoFrom returns the repo ctx was tagged with, or "".
+func RepoFrom(ctx context.Context) string {
+	repo, _ := ctx.Value(repoKey{}).(string)
+	return repo
+}
+
+// Scanner scores every repo of an org. It's the library's counterpart of
+// the CLI's scan without the policy layer: no config files, overrides or
+// gates, just score.Compute over each repo's open issues.
+type Scanner struct {
+	Client  Client
+	Options score.Options
+	// Limit caps the open issues fetched per repo; default 100.
+	Limit int
+	// Concurrency is how many repos are scored at once; default 1.
+	Concurrency int
+	// OnEvent, if set, is called as each repo starts and finishes. With
+	// Concurrency above 1 it's called from several goroutines at once. Hand
+	// the same function to RetryPolicy.OnEvent and Throttle.OnEvent to hear
+	// about retries and pauses too.
+	OnEvent func(Event)
+}
+
+// Result is one repo's outcome. Metrics is zero when Err is set.
+type Result struct {
+	Repo string
+	score.Metrics
+	Err error
+}
+
+// Scan lists org's repos and scores each as of now, returning results in
+// listing order. A repo that fails has its Err set and doesn't stop the
+// rest. Once ctx is done no more repos are started, and Scan returns the
+// results finished so far with ctx's error.
+func (s Scanner) Scan(ctx context.Context, org string, now time.Time) ([]Result, error) {
+	repos, err := s.Client.ListRepos(ctx, org)
+	if err != nil {
+		return nil, err
+	}
+	limit := s.Limit
+	if limit <= 0 {
+		limit = 100
+	}
+	slots := make([]*Result, len(repos))
+	sem := make(chan struct{}, max(s.Concurrency, 1))
+	var wg sync.WaitGroup
+loop:
+	for i, name := range repos {
+		select {
+		case <-ctx.Done():
+			break loop
+		case sem <- struct{}{}:
+		}
+		wg.Go(func() {
+			defer func() { <-sem }()
+			slots[i] = s.scanRepo(ctx, org, name, len(repos), limit, now)
+		})
+	}
+	wg.Wait()
+	results := make([]Result, 0, len(repos))
+	for _, r := range slots {
+		if r != nil {
+			results = append(results, *r)
+		}
+	}
+	return results, ctx.Err()
+}
+
+func (s Scanner) scanRepo(ctx context.Context, org, name string, total, limit int, now time.Time) *Result {
+	full := org + "/" + name
+	ctx = WithRepo(ctx, full)
+	s.emit(Event{Kind: RepoStarted, Repo: full, Total: total})
+	start := time.Now()
+	r := &Result{Repo: full}
+	issues, err := s.Client.ListIssues(ctx, org, name, IssueFields, limit)
+	if err == nil {
+		r.Metrics = score.Compute(issues, s.Options, now)
+	}
+	r.Err = err
+	s.emit(Event{Kind: RepoFinished, Repo: full, Total: total, Elapsed: time.Since(start), Err: err})
+	return r
+}
+
+func (s Scanner) emit(e Event) {
+	if s.OnEvent != nil {
+		s.OnEvent(e)
+	}
+}
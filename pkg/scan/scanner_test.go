@@ -0,0 +1,114 @@
+package scan
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/misty-step/fab-backlog/pkg/score"
+)
+
+func TestScannerEvents(t *testing.T) {
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	old := now.AddDate(0, 0, -200)
+	var mu sync.Mutex
+	webCalls := 0
+	run := func(ctx context.Context, bin string, args ...string) ([]byte, error) {
+		switch {
+		case args[0] == "repo":
+			return []byte(`[{"name":"api","isArchived":false},{"name":"web","isArchived":false},{"name":"cli","isArchived":false}]`), nil
+		case slices.Contains(args, "acme/web"):
+			mu.Lock()
+			webCalls++
+			first := webCalls == 1
+			mu.Unlock()
+			if first {
+				return nil, errors.New("gh: Bad Gateway (HTTP 502)")
+			}
+			return []byte(`[{"number":1,"updatedAt":"` + old.Format(time.RFC3339) + `"}]`), nil
+		case slices.Contains(args, "acme/cli"):
+			return nil, errors.New("gh: Not Found (HTTP 404)")
+		}
+		return []byte(`[]`), nil
+	}
+
+	var events []Event
+	record := func(e Event) {
+		mu.Lock()
+		defer mu.Unlock()
+		events = append(events, e)
+	}
+	s := Scanner{
+		Client:      GH{Run: Retry(run, RetryPolicy{Retries: 1, OnEvent: record})},
+		Options:     score.Options{MinIssues: 1, StaleDays: 90},
+		Concurrency: 2,
+		OnEvent:     record,
+	}
+	results, err := s.Scan(context.Background(), "acme", now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 3 || results[0].Repo != "acme/api" || results[1].Repo != "acme/web" || results[2].Repo != "acme/cli" {
+		t.Fatalf("results out of listing order: %+v", results)
+	}
+	if results[1].Err != nil || results[1].StaleCount != 1 {
+		t.Errorf("web should be scored after its retry: %+v", results[1])
+	}
+	if results[2].Err == nil || results[2].TotalOpen != 0 {
+		t.Errorf("cli should carry its failure: %+v", results[2])
+	}
+
+	kinds := map[string][]EventKind{}
+	for _, e := range events {
+		kinds[e.Repo] = append(kinds[e.Repo], e.Kind)
+		if (e.Kind == RepoStarted || e.Kind == RepoFinished) && e.Total != 3 {
+			t.Errorf("%s event for %s has Total %d", e.Kind, e.Repo, e.Total)
+		}
+	}
+	if got := kinds["acme/web"]; !slices.Equal(got, []EventKind{RepoStarted, Retried, RepoFinished}) {
+		t.Errorf("web events = %v, want started, retry, finished", got)
+	}
+	if got := kinds["acme/api"]; !slices.Equal(got, []EventKind{RepoStarted, RepoFinished}) {
+		t.Errorf("api events = %v", got)
+	}
+	for _, e := range events {
+		if e.Kind == RepoFinished && e.Repo == "acme/cli" && (e.Err == nil || !strings.Contains(e.Err.Error(), "404")) {
+			t.Errorf("cli finished without its error: %+v", e)
+		}
+	}
+}
+
+func TestScannerStopsWhenContextDone(t *testing.T) {
+	f := &Fake{Repos: []FakeRepo{{Owner: "acme", Name: "api"}, {Owner: "acme", Name: "web"}}}
+	ctx, cancel := context.WithCancel(context.Background())
+	started := 0
+	s := Scanner{Client: f, OnEvent: func(e Event) {
+		if e.Kind == RepoStarted {
+			started++
+			cancel()
+		}
+	}}
+	results, err := s.Scan(ctx, "acme", time.Now())
+	if !errors.Is(err, context.Canceled) || len(results) != 1 || started != 1 {
+		t.Errorf("results %+v, err %v after %d repos started; want one repo then context.Canceled", results, err, started)
+	}
+}
+
+func TestThrottleEventsNameTheRepo(t *testing.T) {
+	q := &quotaGitHub{remaining: map[string]int{"graphql": 10}, reset: time.Now().Add(time.Minute)}
+	th := NewThrottle(q.run, 10)
+	var got []Event
+	th.OnEvent = func(e Event) { got = append(got, e) }
+	ctx, cancel := context.WithTimeout(WithRepo(context.Background(), "acme/api"), 20*time.Millisecond)
+	defer cancel()
+	if _, err := th.Run(ctx, "gh", "issue", "list", "--repo", "acme/api"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("err = %v, want the pause cut short by the context", err)
+	}
+	if len(got) != 1 || got[0].Kind != RateLimitPause || got[0].Repo != "acme/api" || got[0].Resource != "graphql" {
+		t.Errorf("events = %+v, want one graphql pause for acme/api", got)
+	}
+}
@@ -0,0 +1,202 @@
+// Package score computes the backlog health score fab-backlog reports for
+// each repository.
+//
+// It works on plain issue data, so issues exported from any tracker can be
+// scored without going through the CLI:
+//
+//	m := score.Compute(issues, score.Options{MinIssues: 5, StaleDays: 90}, time.Now())
+//	fmt.Println(m.HealthScore, m.Status)
+//
+// A score starts from a base of 50 and earns bonuses for volume, freshness
+// and labeling; Breakdown records which were awarded and why.
+package score
+
+import (
+	"fmt"
+	"time"
+)
+
+// Statuses a score maps to.
+const (
+	Healthy  = "healthy"
+	Warning  = "warning"
+	Critical = "critical"
+)
+
+// Issue is an open issue as `gh issue list --json` returns it. Only Number,
+// CreatedAt, UpdatedAt and Labels affect the score; the other fields are
+// carried for callers that compute further metrics.
+type Issue struct {
+	Number         int             `json:"number"`
+	Title          string          `json:"title"`
+	Body           string          `json:"body"`
+	CreatedAt      time.Time       `json:"createdAt"`
+	UpdatedAt      time.Time       `json:"updatedAt"`
+	Labels         []Label         `json:"labels"`
+	Author         Actor           `json:"author"`
+	Assignees      []Actor         `json:"assignees"`
+	Comments       []Comment       `json:"comments"`
+	ReactionGroups []ReactionGroup `json:"reactionGroups"`
+	ProjectItems   []ProjectItem   `json:"projectItems,omitempty"`
+}
+
+type Label struct {
+	Name string `json:"name"`
+}
+
+type Actor struct {
+	Login string `json:"login"`
+}
+
+// ProjectItem is an issue's place on a project board.
+type ProjectItem struct {
+	Title string `json:"title"`
+}
+
+type Comment struct {
+	Author            Actor     `json:"author"`
+	AuthorAssociation string    `json:"authorAssociation"`
+	Body              string    `json:"body"`
+	CreatedAt         time.Time `json:"createdAt"`
+}
+
+type ReactionGroup struct {
+	Content string `json:"content"`
+	Users   struct {
+		TotalCount int `json:"totalCount"`
+	} `json:"users"`
+}
+
+// Options are the thresholds a score is measured against.
+type Options struct {
+	// MinIssues is the open issue count that earns the volume bonus.
+	MinIssues int
+	// StaleDays is how long an issue may go without updates before it
+	// counts as stale.
+	StaleDays int
+	// UnlabeledGraceHours leaves issues younger than this out of the
+	// unlabeled metric, since they haven't had a fair chance at triage.
+	UnlabeledGraceHours int
+	// NeverStale, if set, picks out issues that are long-lived by design.
+	// They still count as open but never as stale.
+	NeverStale func(Issue) bool
+}
+
+// Metrics is the outcome of scoring a set of open issues.
+type Metrics struct {
+	TotalOpen        int
+	StaleCount       int
+	StalePercent     float64
+	UnlabeledCount   int
+	UnlabeledPercent float64
+	HealthScore      int
+	Status           string
+	Breakdown        Breakdown
+}
+
+// Compute scores issues, all assumed open, as of now.
+func Compute(issues []Issue, opts Options, now time.Time) Metrics {
+	m := Metrics{TotalOpen: len(issues)}
+	staleThreshold := now.AddDate(0, 0, -opts.StaleDays)
+	graceThreshold := now.Add(-time.Duration(opts.UnlabeledGraceHours) * time.Hour)
+	labelable := 0
+	for _, is := range issues {
+		if is.UpdatedAt.Before(staleThreshold) && (opts.NeverStale == nil || !opts.NeverStale(is)) {
+			m.StaleCount++
+		}
+		if !is.CreatedAt.After(graceThreshold) {
+			labelable++
+			if len(is.Labels) == 0 {
+				m.UnlabeledCount++
+			}
+		}
+	}
+	if m.TotalOpen > 0 {
+		m.StalePercent = float64(m.StaleCount) / float64(m.TotalOpen) * 100
+	}
+	if labelable > 0 {
+		m.UnlabeledPercent = float64(m.UnlabeledCount) / float64(labelable) * 100
+	}
+	m.Breakdown = BreakdownFor(m.TotalOpen, m.StalePercent, m.UnlabeledPercent, opts.MinIssues)
+	m.HealthScore = m.Breakdown.Total()
+	m.Status = StatusFor(float64(m.HealthScore))
+	return m
+}
+
+// Breakdown shows how a health score was reached: the base plus each
+// bonus, with why it was or wasn't awarded.
+type Breakdown struct {
+	Base      int       `json:"base"`
+	Volume    Component `json:"volume"`
+	Staleness Component `json:"staleness"`
+	Labeling  Component `json:"labeling"`
+	// Tracking, when the caller sets it, is a deduction rather than a
+	// bonus: no points when awarded, minus Max when not.
+	Tracking *Component `json:"tracking,omitempty"`
+	// Set when the status is not the one the score implies.
+	Override string `json:"override,omitempty"`
+}
+
+type Component struct {
+	Points  int    `json:"points"`
+	Max     int    `json:"max"`
+	Awarded bool   `json:"awarded"`
+	Reason  string `json:"reason"`
+}
+
+func component(max int, awarded bool, reason string, args ...any) Component {
+	c := Component{Max: max, Awarded: awarded, Reason: fmt.Sprintf(reason, args...)}
+	if awarded {
+		c.Points = max
+	}
+	return c
+}
+
+// Total is the health score, clamped to 0–100.
+func (b Breakdown) Total() int {
+	total := b.Base + b.Volume.Points + b.Staleness.Points + b.Labeling.Points
+	if b.Tracking != nil {
+		total += b.Tracking.Points
+	}
+	return min(max(total, 0), 100)
+}
+
+// BreakdownFor awards the bonuses for a backlog with the given shape. An
+// empty backlog earns every bonus.
+func BreakdownFor(totalOpen int, stalePercent, unlabeledPercent float64, minIssues int) Breakdown {
+	b := Breakdown{Base: 50}
+	if totalOpen <= 0 {
+		b.Volume = component(20, true, "no open issues")
+		b.Staleness = component(15, true, "no open issues")
+		b.Labeling = component(15, true, "no open issues")
+		return b
+	}
+	if totalOpen >= minIssues {
+		b.Volume = component(20, true, "%d open issues, at least minIssues (%d)", totalOpen, minIssues)
+	} else {
+		b.Volume = component(20, false, "%d open issues, fewer than minIssues (%d)", totalOpen, minIssues)
+	}
+	if stalePercent < 30 {
+		b.Staleness = component(15, true, "%.1f%% stale, under 30%%", stalePercent)
+	} else {
+		b.Staleness = component(15, false, "%.1f%% stale, needs to be under 30%%", stalePercent)
+	}
+	if unlabeledPercent < 20 {
+		b.Labeling = component(15, true, "%.1f%% unlabeled, under 20%%", unlabeledPercent)
+	} else {
+		b.Labeling = component(15, false, "%.1f%% unlabeled, needs to be under 20%%", unlabeledPercent)
+	}
+	return b
+}
+
+// StatusFor maps a score, possibly smoothed and so fractional, to a status.
+func StatusFor(score float64) string {
+	switch {
+	case score >= 70:
+		return Healthy
+	case score >= 40:
+		return Warning
+	default:
+		return Critical
+	}
+}
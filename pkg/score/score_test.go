@@ -0,0 +1,65 @@
+package score
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBreakdownFor(t *testing.T) {
+	b := BreakdownFor(3, 40, 10, 5)
+	if b.Total() != 65 || b.Volume.Awarded || b.Staleness.Awarded || !b.Labeling.Awarded {
+		t.Fatalf("breakdown = %+v", b)
+	}
+	if b.Staleness.Points != 0 || b.Staleness.Max != 15 || b.Staleness.Reason != "40.0% stale, needs to be under 30%" {
+		t.Errorf("staleness = %+v", b.Staleness)
+	}
+	if b.Volume.Reason != "3 open issues, fewer than minIssues (5)" {
+		t.Errorf("volume = %+v", b.Volume)
+	}
+	if got := BreakdownFor(0, 0, 0, 5).Total(); got != 100 {
+		t.Errorf("empty total = %d", got)
+	}
+}
+
+func TestTotalWithTracking(t *testing.T) {
+	b := BreakdownFor(0, 0, 0, 5)
+	b.Tracking = &Component{Points: -10, Max: 10}
+	if got := b.Total(); got != 90 {
+		t.Errorf("total = %d, want 90", got)
+	}
+}
+
+func TestCompute(t *testing.T) {
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	fresh, old := now.AddDate(0, 0, -1), now.AddDate(0, 0, -120)
+	issues := []Issue{
+		{Number: 1, CreatedAt: old, UpdatedAt: old, Labels: []Label{{Name: "bug"}}},
+		{Number: 2, CreatedAt: old, UpdatedAt: fresh},
+		{Number: 3, CreatedAt: fresh, UpdatedAt: fresh},
+		{Number: 4, CreatedAt: old, UpdatedAt: fresh, Labels: []Label{{Name: "docs"}}},
+	}
+	m := Compute(issues, Options{MinIssues: 3, StaleDays: 90, UnlabeledGraceHours: 48}, now)
+	// #3 is inside the grace period, so one of three labelable issues is
+	// unlabeled.
+	if m.TotalOpen != 4 || m.StaleCount != 1 || m.UnlabeledCount != 1 || m.StalePercent != 25 {
+		t.Fatalf("metrics = %+v", m)
+	}
+	if m.HealthScore != 85 || m.Status != Healthy || m.Breakdown.Labeling.Awarded {
+		t.Errorf("score %d %s, breakdown %+v", m.HealthScore, m.Status, m.Breakdown)
+	}
+	if empty := Compute(nil, Options{MinIssues: 5}, now); empty.HealthScore != 100 || empty.Status != Healthy {
+		t.Errorf("empty = %+v", empty)
+	}
+	tracking := Options{MinIssues: 3, StaleDays: 90, NeverStale: func(is Issue) bool { return is.Number == 1 }}
+	if m := Compute(issues, tracking, now); m.TotalOpen != 4 || m.StaleCount != 0 {
+		t.Errorf("NeverStale issue counted: %+v", m)
+	}
+}
+
+func TestStatusFor(t *testing.T) {
+	for score, want := range map[float64]string{100: Healthy, 70: Healthy, 69.9: Warning, 40: Warning, 39: Critical} {
+		if got := StatusFor(score); got != want {
+			t.Errorf("StatusFor(%v) = %s, want %s", score, got, want)
+		}
+	}
+}
@@ -0,0 +1,257 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	planOut = flag.String("plan-out", "", "write suggested remediation actions to this plan file for review before `apply`")
+	dryRun  = flag.Bool("dry-run", false, "with apply, fix, -apply-stale-label or suggest-labels -apply, report what would be done without writing to GitHub")
+)
+
+// planVersion is bumped on incompatible changes to the plan format.
+const planVersion = 1
+
+// plan is the reviewable hand-off between analyses that suggest changes and
+// the applier that makes them. Nothing writes to GitHub except `apply`,
+// and `apply` only does what a plan says, so a plan can go through a PR
+// like any other change.
+type plan struct {
+	Version     int          `json:"version"`
+	GeneratedAt string       `json:"generatedAt"`
+	Org         string       `json:"org"`
+	Actions     []planAction `json:"actions"`
+}
+
+// planAction is one write. Kind is close, addLabel, removeLabel or comment.
+// ExpectedScoreImpact is the change in the repo's health score if this
+// action alone were applied.
+type planAction struct {
+	ID                  string   `json:"id"`
+	Kind                string   `json:"kind"`
+	Repo                string   `json:"repo"` // owner/name
+	Number              int      `json:"number"`
+	Label               string   `json:"label,omitempty"`
+	Comment             string   `json:"comment,omitempty"`
+	Source              string   `json:"source"`
+	Reasons             []string `json:"reasons"`
+	ExpectedScoreImpact int      `json:"expectedScoreImpact"`
+}
+
+func actionID(kind, repo string, number int, label string) string {
+	id := fmt.Sprintf("%s#%d:%s", repo, number, kind)
+	if label != "" {
+		id += ":" + label
+	}
+	return id
+}
+
+// simulate returns issues as they would be after a.
+func simulate(issues []issue, a planAction) []issue {
+	out := make([]issue, 0, len(issues))
+	for _, is := range issues {
+		if is.Number != a.Number {
+			out = append(out, is)
+			continue
+		}
+		switch a.Kind {
+		case "close":
+			continue
+		case "addLabel":
+			is.Labels = append(append([]label(nil), is.Labels...), label{Name: a.Label})
+		case "removeLabel":
+			var kept []label
+			for _, l := range is.Labels {
+				if !strings.EqualFold(l.Name, a.Label) {
+					kept = append(kept, l)
+				}
+			}
+			is.Labels = kept
+		}
+		out = append(out, is)
+	}
+	return out
+}
+
+// buildPlan collects every repo's triage queue into a plan.
+func buildPlan(out output, cfg config, now time.Time) plan {
+	p := plan{Version: planVersion, GeneratedAt: now.UTC().Format(time.RFC3339), Org: out.Org, Actions: []planAction{}}
+	for _, rs := range out.Repos {
+		for _, t := range rs.TriageQueue {
+			a := planAction{
+				Kind:    t.Action,
+				Repo:    rs.fullName,
+				Number:  t.Number,
+				Label:   t.Label,
+				Comment: t.Comment,
+				Source:  t.Source,
+				Reasons: t.Reasons,
+			}
+			a.ID = actionID(a.Kind, a.Repo, a.Number, a.Label)
+			after := scoreIssues(rs.Name, simulate(rs.issues, a), cfg, now)
+			a.ExpectedScoreImpact = after.HealthScore - rs.HealthScore
+			p.Actions = append(p.Actions, a)
+		}
+	}
+	return p
+}
+
+func writePlan(path string, p plan) error {
+	data, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(path, append(data, '\n'))
+}
+
+func readPlan(path string) (plan, error) {
+	var p plan
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return p, fmt.Errorf("read plan: %w", err)
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&p); err != nil {
+		return p, fmt.Errorf("parse plan %s: %w", path, err)
+	}
+	if p.Version != planVersion {
+		return p, fmt.Errorf("plan %s: unsupported version %d (want %d)", path, p.Version, planVersion)
+	}
+	for _, a := range p.Actions {
+		if err := a.validate(); err != nil {
+			return p, fmt.Errorf("plan %s: %w", path, err)
+		}
+	}
+	return p, nil
+}
+
+func (a planAction) validate() error {
+	if !strings.Contains(a.Repo, "/") || a.Number <= 0 {
+		return fmt.Errorf("action %s: repo must be owner/name and number positive", a.ID)
+	}
+	switch a.Kind {
+	case "close":
+	case "addLabel", "removeLabel":
+		if a.Label == "" {
+			return fmt.Errorf("action %s: %s needs a label", a.ID, a.Kind)
+		}
+	case "comment":
+		if a.Comment == "" {
+			return fmt.Errorf("action %s: comment needs a comment", a.ID)
+		}
+	default:
+		return fmt.Errorf("action %s: unknown kind %q", a.ID, a.Kind)
+	}
+	return nil
+}
+
+// ghArgs is the gh invocation that performs a.
+func (a planAction) ghArgs() []string {
+	n := strconv.Itoa(a.Number)
+	switch a.Kind {
+	case "close":
+		args := []string{"issue", "close", n, "--repo", a.Repo}
+		if a.Comment != "" {
+			args = append(args, "--comment", a.Comment)
+		}
+		return args
+	case "addLabel":
+		return []string{"issue", "edit", n, "--repo", a.Repo, "--add-label", a.Label}
+	case "removeLabel":
+		return []string{"issue", "edit", n, "--repo", a.Repo, "--remove-label", a.Label}
+	default:
+		return []string{"issue", "comment", n, "--repo", a.Repo, "--body", a.Comment}
+	}
+}
+
+// expectation is what the next scan should find once a has been applied.
+func (a planAction) expectation(at time.Time) (expectation, bool) {
+	e := expectation{Repo: a.Repo, Number: a.Number, Label: a.Label, Action: a.Source, RecordedAt: at.UTC()}
+	switch a.Kind {
+	case "close":
+		e.Kind = "closed"
+	case "addLabel":
+		e.Kind = "labelPresent"
+	case "removeLabel":
+		e.Kind = "labelAbsent"
+	default:
+		return e, false
+	}
+	return e, true
+}
+
+type applyResult struct {
+	ID      string   `json:"id"`
+	Status  string   `json:"status"` // "applied", "planned" (dry run) or "failed"
+	Command []string `json:"command"`
+	Error   string   `json:"error,omitempty"`
+}
+
+// runApply implements `fab-backlog apply plan.json [--dry-run]`.
+func runApply(args []string) int {
+	if len(args) != 1 {
+		slog.Error("apply requires exactly one plan file")
+		emitJSON(map[string]any{"ok": false, "error": "usage: fab-backlog apply plan.json [--dry-run]"})
+		return 2
+	}
+	p, err := readPlan(args[0])
+	if err != nil {
+		slog.Error("invalid plan", "error", err)
+		emitJSON(map[string]any{"ok": false, "error": err.Error()})
+		return 2
+	}
+	results, exps := applyPlan(p, *dryRun, runBackground, time.Now())
+	failed := 0
+	for _, r := range results {
+		if r.Status == "failed" {
+			failed++
+		}
+	}
+	if *remediationLog != "" && len(exps) > 0 {
+		if err := recordExpectations(*remediationLog, exps); err != nil {
+			slog.Error("failed to record remediation expectations", "path", *remediationLog, "error", err)
+		}
+	}
+	emitJSON(map[string]any{"ok": failed == 0, "dryRun": *dryRun, "actions": len(results), "failed": failed, "results": results})
+	if failed > 0 {
+		return 1
+	}
+	return 0
+}
+
+// applyPlan runs each action through run, or only lists the commands when
+// dryRun is set. It returns a result per action and the expectations of the
+// ones that succeeded.
+func applyPlan(p plan, dryRun bool, run func(string, ...string) ([]byte, error), now time.Time) ([]applyResult, []expectation) {
+	results := make([]applyResult, 0, len(p.Actions))
+	var exps []expectation
+	for _, a := range p.Actions {
+		r := applyResult{ID: a.ID, Command: append([]string{"gh"}, a.ghArgs()...)}
+		switch {
+		case dryRun:
+			r.Status = "planned"
+		default:
+			if _, err := run("gh", a.ghArgs()...); err != nil {
+				r.Status, r.Error = "failed", err.Error()
+				slog.Warn("action failed", "id", a.ID, "error", err)
+			} else {
+				r.Status = "applied"
+				slog.Info("action applied", "id", a.ID)
+				if e, ok := a.expectation(now); ok {
+					exps = append(exps, e)
+				}
+			}
+		}
+		results = append(results, r)
+	}
+	return results, exps
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestBuildPlanScoreImpact(t *testing.T) {
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	old := now.AddDate(-1, 0, 0)
+	issues := []issue{
+		{Number: 1, CreatedAt: old, UpdatedAt: old, Labels: []label{{Name: "bug"}}},
+		{Number: 2, CreatedAt: old, UpdatedAt: old, Labels: []label{{Name: "bug"}}},
+		{Number: 3, CreatedAt: old, UpdatedAt: now, Labels: []label{{Name: "bug"}}},
+		{Number: 4, CreatedAt: old, UpdatedAt: now, Labels: []label{{Name: "bug"}}},
+		{Number: 5, CreatedAt: old, UpdatedAt: now, Labels: []label{{Name: "bug"}}},
+	}
+	cfg := config{MinIssues: 1, StaleDays: 90}
+	rs := scoreIssues("a", issues, cfg, now)
+	rs.fullName, rs.issues = "org/a", issues
+	rs.TriageQueue = []triageItem{{Number: 1, Action: "close", Source: "link-check", Reasons: []string{"dead link: x"}}}
+	p := buildPlan(output{Org: "org", Repos: []repoScore{rs}}, cfg, now)
+	if len(p.Actions) != 1 {
+		t.Fatalf("actions = %+v", p.Actions)
+	}
+	a := p.Actions[0]
+	// Closing #1 drops stale from 40% to 25%, earning the staleness bonus.
+	if a.ID != "org/a#1:close" || a.Repo != "org/a" || a.ExpectedScoreImpact != 15 {
+		t.Errorf("action = %+v, want id org/a#1:close with +15 impact", a)
+	}
+}
+
+func TestApplyPlan(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "plan.json")
+	p := plan{Version: planVersion, Org: "org", Actions: []planAction{
+		{ID: "org/a#1:close", Kind: "close", Repo: "org/a", Number: 1, Source: "link-check"},
+		{ID: "org/a#2:addLabel:stale", Kind: "addLabel", Repo: "org/a", Number: 2, Label: "stale", Source: "stale-label"},
+		{ID: "org/a#3:comment", Kind: "comment", Repo: "org/a", Number: 3, Comment: "ping", Source: "stale-policy"},
+	}}
+	if err := writePlan(path, p); err != nil {
+		t.Fatal(err)
+	}
+	p, err := readPlan(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var calls [][]string
+	run := func(bin string, args ...string) ([]byte, error) {
+		calls = append(calls, args)
+		if args[1] == "edit" {
+			return nil, errors.New("HTTP 403")
+		}
+		return nil, nil
+	}
+	results, exps := applyPlan(p, true, run, time.Now())
+	if len(calls) != 0 || results[0].Status != "planned" || len(exps) != 0 {
+		t.Fatalf("dry run must not write: calls=%v results=%+v", calls, results)
+	}
+	results, exps = applyPlan(p, false, run, time.Now())
+	if len(calls) != 3 || results[0].Status != "applied" || results[1].Status != "failed" || results[2].Status != "applied" {
+		t.Errorf("results = %+v", results)
+	}
+	if len(exps) != 1 || exps[0].Kind != "closed" || exps[0].Action != "link-check" {
+		t.Errorf("expectations = %+v, want only the close", exps)
+	}
+}
+
+func TestReadPlanRejectsInvalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "plan.json")
+	bad := plan{Version: planVersion, Actions: []planAction{{ID: "x", Kind: "addLabel", Repo: "org/a", Number: 1}}}
+	if err := writePlan(path, bad); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := readPlan(path); err == nil {
+		t.Error("addLabel without a label should be rejected")
+	}
+}
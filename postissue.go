@@ -0,0 +1,97 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var postIssue = flag.String("post-issue", "", "owner/repo to create or update a weekly \"Backlog health report\" issue in with the Markdown report")
+
+// reportIssueTitle is per ISO week, so each week gets its own issue and
+// reruns within the week update it.
+func reportIssueTitle(org string, now time.Time) string {
+	year, week := now.UTC().ISOWeek()
+	return fmt.Sprintf("Backlog health report: %s %d-W%02d", org, year, week)
+}
+
+// postReportIssue creates the issue titled title in repo, or replaces the
+// body of the open one that already has it. Bodies go through a temp file
+// because reports can outgrow a command-line argument.
+func postReportIssue(run func(string, ...string) ([]byte, error), repo, title, body string) (int, error) {
+	stdout, err := run("gh", "issue", "list", "--repo", repo, "--state", "open", "--search", "in:title "+strconv.Quote(title), "--json", "number,title", "--limit", "20")
+	if err != nil {
+		return 0, err
+	}
+	var found []struct {
+		Number int    `json:"number"`
+		Title  string `json:"title"`
+	}
+	if err := json.Unmarshal(stdout, &found); err != nil {
+		return 0, fmt.Errorf("parse gh issue list json: %w", err)
+	}
+
+	bodyFile, err := tempFile("fab-backlog-report-*.md", []byte(body))
+	if err != nil {
+		return 0, err
+	}
+	defer os.Remove(bodyFile)
+
+	for _, is := range found {
+		if is.Title == title {
+			_, err := run("gh", "issue", "edit", strconv.Itoa(is.Number), "--repo", repo, "--body-file", bodyFile)
+			return is.Number, err
+		}
+	}
+	stdout, err = run("gh", "issue", "create", "--repo", repo, "--title", title, "--body-file", bodyFile)
+	if err != nil {
+		return 0, err
+	}
+	// gh prints the new issue's URL, ending in its number.
+	url := strings.TrimSpace(string(stdout))
+	n, err := strconv.Atoi(url[strings.LastIndex(url, "/")+1:])
+	if err != nil {
+		return 0, fmt.Errorf("parse gh issue create output %q: %w", url, err)
+	}
+	return n, nil
+}
+
+// tempFile writes data to a new temp file and returns its path; the caller
+// removes it.
+func tempFile(pattern string, data []byte) (string, error) {
+	f, err := os.CreateTemp("", pattern)
+	if err != nil {
+		return "", err
+	}
+	_, err = f.Write(data)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(f.Name())
+		return "", err
+	}
+	return f.Name(), nil
+}
+
+func publishReportIssue(out output) {
+	if *postIssue == "" {
+		return
+	}
+	body, err := renderMarkdown(out)
+	if err != nil {
+		slog.Error("failed to render report", "error", err)
+		return
+	}
+	n, err := postReportIssue(runBackground, *postIssue, reportIssueTitle(out.Org, time.Now()), body)
+	if err != nil {
+		slog.Error("failed to post report issue", "repo", *postIssue, "error", err)
+		return
+	}
+	slog.Info("report issue posted", "repo", *postIssue, "number", n)
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"os"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestReportIssueTitle(t *testing.T) {
+	got := reportIssueTitle("misty-step", time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC))
+	if got != "Backlog health report: misty-step 2025-W01" {
+		t.Errorf("title = %q", got)
+	}
+}
+
+func TestPostReportIssue(t *testing.T) {
+	const title = "Backlog health report: org 2025-W23"
+	for _, tc := range []struct {
+		name     string
+		existing string
+		wantCmd  string
+		wantN    int
+	}{
+		{"create", `[{"number": 4, "title": "Backlog health report: org 2025-W22"}]`, "create", 9},
+		{"update", `[{"number": 7, "title": "` + title + `"}]`, "edit", 7},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var cmds []string
+			var body string
+			run := func(bin string, args ...string) ([]byte, error) {
+				cmds = append(cmds, args[1])
+				if i := slices.Index(args, "--body-file"); i >= 0 {
+					data, _ := os.ReadFile(args[i+1])
+					body = string(data)
+				}
+				switch args[1] {
+				case "list":
+					return []byte(tc.existing), nil
+				case "create":
+					return []byte("https://github.com/org/reports/issues/9\n"), nil
+				}
+				return nil, nil
+			}
+			n, err := postReportIssue(run, "org/reports", title, "# report")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if n != tc.wantN || len(cmds) != 2 || cmds[1] != tc.wantCmd || body != "# report" {
+				t.Errorf("n = %d, cmds = %v, body = %q", n, cmds, body)
+			}
+		})
+	}
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"flag"
+	"fmt"
+	"log/slog"
+
+	"github.com/misty-step/fab-backlog/pkg/score"
+)
+
+var projectCoverageFlag = flag.Bool("project-coverage", false, "report the share of each repo's open issues on a Projects v2 board (needs the read:project scope; -config projectCoverage can make it count toward the score)")
+
+// projectCoverage turns on the projectTracking metric. With MinPercent set,
+// a repo tracking less of its backlog on projects loses Penalty points.
+type projectCoverage struct {
+	MinPercent float64 `json:"minPercent,omitempty"`
+	Penalty    int     `json:"penalty,omitempty"`
+}
+
+// defaultCoveragePenalty is the points lost when Penalty is left at zero.
+const defaultCoveragePenalty = 10
+
+func validateProjectCoverage(c *projectCoverage) error {
+	if c != nil && (c.MinPercent < 0 || c.MinPercent > 100 || c.Penalty < 0) {
+		return fmt.Errorf("projectCoverage: minPercent must be 0-100 and penalty not negative")
+	}
+	return nil
+}
+
+// projectTracking is how much of a repo's backlog is on a project board,
+// where planning can see it.
+type projectTracking struct {
+	Tracked        int     `json:"tracked"`
+	Untracked      int     `json:"untracked"`
+	TrackedPercent float64 `json:"trackedPercent"`
+}
+
+// addProjectCoverage counts the scored open issues that are items on at
+// least one Projects v2 board, with one more issue list asking for just
+// their project items. With a minimum set, falling short of it costs
+// points, recorded in the breakdown as tracking.
+func addProjectCoverage(ctx context.Context, rs *repoScore, owner, repo string, cfg config, limit int) {
+	c := cfg.ProjectCoverage
+	if c == nil {
+		return
+	}
+	found, err := ghListIssues(ctx, owner, repo, "number,projectItems", limit)
+	if err != nil {
+		slog.Warn("project coverage unavailable", "repo", repo, "error", err)
+		return
+	}
+	onProject := map[int]bool{}
+	for _, is := range found {
+		onProject[is.Number] = len(is.ProjectItems) > 0
+	}
+	pt := &projectTracking{TrackedPercent: 100}
+	for _, is := range rs.issues {
+		if onProject[is.Number] {
+			pt.Tracked++
+		} else {
+			pt.Untracked++
+		}
+	}
+	if total := pt.Tracked + pt.Untracked; total > 0 {
+		pt.TrackedPercent = float64(pt.Tracked) / float64(total) * 100
+	}
+	rs.ProjectTracking = pt
+	b := rs.ScoreBreakdown
+	if c.MinPercent <= 0 || b == nil {
+		return
+	}
+	penalty := cmp.Or(c.Penalty, defaultCoveragePenalty)
+	tracking := score.Component{Max: penalty, Awarded: pt.TrackedPercent >= c.MinPercent}
+	if tracking.Awarded {
+		tracking.Reason = fmt.Sprintf("%.1f%% on a project, at least %.0f%%", pt.TrackedPercent, c.MinPercent)
+	} else {
+		tracking.Points = -penalty
+		tracking.Reason = fmt.Sprintf("%.1f%% on a project, needs to be at least %.0f%%", pt.TrackedPercent, c.MinPercent)
+	}
+	b.Tracking = &tracking
+	rs.HealthScore = b.Total()
+	if b.Override == "" {
+		rs.Status = statusFor(float64(rs.HealthScore))
+	}
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	ghscan "github.com/misty-step/fab-backlog/pkg/scan"
+	"github.com/misty-step/fab-backlog/pkg/score"
+)
+
+func TestProjectCoverage(t *testing.T) {
+	now := time.Now()
+	fresh := now.AddDate(0, 0, -3)
+	bug := []label{{Name: "bug"}}
+	roadmap := []score.ProjectItem{{Title: "Roadmap"}}
+	prevClient, prevRun := client, runCmd
+	t.Cleanup(func() { client, runCmd = prevClient, prevRun })
+	runCmd = func(ctx context.Context, bin string, args ...string) ([]byte, error) {
+		t.Errorf("unexpected process: %s %v", bin, args)
+		return nil, context.Canceled
+	}
+	client = &ghscan.Fake{Repos: []ghscan.FakeRepo{{Owner: "acme", Name: "api", Issues: []issue{
+		{Number: 1, Labels: bug, CreatedAt: fresh, UpdatedAt: fresh, ProjectItems: roadmap},
+		{Number: 2, Labels: bug, CreatedAt: fresh, UpdatedAt: fresh},
+		{Number: 3, Labels: bug, CreatedAt: fresh, UpdatedAt: fresh},
+		{Number: 4, Labels: bug, CreatedAt: fresh, UpdatedAt: fresh, ProjectItems: roadmap},
+	}}}}
+	cfg := config{MinIssues: 1, StaleDays: 90, ProjectCoverage: &projectCoverage{}}
+
+	rs := computeRepoScore(t.Context(), "api", "acme", cfg, now)
+	if pt := rs.ProjectTracking; pt == nil || pt.Tracked != 2 || pt.Untracked != 2 || pt.TrackedPercent != 50 {
+		t.Fatalf("projectTracking = %+v", pt)
+	}
+	if rs.HealthScore != 100 || rs.ScoreBreakdown.Tracking != nil {
+		t.Errorf("without minPercent the score shouldn't change: %d, %+v", rs.HealthScore, rs.ScoreBreakdown)
+	}
+
+	cfg.ProjectCoverage.MinPercent = 75
+	rs = computeRepoScore(t.Context(), "api", "acme", cfg, now)
+	if b := rs.ScoreBreakdown.Tracking; b == nil || b.Awarded || b.Points != -defaultCoveragePenalty {
+		t.Errorf("tracking = %+v", b)
+	}
+	if rs.HealthScore != 90 || rs.Status != "healthy" {
+		t.Errorf("score %d %s, want 90 healthy", rs.HealthScore, rs.Status)
+	}
+
+	cfg.ProjectCoverage.Penalty = 40
+	if rs = computeRepoScore(t.Context(), "api", "acme", cfg, now); rs.HealthScore != 60 || rs.Status != "warning" {
+		t.Errorf("score %d %s, want 60 warning", rs.HealthScore, rs.Status)
+	}
+}
+
+func TestValidateProjectCoverage(t *testing.T) {
+	for _, c := range []*projectCoverage{{MinPercent: 101}, {MinPercent: -1}, {Penalty: -5}} {
+		if err := validateProjectCoverage(c); err == nil {
+			t.Errorf("%+v should fail", *c)
+		}
+	}
+	if err := validateProjectCoverage(&projectCoverage{MinPercent: 80, Penalty: 15}); err != nil {
+		t.Error(err)
+	}
+}
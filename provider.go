@@ -0,0 +1,136 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	ghscan "github.com/misty-step/fab-backlog/pkg/scan"
+)
+
+var (
+	providerFlag = flag.String("provider", "github", "forge to scan: github, through gh; gitlab, through glab, with -org naming groups and -repo group/project; gitea, for Gitea, Forgejo and Codeberg; jira; or azure, for Azure DevOps Boards")
+	giteaURL     = flag.String("gitea-url", "https://codeberg.org", "with -provider gitea, the instance's base URL; a token, if needed, is read from FAB_BACKLOG_GITEA_TOKEN")
+	jiraURL      = flag.String("jira-url", "", "with -provider jira, the site's base URL, e.g. https://acme.atlassian.net; credentials are read from FAB_BACKLOG_JIRA_USER and FAB_BACKLOG_JIRA_TOKEN")
+	azureURL     = flag.String("azure-url", "https://dev.azure.com", "with -provider azure, the service's base URL, or a server's such as https://ado.acme.com/tfs, with -org naming the organization or collection; a personal access token is read from FAB_BACKLOG_AZURE_TOKEN")
+)
+
+// providerURL is the base URL of a provider reached over HTTP, "" for the
+// ones reached through a CLI.
+func providerURL() string {
+	switch *providerFlag {
+	case "gitea":
+		return *giteaURL
+	case "jira":
+		return *jiraURL
+	case "azure":
+		return *azureURL
+	}
+	return ""
+}
+
+// githubOnly lists what cfg and the flags turn on that reads or writes
+// GitHub beyond the ghscan.Client reads, so can't run against another
+// provider.
+func githubOnly(cfg config) []string {
+	var on []string
+	add := func(name string, enabled bool) {
+		if enabled {
+			on = append(on, name)
+		}
+	}
+	add("deep", cfg.Deep)
+	add("slos", len(cfg.SLOs) > 0)
+	add("mostWanted", cfg.MostWanted > 0)
+	add("hallOfShame", cfg.HallOfShame > 0)
+	add("responseWindowDays", cfg.ResponseWindowDays > 0)
+	add("labelLatencyWindowDays", cfg.LabelLatencyWindowDays > 0)
+	add("contributorWindowDays", cfg.ContributorWindowDays > 0)
+	add("qaWindowDays", cfg.QAWindowDays > 0)
+	add("automationBots", len(cfg.AutomationBots) > 0)
+	add("triageEffort", cfg.TriageEffort != nil)
+	add("securityAlerts", cfg.SecurityAlerts)
+	add("projectCoverage", cfg.ProjectCoverage != nil)
+	add("linkCheck", cfg.LinkCheck)
+	add("repoTopics", cfg.readsTopics())
+	add("teamRollups", cfg.TeamRollups)
+	add("stalePolicy", cfg.StalePolicy != nil)
+	add("supportSLA", cfg.SupportSLA != nil)
+	add("-since-last-run", *sinceLastRun)
+	add("-apply-stale-label", *applyStaleLabel != "")
+	add("-post-issue", *postIssue != "")
+	add("-check-run", *checkRun != "")
+	add("-commit-report", *commitReport != "")
+	add("-gist", *gistFlag)
+	return on
+}
+
+// validateProvider checks -provider and, for the others, that the command
+// and everything turned on work there. GitLab and Gitea scans compute the
+// core score: open issues, staleness, labels and the repo config file.
+func validateProvider(c command, sel selection, cfg config) error {
+	p := *providerFlag
+	for _, name := range []string{"gitea", "jira", "azure"} {
+		if p != name && flagSet(name+"-url") {
+			return fmt.Errorf("-%s-url only applies to -provider %s", name, name)
+		}
+	}
+	switch p {
+	case "github":
+		return nil
+	case "gitlab":
+	case "gitea", "jira", "azure":
+		if *hostnameFlag != "" {
+			return fmt.Errorf("-hostname doesn't apply to -provider %s: use -%s-url", p, p)
+		}
+		if u, err := url.Parse(providerURL()); err != nil || u.Scheme != "https" && u.Scheme != "http" || u.Host == "" {
+			return fmt.Errorf("-%s-url must be an http(s) URL, got %q", p, providerURL())
+		}
+	default:
+		return fmt.Errorf("-provider must be github, gitlab, gitea, jira or azure, got %q", p)
+	}
+	if *fromFile != "" || *recordDir != "" || *etagCacheDir != "" {
+		return fmt.Errorf("-from-file, -record-dir and -etag-cache only capture gh calls, so don't apply to -provider %s", p)
+	}
+	if c.name != "scan" && c.name != "focus" && (!c.offline || c.name == "login") {
+		return fmt.Errorf("-provider %s doesn't support %s", p, c.name)
+	}
+	if !c.offline && len(sel.Orgs) == 0 && len(sel.Repos) == 0 {
+		return fmt.Errorf("-provider %s needs -org or -repo: orgs aren't discovered", p)
+	}
+	if !c.offline && p == "jira" && len(sel.Orgs) == 0 {
+		return fmt.Errorf("-provider jira needs -org to name the site's projects after")
+	}
+	if on := githubOnly(cfg); len(on) > 0 {
+		return fmt.Errorf("-provider %s doesn't support %s yet", p, strings.Join(on, ", "))
+	}
+	return nil
+}
+
+// applyProvider points client at the chosen provider. GitLab runs glab
+// through runCmd, whose retries, throttle, cache and recording only act on
+// gh and pass glab straight through; the others' HTTP requests go through
+// httpTransport, with its proxy and CAs.
+func applyProvider() {
+	switch *providerFlag {
+	case "gitlab":
+		client = ghscan.GitLab{Run: func(ctx context.Context, bin string, args ...string) ([]byte, error) {
+			return runCmd(ctx, bin, args...)
+		}}
+	case "gitea":
+		client = ghscan.Gitea{BaseURL: *giteaURL, Token: os.Getenv("FAB_BACKLOG_GITEA_TOKEN"), HTTP: providerClient()}
+	case "jira":
+		client = ghscan.Jira{BaseURL: *jiraURL, User: os.Getenv("FAB_BACKLOG_JIRA_USER"), Token: os.Getenv("FAB_BACKLOG_JIRA_TOKEN"), HTTP: providerClient()}
+	case "azure":
+		client = ghscan.AzureDevOps{BaseURL: *azureURL, Token: os.Getenv("FAB_BACKLOG_AZURE_TOKEN"), HTTP: providerClient()}
+	}
+}
+
+func providerClient() *http.Client {
+	return &http.Client{Timeout: 30 * time.Second, Transport: httpTransport}
+}
@@ -0,0 +1,178 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestValidateProvider(t *testing.T) {
+	prev := *providerFlag
+	t.Cleanup(func() { *providerFlag = prev })
+	scanCmd, _, _ := findCommand([]string{"scan"})
+	triage, _, _ := findCommand([]string{"triage"})
+	report, _, _ := findCommand([]string{"report"})
+	login, _, _ := findCommand([]string{"login"})
+	acme := selection{Orgs: []string{"acme"}}
+
+	*providerFlag = "github"
+	if err := validateProvider(triage, acme, config{Deep: true}); err != nil {
+		t.Errorf("github: %v", err)
+	}
+	*providerFlag = "bitbucket"
+	if err := validateProvider(scanCmd, acme, config{}); err == nil {
+		t.Error("unknown provider should fail")
+	}
+
+	*providerFlag = "gitlab"
+	for _, tc := range []struct {
+		name string
+		c    command
+		sel  selection
+		cfg  config
+		want string
+	}{
+		{"scan", scanCmd, acme, config{StaleDays: 90, CodeOwners: true}, ""},
+		{"report", report, selection{}, config{}, ""},
+		{"triage", triage, acme, config{}, "doesn't support triage"},
+		{"login", login, selection{}, config{}, "doesn't support login"},
+		{"no org", scanCmd, selection{}, config{}, "needs -org or -repo"},
+		{"github only", scanCmd, acme, config{SecurityAlerts: true, SLOs: []slo{{Label: "bug"}}}, "slos, securityAlerts"},
+		{"topics", scanCmd, acme, config{Topics: map[string]repoOverrides{"legacy": {}}}, "repoTopics"},
+		{"team rollups", scanCmd, acme, config{TeamRollups: true}, "teamRollups"},
+	} {
+		err := validateProvider(tc.c, tc.sel, tc.cfg)
+		if tc.want == "" && err != nil || tc.want != "" && (err == nil || !strings.Contains(err.Error(), tc.want)) {
+			t.Errorf("%s: err = %v, want %q", tc.name, err, tc.want)
+		}
+	}
+
+	prevRecord := *recordDir
+	t.Cleanup(func() { *recordDir = prevRecord })
+	*recordDir = t.TempDir()
+	if err := validateProvider(scanCmd, acme, config{}); err == nil {
+		t.Error("-record-dir with -provider gitlab should fail: only gh calls are recorded")
+	}
+}
+
+// TestScanGitLab scores a GitLab group through glab, subgroups included.
+func TestScanGitLab(t *testing.T) {
+	old := time.Now().AddDate(0, 0, -200).UTC().Format(time.RFC3339)
+	prevClient, prevRun, prevProvider := client, runCmd, *providerFlag
+	t.Cleanup(func() { client, runCmd, *providerFlag = prevClient, prevRun, prevProvider })
+	runCmd = func(ctx context.Context, bin string, args ...string) ([]byte, error) {
+		if bin != "glab" {
+			t.Errorf("unexpected process: %s %v", bin, args)
+		}
+		path, _, _ := strings.Cut(args[1], "per_page")
+		switch path {
+		case "groups/acme/projects?include_subgroups=true&archived=false&":
+			return []byte(`[{"path_with_namespace":"acme/api"},{"path_with_namespace":"acme/platform/web"}]`), nil
+		case "projects/acme%2Fapi/issues?state=opened&":
+			return []byte(`[{"iid":1,"created_at":"` + old + `","updated_at":"` + old + `","labels":["bug"]}]`), nil
+		case "projects/acme%2Fplatform%2Fweb/issues?state=opened&":
+			return []byte(`[]`), nil
+		}
+		return nil, errors.New("glab: 404 Not Found")
+	}
+	*providerFlag = "gitlab"
+	applyProvider()
+
+	sel := selection{Orgs: []string{"acme"}}
+	repos, err := sel.resolve(t.Context())
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := scan(t.Context(), sel, repos, config{MinIssues: 1, StaleDays: 90})
+	if len(out.Repos) != 2 {
+		t.Fatalf("scored %+v", out.Repos)
+	}
+	for _, rs := range out.Repos {
+		if rs.Error != "" {
+			t.Errorf("%s: %s", rs.Name, rs.Error)
+		}
+		if rs.Name == "api" && rs.StaleCount != 1 {
+			t.Errorf("api = %+v", rs)
+		}
+	}
+}
+
+func TestValidateProviderGitea(t *testing.T) {
+	prev, prevURL, prevHost := *providerFlag, *giteaURL, *hostnameFlag
+	t.Cleanup(func() { *providerFlag, *giteaURL, *hostnameFlag = prev, prevURL, prevHost })
+	scanCmd, _, _ := findCommand([]string{"scan"})
+	acme := selection{Orgs: []string{"acme"}}
+
+	*providerFlag, *giteaURL = "gitea", "https://git.acme.com/forge/"
+	if err := validateProvider(scanCmd, acme, config{}); err != nil {
+		t.Error(err)
+	}
+	if h, u := forgeHost(), webURL("acme/api/issues"); h != "git.acme.com" || u != "https://git.acme.com/forge/acme/api/issues" {
+		t.Errorf("host %s, url %s", h, u)
+	}
+	*giteaURL = "git.acme.com"
+	if err := validateProvider(scanCmd, acme, config{}); err == nil {
+		t.Error("-gitea-url without a scheme should fail")
+	}
+	*giteaURL, *hostnameFlag = "https://codeberg.org", "codeberg.org"
+	if err := validateProvider(scanCmd, acme, config{}); err == nil {
+		t.Error("-hostname with gitea should fail")
+	}
+}
+
+func TestValidateProviderJira(t *testing.T) {
+	prev, prevURL := *providerFlag, *jiraURL
+	t.Cleanup(func() { *providerFlag, *jiraURL = prev, prevURL })
+	scanCmd, _, _ := findCommand([]string{"scan"})
+
+	*providerFlag, *jiraURL = "jira", ""
+	if err := validateProvider(scanCmd, selection{Orgs: []string{"acme"}}, config{}); err == nil || !strings.Contains(err.Error(), "-jira-url") {
+		t.Errorf("missing -jira-url: err = %v", err)
+	}
+	*jiraURL = "https://acme.atlassian.net"
+	if err := validateProvider(scanCmd, selection{Repos: []string{"OPS"}}, config{}); err == nil || !strings.Contains(err.Error(), "needs -org") {
+		t.Errorf("missing -org: err = %v", err)
+	}
+	if err := validateProvider(scanCmd, selection{Orgs: []string{"acme"}, Repos: []string{"OPS"}}, config{}); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestValidateProviderAzure(t *testing.T) {
+	prev, prevURL := *providerFlag, *azureURL
+	t.Cleanup(func() { *providerFlag, *azureURL = prev, prevURL })
+	scanCmd, _, _ := findCommand([]string{"scan"})
+
+	*providerFlag = "azure"
+	if err := validateProvider(scanCmd, selection{Orgs: []string{"acme"}}, config{}); err != nil {
+		t.Error(err)
+	}
+	if err := validateProvider(scanCmd, selection{Orgs: []string{"acme"}}, config{Deep: true}); err == nil {
+		t.Error("deep with azure should fail")
+	}
+	*azureURL = "dev.azure.com"
+	if err := validateProvider(scanCmd, selection{Orgs: []string{"acme"}}, config{}); err == nil || !strings.Contains(err.Error(), "-azure-url") {
+		t.Errorf("-azure-url without a scheme: err = %v", err)
+	}
+}
+
+func TestIssuesURL(t *testing.T) {
+	prev, prevURL, prevAzure, prevHost := *providerFlag, *jiraURL, *azureURL, *hostnameFlag
+	t.Cleanup(func() { *providerFlag, *jiraURL, *azureURL, *hostnameFlag = prev, prevURL, prevAzure, prevHost })
+	*hostnameFlag, *jiraURL, *azureURL = "", "https://acme.atlassian.net/", "https://dev.azure.com"
+	t.Setenv("GH_HOST", "")
+	t.Setenv("GITLAB_HOST", "")
+	for provider, want := range map[string]string{
+		"github": "https://github.com/acme/api/issues",
+		"gitlab": "https://gitlab.com/acme/api/-/issues",
+		"jira":   "https://acme.atlassian.net/browse/api",
+		"azure":  "https://dev.azure.com/acme/api/_workitems",
+	} {
+		*providerFlag = provider
+		if got := issuesURL("acme/api"); got != want {
+			t.Errorf("%s: %s, want %s", provider, got, want)
+		}
+	}
+}
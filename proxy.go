@@ -0,0 +1,111 @@
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+)
+
+var caBundle = flag.String("ca-bundle", "", "PEM file of CA certificates to trust as well as the system's, for proxies that intercept TLS; also handed to gh, with the system's, as SSL_CERT_FILE")
+
+// httpTransport carries fab-backlog's own HTTP requests: notifications,
+// link checks, central exemptions and login. Like gh, it goes through
+// HTTPS_PROXY, HTTP_PROXY and NO_PROXY.
+var httpTransport = http.DefaultTransport.(*http.Transport).Clone()
+
+// loadCABundle reads PEM certificates from path on top of the system pool.
+func loadCABundle(path string) (*x509.CertPool, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("-ca-bundle: %w", err)
+	}
+	pool, err := x509.SystemCertPool()
+	if err != nil {
+		pool = x509.NewCertPool()
+	}
+	if !pool.AppendCertsFromPEM(data) {
+		return nil, fmt.Errorf("-ca-bundle %s: no PEM certificates found", path)
+	}
+	return pool, nil
+}
+
+// systemBundles are where Linux distributions keep the system's CAs as one
+// PEM file, as Go looks for them; the first that exists is used.
+var systemBundles = []string{
+	"/etc/ssl/certs/ca-certificates.crt",
+	"/etc/pki/tls/certs/ca-bundle.crt",
+	"/etc/ssl/ca-bundle.pem",
+	"/etc/pki/tls/cacert.pem",
+	"/etc/pki/ca-trust/extracted/pem/tls-ca-bundle.pem",
+	"/etc/ssl/cert.pem",
+}
+
+// mergedCABundle writes the system bundle followed by extra to a file in
+// the user cache, named by its content so runs share it, and returns its
+// path. SSL_CERT_FILE replaces the system store rather than adding to it,
+// so gh is given both.
+func mergedCABundle(extra []byte) (string, error) {
+	var system []byte
+	for _, path := range systemBundles {
+		if data, err := os.ReadFile(path); err == nil {
+			system = data
+			break
+		}
+	}
+	merged := bytes.Join([][]byte{system, extra}, []byte("\n"))
+	sum := sha256.Sum256(merged)
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		dir = os.TempDir()
+	}
+	dir = filepath.Join(dir, "fab-backlog")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", fmt.Errorf("-ca-bundle: %w", err)
+	}
+	f, err := createAtomic(filepath.Join(dir, "ca-bundle-"+hex.EncodeToString(sum[:8])+".pem"))
+	if err != nil {
+		return "", fmt.Errorf("-ca-bundle: %w", err)
+	}
+	if _, err := f.Write(merged); err != nil {
+		f.abort()
+		return "", fmt.Errorf("-ca-bundle: %w", err)
+	}
+	if err := f.commit(); err != nil {
+		return "", fmt.Errorf("-ca-bundle: %w", err)
+	}
+	return f.path, nil
+}
+
+// applyCABundle trusts -ca-bundle's CAs in httpTransport and exports them,
+// with the system's, as SSL_CERT_FILE, which gh (and any other Go program
+// it runs) reads on Linux in place of the system bundle. An SSL_CERT_FILE
+// already set is left alone.
+func applyCABundle() error {
+	if *caBundle == "" {
+		return nil
+	}
+	pool, err := loadCABundle(*caBundle)
+	if err != nil {
+		return err
+	}
+	httpTransport.TLSClientConfig = &tls.Config{RootCAs: pool}
+	if os.Getenv("SSL_CERT_FILE") != "" {
+		return nil
+	}
+	extra, err := os.ReadFile(*caBundle)
+	if err != nil {
+		return fmt.Errorf("-ca-bundle: %w", err)
+	}
+	merged, err := mergedCABundle(extra)
+	if err != nil {
+		return err
+	}
+	return os.Setenv("SSL_CERT_FILE", merged)
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestApplyCABundle(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+	prev, prevTLS, prevSystem := *caBundle, httpTransport.TLSClientConfig, systemBundles
+	t.Cleanup(func() { *caBundle, httpTransport.TLSClientConfig, systemBundles = prev, prevTLS, prevSystem })
+	t.Setenv("SSL_CERT_FILE", "")
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	system := filepath.Join(t.TempDir(), "system.pem")
+	if err := os.WriteFile(system, []byte("-----BEGIN CERTIFICATE-----\nc3lzdGVt\n-----END CERTIFICATE-----\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	systemBundles = []string{filepath.Join(t.TempDir(), "missing.pem"), system}
+
+	client := &http.Client{Transport: httpTransport}
+	if _, err := client.Get(srv.URL); err == nil {
+		t.Fatal("an unknown CA should be rejected")
+	}
+	*caBundle = filepath.Join(t.TempDir(), "ca.pem")
+	cert := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw})
+	if err := os.WriteFile(*caBundle, cert, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := applyCABundle(); err != nil {
+		t.Fatal(err)
+	}
+	resp, err := client.Get(srv.URL)
+	if err != nil {
+		t.Fatalf("with -ca-bundle: %v", err)
+	}
+	resp.Body.Close()
+	// gh is handed the system's CAs and the bundle's, not the bundle alone.
+	merged, err := os.ReadFile(os.Getenv("SSL_CERT_FILE"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(merged), "c3lzdGVt") || !strings.Contains(string(merged), string(cert)) {
+		t.Errorf("SSL_CERT_FILE holds:\n%s", merged)
+	}
+
+	if err := os.WriteFile(*caBundle, []byte("not a certificate"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := applyCABundle(); err == nil {
+		t.Error("a file without certificates should fail")
+	}
+}
@@ -0,0 +1,169 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	htmltemplate "html/template"
+	"log/slog"
+	"os"
+	"path/filepath"
+
+	"github.com/misty-step/fab-backlog/pkg/report"
+)
+
+var siteDir = flag.String("dir", "", "with publish, the directory to write the static site into (default site)")
+
+const siteStyle = `<style>
+body { font-family: sans-serif; max-width: 60em; margin: 2em auto; padding: 0 1em }
+table { border-collapse: collapse } th, td { border: 1px solid #d0d7de; padding: 4px 8px } td.n { text-align: right }
+.healthy { color: #2da44e } .warning { color: #bf8700 } .critical { color: #cf222e }
+</style>`
+
+var siteIndexTmpl = htmltemplate.Must(htmltemplate.New("index").Funcs(renderFuncs).Parse(`<!DOCTYPE html>
+<html><head><meta charset="utf-8"><title>Backlog health: {{.Report.Org}}</title>
+` + siteStyle + `</head>
+<body>
+<h1>Backlog health: {{.Report.Org}}</h1>
+<p>{{summaryLine .Report}} — generated {{date .Report.GeneratedAt}} from {{num .Runs}} stored runs</p>
+{{with .Report.Gate}}<p><strong>Gate {{if .Passed}}passed{{else}}failed{{end}}{{if .Observed}} (observe mode){{end}}</strong></p>
+{{end}}<table>
+<tr><th>Repo</th><th>Score</th><th>Status</th><th>Open</th><th>Stale</th><th>Unlabeled</th><th>Trend</th></tr>
+{{range .Report.Repos}}{{if not .Error}}<tr><td><a href="{{index $.Pages .Name}}">{{.Name}}</a></td><td class="n">{{if ne .Status "unscored"}}{{num .HealthScore}}{{end}}</td><td class="{{.Status}}">{{.Status}}</td><td class="n">{{num .TotalOpen}}</td><td class="n">{{num .StaleCount}}</td><td class="n">{{num .UnlabeledCount}}</td><td>{{index $.Trends .Name}}</td></tr>
+{{end}}{{end}}</table>
+{{$failed := false}}{{range .Report.Repos}}{{if .Error}}{{if not $failed}}{{$failed = true}}<h2>Failed to scan</h2>
+<ul>{{end}}<li><a href="{{index $.Pages .Name}}">{{.Name}}</a>: {{.Error}}</li>{{end}}{{end}}{{if $failed}}</ul>
+{{end}}</body></html>
+`))
+
+var sitePageTmpl = htmltemplate.Must(htmltemplate.New("repo").Funcs(renderFuncs).Parse(`<!DOCTYPE html>
+<html><head><meta charset="utf-8"><title>Backlog health: {{.Repo.Name}}</title>
+` + siteStyle + `</head>
+<body>
+<p><a href="../index.html">{{.Org}}</a></p>
+{{with .Repo}}<h1>{{.Name}}</h1>
+{{if .Error}}<p>The last scan failed: {{.Error}}</p>
+{{else}}<p class="{{.Status}}"><strong>{{if ne .Status "unscored"}}{{num .HealthScore}} — {{end}}{{.Status}}</strong></p>
+<table>
+<tr><th>Open</th><td class="n">{{num .TotalOpen}}</td></tr>
+<tr><th>Stale</th><td class="n">{{num .StaleCount}} ({{decimal .StalePercent 1}}%)</td></tr>
+<tr><th>Unlabeled</th><td class="n">{{num .UnlabeledCount}}</td></tr>
+<tr><th>Onboarding</th><td class="n">{{num .OnboardingCount}}</td></tr>
+</table>
+{{with .Recommendations}}<h2>Recommendations</h2>
+<ul>{{range .}}<li>{{.}}</li>{{end}}</ul>
+{{end}}{{end}}{{end}}{{with .Series}}<h2>History</h2>
+<p><img src="{{$.Chart}}" alt="Health score history"></p>
+<table>
+<tr><th>Run</th><th>Score</th><th>Status</th></tr>
+{{range .Points}}<tr><td>{{.At.Format "2006-01-02"}}</td><td class="n">{{num .HealthScore}}</td><td class="{{.Status}}">{{.Status}}</td></tr>
+{{end}}</table>
+{{end}}<p>Generated {{date .GeneratedAt}}</p>
+</body></html>
+`))
+
+// siteIndex is the data behind the site's front page.
+type siteIndex struct {
+	Report output
+	Runs   int
+	Pages  map[string]string // repo name -> page, relative to the index
+	Trends map[string]string // repo name -> sparkline
+}
+
+// sitePage is the data behind one repo's page.
+type sitePage struct {
+	Org         string
+	GeneratedAt string
+	Repo        repoScore
+	Series      *trendSeries
+	Chart       string // relative to the page
+}
+
+// runPublish implements `fab-backlog publish -dir site/`.
+func runPublish(args []string, cfg config) int {
+	if len(args) > 0 {
+		return commandFailed(2, "publish failed", errors.New("usage: fab-backlog publish [-dir site]"))
+	}
+	if cfg.Storage == nil {
+		return commandFailed(2, "publish failed", errors.New("publish needs -history-file or storage in -config"))
+	}
+	dir := orDefault(*siteDir, "site")
+	if err := publishSite(openStorage(cfg.Storage), dir); err != nil {
+		return commandFailed(1, "publish failed", err)
+	}
+	emitJSON(map[string]any{"ok": true, "dir": dir})
+	return 0
+}
+
+// publishSite renders the newest stored run and every run's scores into a
+// static site in dir: index.html, a page per repo under repos/, its trend
+// chart under charts/ and its badge under badges/. Files are replaced in
+// place, so dir can be served while it is rewritten.
+func publishSite(store Storage, dir string) error {
+	runs, err := store.ListRuns()
+	if err != nil {
+		return err
+	}
+	if len(runs) == 0 {
+		return errors.New("no stored runs to publish")
+	}
+	latest, err := store.GetRun(runs[0].ID)
+	if err != nil {
+		return err
+	}
+	out := latest.Report
+	t, err := loadTrend(store, "")
+	if err != nil {
+		return err
+	}
+	f, err := report.Lookup(out.Config.Locale)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Join(dir, "repos"), 0o755); err != nil {
+		return err
+	}
+	if err := writeTrendCharts(filepath.Join(dir, "charts"), t.Series); err != nil {
+		return err
+	}
+	if err := writeBadges(filepath.Join(dir, "badges"), out); err != nil {
+		return err
+	}
+
+	series := map[string]*trendSeries{}
+	for i, s := range t.Series {
+		series[s.Repo] = &t.Series[i]
+	}
+	idx := siteIndex{Report: out, Runs: len(runs), Pages: map[string]string{}, Trends: map[string]string{}}
+	for _, rs := range out.Repos {
+		page := sitePage{Org: out.Org, GeneratedAt: out.GeneratedAt, Repo: rs, Series: series[rs.Name]}
+		if page.Series != nil {
+			page.Chart = "../charts/" + repoFile(rs.Name, ".svg")
+			idx.Trends[rs.Name] = sparkline(*page.Series, t.Runs)
+		}
+		name := "repos/" + repoFile(rs.Name, ".html")
+		idx.Pages[rs.Name] = name
+		if err := writeSitePage(filepath.Join(dir, name), sitePageTmpl, f, page); err != nil {
+			return err
+		}
+	}
+	if err := writeSitePage(filepath.Join(dir, "index.html"), siteIndexTmpl, f, idx); err != nil {
+		return err
+	}
+	slog.Info("site published", "dir", dir, "run", latest.ID, "repos", len(out.Repos))
+	return nil
+}
+
+// writeSitePage renders one page with the report's locale.
+func writeSitePage(path string, tmpl *htmltemplate.Template, f report.Format, data any) error {
+	t, err := tmpl.Clone()
+	if err != nil {
+		return err
+	}
+	var b bytes.Buffer
+	if err := t.Funcs(f.Funcs()).Execute(&b, data); err != nil {
+		return fmt.Errorf("render %s: %w", path, err)
+	}
+	return writeFileAtomic(path, b.Bytes())
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestPublishSite(t *testing.T) {
+	store := trendStore(t)
+	dir := t.TempDir()
+	if err := publishSite(store, dir); err != nil {
+		t.Fatal(err)
+	}
+	read := func(name string) string {
+		t.Helper()
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(data)
+	}
+
+	index := read("index.html")
+	for _, want := range []string{
+		"<title>Backlog health: acme</title>",
+		"from 3 stored runs",
+		`<a href="repos/api.html">api</a></td><td class="n">30</td><td class="critical">critical</td>`,
+		"<td>▇▅▃</td>",
+	} {
+		if !strings.Contains(index, want) {
+			t.Errorf("index lacks %q:\n%s", want, index)
+		}
+	}
+	if strings.Contains(index, "cli.html\">cli</a></td><td class=\"n\">0") {
+		t.Errorf("unscored repos shouldn't show a score:\n%s", index)
+	}
+
+	page := read("repos/api.html")
+	for _, want := range []string{
+		`<a href="../index.html">acme</a>`,
+		`<img src="../charts/api.svg"`,
+		`<tr><td>2025-03-08</td><td class="n">60</td><td class="warning">warning</td></tr>`,
+	} {
+		if !strings.Contains(page, want) {
+			t.Errorf("api page lacks %q:\n%s", want, page)
+		}
+	}
+	// cli was never scored, so its page has no history.
+	if cli := read("repos/cli.html"); strings.Contains(cli, "History") {
+		t.Errorf("cli page:\n%s", cli)
+	}
+	for _, name := range []string{"charts/api.svg", "charts/web.svg", "badges/api.json"} {
+		read(name)
+	}
+
+	if err := publishSite(&memoryStorage{}, t.TempDir()); err == nil {
+		t.Error("publishing an empty store should fail")
+	}
+}
@@ -0,0 +1,171 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"math"
+	"time"
+)
+
+var failOffTrack = flag.Bool("fail-off-track", false, "exit non-zero if any repo is behind its ramp plan")
+
+// rampPlan is a gradual cleanup commitment: starting at From on Start, the
+// metric improves by StepPerMonth each month until it reaches Target, e.g.
+// stale% down 5 points a month until under 20%. Repos are owner/name globs;
+// a repo follows the first plan naming it.
+type rampPlan struct {
+	// Name identifies the plan in reports, e.g. the owning team.
+	Name  string       `json:"name,omitempty"`
+	Repos repoPatterns `json:"repos"`
+	// Metric is stalePercent (default), staleCount, totalOpen or
+	// healthScore. Health scores ramp up; the others ramp down.
+	Metric       string  `json:"metric,omitempty"`
+	Start        string  `json:"start"` // YYYY-MM-DD
+	From         float64 `json:"from"`
+	StepPerMonth float64 `json:"stepPerMonth"`
+	Target       float64 `json:"target"`
+}
+
+// Ramp statuses, best first.
+const (
+	rampMet      = "met"
+	rampOnTrack  = "on-track"
+	rampOffTrack = "off-track"
+)
+
+// rampStatus is where a repo stands against its plan this run.
+type rampStatus struct {
+	Plan   string  `json:"plan,omitempty"`
+	Metric string  `json:"metric"`
+	Actual float64 `json:"actual"`
+	// Expected is where the plan says the metric should be by now.
+	Expected float64 `json:"expected"`
+	Target   float64 `json:"target"`
+	// TargetDate is when the plan reaches Target.
+	TargetDate string `json:"targetDate"`
+	Status     string `json:"status"`
+}
+
+func (p rampPlan) metric() string {
+	return orDefault(p.Metric, "stalePercent")
+}
+
+// value reads the plan's metric off a scored repo.
+func (p rampPlan) value(rs repoScore) float64 {
+	switch p.metric() {
+	case "staleCount":
+		return float64(rs.StaleCount)
+	case "totalOpen":
+		return float64(rs.TotalOpen)
+	case "healthScore":
+		return float64(rs.HealthScore)
+	}
+	return rs.StalePercent
+}
+
+// better reports whether a is at least as good as b for the metric.
+func (p rampPlan) better(a, b float64) bool {
+	if p.metric() == "healthScore" {
+		return a >= b
+	}
+	return a <= b
+}
+
+func (p rampPlan) start() time.Time {
+	t, _ := time.Parse("2006-01-02", p.Start)
+	return t
+}
+
+// expected is the plan's value after the whole months elapsed by now,
+// never past Target.
+func (p rampPlan) expected(now time.Time) float64 {
+	step := p.StepPerMonth * float64(monthsBetween(p.start(), now))
+	if p.metric() == "healthScore" {
+		return math.Min(p.From+step, math.Max(p.From, p.Target))
+	}
+	return math.Max(p.From-step, math.Min(p.From, p.Target))
+}
+
+// targetDate is the first month boundary at which the plan reaches Target.
+func (p rampPlan) targetDate() time.Time {
+	months := math.Ceil(math.Abs(p.From-p.Target) / p.StepPerMonth)
+	return p.start().AddDate(0, int(months), 0)
+}
+
+// monthsBetween counts the whole calendar months from start to now, zero
+// before start.
+func monthsBetween(start, now time.Time) int {
+	m := (now.Year()-start.Year())*12 + int(now.Month()-start.Month())
+	if now.Day() < start.Day() {
+		m--
+	}
+	return max(m, 0)
+}
+
+func (p rampPlan) status(rs repoScore, now time.Time) *rampStatus {
+	s := &rampStatus{Plan: p.Name, Metric: p.metric(), Actual: p.value(rs), Expected: p.expected(now), Target: p.Target,
+		TargetDate: p.targetDate().Format("2006-01-02")}
+	switch {
+	case p.better(s.Actual, s.Target):
+		s.Status = rampMet
+	case p.better(s.Actual, s.Expected):
+		s.Status = rampOnTrack
+	default:
+		s.Status = rampOffTrack
+	}
+	return s
+}
+
+func validateRamps(plans []rampPlan) error {
+	for i, p := range plans {
+		name := orDefault(p.Name, fmt.Sprintf("#%d", i+1))
+		if len(p.Repos) == 0 {
+			return fmt.Errorf("ramp %s: repos required", name)
+		}
+		if err := p.Repos.validate(); err != nil {
+			return fmt.Errorf("ramp %s: %w", name, err)
+		}
+		switch p.metric() {
+		case "stalePercent", "staleCount", "totalOpen", "healthScore":
+		default:
+			return fmt.Errorf("ramp %s: metric must be stalePercent, staleCount, totalOpen or healthScore, got %q", name, p.Metric)
+		}
+		if _, err := time.Parse("2006-01-02", p.Start); err != nil {
+			return fmt.Errorf("ramp %s: start: %w", name, err)
+		}
+		if p.StepPerMonth <= 0 {
+			return fmt.Errorf("ramp %s: stepPerMonth must be positive", name)
+		}
+	}
+	return nil
+}
+
+// addRampStatus compares each scanned repo with the first plan covering it.
+func addRampStatus(repos []repoScore, plans []rampPlan, now time.Time) {
+	for i, rs := range repos {
+		if rs.Error != "" || rs.Status == unscored {
+			continue
+		}
+		for _, p := range plans {
+			if p.Repos.matches(rs.fullName) {
+				repos[i].Ramp = p.status(rs, now)
+				break
+			}
+		}
+	}
+}
+
+// gateRamps adds the -fail-off-track check to the gate.
+func gateRamps(g *gateResult, s summary, enforce bool) *gateResult {
+	if !enforce {
+		return g
+	}
+	if g == nil {
+		g = &gateResult{}
+	}
+	if s.OffTrack > 0 {
+		g.Reasons = append(g.Reasons, fmt.Sprintf("%d repos behind their ramp plans", s.OffTrack))
+	}
+	g.Passed = len(g.Reasons) == 0
+	return g
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRampPlan(t *testing.T) {
+	p := rampPlan{Name: "platform", Repos: []string{"acme/*"}, Start: "2025-01-15", From: 60, StepPerMonth: 5, Target: 20}
+	if err := validateRamps([]rampPlan{p}); err != nil {
+		t.Fatal(err)
+	}
+	for now, want := range map[string]float64{
+		"2024-12-01": 60, // not started
+		"2025-02-14": 60, // a day short of the first month
+		"2025-02-15": 55,
+		"2025-07-20": 30,
+		"2026-06-01": 20, // held at the target
+	} {
+		at, _ := time.Parse("2006-01-02", now)
+		if got := p.expected(at); got != want {
+			t.Errorf("expected on %s = %v, want %v", now, got, want)
+		}
+	}
+	if d := p.targetDate().Format("2006-01-02"); d != "2025-09-15" {
+		t.Errorf("targetDate = %s", d)
+	}
+
+	now := time.Date(2025, 4, 20, 0, 0, 0, 0, time.UTC) // three months in: 45%
+	for actual, want := range map[float64]string{44: rampOnTrack, 45: rampOnTrack, 45.5: rampOffTrack, 20: rampMet} {
+		if s := p.status(repoScore{StalePercent: actual}, now); s.Status != want || s.Expected != 45 || s.Plan != "platform" {
+			t.Errorf("stale %v%%: %+v, want %s", actual, s, want)
+		}
+	}
+	up := rampPlan{Repos: []string{"acme/*"}, Metric: "healthScore", Start: "2025-01-15", From: 40, StepPerMonth: 10, Target: 70}
+	if s := up.status(repoScore{HealthScore: 65}, now); s.Expected != 70 || s.Status != rampOffTrack {
+		t.Errorf("health ramp: %+v", s)
+	}
+	if s := up.status(repoScore{HealthScore: 85}, now); s.Status != rampMet {
+		t.Errorf("health ramp: %+v", s)
+	}
+
+	for name, bad := range map[string]rampPlan{
+		"no repos": {Start: "2025-01-01", StepPerMonth: 1},
+		"metric":   {Repos: p.Repos, Metric: "vibes", Start: "2025-01-01", StepPerMonth: 1},
+		"start":    {Repos: p.Repos, Start: "soon", StepPerMonth: 1},
+		"step":     {Repos: p.Repos, Start: "2025-01-01"},
+	} {
+		if err := validateRamps([]rampPlan{bad}); err == nil {
+			t.Errorf("%s: want error", name)
+		}
+	}
+}
+
+func TestRampStatusInReport(t *testing.T) {
+	now := time.Date(2025, 4, 20, 0, 0, 0, 0, time.UTC)
+	cfg := config{Ramps: []rampPlan{
+		{Name: "web", Repos: []string{"acme/web"}, Start: "2025-01-01", From: 60, StepPerMonth: 5, Target: 20},
+		{Name: "rest", Repos: []string{"acme/*"}, Start: "2025-01-01", From: 60, StepPerMonth: 10, Target: 20},
+	}}
+	repos := []repoScore{
+		{Name: "web", fullName: "acme/web", Status: "warning", StalePercent: 40},
+		{Name: "api", fullName: "acme/api", Status: "warning", StalePercent: 40},
+		{Name: "cli", fullName: "acme/cli", Error: "boom"},
+	}
+	out := assemble("acme", cfg, repos, now)
+	got := map[string]*rampStatus{}
+	for _, rs := range out.Repos {
+		got[rs.Name] = rs.Ramp
+	}
+	if got["web"] == nil || got["web"].Status != rampOnTrack || got["api"] == nil || got["api"].Status != rampOffTrack || got["cli"] != nil {
+		t.Errorf("ramps: web %+v, api %+v, cli %+v", got["web"], got["api"], got["cli"])
+	}
+	if out.Summary.OffTrack != 1 {
+		t.Errorf("summary %+v", out.Summary)
+	}
+	if g := gateRamps(nil, out.Summary, true); g == nil || g.Passed {
+		t.Errorf("gate %+v should fail with a repo off track", g)
+	}
+	if g := gateRamps(nil, out.Summary, false); g != nil {
+		t.Errorf("gate %+v without -fail-off-track", g)
+	}
+}
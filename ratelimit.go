@@ -0,0 +1,66 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log/slog"
+	"time"
+
+	ghscan "github.com/misty-step/fab-backlog/pkg/scan"
+)
+
+var rateLimitReserve = flag.Int("rate-limit-reserve", 100, "wait for the rate-limit window to reset rather than spend the last N requests of a resource (0: wait only once GitHub refuses calls)")
+
+// throttle is the rate-limit layer main installs under the retries; nil when
+// runCmd is used bare, as in tests.
+var throttle *ghscan.Throttle
+
+func validateRateLimit() error {
+	if *rateLimitReserve < 0 {
+		return fmt.Errorf("-rate-limit-reserve must not be negative")
+	}
+	return nil
+}
+
+// withThrottle wraps run in the -rate-limit-reserve throttle, logging each
+// wait so a stalled scan explains itself.
+func withThrottle(run ghscan.Runner) ghscan.Runner {
+	throttle = ghscan.NewThrottle(run, *rateLimitReserve)
+	throttle.OnEvent = func(e ghscan.Event) {
+		slog.Warn("rate limit reached; waiting for the window to reset", "repo", e.Repo, "resource", e.Resource, "wait", e.Wait.Round(time.Second))
+	}
+	return throttle.Run
+}
+
+// apiUsage is what a run cost against GitHub's rate limits.
+type apiUsage struct {
+	// Calls counts gh invocations, retries included; a paginated call may
+	// be several requests.
+	Calls         int `json:"calls"`
+	WaitedSeconds int `json:"waitedSeconds,omitempty"`
+	// CacheHits counts calls answered from -etag-cache after a 304.
+	CacheHits int `json:"cacheHits,omitempty"`
+	// RateLimits is the quota left afterwards in each resource the run drew
+	// on. It's omitted when the host doesn't report rate limits.
+	RateLimits map[string]ghscan.Bucket `json:"rateLimits,omitempty"`
+}
+
+// currentAPIUsage reports the calls made so far and the quota they left, or
+// nil without a throttle.
+func currentAPIUsage(ctx context.Context) *apiUsage {
+	if throttle == nil {
+		return nil
+	}
+	u := &apiUsage{Calls: throttle.Calls(), WaitedSeconds: int(throttle.Waited().Seconds())}
+	if etagCache != nil {
+		u.CacheHits = etagCache.Hits()
+	}
+	limits, err := throttle.Limits(ctx)
+	if err != nil {
+		slog.Debug("rate limits unavailable", "error", err)
+		return u
+	}
+	u.RateLimits = limits
+	return u
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestScanReportsAPIUsage(t *testing.T) {
+	gh := newFakeGitHub(t)
+	genFixture(gh, "acme", 3, 2, 5, time.Now())
+	prev := throttle
+	t.Cleanup(func() { throttle = prev })
+
+	var calls atomic.Int64
+	fake := runCmd
+	runCmd = withThrottle(func(ctx context.Context, bin string, args ...string) ([]byte, error) {
+		if args[0] != "api" || args[1] != "rate_limit" {
+			calls.Add(1)
+		}
+		return fake(ctx, bin, args...)
+	})
+	scan(t.Context(), selection{Orgs: []string{"acme"}}, []string{"acme/repo-00", "acme/repo-01"}, config{MinIssues: 1, StaleDays: 90})
+
+	u := currentAPIUsage(t.Context())
+	if u == nil || u.Calls == 0 || int64(u.Calls) != calls.Load() {
+		t.Fatalf("usage = %+v, want %d calls", u, calls.Load())
+	}
+	// gh issue list is a GraphQL query; nothing searched.
+	if _, searched := u.RateLimits["search"]; searched || u.RateLimits["graphql"].Remaining != 4990 {
+		t.Errorf("rate limits = %+v, want graphql and no search", u.RateLimits)
+	}
+}
+
+func TestAPIUsageWithoutThrottle(t *testing.T) {
+	prev := throttle
+	throttle = nil
+	t.Cleanup(func() { throttle = prev })
+	if u := currentAPIUsage(t.Context()); u != nil {
+		t.Errorf("usage = %+v without a throttle", u)
+	}
+}
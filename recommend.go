@@ -0,0 +1,72 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// recommend turns a repo's metrics into concrete next steps, most
+// pressing first. Staleness and labeling only come up once they cost the
+// repo its bonus. issues are the ones that can go stale, and cfg is the
+// repo's own, with any staleDays override applied.
+func recommend(rs repoScore, issues []issue, cfg config, now time.Time) []string {
+	var recs []string
+	add := func(format string, args ...any) { recs = append(recs, fmt.Sprintf(format, args...)) }
+
+	if rs.SecurityOverdueCount > 0 {
+		add("%s open longer than %d days — fix or triage them; they force critical status", plural(rs.SecurityOverdueCount, "security issue"), cfg.SecurityMaxDays)
+	}
+	if rs.StalePercent >= 30 {
+		closing, updating := staleFixes(rs.StaleCount, rs.TotalOpen)
+		add("%s not updated in %d days (%.0f%%) — close %d or update %d to get under 30%% (+15 points)", plural(rs.StaleCount, "issue"), cfg.StaleDays, rs.StalePercent, closing, updating)
+	}
+	if ancient := countIdleSince(issues, now.AddDate(-1, 0, 0)); ancient > 0 {
+		add("%s stale >1 year — close or reprioritize", plural(ancient, "issue"))
+	}
+	if rs.unlabeledPercent >= 20 {
+		add("%s unlabeled — run a labeling pass (suggest-labels can propose labels) to get under 20%% (+15 points)", plural(rs.UnlabeledCount, "issue"))
+	}
+	if n := len(rs.SLOBreaches); n > 0 {
+		add("%s — respond to or resolve the listed issues", plural(n, "SLO breach"))
+	}
+	if n := len(rs.Duplicates); n > 0 {
+		add("%s — close them in favour of the originals", plural(n, "probable duplicate"))
+	}
+	if n := len(rs.TriageQueue); n > 0 {
+		add("%s queued — review them with -plan-out and apply", plural(n, "triage action"))
+	}
+	return recs
+}
+
+// staleFixes is how many of stale issues out of total must be closed, or
+// instead updated, to bring the stale share under 30%. Updating one takes
+// it out of the stale count only; closing one lowers the total too, so more
+// are needed. It counts in tenths to stay clear of float rounding.
+func staleFixes(stale, total int) (closing, updating int) {
+	excess := 10*stale - 3*total
+	if excess < 0 {
+		return 0, 0
+	}
+	return excess/7 + 1, excess/10 + 1
+}
+
+func countIdleSince(issues []issue, cutoff time.Time) int {
+	n := 0
+	for _, is := range issues {
+		if is.UpdatedAt.Before(cutoff) {
+			n++
+		}
+	}
+	return n
+}
+
+func plural(n int, noun string) string {
+	if n == 1 {
+		return "1 " + noun
+	}
+	if strings.HasSuffix(noun, "ch") {
+		return fmt.Sprintf("%d %ses", n, noun)
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
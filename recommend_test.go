@@ -0,0 +1,78 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRecommend(t *testing.T) {
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	var issues []issue
+	for i := 1; i <= 10; i++ {
+		is := issue{Number: i, CreatedAt: now.AddDate(-2, 0, 0), UpdatedAt: now}
+		switch {
+		case i <= 2:
+			is.UpdatedAt = now.AddDate(-2, 0, 0)
+		case i <= 5:
+			is.UpdatedAt = now.AddDate(0, 0, -100)
+		}
+		if i%2 == 0 {
+			is.Labels = []label{{Name: "bug"}}
+		}
+		issues = append(issues, is)
+	}
+	cfg := config{MinIssues: 5, StaleDays: 90}
+	rs := scoreIssues("api", issues, cfg, now)
+	got := recommend(rs, issues, cfg, now)
+	want := []string{
+		"5 issues not updated in 90 days (50%) — close 3 or update 3 to get under 30% (+15 points)",
+		"2 issues stale >1 year — close or reprioritize",
+		"5 issues unlabeled — run a labeling pass",
+	}
+	if len(got) != len(want) {
+		t.Fatalf("recommendations = %q", got)
+	}
+	for i := range want {
+		if !strings.HasPrefix(got[i], want[i]) {
+			t.Errorf("[%d] = %q, want prefix %q", i, got[i], want[i])
+		}
+	}
+
+	fresh := scoreIssues("web", issues[5:], cfg, now)
+	if got := recommend(fresh, issues[5:], cfg, now); len(got) != 1 || !strings.Contains(got[0], "unlabeled") {
+		t.Errorf("fresh recommendations = %q", got)
+	}
+	for _, tc := range []struct{ stale, total, close, update int }{
+		{5, 10, 3, 3},
+		{8, 10, 8, 6},
+		{3, 10, 1, 1},
+	} {
+		if closing, updating := staleFixes(tc.stale, tc.total); closing != tc.close || updating != tc.update {
+			t.Errorf("staleFixes(%d, %d) = close %d, update %d; want %d, %d", tc.stale, tc.total, closing, updating, tc.close, tc.update)
+		}
+	}
+	if got := plural(1, "SLO breach") + "," + plural(2, "SLO breach"); got != "1 SLO breach,2 SLO breaches" {
+		t.Errorf("plural = %q", got)
+	}
+}
+
+func TestRecommendUsesRepoConfig(t *testing.T) {
+	gh := newFakeGitHub(t)
+	now := time.Now()
+	old := now.AddDate(-2, 0, 0)
+	gh.addRepo(&fakeRepo{Owner: "acme", Name: "api", Issues: []*fakeIssue{
+		{Number: 1, Title: "crash", State: "open", CreatedAt: old, UpdatedAt: old, Labels: []string{"bug"}},
+		{Number: 2, Title: "leak", State: "open", CreatedAt: old, UpdatedAt: old, Labels: []string{"bug"}},
+		{Number: 3, Title: "RFC: plugins", State: "open", CreatedAt: old, UpdatedAt: old, Labels: []string{"idea"}},
+	}, Files: map[string]string{repoConfigPath: "staleDays: 400\nstaleExcludeTitles: [\"/^RFC:/\"]\n"}})
+	cfg := config{MinIssues: 1, StaleDays: 90}
+	out := scan(t.Context(), selection{Orgs: []string{"acme"}}, []string{"acme/api"}, cfg)
+	recs := strings.Join(out.Repos[0].Recommendations, "\n")
+	if !strings.Contains(recs, "not updated in 400 days") {
+		t.Errorf("recommendations should use the repo's staleDays:\n%s", recs)
+	}
+	if !strings.Contains(recs, "2 issues stale >1 year") {
+		t.Errorf("the exempt RFC shouldn't count as stale:\n%s", recs)
+	}
+}
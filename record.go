@@ -0,0 +1,44 @@
+package main
+
+import (
+	"flag"
+	"log/slog"
+
+	ghscan "github.com/misty-step/fab-backlog/pkg/scan"
+)
+
+var (
+	recordDir = flag.String("record-dir", "", "write the raw response of every gh call to this directory, for replaying the scan later with -from-file")
+	fromFile  = flag.String("from-file", "", "answer gh calls from responses recorded with -record-dir (the directory, or its files combined into one JSON array) instead of calling GitHub")
+)
+
+// replay is the -from-file recordings; nil unless replaying.
+var replay *ghscan.Replay
+
+// withRecording wraps run so every gh call is written to -record-dir, if
+// one was asked for.
+func withRecording(run ghscan.Runner) (ghscan.Runner, error) {
+	if *recordDir == "" {
+		return run, nil
+	}
+	return ghscan.Record(*recordDir, run)
+}
+
+// withReplay answers gh calls from the -from-file recordings, passing
+// anything else to run. Nothing is throttled or cached: no call reaches
+// GitHub.
+func withReplay(run ghscan.Runner) (ghscan.Runner, error) {
+	var err error
+	if replay, err = ghscan.LoadReplay(*fromFile); err != nil {
+		return nil, err
+	}
+	return replay.Wrap(run), nil
+}
+
+// warnReplayMisses points out calls a replayed scan had no recording for;
+// the repos that needed them are reported as errors.
+func warnReplayMisses() {
+	if replay != nil && replay.Misses() > 0 {
+		slog.Warn("recordings lacked some gh responses; rerun -record-dir with the same flags", "from_file", *fromFile, "missing", replay.Misses())
+	}
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	ghscan "github.com/misty-step/fab-backlog/pkg/scan"
+)
+
+func TestReplayRescoresRecordedScan(t *testing.T) {
+	gh := newFakeGitHub(t)
+	genFixture(gh, "acme", 4, 3, 8, time.Now())
+	sel := selection{Orgs: []string{"acme"}}
+	cfg := config{MinIssues: 1, StaleDays: 90, ResponseWindowDays: 30}
+
+	dir := t.TempDir()
+	rec, err := ghscan.Record(dir, runCmd)
+	if err != nil {
+		t.Fatal(err)
+	}
+	runCmd = rec
+	repos, err := sel.resolve(t.Context())
+	if err != nil {
+		t.Fatal(err)
+	}
+	live := scan(t.Context(), sel, repos, cfg)
+
+	// Offline from here on.
+	prevReplay := replay
+	t.Cleanup(func() { replay = prevReplay })
+	*fromFile = dir
+	defer func() { *fromFile = "" }()
+	runCmd, err = withReplay(func(context.Context, string, ...string) ([]byte, error) {
+		return nil, errors.New("network access during replay")
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	replayedRepos, err := sel.resolve(t.Context())
+	if err != nil {
+		t.Fatal(err)
+	}
+	again := scan(t.Context(), sel, replayedRepos, cfg)
+	if contentHash(again) != contentHash(live) {
+		t.Error("replaying the recorded scan with the same settings should reproduce its report")
+	}
+	if replay.Misses() != 0 {
+		t.Errorf("replay missed %d calls", replay.Misses())
+	}
+
+	cfg.StaleDays = 1
+	strict := scan(t.Context(), sel, replayedRepos, cfg)
+	stale := func(out output) (n int) {
+		for _, rs := range out.Repos {
+			if rs.Error != "" {
+				t.Errorf("%s: %s", rs.Name, rs.Error)
+			}
+			n += rs.StaleCount
+		}
+		return n
+	}
+	if stale(strict) <= stale(live) {
+		t.Errorf("re-scoring with -stale-days 1 found %d stale issues, live scan %d", stale(strict), stale(live))
+	}
+}
@@ -0,0 +1,148 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"strings"
+	"time"
+)
+
+var (
+	fromLabel = flag.String("from", "", "with fix rename-label, the label to rename")
+	toLabel   = flag.String("to", "", "with fix rename-label, the new label name")
+)
+
+var errFixUsage = errors.New("usage: fab-backlog fix rename-label --from OLD --to NEW [repo...] [--dry-run]")
+
+// renameResult is one repo's part of a label migration. Strategy is
+// rename when the label can simply be renamed, merge when the new name
+// already exists and every issue is relabeled instead, and skip when the
+// repo doesn't have the label.
+type renameResult struct {
+	Repo     string        `json:"repo"`
+	Strategy string        `json:"strategy"`
+	Status   string        `json:"status"` // applied, planned, failed or skipped
+	Issues   int           `json:"issues,omitempty"`
+	Actions  []applyResult `json:"actions,omitempty"`
+	Error    string        `json:"error,omitempty"`
+}
+
+func labelPath(owner, repo, name string) string {
+	return fmt.Sprintf("repos/%s/%s/labels/%s", owner, repo, url.PathEscape(name))
+}
+
+// renameLabel renames from to to in one repo. Renaming keeps the label on
+// every issue and PR. If to already exists the two can't be renamed into
+// one, so each issue carrying from gets to added and from removed; the
+// old label itself is left in place.
+func renameLabel(ctx context.Context, owner, repo, from, to string, dryRun bool, now time.Time) renameResult {
+	full := owner + "/" + repo
+	res := renameResult{Repo: full, Strategy: "rename"}
+	failed := func(err error) renameResult {
+		res.Status, res.Error = "failed", err.Error()
+		return res
+	}
+	hasFrom, err := ghHasLabel(ctx, owner, repo, from)
+	if err != nil {
+		return failed(err)
+	}
+	if !hasFrom {
+		res.Strategy, res.Status = "skip", "skipped"
+		return res
+	}
+	conflict := false
+	if !strings.EqualFold(from, to) {
+		if conflict, err = ghHasLabel(ctx, owner, repo, to); err != nil {
+			return failed(err)
+		}
+	}
+	if !conflict {
+		args := []string{"api", "--silent", "-X", "PATCH", labelPath(owner, repo, from), "-f", "new_name=" + to}
+		r := applyResult{ID: full + ":rename-label", Status: "planned", Command: append([]string{"gh"}, args...)}
+		if !dryRun {
+			if _, err := runCmd(ctx, "gh", args...); err != nil {
+				r.Status, r.Error = "failed", err.Error()
+			} else {
+				r.Status = "applied"
+			}
+		}
+		res.Status, res.Error, res.Actions = r.Status, r.Error, []applyResult{r}
+		return res
+	}
+
+	res.Strategy = "merge"
+	type labeled struct {
+		Number      int       `json:"number"`
+		PullRequest *struct{} `json:"pull_request"`
+	}
+	items, err := ghAPIList[labeled](ctx, fmt.Sprintf("repos/%s/issues?state=all&per_page=100&labels=%s", full, url.QueryEscape(from)))
+	if err != nil {
+		return failed(err)
+	}
+	p := plan{Version: planVersion, GeneratedAt: now.UTC().Format(time.RFC3339), Org: owner}
+	for _, it := range items {
+		if it.PullRequest != nil {
+			continue
+		}
+		res.Issues++
+		for _, a := range []planAction{
+			{Kind: "addLabel", Repo: full, Number: it.Number, Label: to},
+			{Kind: "removeLabel", Repo: full, Number: it.Number, Label: from},
+		} {
+			a.ID, a.Source = actionID(a.Kind, a.Repo, a.Number, a.Label), "rename-label"
+			p.Actions = append(p.Actions, a)
+		}
+	}
+	res.Actions, _ = applyPlan(p, dryRun, runBackground, now)
+	res.Status = "applied"
+	if dryRun {
+		res.Status = "planned"
+	}
+	for _, r := range res.Actions {
+		if r.Status == "failed" {
+			res.Status = "failed"
+		}
+	}
+	return res
+}
+
+// runFix implements `fab-backlog fix rename-label`.
+func runFix(ctx context.Context, sel selection, positional []string) int {
+	if len(positional) == 0 || positional[0] != "rename-label" || *fromLabel == "" || *toLabel == "" || *fromLabel == *toLabel {
+		slog.Error("invalid fix command", "error", errFixUsage)
+		emitJSON(map[string]any{"ok": false, "error": errFixUsage.Error()})
+		return 2
+	}
+	primary := sel.primaryOrg()
+	repos := dedupeRepos(primary, positional[1:])
+	if len(repos) == 0 {
+		var err error
+		if repos, err = sel.resolve(ctx); err != nil {
+			slog.Error("failed to list repos", "orgs", sel.Orgs, "error", err)
+			emitJSON(map[string]any{"ok": false, "error": "failed to list repos: " + err.Error()})
+			return 1
+		}
+	}
+	now := time.Now()
+	ok := true
+	results := make([]renameResult, 0, len(repos))
+	for i, repo := range repos {
+		owner, name := splitRepo(primary, repo)
+		r := renameLabel(ctx, owner, name, *fromLabel, *toLabel, *dryRun, now)
+		slog.Info("label migration", "repo", r.Repo, "progress", fmt.Sprintf("%d/%d", i+1, len(repos)), "strategy", r.Strategy, "status", r.Status, "issues", r.Issues)
+		if r.Status == "failed" {
+			ok = false
+			slog.Warn("label migration failed", "repo", r.Repo, "error", r.Error)
+		}
+		results = append(results, r)
+	}
+	emitJSON(map[string]any{"ok": ok, "from": *fromLabel, "to": *toLabel, "dryRun": *dryRun, "repos": results})
+	if !ok {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestRenameLabel(t *testing.T) {
+	gh := newFakeGitHub(t)
+	now := time.Now()
+	gh.addRepo(&fakeRepo{Owner: "acme", Name: "plain", Issues: []*fakeIssue{
+		{Number: 1, State: "open", Labels: []string{"prio:high"}},
+		{Number: 2, State: "closed", Labels: []string{"prio:high", "bug"}},
+	}})
+	gh.addRepo(&fakeRepo{Owner: "acme", Name: "conflict", Labels: []string{"P1"}, Issues: []*fakeIssue{
+		{Number: 1, State: "open", Labels: []string{"prio:high"}},
+		{Number: 2, State: "closed", Labels: []string{"prio:high"}},
+		{Number: 3, State: "open", Labels: []string{"prio:high"}, IsPR: true},
+		{Number: 4, State: "open", Labels: []string{"docs"}},
+	}})
+	gh.addRepo(&fakeRepo{Owner: "acme", Name: "none"})
+
+	if r := renameLabel(t.Context(), "acme", "conflict", "prio:high", "P1", true, now); r.Status != "planned" || r.Strategy != "merge" || r.Issues != 2 || len(r.Actions) != 4 {
+		t.Fatalf("dry run = %+v", r)
+	}
+	if len(gh.writeLog()) != 0 {
+		t.Fatalf("dry run wrote %v", gh.writeLog())
+	}
+
+	if r := renameLabel(t.Context(), "acme", "plain", "prio:high", "P1", false, now); r.Status != "applied" || r.Strategy != "rename" {
+		t.Errorf("plain = %+v", r)
+	}
+	if got := gh.issue("acme/plain", 2).Labels; !slices.Equal(got, []string{"P1", "bug"}) {
+		t.Errorf("renamed labels = %v", got)
+	}
+
+	if r := renameLabel(t.Context(), "acme", "conflict", "prio:high", "P1", false, now); r.Status != "applied" || r.Strategy != "merge" || r.Issues != 2 {
+		t.Errorf("conflict = %+v", r)
+	}
+	for _, n := range []int{1, 2} {
+		if got := gh.issue("acme/conflict", n).Labels; !slices.Equal(got, []string{"P1"}) {
+			t.Errorf("#%d labels = %v", n, got)
+		}
+	}
+
+	if r := renameLabel(t.Context(), "acme", "none", "prio:high", "P1", false, now); r.Status != "skipped" {
+		t.Errorf("none = %+v", r)
+	}
+}
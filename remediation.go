@@ -0,0 +1,184 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+var remediationLog = flag.String("remediation-log", "", "path to the remediation ledger; expectations recorded by fix actions are verified on the next scan")
+
+// expectation is an outcome a fix action promised, checked by the next scan.
+type expectation struct {
+	Repo       string    `json:"repo"` // owner/name
+	Number     int       `json:"number"`
+	Kind       string    `json:"kind"` // "labelPresent", "labelAbsent" or "closed"
+	Label      string    `json:"label,omitempty"`
+	Action     string    `json:"action"`
+	RecordedAt time.Time `json:"recordedAt"`
+}
+
+type remediationReport struct {
+	Checked              int                 `json:"checked"`
+	Held                 int                 `json:"held"`
+	Reverted             int                 `json:"reverted"`
+	Unverifiable         int                 `json:"unverifiable"`
+	EffectivenessPercent float64             `json:"effectivenessPercent"`
+	RevertedActions      []revertedAction    `json:"revertedActions,omitempty"`
+	ByAction             map[string]*tallies `json:"byAction,omitempty"`
+}
+
+type tallies struct {
+	Held     int `json:"held"`
+	Reverted int `json:"reverted"`
+}
+
+type revertedAction struct {
+	expectation
+	Observed string `json:"observed"`
+}
+
+// verdict results for a single expectation.
+const (
+	verdictHeld     = "held"
+	verdictReverted = "reverted"
+	verdictUnknown  = "unknown"
+)
+
+// checkExpectation judges e against the open issues fetched for its repo.
+// truncated means the fetch hit its cap, so an absent issue proves nothing.
+func checkExpectation(e expectation, open []issue, truncated bool) (verdict, observed string) {
+	var found *issue
+	for i := range open {
+		if open[i].Number == e.Number {
+			found = &open[i]
+			break
+		}
+	}
+	if found == nil {
+		if truncated {
+			return verdictUnknown, ""
+		}
+		// Closing an issue settles label expectations too.
+		return verdictHeld, ""
+	}
+	switch e.Kind {
+	case "closed":
+		return verdictReverted, "issue is open again"
+	case "labelPresent":
+		if hasAnyLabel(*found, []string{e.Label}) {
+			return verdictHeld, ""
+		}
+		return verdictReverted, fmt.Sprintf("label %q was removed", e.Label)
+	case "labelAbsent":
+		if !hasAnyLabel(*found, []string{e.Label}) {
+			return verdictHeld, ""
+		}
+		return verdictReverted, fmt.Sprintf("label %q was re-added", e.Label)
+	}
+	return verdictUnknown, ""
+}
+
+// verifyRemediations checks every expectation whose repo's issues were
+// fetched and returns the report plus the expectations that could not be
+// checked yet. Repos scanned with -scope leaving out issues are unscored,
+// and reused ones weren't fetched; their issues aren't known, so their
+// expectations wait. Issues are checked against everything open, so one
+// that gained an ignored label or counts as code debt hasn't been closed.
+func verifyRemediations(exps []expectation, repos []repoScore) (*remediationReport, []expectation) {
+	byRepo := map[string]*repoScore{}
+	for i := range repos {
+		if repos[i].Error == "" && repos[i].Status != unscored && !repos[i].reused {
+			byRepo[strings.ToLower(repos[i].fullName)] = &repos[i]
+		}
+	}
+	rep := &remediationReport{ByAction: map[string]*tallies{}}
+	var pending []expectation
+	for _, e := range exps {
+		rs, ok := byRepo[strings.ToLower(e.Repo)]
+		if !ok {
+			pending = append(pending, e)
+			continue
+		}
+		verdict, observed := checkExpectation(e, rs.openIssues, rs.truncated)
+		if verdict == verdictUnknown {
+			rep.Unverifiable++
+			pending = append(pending, e)
+			continue
+		}
+		rep.Checked++
+		t := rep.ByAction[e.Action]
+		if t == nil {
+			t = &tallies{}
+			rep.ByAction[e.Action] = t
+		}
+		if verdict == verdictHeld {
+			rep.Held++
+			t.Held++
+		} else {
+			rep.Reverted++
+			t.Reverted++
+			rep.RevertedActions = append(rep.RevertedActions, revertedAction{expectation: e, Observed: observed})
+		}
+	}
+	if rep.Checked > 0 {
+		rep.EffectivenessPercent = float64(rep.Held) / float64(rep.Checked) * 100
+	}
+	return rep, pending
+}
+
+// verifyRemediationLog verifies the ledger at path and rewrites it with only
+// the expectations still awaiting a verdict. A missing ledger is empty.
+func verifyRemediationLog(path string, repos []repoScore) (*remediationReport, error) {
+	exps, err := readExpectations(path)
+	if err != nil {
+		return nil, err
+	}
+	rep, pending := verifyRemediations(exps, repos)
+	if err := writeExpectations(path, pending); err != nil {
+		return nil, err
+	}
+	return rep, nil
+}
+
+// recordExpectations appends the outcomes a fix action promises to the ledger.
+func recordExpectations(path string, exps []expectation) error {
+	existing, err := readExpectations(path)
+	if err != nil {
+		return err
+	}
+	return writeExpectations(path, append(existing, exps...))
+}
+
+func readExpectations(path string) ([]expectation, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read remediation log: %w", err)
+	}
+	var exps []expectation
+	if err := json.Unmarshal(data, &exps); err != nil {
+		return nil, fmt.Errorf("parse remediation log %s: %w", path, err)
+	}
+	return exps, nil
+}
+
+func writeExpectations(path string, exps []expectation) error {
+	if exps == nil {
+		exps = []expectation{}
+	}
+	data, err := json.MarshalIndent(exps, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("write remediation log: %w", err)
+	}
+	return nil
+}
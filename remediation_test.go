@@ -0,0 +1,73 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestVerifyRemediations(t *testing.T) {
+	repos := []repoScore{
+		{Name: "a", fullName: "org/a", openIssues: []issue{
+			{Number: 1, Labels: []label{{Name: "stale"}}},
+			{Number: 2},
+			{Number: 3},
+		}},
+		{Name: "b", fullName: "org/b", Error: "boom"},
+		// -scope left issues out, so none were fetched.
+		{Name: "c", fullName: "org/c", Status: unscored},
+	}
+	exps := []expectation{
+		{Repo: "org/a", Number: 1, Kind: "labelPresent", Label: "stale", Action: "stale-label"},
+		{Repo: "org/a", Number: 2, Kind: "labelPresent", Label: "stale", Action: "stale-label"},
+		{Repo: "org/a", Number: 3, Kind: "closed", Action: "close"},
+		{Repo: "org/a", Number: 4, Kind: "closed", Action: "close"},
+		{Repo: "org/b", Number: 1, Kind: "closed", Action: "close"},
+		{Repo: "org/c", Number: 1, Kind: "labelPresent", Label: "stale", Action: "stale-label"},
+	}
+	rep, pending := verifyRemediations(exps, repos)
+	if rep.Checked != 4 || rep.Held != 2 || rep.Reverted != 2 || rep.EffectivenessPercent != 50 {
+		t.Errorf("report = %+v, want 4 checked, 2 held, 2 reverted", rep)
+	}
+	if len(rep.RevertedActions) != 2 || rep.RevertedActions[0].Number != 2 || rep.RevertedActions[1].Number != 3 {
+		t.Errorf("reverted = %+v, want #2 and #3", rep.RevertedActions)
+	}
+	if len(pending) != 2 || pending[0].Repo != "org/b" || pending[1].Repo != "org/c" {
+		t.Errorf("pending = %+v, want the errored and unscored repos' expectations", pending)
+	}
+}
+
+func TestVerifyRemediationsSeesIgnoredIssues(t *testing.T) {
+	gh := newFakeGitHub(t)
+	now := time.Now()
+	gh.addRepo(&fakeRepo{Owner: "acme", Name: "api", Issues: []*fakeIssue{
+		{Number: 1, State: "open", CreatedAt: now, UpdatedAt: now, Labels: []string{"upstream"}},
+		{Number: 2, State: "open", CreatedAt: now, UpdatedAt: now, Labels: []string{"bug"}},
+	}})
+	cfg := config{MinIssues: 1, StaleDays: 90, IgnoreLabels: []string{"upstream"}}
+	out := scan(t.Context(), selection{Orgs: []string{"acme"}}, []string{"acme/api"}, cfg)
+	exps := []expectation{{Repo: "acme/api", Number: 1, Kind: "closed", Action: "close"}}
+	rep, _ := verifyRemediations(exps, out.Repos)
+	if rep.Reverted != 1 || rep.Held != 0 {
+		t.Errorf("report = %+v: #1 is ignored, not closed", rep)
+	}
+}
+
+func TestRemediationLogRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ledger.json")
+	e := expectation{Repo: "org/a", Number: 9, Kind: "closed", Action: "close", RecordedAt: time.Now().UTC()}
+	if err := recordExpectations(path, []expectation{e}); err != nil {
+		t.Fatal(err)
+	}
+	rep, err := verifyRemediationLog(path, []repoScore{{fullName: "org/a"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rep.Held != 1 {
+		t.Errorf("closed issue absent from open list should hold, got %+v", rep)
+	}
+	left, err := readExpectations(path)
+	if err != nil || len(left) != 0 {
+		t.Errorf("ledger should be cleared, got %v (err %v)", left, err)
+	}
+}
@@ -0,0 +1,135 @@
+package main
+
+import (
+	"bytes"
+	htmltemplate "html/template"
+	"text/template"
+
+	"github.com/misty-step/fab-backlog/pkg/report"
+)
+
+// The Markdown and HTML renderings carry the same content: the summary, the
+// repo table worst first, failed repos, and any org-wide lists in the report.
+// Numbers and dates go through the num, decimal and date helpers, which
+// format for the config's locale at render time.
+
+var renderFuncs = map[string]any{
+	"summaryLine": summaryLine,
+	"cell":        report.MarkdownCell,
+	"minutes":     formatMinutes,
+	"num":         report.ISO.Integer,
+	"decimal":     report.ISO.Float,
+	"date":        report.ISO.Timestamp,
+}
+
+var markdownTmpl = template.Must(template.New("md").Funcs(renderFuncs).Parse(`# Backlog health: {{.Org}}
+
+{{summaryLine .}}{{if .GeneratedAt}} — generated {{date .GeneratedAt}}{{end}}
+{{with .Gate}}
+**Gate {{if .Passed}}passed{{else}}failed{{end}}{{if .Observed}} (observe mode){{end}}**{{range .Reasons}}
+- {{.}}{{end}}
+{{end}}
+| Repo | Score | Status | Open | Stale | Unlabeled |{{if .Config.TriageEffort}} Triage |{{end}}
+|------|------:|--------|-----:|------:|----------:|{{if .Config.TriageEffort}}-------:|{{end}}
+{{range .Repos}}{{if and (not .Error) (ne .Status "unscored")}}| {{cell .Name}} | {{num .HealthScore}} | {{.Status}} | {{num .TotalOpen}} | {{num .StaleCount}} | {{num .UnlabeledCount}} |{{if $.Config.TriageEffort}} {{with .TriageEffort}}{{minutes .Minutes}}{{end}} |{{end}}
+{{end}}{{end}}{{$failed := false}}{{range .Repos}}{{if .Error}}{{if not $failed}}{{$failed = true}}
+## Failed to scan
+{{end}}
+- {{.Name}}: {{.Error}}{{end}}{{end}}{{if $failed}}
+{{end}}{{with .TeamRollups}}
+## Teams
+
+| Team | Repos | Mean score | Healthy | Warning | Critical |
+|------|------:|-----------:|--------:|--------:|---------:|
+{{range .}}| {{cell .Name}} | {{num (len .Repos)}} | {{decimal .HealthScore 1}} | {{num .Healthy}} | {{num .Warning}} | {{num .Critical}} |
+{{end}}{{end}}{{with .Ownership}}
+## Ownership
+
+| Owner | Repos | Mean score | Healthy | Warning | Critical |
+|-------|------:|-----------:|--------:|--------:|---------:|
+{{range .}}| {{cell .Owner}} | {{num (len .Repos)}} | {{decimal .HealthScore 1}} | {{num .Healthy}} | {{num .Warning}} | {{num .Critical}} |
+{{end}}{{end}}{{with .Groups}}
+## Groups
+
+| Group | Repos | Mean score | Healthy | Warning | Critical | Gate |
+|-------|------:|-----------:|--------:|--------:|---------:|------|
+{{range .}}| {{cell .Name}} | {{num (len .Repos)}} | {{decimal .HealthScore 1}} | {{num .Healthy}} | {{num .Warning}} | {{num .Critical}} | {{with .Gate}}{{if .Passed}}passed{{else}}failed{{end}}{{end}} |
+{{end}}{{end}}{{with .SecurityBacklog}}
+## Overdue security issues
+{{range .}}
+- {{.Repo}}#{{.Number}} {{.Title}} ({{num .AgeDays}} days){{end}}
+{{end}}{{with .MostWanted}}
+## Most wanted
+{{range .}}
+- {{.Repo}}#{{.Number}} {{.Title}} (👍 {{num .ThumbsUp}}){{end}}
+{{end}}{{with .HallOfShame}}
+## Hall of shame
+{{range .}}
+- [{{.Repo}}#{{.Number}}]({{.URL}}) {{.Title}} ({{decimal .AgeYears 1}} years){{with .Owners}}, owned by {{range $i, $o := .}}{{if $i}}, {{end}}{{$o}}{{end}}{{end}}{{end}}
+{{end}}`))
+
+var htmlTmpl = htmltemplate.Must(htmltemplate.New("html").Funcs(renderFuncs).Parse(`<!DOCTYPE html>
+<html><head><meta charset="utf-8"><title>Backlog health: {{.Org}}</title></head>
+<body style="font-family: sans-serif">
+<h1>Backlog health: {{.Org}}</h1>
+<p>{{summaryLine .}}{{if .GeneratedAt}} — generated {{date .GeneratedAt}}{{end}}</p>
+{{with .Gate}}<p><strong>Gate {{if .Passed}}passed{{else}}failed{{end}}{{if .Observed}} (observe mode){{end}}</strong></p>{{with .Reasons}}<ul>{{range .}}<li>{{.}}</li>{{end}}</ul>{{end}}
+{{end}}<table border="1" cellpadding="4" cellspacing="0">
+<tr><th>Repo</th><th>Score</th><th>Status</th><th>Open</th><th>Stale</th><th>Unlabeled</th>{{if .Config.TriageEffort}}<th>Triage</th>{{end}}</tr>
+{{range .Repos}}{{if and (not .Error) (ne .Status "unscored")}}<tr><td>{{.Name}}</td><td>{{num .HealthScore}}</td><td>{{.Status}}</td><td>{{num .TotalOpen}}</td><td>{{num .StaleCount}}</td><td>{{num .UnlabeledCount}}</td>{{if $.Config.TriageEffort}}<td>{{with .TriageEffort}}{{minutes .Minutes}}{{end}}</td>{{end}}</tr>
+{{end}}{{end}}</table>
+{{$failed := false}}{{range .Repos}}{{if .Error}}{{if not $failed}}{{$failed = true}}<h2>Failed to scan</h2>
+<ul>{{end}}<li>{{.Name}}: {{.Error}}</li>{{end}}{{end}}{{if $failed}}</ul>
+{{end}}{{with .TeamRollups}}<h2>Teams</h2>
+<table border="1" cellpadding="4" cellspacing="0">
+<tr><th>Team</th><th>Repos</th><th>Mean score</th><th>Healthy</th><th>Warning</th><th>Critical</th></tr>
+{{range .}}<tr><td>{{.Name}}</td><td>{{num (len .Repos)}}</td><td>{{decimal .HealthScore 1}}</td><td>{{num .Healthy}}</td><td>{{num .Warning}}</td><td>{{num .Critical}}</td></tr>
+{{end}}</table>
+{{end}}{{with .Ownership}}<h2>Ownership</h2>
+<table border="1" cellpadding="4" cellspacing="0">
+<tr><th>Owner</th><th>Repos</th><th>Mean score</th><th>Healthy</th><th>Warning</th><th>Critical</th></tr>
+{{range .}}<tr><td>{{.Owner}}</td><td>{{num (len .Repos)}}</td><td>{{decimal .HealthScore 1}}</td><td>{{num .Healthy}}</td><td>{{num .Warning}}</td><td>{{num .Critical}}</td></tr>
+{{end}}</table>
+{{end}}{{with .Groups}}<h2>Groups</h2>
+<table border="1" cellpadding="4" cellspacing="0">
+<tr><th>Group</th><th>Repos</th><th>Mean score</th><th>Healthy</th><th>Warning</th><th>Critical</th><th>Gate</th></tr>
+{{range .}}<tr><td>{{.Name}}</td><td>{{num (len .Repos)}}</td><td>{{decimal .HealthScore 1}}</td><td>{{num .Healthy}}</td><td>{{num .Warning}}</td><td>{{num .Critical}}</td><td>{{with .Gate}}{{if .Passed}}passed{{else}}failed{{end}}{{end}}</td></tr>
+{{end}}</table>
+{{end}}{{with .SecurityBacklog}}<h2>Overdue security issues</h2>
+<ul>{{range .}}<li>{{.Repo}}#{{.Number}} {{.Title}} ({{num .AgeDays}} days)</li>{{end}}</ul>
+{{end}}{{with .MostWanted}}<h2>Most wanted</h2>
+<ul>{{range .}}<li>{{.Repo}}#{{.Number}} {{.Title}} (👍 {{num .ThumbsUp}})</li>{{end}}</ul>
+{{end}}{{with .HallOfShame}}<h2>Hall of shame</h2>
+<ul>{{range .}}<li><a href="{{.URL}}">{{.Repo}}#{{.Number}}</a> {{.Title}} ({{decimal .AgeYears 1}} years){{with .Owners}}, owned by {{range $i, $o := .}}{{if $i}}, {{end}}{{$o}}{{end}}{{end}}</li>{{end}}</ul>
+{{end}}</body></html>
+`))
+
+func renderMarkdown(out output) (string, error) {
+	f, err := report.Lookup(out.Config.Locale)
+	if err != nil {
+		return "", err
+	}
+	t, err := markdownTmpl.Clone()
+	if err != nil {
+		return "", err
+	}
+	var b bytes.Buffer
+	err = t.Funcs(f.Funcs()).Execute(&b, out)
+	return b.String(), err
+}
+
+func renderHTML(out output) (string, error) {
+	f, err := report.Lookup(out.Config.Locale)
+	if err != nil {
+		return "", err
+	}
+	// The shared template is only ever cloned, never executed, so every
+	// render can bind its own locale.
+	t, err := htmlTmpl.Clone()
+	if err != nil {
+		return "", err
+	}
+	var b bytes.Buffer
+	err = t.Funcs(f.Funcs()).Execute(&b, out)
+	return b.String(), err
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRenderMarkdown(t *testing.T) {
+	out := sampleReport()
+	out.Gate = &gateResult{Passed: false, Reasons: []string{"1 repos critical (max 0)"}}
+	out.HallOfShame = []shameIssue{{Repo: "misty-step/meh", Number: 3, Title: "ancient", URL: "https://github.com/misty-step/meh/issues/3", AgeYears: 4.2}}
+	md, err := renderMarkdown(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"# Backlog health: misty-step",
+		"3 repos: 1 healthy, 1 warning, 1 critical (1 failed to scan)",
+		"**Gate failed**\n- 1 repos critical (max 0)",
+		"| neglected | 35 | critical |",
+		"## Failed to scan\n\n- broken: gh: HTTP 500",
+		"- [misty-step/meh#3](https://github.com/misty-step/meh/issues/3) ancient (4.2 years)",
+	} {
+		if !strings.Contains(md, want) {
+			t.Errorf("markdown missing %q:\n%s", want, md)
+		}
+	}
+	if strings.Contains(md, "Most wanted") {
+		t.Error("empty sections should be omitted")
+	}
+}
+
+func TestRenderHTMLEscapes(t *testing.T) {
+	out := sampleReport()
+	out.Repos[0].Name = "<script>"
+	html, err := renderHTML(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(html, "<script>") || !strings.Contains(html, "&lt;script&gt;") {
+		t.Errorf("repo name not escaped:\n%s", html)
+	}
+	if !strings.Contains(html, "<li>broken: gh: HTTP 500</li>") {
+		t.Errorf("failed repos missing:\n%s", html)
+	}
+}
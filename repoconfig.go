@@ -0,0 +1,159 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	ghscan "github.com/misty-step/fab-backlog/pkg/scan"
+)
+
+// repoConfigPath is where a repo keeps its own overrides of the scan
+// config, so monorepos and archive-ish repos can ask for different rules
+// without a change to the central config.
+const repoConfigPath = ".github/fab-backlog.yml"
+
+// repoOverrides is the in-repo config. Zero values leave the central
+// config alone.
+type repoOverrides struct {
+	StaleDays          int      `json:"staleDays,omitempty"`
+	IgnoreLabels       []string `json:"ignoreLabels,omitempty"`
+	StaleExcludeTitles []string `json:"staleExcludeTitles,omitempty"`
+	OptOut             bool     `json:"optOut,omitempty"`
+}
+
+// apply returns cfg with o merged in. Ignored labels and excluded titles
+// add to the central ones rather than replacing them.
+func (o *repoOverrides) apply(cfg config) config {
+	if o == nil {
+		return cfg
+	}
+	if o.StaleDays > 0 {
+		cfg.StaleDays = o.StaleDays
+	}
+	if len(o.IgnoreLabels) > 0 {
+		cfg.IgnoreLabels = append(append([]string(nil), cfg.IgnoreLabels...), o.IgnoreLabels...)
+	}
+	if len(o.StaleExcludeTitles) > 0 {
+		cfg.StaleExcludeTitles = append(append([]string(nil), cfg.StaleExcludeTitles...), o.StaleExcludeTitles...)
+	}
+	return cfg
+}
+
+// ghRepoOverrides fetches and parses a repo's config file. A repo without
+// one has no overrides.
+func ghRepoOverrides(ctx context.Context, owner, repo string) (*repoOverrides, error) {
+	data, err := client.RepoFile(ctx, owner, repo, repoConfigPath)
+	if err != nil {
+		if errors.Is(err, ghscan.ErrNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return parseRepoOverrides(string(data))
+}
+
+// parseRepoOverrides reads the small YAML subset the file needs: top-level
+// "key: value" pairs, with lists either inline ([a, "b c"]) or as "- item"
+// lines, and # comments.
+func parseRepoOverrides(src string) (*repoOverrides, error) {
+	o := &repoOverrides{}
+	var list *[]string // the block list being filled, if any
+	for i, line := range strings.Split(src, "\n") {
+		line = stripYAMLComment(line)
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		fail := func(format string, args ...any) error {
+			return fmt.Errorf("%s line %d: %s", repoConfigPath, i+1, fmt.Sprintf(format, args...))
+		}
+		if item, ok := strings.CutPrefix(strings.TrimSpace(line), "- "); ok {
+			if list == nil {
+				return nil, fail("list item outside a list")
+			}
+			*list = append(*list, yamlScalar(item))
+			continue
+		}
+		list = nil
+		key, value, ok := strings.Cut(line, ":")
+		if !ok || line[0] == ' ' || line[0] == '\t' {
+			return nil, fail("want a top-level key: value")
+		}
+		value = strings.TrimSpace(value)
+		switch strings.TrimSpace(key) {
+		case "staleDays":
+			n, err := strconv.Atoi(value)
+			if err != nil || n <= 0 {
+				return nil, fail("staleDays must be a positive integer")
+			}
+			o.StaleDays = n
+		case "ignoreLabels":
+			list = yamlList(value, &o.IgnoreLabels)
+		case "staleExcludeTitles":
+			list = yamlList(value, &o.StaleExcludeTitles)
+		case "optOut":
+			b, err := strconv.ParseBool(value)
+			if err != nil {
+				return nil, fail("optOut must be true or false")
+			}
+			o.OptOut = b
+		default:
+			return nil, fail("unknown key %q", strings.TrimSpace(key))
+		}
+	}
+	for _, p := range o.IgnoreLabels {
+		if err := validatePattern(p); err != nil {
+			return nil, fmt.Errorf("%s: %w", repoConfigPath, err)
+		}
+	}
+	if _, err := newTitleMatcher(o.StaleExcludeTitles); err != nil {
+		return nil, fmt.Errorf("%s: %w", repoConfigPath, err)
+	}
+	return o, nil
+}
+
+// yamlList adds an inline list or single value to dst. An empty value
+// starts a block list, which is returned for the "- item" lines to fill.
+func yamlList(value string, dst *[]string) *[]string {
+	switch {
+	case value == "":
+		return dst
+	case strings.HasPrefix(value, "[") && strings.HasSuffix(value, "]"):
+		for _, item := range strings.Split(value[1:len(value)-1], ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				*dst = append(*dst, yamlScalar(item))
+			}
+		}
+	default:
+		*dst = append(*dst, yamlScalar(value))
+	}
+	return nil
+}
+
+// stripYAMLComment drops a # comment that isn't inside quotes.
+func stripYAMLComment(line string) string {
+	var quote rune
+	for i, r := range line {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '"' || r == '\'':
+			quote = r
+		case r == '#' && (i == 0 || line[i-1] == ' ' || line[i-1] == '\t'):
+			return strings.TrimRight(line[:i], " \t")
+		}
+	}
+	return strings.TrimRight(line, " \t\r")
+}
+
+func yamlScalar(s string) string {
+	s = strings.TrimSpace(s)
+	if len(s) >= 2 && (s[0] == '"' || s[0] == '\'') && s[len(s)-1] == s[0] {
+		return s[1 : len(s)-1]
+	}
+	return s
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestParseRepoOverrides(t *testing.T) {
+	o, err := parseRepoOverrides(`# archive-ish: only flag truly ancient issues
+staleDays: 365
+ignoreLabels:
+  - "on hold"
+  - upstream # tracked elsewhere
+optOut: false
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if o.StaleDays != 365 || !slices.Equal(o.IgnoreLabels, []string{"on hold", "upstream"}) || o.OptOut {
+		t.Errorf("got %+v", o)
+	}
+	if o, err := parseRepoOverrides("ignoreLabels: [wontfix, 'needs design']\noptOut: true\n"); err != nil || !o.OptOut || !slices.Equal(o.IgnoreLabels, []string{"wontfix", "needs design"}) {
+		t.Errorf("inline list: %+v, %v", o, err)
+	}
+	for _, bad := range []string{"staleDays: soon", "stale: 3", "  - orphan", "ignoreLabels: [/(/]"} {
+		if _, err := parseRepoOverrides(bad); err == nil {
+			t.Errorf("%q: want error", bad)
+		}
+	}
+	cfg := (&repoOverrides{StaleDays: 30, IgnoreLabels: []string{"upstream"}}).apply(config{StaleDays: 90, IgnoreLabels: []string{"wontfix"}})
+	if cfg.StaleDays != 30 || !slices.Equal(cfg.IgnoreLabels, []string{"wontfix", "upstream"}) {
+		t.Errorf("applied = %+v", cfg)
+	}
+}
+
+func TestScanHonoursRepoOverrides(t *testing.T) {
+	gh := newFakeGitHub(t)
+	now := time.Now()
+	old := now.AddDate(0, 0, -120)
+	issues := func() []*fakeIssue {
+		return []*fakeIssue{
+			{Number: 1, State: "open", CreatedAt: old, UpdatedAt: old, Labels: []string{"bug"}},
+			{Number: 2, State: "open", CreatedAt: old, UpdatedAt: old, Labels: []string{"upstream"}},
+			{Number: 3, State: "open", CreatedAt: old, UpdatedAt: now, Labels: []string{"bug"}},
+		}
+	}
+	gh.addRepo(&fakeRepo{Owner: "acme", Name: "plain", Issues: issues()})
+	gh.addRepo(&fakeRepo{Owner: "acme", Name: "slow", Issues: issues(), Files: map[string]string{repoConfigPath: "staleDays: 180\nignoreLabels: [upstream]\n"}})
+	gh.addRepo(&fakeRepo{Owner: "acme", Name: "gone", Issues: issues(), Files: map[string]string{repoConfigPath: "optOut: true\n"}})
+
+	out := scan(t.Context(), selection{Orgs: []string{"acme"}}, []string{"plain", "slow", "gone"}, config{MinIssues: 1, StaleDays: 90})
+	byName := map[string]repoScore{}
+	for _, rs := range out.Repos {
+		byName[rs.Name] = rs
+	}
+	if _, ok := byName["gone"]; ok || len(out.Repos) != 2 {
+		t.Errorf("opted-out repo reported: %+v", out.Repos)
+	}
+	if rs := byName["plain"]; rs.TotalOpen != 3 || rs.StaleCount != 2 || rs.Overrides != nil {
+		t.Errorf("plain = %+v", rs)
+	}
+	if rs := byName["slow"]; rs.TotalOpen != 2 || rs.StaleCount != 0 || rs.Overrides == nil {
+		t.Errorf("slow = %+v", rs)
+	}
+}
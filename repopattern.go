@@ -0,0 +1,47 @@
+package main
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// repoPattern is an owner/name glob, such as acme/api-*, matched
+// case-insensitively against a repo's full name.
+type repoPattern string
+
+func (p repoPattern) validate() error {
+	if !strings.Contains(string(p), "/") {
+		return fmt.Errorf("repo %q must be owner/name", string(p))
+	}
+	if _, err := path.Match(string(p), ""); err != nil {
+		return fmt.Errorf("bad pattern %q", string(p))
+	}
+	return nil
+}
+
+func (p repoPattern) matches(full string) bool {
+	ok, _ := path.Match(strings.ToLower(string(p)), strings.ToLower(full))
+	return ok
+}
+
+// repoPatterns matches a repo when any of its patterns does.
+type repoPatterns []string
+
+func (ps repoPatterns) validate() error {
+	for _, p := range ps {
+		if err := repoPattern(p).validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (ps repoPatterns) matches(full string) bool {
+	for _, p := range ps {
+		if repoPattern(p).matches(full) {
+			return true
+		}
+	}
+	return false
+}
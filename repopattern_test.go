@@ -0,0 +1,20 @@
+package main
+
+import "testing"
+
+func TestRepoPatterns(t *testing.T) {
+	ps := repoPatterns{"acme/api-*", "Globex/Billing"}
+	if err := ps.validate(); err != nil {
+		t.Fatal(err)
+	}
+	for full, want := range map[string]bool{"acme/api-gateway": true, "ACME/API-x": true, "globex/billing": true, "acme/web": false, "other/api-x": false} {
+		if got := ps.matches(full); got != want {
+			t.Errorf("matches(%q) = %v, want %v", full, got, want)
+		}
+	}
+	for _, bad := range []repoPattern{"", "web", "acme/["} {
+		if err := bad.validate(); err == nil {
+			t.Errorf("%q should be rejected", bad)
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"time"
+)
+
+var responseWindowDays = flag.Int("response-window-days", 0, "measure median first-response time over issues created in the last N days (0 disables; --deep uses 90)")
+
+// defaultResponseWindowDays applies when --deep enables the metric without
+// an explicit window.
+const defaultResponseWindowDays = 90
+
+// responseMetrics summarises how quickly maintainers first reply to issues
+// filed inside the window, open or closed.
+type responseMetrics struct {
+	WindowDays  int `json:"windowDays"`
+	Sampled     int `json:"sampled"`
+	Responded   int `json:"responded"`
+	Unresponded int `json:"unresponded"`
+	// Excused issues are still waiting, but on assignees who are all out
+	// of office; they aren't counted as unresponded.
+	Excused     int `json:"excused,omitempty"`
+	MedianHours int `json:"medianHours"`
+}
+
+func (c config) responseWindow() int {
+	if c.ResponseWindowDays > 0 {
+		return c.ResponseWindowDays
+	}
+	if c.Deep {
+		return defaultResponseWindowDays
+	}
+	return 0
+}
+
+// recentIssueFields are what first-response measurements need.
+const recentIssueFields = "number,createdAt,author,comments"
+
+// ghListRecentIssues fetches issues in any state created on or after since,
+// with the given gh fields.
+func ghListRecentIssues(ctx context.Context, owner, repo string, since time.Time, limit int, fields string) ([]issue, error) {
+	args := []string{"issue", "list", "--repo", owner + "/" + repo, "--state", "all",
+		"--search", "created:>=" + since.Format("2006-01-02"),
+		"--json", fields, "--limit", strconv.Itoa(limit)}
+	stdout, err := runCmd(ctx, "gh", args...)
+	if err != nil {
+		return nil, err
+	}
+	var issues []issue
+	if err := json.Unmarshal(stdout, &issues); err != nil {
+		return nil, fmt.Errorf("parse gh issue list json: %w", err)
+	}
+	return issues, nil
+}
+
+func addResponseMetrics(ctx context.Context, score *repoScore, owner, repo string, cfg config, limit int, now time.Time) {
+	window := cfg.responseWindow()
+	if window <= 0 {
+		return
+	}
+	// Assignees are only requested when there are absences to check them
+	// against.
+	fields := recentIssueFields
+	if len(cfg.away) > 0 {
+		fields += ",assignees"
+	}
+	recent, err := ghListRecentIssues(ctx, owner, repo, now.AddDate(0, 0, -window), limit, fields)
+	if err != nil {
+		slog.Warn("first-response metric unavailable", "repo", repo, "error", err)
+		return
+	}
+	score.FirstResponse = computeResponseMetrics(recent, window, cfg.away, now)
+}
+
+// computeResponseMetrics measures each wait net of the time everyone the
+// issue is assigned to was out of office.
+func computeResponseMetrics(issues []issue, windowDays int, away awaySchedule, now time.Time) *responseMetrics {
+	rm := &responseMetrics{WindowDays: windowDays, Sampled: len(issues)}
+	var hours []int
+	for _, is := range issues {
+		at := firstResponseAt(is)
+		if at.IsZero() {
+			if away.allAway(is.Assignees, now) {
+				rm.Excused++
+			} else {
+				rm.Unresponded++
+			}
+			continue
+		}
+		rm.Responded++
+		hours = append(hours, int((at.Sub(is.CreatedAt) - away.awayDuring(is.Assignees, is.CreatedAt, at)).Hours()))
+	}
+	rm.MedianHours = median(hours)
+	return rm
+}
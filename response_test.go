@@ -0,0 +1,26 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestComputeResponseMetrics(t *testing.T) {
+	t0 := time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC)
+	reply := func(after time.Duration) []comment {
+		return []comment{{Author: actor{Login: "maint"}, AuthorAssociation: "MEMBER", CreatedAt: t0.Add(after)}}
+	}
+	issues := []issue{
+		{Number: 1, CreatedAt: t0, Comments: reply(2 * time.Hour)},
+		{Number: 2, CreatedAt: t0, Comments: reply(10 * time.Hour)},
+		{Number: 3, CreatedAt: t0, Comments: reply(48 * time.Hour)},
+		{Number: 4, CreatedAt: t0, Comments: []comment{{Author: actor{Login: "user"}, AuthorAssociation: "NONE", CreatedAt: t0}}},
+	}
+	rm := computeResponseMetrics(issues, 30, nil, t0)
+	if rm.Sampled != 4 || rm.Responded != 3 || rm.Unresponded != 1 || rm.MedianHours != 10 {
+		t.Errorf("got %+v, want 4 sampled, 3 responded, median 10h", rm)
+	}
+	if (config{Deep: true}).responseWindow() != defaultResponseWindowDays || (config{}).responseWindow() != 0 {
+		t.Error("deep should enable the default window; plain scans leave it off")
+	}
+}
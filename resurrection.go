@@ -0,0 +1,76 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Resurrections worth a recommendation: at least this many, making up at
+// least this share of the issues that were stale last run.
+const (
+	minResurrections    = 3
+	resurrectionRateCap = 0.25
+)
+
+// resurrections counts issues that were stale at the previous run and have
+// seen activity since. Many of them suggest staleDays is too short: issues
+// are being written off while people still care about them.
+type resurrections struct {
+	Count int `json:"count"`
+	// Rate is Count as a share of the issues stale at the previous run.
+	Rate   float64 `json:"rate"`
+	Issues []int   `json:"issues"`
+}
+
+// staleNumbers returns the numbers of the issues stale as of now, the set
+// the next run checks for resurrections.
+func staleNumbers(issues []issue, staleDays int, now time.Time) []int {
+	var out []int
+	for _, is := range issues {
+		if IsStale(is.UpdatedAt, staleDays, now) {
+			out = append(out, is.Number)
+		}
+	}
+	slices.Sort(out)
+	return out
+}
+
+// markResurrections compares each freshly scanned repo's issues with the
+// stale set h kept from the previous run. An issue stale then and active
+// now was resurrected; one closed in between wasn't. Issues carrying
+// staleLabel are left out, since applying it bumps updatedAt by itself.
+func markResurrections(repos []repoScore, h *history, staleLabel string) {
+	for i := range repos {
+		rs := &repos[i]
+		prev := h.Repos[strings.ToLower(rs.fullName)]
+		if rs.reused || rs.Error != "" || prev == nil || len(prev.Stale) == 0 {
+			continue
+		}
+		stale := map[int]bool{}
+		for _, n := range rs.staleIssues {
+			stale[n] = true
+		}
+		var back []int
+		for _, is := range rs.issues {
+			if stale[is.Number] || !slices.Contains(prev.Stale, is.Number) {
+				continue
+			}
+			if staleLabel != "" && hasAnyLabel(is, []string{staleLabel}) {
+				continue
+			}
+			back = append(back, is.Number)
+		}
+		if len(back) == 0 {
+			continue
+		}
+		slices.Sort(back)
+		rate := math.Round(float64(len(back))/float64(len(prev.Stale))*100) / 100
+		rs.Resurrections = &resurrections{Count: len(back), Rate: rate, Issues: back}
+		if len(back) >= minResurrections && rate >= resurrectionRateCap {
+			rs.Recommendations = append(rs.Recommendations, fmt.Sprintf("%d%% of last run's stale issues saw activity again — staleDays may be too short", int(rate*100)))
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMarkResurrections(t *testing.T) {
+	now := time.Now()
+	active, idle := now.AddDate(0, 0, -2), now.AddDate(0, 0, -200)
+	issues := []issue{
+		{Number: 1, UpdatedAt: active},
+		{Number: 2, UpdatedAt: active},
+		{Number: 3, UpdatedAt: active, Labels: []label{{Name: "stale"}}},
+		{Number: 4, UpdatedAt: idle},
+		{Number: 9, UpdatedAt: active}, // new since last run
+	}
+	// 5 was stale last run and has been closed since.
+	h := &history{Repos: map[string]*repoHistory{"acme/api": {Status: "warning", Stale: []int{1, 2, 3, 4, 5}}}}
+	repos := []repoScore{{Name: "api", fullName: "acme/api", Status: "warning", issues: issues, staleIssues: staleNumbers(issues, 90, now)}}
+
+	markResurrections(repos, h, "stale")
+	r := repos[0].Resurrections
+	if r == nil || r.Count != 2 || r.Rate != 0.4 || !slices.Equal(r.Issues, []int{1, 2}) {
+		t.Fatalf("resurrections = %+v", r)
+	}
+	if len(repos[0].Recommendations) != 0 {
+		t.Errorf("two resurrections shouldn't trigger a recommendation: %v", repos[0].Recommendations)
+	}
+
+	// Without the stale label filter, #3 counts too, making it 3 of 5.
+	repos[0].Resurrections = nil
+	markResurrections(repos, h, "")
+	if r := repos[0].Resurrections; r == nil || r.Count != 3 {
+		t.Fatalf("resurrections = %+v", r)
+	}
+	if len(repos[0].Recommendations) != 1 || !strings.Contains(repos[0].Recommendations[0], "60% of last run's stale issues") {
+		t.Errorf("recommendations = %v", repos[0].Recommendations)
+	}
+	if s := summarize(repos); s.Resurrected != 3 {
+		t.Errorf("summary resurrected = %d", s.Resurrected)
+	}
+}
+
+func TestHistoryKeepsStaleIssues(t *testing.T) {
+	h := &history{Repos: map[string]*repoHistory{}}
+	now := time.Now()
+	h.record([]repoScore{{fullName: "acme/api", Status: "warning", staleIssues: []int{4, 7}}}, 0, now)
+	if got := h.Repos["acme/api"].Stale; !slices.Equal(got, []int{4, 7}) {
+		t.Fatalf("stale = %v", got)
+	}
+	// A reused score has no issues to measure, so the old set carries over.
+	h.record([]repoScore{{fullName: "acme/api", Status: "warning", reused: true}}, 0, now)
+	if got := h.Repos["acme/api"].Stale; !slices.Equal(got, []int{4, 7}) {
+		t.Errorf("stale after a reused run = %v", got)
+	}
+}
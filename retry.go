@@ -0,0 +1,39 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log/slog"
+	"time"
+
+	ghscan "github.com/misty-step/fab-backlog/pkg/scan"
+)
+
+var (
+	retries      = flag.Int("retries", 2, "retry read-only gh calls this many times after a transient failure (server error, dropped connection, -call-timeout)")
+	retryBackoff = flag.Duration("retry-backoff", time.Second, "ceiling of the first jittered wait before a retry; doubles per retry up to 30s")
+	callTimeout  = flag.Duration("call-timeout", 5*time.Minute, "give up on a single gh call after this long (0: no limit)")
+)
+
+const maxRetryBackoff = 30 * time.Second
+
+func validateRetry() error {
+	if *retries < 0 || *retryBackoff < 0 || *callTimeout < 0 {
+		return fmt.Errorf("-retries, -retry-backoff and -call-timeout must not be negative")
+	}
+	return nil
+}
+
+// withRetries wraps run in the -retries/-call-timeout policy, logging each
+// retry, and the repo it was for, so flaky runs are visible.
+func withRetries(run ghscan.Runner) ghscan.Runner {
+	return ghscan.Retry(run, ghscan.RetryPolicy{
+		Retries:    *retries,
+		Backoff:    *retryBackoff,
+		MaxBackoff: maxRetryBackoff,
+		Timeout:    *callTimeout,
+		OnEvent: func(e ghscan.Event) {
+			slog.Warn("gh call failed; retrying", "repo", e.Repo, "attempt", e.Attempt, "wait", e.Wait.Round(time.Millisecond), "error", e.Err)
+		},
+	})
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestScanRetriesTransientFailures(t *testing.T) {
+	gh := newFakeGitHub(t)
+	genFixture(gh, "acme", 3, 2, 5, time.Now())
+	prevBackoff := *retryBackoff
+	*retryBackoff = time.Millisecond
+	t.Cleanup(func() { *retryBackoff = prevBackoff })
+
+	var (
+		mu       sync.Mutex
+		failures = map[string]int{}
+	)
+	fake := runCmd
+	// Each repo's first issue list hits a 502.
+	runCmd = withRetries(func(ctx context.Context, bin string, args ...string) ([]byte, error) {
+		if args[0] == "issue" && args[1] == "list" {
+			repo := ghFlag(args, "--repo")
+			mu.Lock()
+			failures[repo]++
+			first := failures[repo] == 1
+			mu.Unlock()
+			if first {
+				return nil, errors.New("gh: Bad Gateway (HTTP 502)")
+			}
+		}
+		return fake(ctx, bin, args...)
+	})
+	out := scan(t.Context(), selection{Orgs: []string{"acme"}}, []string{"acme/repo-00", "acme/repo-01"}, config{MinIssues: 1, StaleDays: 90})
+	for _, rs := range out.Repos {
+		if rs.Error != "" {
+			t.Errorf("%s errored despite retries: %s", rs.Name, rs.Error)
+		}
+	}
+	if failures["acme/repo-00"] != 2 || failures["acme/repo-01"] != 2 {
+		t.Errorf("attempts = %v", failures)
+	}
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+)
+
+// runRuns implements `fab-backlog runs [show ID | diff [FROM TO]]` over the
+// configured storage. With no arguments it lists stored runs, newest first;
+// diff without IDs compares the two newest.
+func runRuns(args []string, cfg config) int {
+	if cfg.Storage == nil {
+		return runsFailed(2, errors.New("runs needs -history-file or storage in -config"))
+	}
+	v, err := runsCommand(openStorage(cfg.Storage), args)
+	if errors.Is(err, errRunsUsage) {
+		return runsFailed(2, err)
+	}
+	if err != nil {
+		return runsFailed(1, err)
+	}
+	emitJSON(v)
+	return 0
+}
+
+var errRunsUsage = errors.New("usage: fab-backlog runs [show ID | diff [FROM TO]]")
+
+func runsCommand(store Storage, args []string) (any, error) {
+	switch {
+	case len(args) == 0:
+		return store.ListRuns()
+	case args[0] == "show" && len(args) == 2:
+		r, err := store.GetRun(args[1])
+		return r.Report, err
+	case args[0] == "diff" && len(args) == 3:
+		return store.Diff(args[1], args[2])
+	case args[0] == "diff" && len(args) == 1:
+		runs, err := store.ListRuns()
+		if err != nil {
+			return nil, err
+		}
+		if len(runs) < 2 {
+			return nil, fmt.Errorf("diff needs two stored runs, found %d", len(runs))
+		}
+		return store.Diff(runs[1].ID, runs[0].ID)
+	}
+	return nil, errRunsUsage
+}
+
+func runsFailed(code int, err error) int {
+	slog.Error("runs failed", "error", err)
+	emitJSON(map[string]any{"ok": false, "error": err.Error()})
+	return code
+}
@@ -0,0 +1,176 @@
+package main
+
+import (
+	"flag"
+	"time"
+)
+
+var showPlan = flag.Bool("plan", false, "print which repos a scan would cover, with which filters, metrics, caps and estimated gh calls, then exit without scanning (see also -plan-out)")
+
+// scanPlan is what a scan would do, printed by -plan instead of scanning.
+type scanPlan struct {
+	Orgs        []string    `json:"orgs,omitempty"`
+	Repos       []string    `json:"repos"`
+	Ignore      []string    `json:"ignore,omitempty"`
+	Scopes      []string    `json:"scopes"`
+	Filters     planFilters `json:"filters"`
+	IssueFields string      `json:"issueFields,omitempty"`
+	Tiers       []planTier  `json:"tiers"`
+	Caps        planCaps    `json:"caps"`
+	Calls       planCalls   `json:"estimatedCalls"`
+	Notes       []string    `json:"notes,omitempty"`
+}
+
+type planFilters struct {
+	MinIssues           int      `json:"minIssues"`
+	StaleDays           int      `json:"staleDays"`
+	UnlabeledGraceHours int      `json:"unlabeledGraceHours,omitempty"`
+	IgnoreLabels        []string `json:"ignoreLabels,omitempty"`
+	StaleExcludeTitles  []string `json:"staleExcludeTitles,omitempty"`
+	CodeDebtAuthors     []string `json:"codeDebtAuthors,omitempty"`
+}
+
+// planTier is one metric the scan would compute and the gh calls it costs
+// per repo, or per org for org-wide sections.
+type planTier struct {
+	Name       string `json:"name"`
+	WindowDays int    `json:"windowDays,omitempty"`
+	Calls      int    `json:"calls"`
+	PerOrg     bool   `json:"perOrg,omitempty"`
+	Note       string `json:"note,omitempty"`
+}
+
+type planCaps struct {
+	IssueLimit       int    `json:"issueLimit"`
+	Concurrency      int    `json:"concurrency"`
+	Timeout          string `json:"timeout,omitempty"`
+	CallTimeout      string `json:"callTimeout,omitempty"`
+	Retries          int    `json:"retries"`
+	RateLimitReserve int    `json:"rateLimitReserve"`
+}
+
+// planCalls estimates a scan's gh calls, counted the way meta.api.calls
+// counts them. It's a floor: pagination, retries and link checks add more.
+type planCalls struct {
+	PerRepo int `json:"perRepo"`
+	PerOrg  int `json:"perOrg,omitempty"`
+	Total   int `json:"total"`
+}
+
+// buildScanPlan describes a scan of repos with cfg. It makes no calls;
+// resolving the selection beforehand cost one repo list per org.
+func buildScanPlan(sel selection, repos []string, cfg config) scanPlan {
+	p := scanPlan{
+		Orgs:   sel.Orgs,
+		Repos:  repos,
+		Ignore: sel.Ignore,
+		Scopes: cfg.scopes(),
+		Filters: planFilters{
+			MinIssues:           cfg.MinIssues,
+			StaleDays:           cfg.StaleDays,
+			UnlabeledGraceHours: cfg.UnlabeledGraceHours,
+			IgnoreLabels:        cfg.IgnoreLabels,
+			StaleExcludeTitles:  cfg.StaleExcludeTitles,
+		},
+		Caps: planCaps{
+			IssueLimit:       issueLimit,
+			Concurrency:      *concurrency,
+			Timeout:          durationOrEmpty(*scanTimeout),
+			CallTimeout:      durationOrEmpty(*callTimeout),
+			Retries:          *retries,
+			RateLimitReserve: *rateLimitReserve,
+		},
+		Notes: []string{"per-repo " + repoConfigPath + " overrides are applied during the scan and may change a repo's metrics"},
+	}
+	if p.Repos == nil {
+		p.Repos = []string{}
+	}
+	if cfg.Deep {
+		p.Caps.IssueLimit = deepIssueLimit
+	}
+	if cfg.CodeDebt {
+		p.Filters.CodeDebtAuthors = cfg.codeDebtAuthors()
+	}
+
+	add := func(t planTier) { p.Tiers = append(p.Tiers, t) }
+	add(planTier{Name: "repoConfig", Calls: 1, Note: repoConfigPath})
+	if cfg.readsTopics() {
+		add(planTier{Name: "repoTopics", Calls: 1})
+	}
+	if cfg.inScope("issues") {
+		p.IssueFields = issueFields(cfg)
+		add(planTier{Name: "core", Calls: 1})
+		if cfg.Deep {
+			add(planTier{Name: "deep"})
+		}
+		if w := cfg.responseWindow(); w > 0 {
+			add(planTier{Name: "firstResponse", WindowDays: w, Calls: 1})
+		}
+		if w := cfg.labelLatencyWindow(); w > 0 {
+			add(planTier{Name: "firstLabel", WindowDays: w, Calls: 1})
+		}
+		if w := cfg.contributorWindow(); w > 0 {
+			add(planTier{Name: "contributors", WindowDays: w, Calls: 2, Note: "up to 10 more pages of issue events on busy repos"})
+		}
+		if len(cfg.SLOs) > 0 {
+			add(planTier{Name: "slos"})
+		}
+		if cfg.SupportSLA != nil {
+			add(planTier{Name: "supportSLA", WindowDays: cfg.SupportSLA.windowDays(), Calls: 2, Note: "only repos a commitment covers"})
+		}
+		if cfg.CodeDebt {
+			add(planTier{Name: "codeDebt"})
+		}
+		if cfg.TriageEffort != nil {
+			add(planTier{Name: "triageEffort"})
+		}
+		if cfg.ProjectCoverage != nil {
+			add(planTier{Name: "projectCoverage", Calls: 1})
+		}
+		if cfg.StalePolicy != nil {
+			add(planTier{Name: "stalePolicy", Calls: 1, Note: "skipped for repos with no open issues"})
+		}
+		if cfg.LinkCheck {
+			add(planTier{Name: "linkCheck", Note: "one request per distinct link in stale issues"})
+		}
+		if cfg.MostWanted > 0 {
+			add(planTier{Name: "mostWanted"})
+		}
+	}
+	if w := cfg.qaWindow(); w > 0 && cfg.inScope("discussions") {
+		add(planTier{Name: "qa", WindowDays: w, Calls: 1})
+	}
+	if len(cfg.AutomationBots) > 0 && cfg.inScope("prs") {
+		add(planTier{Name: "automationBacklog", Calls: 1})
+	}
+	if cfg.SecurityAlerts {
+		add(planTier{Name: "securityAlerts", Calls: 2})
+	}
+	if cfg.CodeOwners {
+		add(planTier{Name: "codeOwners", Calls: 1, Note: "up to 3 on repos without .github/CODEOWNERS"})
+	}
+	if cfg.HallOfShame > 0 {
+		add(planTier{Name: "hallOfShame", Calls: 1, PerOrg: true, Note: "more when the oldest issues are in repos left out"})
+	}
+	if cfg.TeamRollups {
+		add(planTier{Name: "teamRollups", Calls: 1, PerOrg: true, Note: "plus one call per team"})
+	}
+
+	for _, t := range p.Tiers {
+		if t.PerOrg {
+			p.Calls.PerOrg += t.Calls
+		} else {
+			p.Calls.PerRepo += t.Calls
+		}
+	}
+	orgs := max(len(sel.Orgs), 1)
+	p.Calls.Total = p.Calls.PerRepo*len(repos) + p.Calls.PerOrg*orgs
+	return p
+}
+
+func durationOrEmpty(d time.Duration) string {
+	if d <= 0 {
+		return ""
+	}
+	return d.String()
+}
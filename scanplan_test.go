@@ -0,0 +1,43 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestScanPlanEstimatesCalls(t *testing.T) {
+	sel := selection{Orgs: []string{"acme", "globex"}, Ignore: []string{"acme/legacy-*"}}
+	repos := []string{"acme/api", "acme/web", "globex/site"}
+	cfg := config{MinIssues: 5, StaleDays: 90, IgnoreLabels: []string{"wontfix"}, Deep: true, SecurityAlerts: true, HallOfShame: 10, MostWanted: 5}
+	p := buildScanPlan(sel, repos, cfg)
+
+	var names []string
+	for _, tier := range p.Tiers {
+		names = append(names, tier.Name)
+	}
+	// -deep turns on the first-response, first-label, contributor and Q&A windows.
+	if got := strings.Join(names, ","); got != "repoConfig,core,deep,firstResponse,firstLabel,contributors,mostWanted,qa,securityAlerts,hallOfShame" {
+		t.Errorf("tiers = %s", got)
+	}
+	// repoConfig 1 + core 1 + firstResponse 1 + firstLabel 1 + contributors 2 + qa 1 + alerts 2.
+	if p.Calls.PerRepo != 9 || p.Calls.PerOrg != 1 || p.Calls.Total != 9*3+2 {
+		t.Errorf("calls = %+v", p.Calls)
+	}
+	if p.Caps.IssueLimit != deepIssueLimit || !strings.Contains(p.IssueFields, "reactionGroups") {
+		t.Errorf("deep plan: limit %d, fields %q", p.Caps.IssueLimit, p.IssueFields)
+	}
+	if len(p.Filters.IgnoreLabels) != 1 || len(p.Ignore) != 1 {
+		t.Errorf("filters = %+v, ignore = %v", p.Filters, p.Ignore)
+	}
+}
+
+func TestScanPlanRespectsScopes(t *testing.T) {
+	cfg := config{MinIssues: 5, StaleDays: 90, Scopes: []string{"prs"}, AutomationBots: []string{"dependabot[bot]"}, CodeDebt: true}
+	p := buildScanPlan(selection{Repos: []string{"acme/api"}}, []string{"acme/api"}, cfg)
+	if len(p.Tiers) != 2 || p.Tiers[1].Name != "automationBacklog" || p.IssueFields != "" {
+		t.Errorf("prs-only plan: tiers %+v, fields %q", p.Tiers, p.IssueFields)
+	}
+	if p.Calls.Total != 2 {
+		t.Errorf("total = %d, want repo config and open PRs", p.Calls.Total)
+	}
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"slices"
+)
+
+var scopeFlag = flag.String("scope", "issues,prs,discussions", "comma-separated backlog dimensions to evaluate: issues, prs, discussions")
+
+// Scopes gate which backlog dimensions a run fetches. issues covers the
+// health score and every issue-derived metric; prs the open-PR metrics;
+// discussions the Q&A metrics. Metrics inside a scope still need their own
+// options to be enabled.
+var allScopes = []string{"issues", "prs", "discussions"}
+
+// unscored is the status of a repo whose issues were out of scope: it has
+// no health score and is left out of the summary and gates.
+const unscored = "unscored"
+
+func validateScopes(scopes []string) error {
+	for _, s := range scopes {
+		if !slices.Contains(allScopes, s) {
+			return fmt.Errorf("unknown scope %q (want issues, prs or discussions)", s)
+		}
+	}
+	return nil
+}
+
+// scopes returns the evaluated scopes; none configured means all.
+func (c config) scopes() []string {
+	if len(c.Scopes) == 0 {
+		return allScopes
+	}
+	return c.Scopes
+}
+
+func (c config) inScope(s string) bool {
+	return slices.Contains(c.scopes(), s)
+}
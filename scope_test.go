@@ -0,0 +1,57 @@
+package main
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestScopeSkipsIssues(t *testing.T) {
+	gh := newFakeGitHub(t)
+	genFixture(gh, "acme", 1, 2, 5, time.Now())
+	var (
+		mu   sync.Mutex
+		cmds []string
+	)
+	fake := runCmd
+	runCmd = func(ctx context.Context, bin string, args ...string) ([]byte, error) {
+		mu.Lock()
+		cmds = append(cmds, args[0]+" "+args[1])
+		mu.Unlock()
+		return fake(ctx, bin, args...)
+	}
+
+	cfg := config{MinIssues: 1, StaleDays: 90, Scopes: []string{"prs"}, ContributorWindowDays: 30}
+	out := scan(t.Context(), selection{Orgs: []string{"acme"}}, []string{"acme/repo-00", "acme/repo-01"}, cfg)
+	for _, c := range cmds {
+		if c == "issue list" || c == "api graphql" {
+			t.Errorf("issues out of scope, but ran gh %s", c)
+		}
+	}
+	if out.Summary.Total != 0 || out.Repos[0].Status != unscored || len(out.Scopes) != 1 {
+		t.Errorf("summary = %+v, status = %s, scopes = %v", out.Summary, out.Repos[0].Status, out.Scopes)
+	}
+	if err := validateScopes([]string{"commits"}); err == nil {
+		t.Error("unknown scope should be rejected")
+	}
+}
+
+func TestAssembleOrdersUnscored(t *testing.T) {
+	out := assemble("org", config{}, []repoScore{
+		{Name: "err", Error: "boom"},
+		{Name: "u", Status: unscored},
+		{Name: "good", HealthScore: 90, Status: "healthy"},
+		{Name: "bad", HealthScore: 30, Status: "critical"},
+	}, time.Now())
+	var names []string
+	for _, rs := range out.Repos {
+		names = append(names, rs.Name)
+	}
+	if names[0] != "bad" || names[1] != "good" || names[2] != "u" || names[3] != "err" {
+		t.Errorf("order = %v", names)
+	}
+	if out.Summary.Total != 2 {
+		t.Errorf("summary = %+v", out.Summary)
+	}
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+)
+
+var issuesFile = flag.String("issues-file", "", "score: path to a JSON array of issues to score (- for stdin)")
+
+// runScore implements `fab-backlog score --issues-file issues.json [name]`:
+// it scores issues exported from any tracker with the same engine a scan
+// uses and prints the resulting repoScore.
+func runScore(args []string, cfg config) int {
+	if *issuesFile == "" {
+		slog.Error("score requires --issues-file")
+		emitJSON(map[string]any{"ok": false, "error": "score requires --issues-file"})
+		return 2
+	}
+	name := "issues"
+	if len(args) > 0 {
+		name = args[0]
+	}
+	issues, err := readIssuesFile(*issuesFile)
+	if err != nil {
+		slog.Error("failed to read issues", "path", *issuesFile, "error", err)
+		emitJSON(map[string]any{"ok": false, "error": err.Error()})
+		return 1
+	}
+	now := clock()
+	rs := scoreIssues(name, issues, cfg, now)
+	rs.issues = issues
+	rs.Recommendations = recommend(rs, rs.staleCandidates(), cfg, now)
+	emitJSON(rs)
+	return 0
+}
+
+func readIssuesFile(path string) ([]issue, error) {
+	var r io.Reader = os.Stdin
+	if path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, fmt.Errorf("open issues file: %w", err)
+		}
+		defer f.Close()
+		r = f
+	}
+	return parseIssues(r)
+}
+
+// parseIssues decodes the documented issue schema (the shape of
+// `gh issue list --json`) and rejects records that can't be scored.
+func parseIssues(r io.Reader) ([]issue, error) {
+	var issues []issue
+	if err := json.NewDecoder(r).Decode(&issues); err != nil {
+		return nil, fmt.Errorf("parse issues json: %w", err)
+	}
+	for i, is := range issues {
+		if is.CreatedAt.IsZero() || is.UpdatedAt.IsZero() {
+			return nil, fmt.Errorf("issue %d (number %d): createdAt and updatedAt are required", i, is.Number)
+		}
+	}
+	return issues, nil
+}
@@ -0,0 +1,27 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseIssuesSchema(t *testing.T) {
+	data := `[
+		{"number": 1, "title": "Crash", "createdAt": "2024-01-01T00:00:00Z", "updatedAt": "2024-01-02T00:00:00Z", "labels": [{"name": "bug"}]},
+		{"number": 2, "title": "Idea", "createdAt": "2025-05-01T00:00:00Z", "updatedAt": "2025-05-30T00:00:00Z", "labels": []}
+	]`
+	issues, err := parseIssues(strings.NewReader(data))
+	if err != nil {
+		t.Fatalf("parseIssues: %v", err)
+	}
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	rs := scoreIssues("jira-proj", issues, config{MinIssues: 5, StaleDays: 90}, now)
+	if rs.TotalOpen != 2 || rs.StaleCount != 1 || rs.UnlabeledCount != 1 || rs.HealthScore != 50 || rs.Status != "warning" {
+		t.Errorf("unexpected score: %+v", rs)
+	}
+
+	if _, err := parseIssues(strings.NewReader(`[{"number": 3, "title": "no dates"}]`)); err == nil {
+		t.Error("issues without timestamps should be rejected")
+	}
+}
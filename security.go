@@ -0,0 +1,30 @@
+package main
+
+import "time"
+
+// securityIssue is an entry in the report's top-level securityBacklog: an
+// open security-labeled issue older than the configured limit.
+type securityIssue struct {
+	Repo    string `json:"repo"`
+	Number  int    `json:"number"`
+	Title   string `json:"title"`
+	AgeDays int    `json:"ageDays"`
+}
+
+// overdueSecurityIssues returns the issues carrying a security label that
+// have been open longer than maxDays. A non-positive maxDays disables the check.
+func overdueSecurityIssues(repo string, issues []issue, security labelMatcher, maxDays int, now time.Time) []securityIssue {
+	if maxDays <= 0 {
+		return nil
+	}
+	var out []securityIssue
+	for _, is := range issues {
+		if !security.any(is) {
+			continue
+		}
+		if age := daysBetween(is.CreatedAt, now); age > maxDays {
+			out = append(out, securityIssue{Repo: repo, Number: is.Number, Title: is.Title, AgeDays: age})
+		}
+	}
+	return out
+}
@@ -0,0 +1,29 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestOverdueSecurityIssuesForcesCritical(t *testing.T) {
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	issues := []issue{
+		{Number: 1, CreatedAt: now.AddDate(0, 0, -45), UpdatedAt: now, Labels: []label{{Name: "security"}}},
+		{Number: 2, CreatedAt: now.AddDate(0, 0, -5), UpdatedAt: now, Labels: []label{{Name: "security"}}},
+		{Number: 3, CreatedAt: now.AddDate(0, 0, -400), UpdatedAt: now, Labels: []label{{Name: "bug"}}},
+		{Number: 4, CreatedAt: now.AddDate(0, 0, -1), UpdatedAt: now, Labels: []label{{Name: "bug"}}},
+		{Number: 5, CreatedAt: now.AddDate(0, 0, -1), UpdatedAt: now, Labels: []label{{Name: "bug"}}},
+	}
+	if got := overdueSecurityIssues("r", issues, config{}.labelMatcher("security"), 0, now); got != nil {
+		t.Errorf("maxDays 0 should disable the check, got %+v", got)
+	}
+
+	cfg := config{MinIssues: 5, StaleDays: 90, SecurityLabel: "security", SecurityMaxDays: 30}
+	rs := scoreIssues("r", issues, cfg, now)
+	if rs.HealthScore != 100 || rs.Status != "critical" {
+		t.Errorf("score %d status %q, want 100 and critical", rs.HealthScore, rs.Status)
+	}
+	if rs.SecurityOverdueCount != 1 || len(rs.securityOverdue) != 1 || rs.securityOverdue[0].Number != 1 {
+		t.Errorf("overdue = %+v, want only #1", rs.securityOverdue)
+	}
+}
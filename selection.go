@@ -0,0 +1,105 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log/slog"
+	"strings"
+)
+
+var repoFlags stringList
+
+func init() {
+	flag.Var(&repoFlags, "repo", "owner/name of a repo to scan (repeatable); without an explicit -org only these repos are scanned")
+}
+
+// stringList is a repeatable string flag.
+type stringList []string
+
+func (l *stringList) String() string { return strings.Join(*l, ",") }
+
+func (l *stringList) Set(v string) error {
+	*l = append(*l, v)
+	return nil
+}
+
+// selection is what a scan covers: every repo in Orgs plus explicit Repos.
+// Selections may overlap; resolve makes sure each repo is fetched once.
+// Org repos matching an Ignore pattern are skipped; repos named explicitly
+// are always scanned.
+type selection struct {
+	Orgs   []string
+	Repos  []string
+	Ignore []string
+}
+
+func currentSelection() selection {
+	sel := selection{Orgs: splitList(*org), Repos: repoFlags}
+	if len(sel.Repos) > 0 && !flagSet("org") {
+		sel.Orgs = nil
+	}
+	return sel
+}
+
+// primaryOrg is the org bare repo names resolve against and the report's org.
+func (s selection) primaryOrg() string {
+	if len(s.Orgs) > 0 {
+		return s.Orgs[0]
+	}
+	for _, r := range s.Repos {
+		if owner, _ := splitRepo("", r); owner != "" {
+			return owner
+		}
+	}
+	return *org
+}
+
+// resolve lists every selected org and returns the deduplicated repos as
+// owner/name.
+func (s selection) resolve(ctx context.Context) ([]string, error) {
+	var all []string
+	for _, o := range s.Orgs {
+		slog.Info("scanning repos", "org", o)
+		names, err := ghListRepos(ctx, o)
+		if err != nil {
+			return nil, err
+		}
+		slog.Info("repo scan complete", "org", o, "count", len(names))
+		for _, n := range names {
+			if ignoredRepo(o+"/"+n, s.Ignore) {
+				slog.Info("repo ignored", "repo", o+"/"+n)
+				continue
+			}
+			all = append(all, o+"/"+n)
+		}
+	}
+	all = append(all, s.Repos...)
+	return dedupeRepos(s.primaryOrg(), all), nil
+}
+
+// dedupeRepos qualifies bare names against org and drops repeats, compared
+// case-insensitively as GitHub does, keeping the first spelling seen.
+func dedupeRepos(org string, repos []string) []string {
+	seen := map[string]bool{}
+	out := make([]string, 0, len(repos))
+	for _, r := range repos {
+		owner, name := splitRepo(org, r)
+		full := owner + "/" + name
+		if key := strings.ToLower(full); !seen[key] {
+			seen[key] = true
+			out = append(out, full)
+		}
+	}
+	return out
+}
+
+// flagSet reports whether the named flag was given on the command line.
+func flagSet(name string) bool {
+	set := false
+	flag.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			set = true
+		}
+	})
+	return set
+}
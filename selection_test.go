@@ -0,0 +1,53 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestDedupeRepos(t *testing.T) {
+	got := dedupeRepos("org", []string{"org/api", "api", "Org/API", "other/api", "web", "org/web"})
+	want := []string{"org/api", "other/api", "org/web"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("dedupeRepos = %v, want %v", got, want)
+	}
+}
+
+func TestSelectionPrimaryOrg(t *testing.T) {
+	if got := (selection{Orgs: []string{"a", "b"}}).primaryOrg(); got != "a" {
+		t.Errorf("primaryOrg = %q, want a", got)
+	}
+	if got := (selection{Repos: []string{"x/one", "y/two"}}).primaryOrg(); got != "x" {
+		t.Errorf("primaryOrg = %q, want x", got)
+	}
+}
+
+func TestIgnoredRepo(t *testing.T) {
+	dir := t.TempDir()
+	p := filepath.Join(dir, ".fabignore")
+	if err := os.WriteFile(p, []byte("# sandboxes\nacme/sandbox-*\n\narchive-*\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	patterns, err := loadIgnoreFile(p, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for repo, want := range map[string]bool{
+		"acme/sandbox-1":    true,
+		"other/sandbox-1":   false,
+		"Other/Archive-Old": true,
+		"acme/api":          false,
+	} {
+		if got := ignoredRepo(repo, patterns); got != want {
+			t.Errorf("ignoredRepo(%q) = %v, want %v", repo, got, want)
+		}
+	}
+	if patterns, err := loadIgnoreFile(filepath.Join(dir, "missing"), false); err != nil || patterns != nil {
+		t.Errorf("missing default file: %v, %v", patterns, err)
+	}
+	if _, err := loadIgnoreFile(filepath.Join(dir, "missing"), true); err == nil {
+		t.Error("missing explicit file should fail")
+	}
+}
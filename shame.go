@@ -0,0 +1,160 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"math"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// shameIssue is an entry in the org-wide hallOfShame: one of the oldest
+// open issues in the org.
+type shameIssue struct {
+	Repo     string  `json:"repo"`
+	Number   int     `json:"number"`
+	Title    string  `json:"title"`
+	URL      string  `json:"url"`
+	AgeDays  int     `json:"ageDays"`
+	AgeYears float64 `json:"ageYears"`
+	// Owners are who owns the repo: its CODEOWNERS catch-all owners with
+	// -codeowners, else the teams assigned it with -team-rollups.
+	Owners []string `json:"owners,omitempty"`
+}
+
+type searchHit struct {
+	Number     int       `json:"number"`
+	Title      string    `json:"title"`
+	URL        string    `json:"url"`
+	CreatedAt  time.Time `json:"createdAt"`
+	Repository struct {
+		Name          string `json:"name"`
+		NameWithOwner string `json:"nameWithOwner"`
+	} `json:"repository"`
+}
+
+// ghOldestIssues searches org for its oldest open issues. A single search
+// covers every repo; the per-repo issue fetch is newest-first and capped, so
+// it would miss exactly the issues this section is about.
+func ghOldestIssues(ctx context.Context, org string, limit int) ([]searchHit, error) {
+	args := []string{"search", "issues", "--owner", org, "--state", "open", "--archived=false",
+		"--sort", "created", "--order", "asc",
+		"--json", "number,title,url,createdAt,repository", "--limit", strconv.Itoa(limit)}
+	stdout, err := runCmd(ctx, "gh", args...)
+	if err != nil {
+		return nil, err
+	}
+	var hits []searchHit
+	if err := json.Unmarshal(stdout, &hits); err != nil {
+		return nil, fmt.Errorf("parse gh search issues json: %w", err)
+	}
+	return hits, nil
+}
+
+// searchCap is the most results GitHub's search returns for one query.
+const searchCap = 1000
+
+// oldestScannedIssues searches org for its oldest open issues until n of
+// them belong to scanned repos. Hits in repos the scan left out don't
+// count, so a short result is searched again, further, up to searchCap.
+func oldestScannedIssues(ctx context.Context, org string, scanned map[string]repoScore, n int) ([]searchHit, error) {
+	for limit := min(n, searchCap); ; limit = min(limit*4, searchCap) {
+		hits, err := ghOldestIssues(ctx, org, limit)
+		if err != nil {
+			return nil, err
+		}
+		kept := 0
+		for _, h := range hits {
+			if _, ok := scanned[strings.ToLower(h.Repository.NameWithOwner)]; ok {
+				kept++
+			}
+		}
+		if kept >= n || len(hits) < limit || limit == searchCap {
+			return hits, nil
+		}
+	}
+}
+
+// byFullName indexes repos by lowercased full name.
+func byFullName(repos []repoScore) map[string]repoScore {
+	m := map[string]repoScore{}
+	for _, rs := range repos {
+		m[strings.ToLower(rs.fullName)] = rs
+	}
+	return m
+}
+
+// repoOwners is who owns rs: its CODEOWNERS catch-all owners, else the
+// teams of rollups assigned it, as @org/team.
+func repoOwners(rs repoScore, rollups []teamRollup) []string {
+	if co := rs.CodeOwners; co != nil && len(co.Default) > 0 {
+		return co.Default
+	}
+	owner, _ := splitRepo("", rs.fullName)
+	var teams []string
+	for _, r := range rollups {
+		if strings.EqualFold(r.Org, owner) && slices.Contains(r.Repos, rs.Name) {
+			teams = append(teams, "@"+r.Org+"/"+r.Team)
+		}
+	}
+	return teams
+}
+
+// hallOfShame keeps the hits belonging to scanned repos, oldest first,
+// with who owns each.
+func hallOfShame(hits []searchHit, repos []repoScore, rollups []teamRollup, n int, now time.Time) []shameIssue {
+	scanned := byFullName(repos)
+	out := []shameIssue{}
+	for _, h := range hits {
+		rs, ok := scanned[strings.ToLower(h.Repository.NameWithOwner)]
+		if !ok {
+			continue
+		}
+		age := daysBetween(h.CreatedAt, now)
+		out = append(out, shameIssue{
+			Repo:     rs.Name,
+			Number:   h.Number,
+			Title:    h.Title,
+			URL:      h.URL,
+			AgeDays:  age,
+			AgeYears: math.Round(float64(age)/365.25*10) / 10,
+			Owners:   repoOwners(rs, rollups),
+		})
+		if len(out) == n {
+			break
+		}
+	}
+	return out
+}
+
+// addOrgSections fills in the report sections that need org-wide queries
+// rather than the per-repo data assemble works from.
+func addOrgSections(ctx context.Context, out *output, cfg config, now time.Time) {
+	orgs := out.Orgs
+	if len(orgs) == 0 {
+		orgs = []string{out.Org}
+	}
+	// Team rollups go first: the hall of shame names the teams.
+	if cfg.TeamRollups {
+		addTeamRollups(ctx, out, orgs)
+	}
+	if cfg.HallOfShame > 0 {
+		scanned := byFullName(out.Repos)
+		var hits []searchHit
+		for _, org := range orgs {
+			h, err := oldestScannedIssues(ctx, org, scanned, cfg.HallOfShame)
+			if err != nil {
+				slog.Warn("hall of shame unavailable", "org", org, "error", err)
+				continue
+			}
+			hits = append(hits, h...)
+		}
+		sort.SliceStable(hits, func(i, j int) bool { return hits[i].CreatedAt.Before(hits[j].CreatedAt) })
+		out.HallOfShame = hallOfShame(hits, out.Repos, out.TeamRollups, cfg.HallOfShame, now)
+	}
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestHallOfShame(t *testing.T) {
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	hit := func(repo string, n int, created time.Time) searchHit {
+		h := searchHit{Number: n, CreatedAt: created, URL: "https://github.com/" + repo + "/issues/1"}
+		h.Repository.NameWithOwner = repo
+		return h
+	}
+	hits := []searchHit{
+		hit("org/a", 1, now.AddDate(-6, 0, 0)),
+		hit("org/archived", 2, now.AddDate(-5, 0, 0)),
+		hit("org/b", 3, now.AddDate(-3, -6, 0)),
+		hit("org/a", 4, now.AddDate(-1, 0, 0)),
+	}
+	repos := []repoScore{{Name: "a", fullName: "org/a", CodeOwners: &codeOwners{Default: []string{"@org/core"}}}, {Name: "b", fullName: "org/b"}}
+	rollups := []teamRollup{{Org: "org", Team: "web", Repos: []string{"b"}}, {Org: "other", Team: "x", Repos: []string{"b"}}}
+	got := hallOfShame(hits, repos, rollups, 2, now)
+	if len(got) != 2 || got[0].Number != 1 || got[1].Number != 3 {
+		t.Fatalf("hallOfShame = %+v, want #1 then #3 (unscanned repos skipped)", got)
+	}
+	if got[0].AgeYears != 6 || got[1].AgeYears != 3.5 || got[1].Repo != "b" {
+		t.Errorf("ages = %v, %v; want 6 and 3.5", got[0].AgeYears, got[1].AgeYears)
+	}
+	if !slices.Equal(got[0].Owners, []string{"@org/core"}) || !slices.Equal(got[1].Owners, []string{"@org/web"}) {
+		t.Errorf("owners = %v, %v", got[0].Owners, got[1].Owners)
+	}
+}
+
+func TestOldestScannedIssuesSearchesFurther(t *testing.T) {
+	prev := runCmd
+	t.Cleanup(func() { runCmd = prev })
+	var limits []string
+	runCmd = func(ctx context.Context, bin string, args ...string) ([]byte, error) {
+		limit := args[len(args)-1]
+		limits = append(limits, limit)
+		// The two oldest issues are in a repo the scan leaves out.
+		hits := `[{"number":1,"repository":{"nameWithOwner":"org/skip"}},{"number":2,"repository":{"nameWithOwner":"org/skip"}}`
+		if limit != "2" {
+			hits += `,{"number":3,"repository":{"nameWithOwner":"org/a"}},{"number":4,"repository":{"nameWithOwner":"org/a"}}`
+		}
+		return []byte(hits + "]"), nil
+	}
+	scanned := byFullName([]repoScore{{Name: "a", fullName: "org/a"}})
+	hits, err := oldestScannedIssues(t.Context(), "org", scanned, 2)
+	if err != nil || len(hits) != 4 || !slices.Equal(limits, []string{"2", "8"}) {
+		t.Errorf("hits %+v, err %v, limits %v", hits, err, limits)
+	}
+}
@@ -0,0 +1,251 @@
+package main
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"math"
+	"slices"
+	"text/template"
+	"time"
+
+	"github.com/misty-step/fab-backlog/pkg/report"
+)
+
+// supportSLA maps repos to the support commitments published for them, so
+// `report -format sla` can show customers whether each product keeps its
+// promise. Only public repos are measured, and issues carrying
+// ExcludeLabels (embargoed security reports, internal chores) are left out.
+type supportSLA struct {
+	// Title heads the published page; default "Support SLA compliance".
+	Title string `json:"title,omitempty"`
+	// WindowDays is how far back issues are sampled; default 30.
+	WindowDays    int             `json:"windowDays,omitempty"`
+	ExcludeLabels []string        `json:"excludeLabels,omitempty"`
+	Commitments   []slaCommitment `json:"commitments"`
+}
+
+// slaCommitment is the first-response promise made for one product: a
+// maintainer replies within FirstResponseHours to at least TargetPercent of
+// issues. Repos are owner/name globs; a repo takes the first commitment
+// naming it.
+type slaCommitment struct {
+	Product            string       `json:"product"`
+	Repos              repoPatterns `json:"repos"`
+	FirstResponseHours int          `json:"firstResponseHours"`
+	TargetPercent      float64      `json:"targetPercent,omitempty"` // default 90
+}
+
+const (
+	defaultSLATitle      = "Support SLA compliance"
+	defaultSLAWindowDays = 30
+	defaultSLATarget     = 90
+)
+
+func (s supportSLA) title() string {
+	return orDefault(s.Title, defaultSLATitle)
+}
+
+func (s supportSLA) windowDays() int {
+	if s.WindowDays > 0 {
+		return s.WindowDays
+	}
+	return defaultSLAWindowDays
+}
+
+func (c slaCommitment) target() float64 {
+	if c.TargetPercent > 0 {
+		return c.TargetPercent
+	}
+	return defaultSLATarget
+}
+
+func (s supportSLA) validate() error {
+	if len(s.Commitments) == 0 {
+		return fmt.Errorf("supportSLA: at least one commitment required")
+	}
+	if s.WindowDays < 0 {
+		return fmt.Errorf("supportSLA: windowDays must not be negative")
+	}
+	for _, p := range s.ExcludeLabels {
+		if err := validatePattern(p); err != nil {
+			return fmt.Errorf("supportSLA: %w", err)
+		}
+	}
+	for _, c := range s.Commitments {
+		if c.Product == "" {
+			return fmt.Errorf("supportSLA: commitment product required")
+		}
+		if len(c.Repos) == 0 {
+			return fmt.Errorf("supportSLA %q: repos required", c.Product)
+		}
+		if err := c.Repos.validate(); err != nil {
+			return fmt.Errorf("supportSLA %q: %w", c.Product, err)
+		}
+		if c.FirstResponseHours <= 0 {
+			return fmt.Errorf("supportSLA %q: firstResponseHours must be positive", c.Product)
+		}
+		if c.TargetPercent < 0 || c.TargetPercent > 100 {
+			return fmt.Errorf("supportSLA %q: targetPercent must be between 0 and 100", c.Product)
+		}
+	}
+	return nil
+}
+
+// commitmentFor returns the commitment covering owner/name, or nil.
+func (s supportSLA) commitmentFor(full string) *slaCommitment {
+	for i, c := range s.Commitments {
+		if c.Repos.matches(full) {
+			return &s.Commitments[i]
+		}
+	}
+	return nil
+}
+
+// slaCompliance is how a repo did against its commitment over the window.
+// Issues still inside their response time with no reply yet are pending
+// and don't count either way.
+type slaCompliance struct {
+	Product            string  `json:"product"`
+	FirstResponseHours int     `json:"firstResponseHours"`
+	TargetPercent      float64 `json:"targetPercent"`
+	WindowDays         int     `json:"windowDays"`
+	Met                int     `json:"met"`
+	Missed             int     `json:"missed"`
+	Pending            int     `json:"pending"`
+	CompliancePercent  float64 `json:"compliancePercent"`
+	Compliant          bool    `json:"compliant"`
+}
+
+// computeSLACompliance checks each issue's first maintainer response
+// against the commitment. Unlike the internal first-response metric, time
+// out of office isn't discounted: the promise to users doesn't pause.
+func computeSLACompliance(issues []issue, c slaCommitment, windowDays int, now time.Time) *slaCompliance {
+	sc := &slaCompliance{Product: c.Product, FirstResponseHours: c.FirstResponseHours, TargetPercent: c.target(), WindowDays: windowDays}
+	allowed := time.Duration(c.FirstResponseHours) * time.Hour
+	for _, is := range issues {
+		deadline := is.CreatedAt.Add(allowed)
+		at := firstResponseAt(is)
+		switch {
+		case !at.IsZero() && !at.After(deadline):
+			sc.Met++
+		case !at.IsZero() || now.After(deadline):
+			sc.Missed++
+		default:
+			sc.Pending++
+		}
+	}
+	sc.CompliancePercent = 100
+	if due := sc.Met + sc.Missed; due > 0 {
+		sc.CompliancePercent = math.Round(float64(sc.Met)/float64(due)*1000) / 10
+	}
+	sc.Compliant = sc.CompliancePercent >= sc.TargetPercent
+	return sc
+}
+
+// ghRepoIsPublic reports whether a repo is public. Internal repos count as
+// private.
+func ghRepoIsPublic(ctx context.Context, owner, repo string) (bool, error) {
+	stdout, err := runCmd(ctx, "gh", "api", fmt.Sprintf("repos/%s/%s", owner, repo))
+	if err != nil {
+		return false, err
+	}
+	var r struct {
+		Private bool `json:"private"`
+	}
+	if err := json.Unmarshal(stdout, &r); err != nil {
+		return false, fmt.Errorf("parse repo: %w", err)
+	}
+	return !r.Private, nil
+}
+
+func addSLACompliance(ctx context.Context, score *repoScore, owner, repo string, cfg config, limit int, now time.Time) {
+	s := cfg.SupportSLA
+	if s == nil {
+		return
+	}
+	c := s.commitmentFor(owner + "/" + repo)
+	if c == nil {
+		return
+	}
+	public, err := ghRepoIsPublic(ctx, owner, repo)
+	if err != nil {
+		slog.Warn("support SLA unavailable", "repo", repo, "error", err)
+		return
+	}
+	if !public {
+		slog.Info("support SLA skipped: repo isn't public", "repo", repo, "product", c.Product)
+		return
+	}
+	window := s.windowDays()
+	recent, err := ghListRecentIssues(ctx, owner, repo, now.AddDate(0, 0, -window), limit, recentIssueFields+",labels")
+	if err != nil {
+		slog.Warn("support SLA unavailable", "repo", repo, "error", err)
+		return
+	}
+	if len(s.ExcludeLabels) > 0 {
+		excluded := cfg.labelMatcher(s.ExcludeLabels...)
+		recent = slices.DeleteFunc(recent, excluded.any)
+	}
+	score.SLA = computeSLACompliance(recent, *c, window, now)
+}
+
+// slaRow is one repo on the published page.
+type slaRow struct {
+	Repo string
+	*slaCompliance
+}
+
+// Due is how many issues count towards compliance: answered, or out of
+// time.
+func (r slaRow) Due() int { return r.Met + r.Missed }
+
+var slaTmpl = template.Must(template.New("sla").Funcs(renderFuncs).Parse(`# {{.Title}}
+
+{{if .GeneratedAt}}As of {{date .GeneratedAt}}. {{end}}Each product commits to a first response from a maintainer within the stated time. Compliance is the share of public issues opened in the measurement window that received one in time; issues still inside their response time aren't counted yet.
+{{if .Rows}}
+| Product | Repository | First response within | Target | Compliance | Status |
+|---------|------------|----------------------:|-------:|-----------:|--------|
+{{range .Rows}}| {{cell .Product}} | {{cell .Repo}} | {{num .FirstResponseHours}} hours | {{decimal .TargetPercent 0}}% | {{if .Due}}{{decimal .CompliancePercent 1}}% ({{num .Met}} of {{num .Due}}){{else}}no issues due{{end}} | {{if .Compliant}}Meeting{{else}}Not meeting{{end}} |
+{{end}}
+Measured over the last {{num .WindowDays}} days.
+{{else}}
+No public repositories are covered by a support commitment.
+{{end}}`))
+
+// renderSLA renders the customer-facing SLA page: commitment compliance
+// per public repo and nothing else from the report.
+func renderSLA(out output) (string, error) {
+	f, err := report.Lookup(out.Config.Locale)
+	if err != nil {
+		return "", err
+	}
+	var rows []slaRow
+	for _, rs := range out.Repos {
+		if rs.SLA != nil && rs.Error == "" {
+			rows = append(rows, slaRow{Repo: rs.Name, slaCompliance: rs.SLA})
+		}
+	}
+	slices.SortFunc(rows, func(a, b slaRow) int {
+		return cmp.Or(cmp.Compare(a.Product, b.Product), cmp.Compare(a.Repo, b.Repo))
+	})
+	s := supportSLA{}
+	if out.Config.SupportSLA != nil {
+		s = *out.Config.SupportSLA
+	}
+	t, err := slaTmpl.Clone()
+	if err != nil {
+		return "", err
+	}
+	var b bytes.Buffer
+	err = t.Funcs(f.Funcs()).Execute(&b, map[string]any{
+		"Title":       s.title(),
+		"GeneratedAt": out.GeneratedAt,
+		"WindowDays":  s.windowDays(),
+		"Rows":        rows,
+	})
+	return b.String(), err
+}
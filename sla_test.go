@@ -0,0 +1,103 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestComputeSLACompliance(t *testing.T) {
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	t0 := now.AddDate(0, 0, -10)
+	reply := func(after time.Duration) []comment {
+		return []comment{{Author: actor{Login: "maint"}, AuthorAssociation: "MEMBER", CreatedAt: t0.Add(after)}}
+	}
+	issues := []issue{
+		{Number: 1, CreatedAt: t0, Comments: reply(2 * time.Hour)},
+		{Number: 2, CreatedAt: t0, Comments: reply(48 * time.Hour)},
+		{Number: 3, CreatedAt: t0, Comments: reply(100 * time.Hour)},
+		{Number: 4, CreatedAt: t0},
+		{Number: 5, CreatedAt: now.Add(-time.Hour)},
+	}
+	sc := computeSLACompliance(issues, slaCommitment{Product: "Widgets", FirstResponseHours: 48, TargetPercent: 50}, 30, now)
+	// #2 answered exactly on time, #3 late, #4 never, #5 still has time.
+	if sc.Met != 2 || sc.Missed != 2 || sc.Pending != 1 || sc.CompliancePercent != 50 || !sc.Compliant {
+		t.Errorf("got %+v", sc)
+	}
+	if sc := computeSLACompliance(issues[:4], slaCommitment{FirstResponseHours: 24}, 30, now); sc.TargetPercent != 90 || sc.CompliancePercent != 25 || sc.Compliant {
+		t.Errorf("default target: %+v", sc)
+	}
+	if sc := computeSLACompliance(nil, slaCommitment{FirstResponseHours: 24}, 30, now); sc.CompliancePercent != 100 || !sc.Compliant {
+		t.Errorf("no issues due should comply: %+v", sc)
+	}
+}
+
+func TestSupportSLAValidate(t *testing.T) {
+	ok := slaCommitment{Product: "Widgets", Repos: []string{"acme/widget-*"}, FirstResponseHours: 72}
+	for name, s := range map[string]supportSLA{
+		"no commitments": {},
+		"no product":     {Commitments: []slaCommitment{{Repos: ok.Repos, FirstResponseHours: 72}}},
+		"bare repo":      {Commitments: []slaCommitment{{Product: "W", Repos: []string{"widget"}, FirstResponseHours: 72}}},
+		"no hours":       {Commitments: []slaCommitment{{Product: "W", Repos: ok.Repos}}},
+		"target":         {Commitments: []slaCommitment{{Product: "W", Repos: ok.Repos, FirstResponseHours: 72, TargetPercent: 120}}},
+		"label":          {ExcludeLabels: []string{"/(/"}, Commitments: []slaCommitment{ok}},
+	} {
+		if err := s.validate(); err == nil {
+			t.Errorf("%s: want error", name)
+		}
+	}
+	s := supportSLA{Commitments: []slaCommitment{ok}}
+	if err := s.validate(); err != nil {
+		t.Error(err)
+	}
+	if s.commitmentFor("Acme/Widget-Core") == nil || s.commitmentFor("acme/site") != nil {
+		t.Error("commitmentFor should match repo globs case-insensitively")
+	}
+}
+
+func TestSLAReport(t *testing.T) {
+	gh := newFakeGitHub(t)
+	now := time.Now()
+	old := now.AddDate(0, 0, -5)
+	issues := func() []*fakeIssue {
+		return []*fakeIssue{
+			{Number: 1, Title: "crash on start", State: "open", CreatedAt: old, UpdatedAt: old},
+			{Number: 2, Title: "embargoed CVE", State: "open", CreatedAt: old, UpdatedAt: old, Labels: []string{"security"}},
+			{Number: 3, Title: "typo", State: "open", CreatedAt: now.Add(-time.Hour), UpdatedAt: now},
+		}
+	}
+	gh.addRepo(&fakeRepo{Owner: "acme", Name: "widget", Issues: issues()})
+	gh.addRepo(&fakeRepo{Owner: "acme", Name: "widget-internal", Private: true, Issues: issues()})
+	gh.addRepo(&fakeRepo{Owner: "acme", Name: "site", Issues: issues()})
+	cfg := config{MinIssues: 1, StaleDays: 90, SupportSLA: &supportSLA{
+		Title:         "Acme support commitments",
+		ExcludeLabels: []string{"security"},
+		Commitments:   []slaCommitment{{Product: "Widgets", Repos: []string{"acme/widget*"}, FirstResponseHours: 48}},
+	}}
+	out := scan(t.Context(), selection{Orgs: []string{"acme"}}, []string{"site", "widget", "widget-internal"}, cfg)
+	for _, rs := range out.Repos {
+		switch {
+		case rs.Name == "widget":
+			if sla := rs.SLA; sla == nil || sla.Met != 0 || sla.Missed != 1 || sla.Pending != 1 || sla.Compliant {
+				t.Errorf("widget SLA = %+v, want #1 missed, #3 pending, #2 excluded", sla)
+			}
+		case rs.SLA != nil:
+			t.Errorf("%s: SLA measured for a private or uncovered repo", rs.Name)
+		}
+	}
+	out.GeneratedAt = "2025-06-01T00:00:00Z"
+	doc, err := renderSLA(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"# Acme support commitments", "| Widgets | widget | 48 hours | 90% | 0.0% (0 of 1) | Not meeting |"} {
+		if !strings.Contains(doc, want) {
+			t.Errorf("page lacks %q:\n%s", want, doc)
+		}
+	}
+	for _, leak := range []string{"widget-internal", "site", "crash", "embargoed", "healthScore"} {
+		if strings.Contains(doc, leak) {
+			t.Errorf("page leaks %q:\n%s", leak, doc)
+		}
+	}
+}
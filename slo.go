@@ -0,0 +1,80 @@
+package main
+
+import (
+	"strings"
+	"time"
+)
+
+// slo is a per-label service level objective, e.g. security issues get a
+// maintainer response within 2 days and are resolved within 30.
+type slo struct {
+	Label             string `json:"label"`
+	FirstResponseDays int    `json:"firstResponseDays,omitempty"`
+	ResolutionDays    int    `json:"resolutionDays,omitempty"`
+}
+
+type sloBreach struct {
+	Number     int    `json:"number"`
+	Label      string `json:"label"`
+	Kind       string `json:"kind"` // "firstResponse" or "resolution"
+	TargetDays int    `json:"targetDays"`
+	ActualDays int    `json:"actualDays"`
+	Responded  bool   `json:"responded"`
+}
+
+// isMaintainer reports whether a comment's author association marks a
+// maintainer rather than a drive-by commenter.
+func isMaintainer(association string) bool {
+	switch strings.ToUpper(association) {
+	case "OWNER", "MEMBER", "COLLABORATOR":
+		return true
+	}
+	return false
+}
+
+// firstResponseAt returns when a maintainer other than the author first
+// commented on is, or the zero time if nobody has.
+func firstResponseAt(is issue) time.Time {
+	var first time.Time
+	for _, c := range is.Comments {
+		if c.Author.Login == is.Author.Login || !isMaintainer(c.AuthorAssociation) {
+			continue
+		}
+		if first.IsZero() || c.CreatedAt.Before(first) {
+			first = c.CreatedAt
+		}
+	}
+	return first
+}
+
+// computeSLOBreaches checks every open issue carrying an SLO label. An issue
+// still waiting on a response counts as breached once its age exceeds the
+// target; open issues are unresolved by definition. Time when every assignee
+// was out of office doesn't count towards the first response.
+func computeSLOBreaches(issues []issue, cfg config, now time.Time) []sloBreach {
+	var breaches []sloBreach
+	for _, s := range cfg.SLOs {
+		m := cfg.labelMatcher(s.Label)
+		for _, is := range issues {
+			if !m.any(is) {
+				continue
+			}
+			age := daysBetween(is.CreatedAt, now)
+			if s.FirstResponseDays > 0 {
+				responded := firstResponseAt(is)
+				until := now
+				if !responded.IsZero() {
+					until = responded
+				}
+				waited := daysBetween(is.CreatedAt.Add(cfg.away.awayDuring(is.Assignees, is.CreatedAt, until)), until)
+				if waited > s.FirstResponseDays {
+					breaches = append(breaches, sloBreach{Number: is.Number, Label: s.Label, Kind: "firstResponse", TargetDays: s.FirstResponseDays, ActualDays: waited, Responded: !responded.IsZero()})
+				}
+			}
+			if s.ResolutionDays > 0 && age > s.ResolutionDays {
+				breaches = append(breaches, sloBreach{Number: is.Number, Label: s.Label, Kind: "resolution", TargetDays: s.ResolutionDays, ActualDays: age})
+			}
+		}
+	}
+	return breaches
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestComputeSLOBreaches(t *testing.T) {
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	author := actor{Login: "reporter"}
+	issues := []issue{
+		// No response after 5 days, within resolution.
+		{Number: 1, Author: author, CreatedAt: now.AddDate(0, 0, -5), Labels: []label{{Name: "security"}}},
+		// Answered by a maintainer on day 1; the reporter's own comment doesn't count.
+		{Number: 2, Author: author, CreatedAt: now.AddDate(0, 0, -10), Labels: []label{{Name: "Security"}}, Comments: []comment{
+			{Author: author, AuthorAssociation: "OWNER", CreatedAt: now.AddDate(0, 0, -10)},
+			{Author: actor{Login: "maint"}, AuthorAssociation: "MEMBER", CreatedAt: now.AddDate(0, 0, -9)},
+		}},
+		// Answered late and open past resolution.
+		{Number: 3, Author: author, CreatedAt: now.AddDate(0, 0, -40), Labels: []label{{Name: "security"}}, Comments: []comment{
+			{Author: actor{Login: "maint"}, AuthorAssociation: "COLLABORATOR", CreatedAt: now.AddDate(0, 0, -35)},
+		}},
+		// Unlabeled issues are outside the SLO.
+		{Number: 4, Author: author, CreatedAt: now.AddDate(0, 0, -100)},
+	}
+	got := computeSLOBreaches(issues, config{SLOs: []slo{{Label: "security", FirstResponseDays: 2, ResolutionDays: 30}}}, now)
+	want := []sloBreach{
+		{Number: 1, Label: "security", Kind: "firstResponse", TargetDays: 2, ActualDays: 5},
+		{Number: 3, Label: "security", Kind: "firstResponse", TargetDays: 2, ActualDays: 5, Responded: true},
+		{Number: 3, Label: "security", Kind: "resolution", TargetDays: 30, ActualDays: 40},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d breaches %+v, want %d", len(got), got, len(want))
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("breach %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,135 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// sqliteStorage keeps runs in a SQLite database through the sqlite3 CLI, the
+// same way GitHub is reached through gh, so the binary stays dependency-free.
+type sqliteStorage struct {
+	path string
+	keep int
+	run  func(string, ...string) ([]byte, error)
+}
+
+const sqliteSchema = `CREATE TABLE IF NOT EXISTS runs (
+  id TEXT NOT NULL,
+  generated_at TEXT NOT NULL,
+  summary TEXT NOT NULL,
+  report TEXT NOT NULL,
+  history TEXT NOT NULL
+);
+`
+
+func sqlQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+// exec runs a script through .read so large reports don't hit argument
+// length limits.
+func (s *sqliteStorage) exec(script string) error {
+	path, err := tempFile("fab-backlog-*.sql", []byte(sqliteSchema+script))
+	if err != nil {
+		return err
+	}
+	defer os.Remove(path)
+	_, err = s.run("sqlite3", s.path, ".read "+path)
+	return err
+}
+
+func (s *sqliteStorage) query(sql string, v any) error {
+	if err := s.exec(""); err != nil {
+		return err
+	}
+	stdout, err := s.run("sqlite3", "-json", s.path, sql)
+	if err != nil {
+		return err
+	}
+	if strings.TrimSpace(string(stdout)) == "" {
+		return nil // sqlite3 prints nothing for an empty result
+	}
+	if err := json.Unmarshal(stdout, v); err != nil {
+		return fmt.Errorf("parse sqlite3 json: %w", err)
+	}
+	return nil
+}
+
+func (s *sqliteStorage) SaveRun(r storedRun) error {
+	sum, err := json.Marshal(r.Report.Summary)
+	if err != nil {
+		return err
+	}
+	report, err := json.Marshal(r.Report)
+	if err != nil {
+		return err
+	}
+	hist, err := json.Marshal(r.History)
+	if err != nil {
+		return err
+	}
+	script := fmt.Sprintf("INSERT INTO runs (id, generated_at, summary, report, history) VALUES (%s, %s, %s, %s, %s);\n",
+		sqlQuote(r.ID), sqlQuote(r.GeneratedAt.Format(time.RFC3339)), sqlQuote(string(sum)), sqlQuote(string(report)), sqlQuote(string(hist)))
+	if s.keep > 0 {
+		script += "DELETE FROM runs WHERE rowid NOT IN (SELECT rowid FROM runs ORDER BY rowid DESC LIMIT " + strconv.Itoa(s.keep) + ");\n"
+	}
+	return s.exec(script)
+}
+
+func (s *sqliteStorage) ListRuns() ([]runInfo, error) {
+	var rows []struct {
+		ID          string `json:"id"`
+		GeneratedAt string `json:"generated_at"`
+		Summary     string `json:"summary"`
+	}
+	if err := s.query("SELECT id, generated_at, summary FROM runs ORDER BY rowid DESC", &rows); err != nil {
+		return nil, err
+	}
+	infos := make([]runInfo, 0, len(rows))
+	for _, row := range rows {
+		info := runInfo{ID: row.ID}
+		var err error
+		if info.GeneratedAt, err = time.Parse(time.RFC3339, row.GeneratedAt); err != nil {
+			return nil, fmt.Errorf("run %s: %w", row.ID, err)
+		}
+		if err := json.Unmarshal([]byte(row.Summary), &info.Summary); err != nil {
+			return nil, fmt.Errorf("run %s: %w", row.ID, err)
+		}
+		infos = append(infos, info)
+	}
+	return infos, nil
+}
+
+func (s *sqliteStorage) GetRun(id string) (storedRun, error) {
+	var rows []struct {
+		GeneratedAt string `json:"generated_at"`
+		Report      string `json:"report"`
+		History     string `json:"history"`
+	}
+	if err := s.query("SELECT generated_at, report, history FROM runs WHERE id = "+sqlQuote(id)+" ORDER BY rowid DESC LIMIT 1", &rows); err != nil {
+		return storedRun{}, err
+	}
+	if len(rows) == 0 {
+		return storedRun{}, fmt.Errorf("%w: %s", errRunNotFound, id)
+	}
+	r := storedRun{ID: id}
+	var err error
+	if r.GeneratedAt, err = time.Parse(time.RFC3339, rows[0].GeneratedAt); err != nil {
+		return r, fmt.Errorf("run %s: %w", id, err)
+	}
+	if err := json.Unmarshal([]byte(rows[0].Report), &r.Report); err != nil {
+		return r, fmt.Errorf("run %s: %w", id, err)
+	}
+	if err := json.Unmarshal([]byte(rows[0].History), &r.History); err != nil {
+		return r, fmt.Errorf("run %s: %w", id, err)
+	}
+	return r, nil
+}
+
+func (s *sqliteStorage) Diff(fromID, toID string) (runDiff, error) {
+	return diffStored(s, fromID, toID)
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// titleMatcher matches issue titles against staleExcludeTitles, the
+// well-known long-lived issue styles — trackers, RFCs, roadmaps — whose
+// quiet isn't rot. A pattern is one of:
+//
+//	[tracking]   keyword, anywhere in the title, case-insensitive
+//	/^RFC:/      regular expression, case-insensitive
+type titleMatcher []*regexp.Regexp
+
+func newTitleMatcher(patterns []string) (titleMatcher, error) {
+	var m titleMatcher
+	for _, p := range patterns {
+		if strings.TrimSpace(p) == "" {
+			return nil, fmt.Errorf("staleExcludeTitles: empty pattern would match every title")
+		}
+		body := regexp.QuoteMeta(p)
+		if len(p) >= 2 && strings.HasPrefix(p, "/") && strings.HasSuffix(p, "/") {
+			body = p[1 : len(p)-1]
+		}
+		re, err := regexp.Compile("(?i)" + body)
+		if err != nil {
+			return nil, fmt.Errorf("title pattern %s: %w", p, err)
+		}
+		m = append(m, re)
+	}
+	return m, nil
+}
+
+func (m titleMatcher) match(title string) bool {
+	for _, re := range m {
+		if re.MatchString(title) {
+			return true
+		}
+	}
+	return false
+}
+
+// neverStale returns whether an issue's title keeps it out of staleness,
+// or nil when the config excludes no titles. The patterns were checked by
+// validate.
+func (c config) neverStale() func(issue) bool {
+	m, _ := newTitleMatcher(c.StaleExcludeTitles)
+	if len(m) == 0 {
+		return nil
+	}
+	return func(is issue) bool { return m.match(is.Title) }
+}
+
+// matchingNumbers returns the sorted numbers of the issues pred picks.
+func matchingNumbers(issues []issue, pred func(issue) bool) []int {
+	if pred == nil {
+		return nil
+	}
+	var out []int
+	for _, is := range issues {
+		if pred(is) {
+			out = append(out, is.Number)
+		}
+	}
+	slices.Sort(out)
+	return out
+}
+
+// staleCandidates is the repo's open issues less the ones excluded from
+// staleness by title: those are never labeled, warned or closed as stale.
+func (rs repoScore) staleCandidates() []issue {
+	if len(rs.StaleExempt) == 0 {
+		return rs.issues
+	}
+	var out []issue
+	for _, is := range rs.issues {
+		if !slices.Contains(rs.StaleExempt, is.Number) {
+			out = append(out, is)
+		}
+	}
+	return out
+}
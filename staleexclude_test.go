@@ -0,0 +1,68 @@
+package main
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestTitleMatcher(t *testing.T) {
+	m, err := newTitleMatcher([]string{"[tracking]", "/^rfc:/"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for title, want := range map[string]bool{
+		"[Tracking] v2 migration": true,
+		"RFC: plugin API":         true,
+		"Comment on the RFC: API": false,
+		"tracking down a leak":    false,
+	} {
+		if got := m.match(title); got != want {
+			t.Errorf("match(%q) = %v, want %v", title, got, want)
+		}
+	}
+	for _, bad := range [][]string{{"/(/"}, {" "}} {
+		if _, err := newTitleMatcher(bad); err == nil {
+			t.Errorf("%q: want error", bad)
+		}
+	}
+	if err := (config{StaleExcludeTitles: []string{"/[/"}}).validate(); err == nil {
+		t.Error("config with a bad title pattern validated")
+	}
+}
+
+func TestStaleExcludeTitles(t *testing.T) {
+	gh := newFakeGitHub(t)
+	now := time.Now()
+	old := now.AddDate(0, 0, -200)
+	issues := func() []*fakeIssue {
+		return []*fakeIssue{
+			{Number: 1, Title: "[tracking] Q3 roadmap", State: "open", CreatedAt: old, UpdatedAt: old},
+			{Number: 2, Title: "RFC: new config format", State: "open", CreatedAt: old, UpdatedAt: old},
+			{Number: 3, Title: "crash on start", State: "open", CreatedAt: old, UpdatedAt: old},
+		}
+	}
+	gh.addRepo(&fakeRepo{Owner: "acme", Name: "api", Issues: issues()})
+	gh.addRepo(&fakeRepo{Owner: "acme", Name: "web", Issues: issues(), Files: map[string]string{repoConfigPath: "staleExcludeTitles: [\"/^RFC:/\"]\n"}})
+
+	cfg := config{MinIssues: 1, StaleDays: 90, Deep: true, StaleExcludeTitles: []string{"[tracking]"}}
+	out := scan(t.Context(), selection{Orgs: []string{"acme"}}, []string{"api", "web"}, cfg)
+	want := map[string]struct {
+		stale  int
+		exempt []int
+	}{"api": {2, []int{1}}, "web": {1, []int{1, 2}}}
+	for _, rs := range out.Repos {
+		w := want[rs.Name]
+		if rs.TotalOpen != 3 || rs.StaleCount != w.stale || !slices.Equal(rs.StaleExempt, w.exempt) {
+			t.Errorf("%s: open %d, stale %d, exempt %v; want 3, %d, %v", rs.Name, rs.TotalOpen, rs.StaleCount, rs.StaleExempt, w.stale, w.exempt)
+		}
+		for _, d := range rs.Deep.Issues {
+			if d.Stale == slices.Contains(w.exempt, d.Number) {
+				t.Errorf("%s#%d: deep stale = %v", rs.Name, d.Number, d.Stale)
+			}
+		}
+		if got := staleNumbers(rs.staleCandidates(), cfg.StaleDays, now); len(got) != w.stale {
+			t.Errorf("%s: stale candidates %v", rs.Name, got)
+		}
+	}
+}
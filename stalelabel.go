@@ -0,0 +1,152 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"flag"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	ghscan "github.com/misty-step/fab-backlog/pkg/scan"
+)
+
+var applyStaleLabel = flag.String("apply-stale-label", "", "add this label to stale issues and remove it from ones active again (honours -dry-run)")
+
+// stalePlanSource marks the plan actions that -apply-stale-label applies.
+const stalePlanSource = "stale-label"
+
+// staleLabelQuery finds when each open issue carrying the label was last
+// labeled. Labeling bumps updatedAt, so without this the bot's own label
+// would look like fresh activity and be removed on the next run.
+const staleLabelQuery = `query($q: String!, $endCursor: String) {
+  search(query: $q, type: ISSUE, first: 100, after: $endCursor) {
+    pageInfo { hasNextPage endCursor }
+    nodes {
+      ... on Issue {
+        number
+        timelineItems(itemTypes: [LABELED_EVENT], last: 1) {
+          nodes { ... on LabeledEvent { createdAt } }
+        }
+      }
+    }
+  }
+}`
+
+type labeledAtPage struct {
+	Search struct {
+		Nodes []*struct {
+			Number        int `json:"number"`
+			TimelineItems *struct {
+				Nodes []struct {
+					CreatedAt time.Time `json:"createdAt"`
+				} `json:"nodes"`
+			} `json:"timelineItems"`
+		} `json:"nodes"`
+	} `json:"search"`
+}
+
+func ghLabeledAt(ctx context.Context, owner, repo, name string) (map[int]time.Time, []ghscan.GraphQLError, error) {
+	q := fmt.Sprintf("repo:%s/%s is:issue is:open label:%s", owner, repo, strconv.Quote(name))
+	pages, errs, err := ghscan.GraphQL[labeledAtPage](ctx, runCmd, staleLabelQuery, map[string]string{"q": q})
+	if err != nil {
+		return nil, errs, err
+	}
+	at := map[int]time.Time{}
+	for _, page := range pages {
+		for _, n := range page.Search.Nodes {
+			if n != nil && n.TimelineItems != nil && len(n.TimelineItems.Nodes) > 0 {
+				at[n.Number] = n.TimelineItems.Nodes[0].CreatedAt
+			}
+		}
+	}
+	return at, errs, nil
+}
+
+// withoutStaleLabel strips the -apply-stale-label label from issues for
+// scoring: fab-backlog's own marking isn't triage, so a stale issue it
+// labeled still counts as unlabeled. Issues are copied only if they carry it.
+func withoutStaleLabel(issues []issue) []issue {
+	name := *applyStaleLabel
+	var out []issue
+	for i, is := range issues {
+		if name == "" || !hasAnyLabel(is, []string{name}) {
+			continue
+		}
+		if out == nil {
+			out = slices.Clone(issues)
+		}
+		out[i].Labels = slices.DeleteFunc(slices.Clone(is.Labels), func(l label) bool { return strings.EqualFold(l.Name, name) })
+	}
+	if out == nil {
+		return issues
+	}
+	return out
+}
+
+// staleLabelActions labels stale issues and unlabels ones updated since
+// they were labeled. An issue whose labeling time is unknown keeps the label.
+func staleLabelActions(issues []issue, name string, staleDays int, labeledAt map[int]time.Time, now time.Time) []triageItem {
+	cutoff := now.AddDate(0, 0, -staleDays)
+	var items []triageItem
+	for _, is := range issues {
+		has := false
+		for _, l := range is.Labels {
+			if strings.EqualFold(l.Name, name) {
+				has = true
+			}
+		}
+		stale := is.UpdatedAt.Before(cutoff)
+		switch {
+		case stale && !has:
+			items = append(items, triageItem{Number: is.Number, Title: is.Title, Action: "addLabel", Label: name, Source: stalePlanSource,
+				Reasons: []string{fmt.Sprintf("no activity for %d days", daysBetween(is.UpdatedAt, now))}})
+		case !stale && has:
+			at, ok := labeledAt[is.Number]
+			// Allow a little slack: the labeling itself sets updatedAt.
+			if !ok || !is.UpdatedAt.After(at.Add(time.Minute)) {
+				continue
+			}
+			items = append(items, triageItem{Number: is.Number, Title: is.Title, Action: "removeLabel", Label: name, Source: stalePlanSource,
+				Reasons: []string{fmt.Sprintf("active again %d days after being labeled", daysBetween(at, is.UpdatedAt))}})
+		}
+	}
+	return items
+}
+
+func addStaleLabelActions(ctx context.Context, score *repoScore, name string, cfg config, now time.Time) {
+	owner, repo := splitRepo("", score.fullName)
+	labeledAt, errs, err := ghLabeledAt(ctx, owner, repo, name)
+	if err != nil {
+		slog.Warn("stale label history unavailable; not removing labels", "repo", score.fullName, "error", err)
+	}
+	// Issues whose labeling time didn't come back keep the label.
+	addAPIErrors(score, "staleLabel", errs)
+	// Repos with their own staleDays are labeled on their own clock.
+	staleDays := cmp.Or(score.staleDays, cfg.StaleDays)
+	score.TriageQueue = append(score.TriageQueue, staleLabelActions(score.staleCandidates(), name, staleDays, labeledAt, now)...)
+}
+
+// runStaleLabeling queues and applies the stale-label actions for every
+// scanned repo, and returns what was (or, with -dry-run, would be) done.
+func runStaleLabeling(ctx context.Context, out *output, cfg config, name string, dryRun bool, now time.Time) []expectation {
+	for i := range out.Repos {
+		if out.Repos[i].Error == "" && out.Repos[i].Source == "" {
+			addStaleLabelActions(ctx, &out.Repos[i], name, cfg, now)
+		}
+	}
+	p := buildPlan(*out, cfg, now)
+	var actions []planAction
+	for _, a := range p.Actions {
+		if a.Source == stalePlanSource {
+			actions = append(actions, a)
+		}
+	}
+	p.Actions = actions
+	results, exps := applyPlan(p, dryRun, runBackground, now)
+	out.Applied = results
+	return exps
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestStaleLabelActions(t *testing.T) {
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	old := now.AddDate(0, 0, -200)
+	labeled := now.AddDate(0, 0, -30)
+	stale := []label{{Name: "Stale"}}
+	issues := []issue{
+		{Number: 1, UpdatedAt: old},                                          // stale: label it
+		{Number: 2, UpdatedAt: old, Labels: stale},                           // already labeled
+		{Number: 3, UpdatedAt: labeled.Add(30 * time.Second), Labels: stale}, // only our own labeling
+		{Number: 4, UpdatedAt: now.AddDate(0, 0, -2), Labels: stale},         // commented since
+		{Number: 5, UpdatedAt: now.AddDate(0, 0, -2), Labels: stale},         // labeling time unknown
+		{Number: 6, UpdatedAt: now},                                          // active, unlabeled
+	}
+	at := map[int]time.Time{2: old, 3: labeled, 4: labeled}
+	items := staleLabelActions(issues, "stale", 90, at, now)
+	if len(items) != 2 {
+		t.Fatalf("items = %+v", items)
+	}
+	if items[0].Number != 1 || items[0].Action != "addLabel" || items[0].Label != "stale" || items[0].Source != stalePlanSource {
+		t.Errorf("add = %+v", items[0])
+	}
+	if items[1].Number != 4 || items[1].Action != "removeLabel" {
+		t.Errorf("remove = %+v", items[1])
+	}
+}
+
+func TestRunStaleLabelingAgainstFake(t *testing.T) {
+	gh := newFakeGitHub(t)
+	now := time.Now()
+	old := now.AddDate(-1, 0, 0)
+	gh.addRepo(&fakeRepo{Owner: "acme", Name: "api", Issues: []*fakeIssue{
+		{Number: 1, State: "open", CreatedAt: old, UpdatedAt: old, Labels: []string{"bug"}},
+		{Number: 2, State: "open", CreatedAt: old, UpdatedAt: now, Labels: []string{"bug"}},
+	}})
+	cfg := config{MinIssues: 1, StaleDays: 90}
+	out := scan(t.Context(), selection{Orgs: []string{"acme"}}, []string{"acme/api"}, cfg)
+
+	if exps := runStaleLabeling(t.Context(), &out, cfg, "stale", true, now); len(exps) != 0 || len(gh.writeLog()) != 0 {
+		t.Fatalf("dry run wrote %v", gh.writeLog())
+	}
+	if len(out.Applied) != 1 || out.Applied[0].Status != "planned" {
+		t.Fatalf("dry run results = %+v", out.Applied)
+	}
+
+	out = scan(t.Context(), selection{Orgs: []string{"acme"}}, []string{"acme/api"}, cfg)
+	exps := runStaleLabeling(t.Context(), &out, cfg, "stale", false, now)
+	if len(exps) != 1 || exps[0].Kind != "labelPresent" || exps[0].Number != 1 {
+		t.Errorf("expectations = %+v", exps)
+	}
+	if got := gh.issue("acme/api", 1).Labels; len(got) != 2 || got[1] != "stale" {
+		t.Errorf("labels = %v", got)
+	}
+}
+
+func TestRunStaleLabelingUsesRepoStaleDays(t *testing.T) {
+	gh := newFakeGitHub(t)
+	now := time.Now()
+	old := now.AddDate(-1, 0, 0)
+	gh.addRepo(&fakeRepo{Owner: "acme", Name: "api", Issues: []*fakeIssue{
+		{Number: 1, State: "open", CreatedAt: old, UpdatedAt: old, Labels: []string{"bug"}},
+	}, Files: map[string]string{repoConfigPath: "staleDays: 400\n"}})
+	cfg := config{MinIssues: 1, StaleDays: 90}
+	out := scan(t.Context(), selection{Orgs: []string{"acme"}}, []string{"acme/api"}, cfg)
+
+	runStaleLabeling(t.Context(), &out, cfg, "stale", true, now)
+	if len(out.Applied) != 0 {
+		t.Errorf("a year-old issue is fresh under the repo's 400 days; results = %+v", out.Applied)
+	}
+}
+
+func TestStaleLabelDoesNotCountAsTriage(t *testing.T) {
+	prev := *applyStaleLabel
+	t.Cleanup(func() { *applyStaleLabel = prev })
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	old := now.AddDate(0, 0, -200)
+	issues := []issue{
+		{Number: 1, CreatedAt: old, UpdatedAt: old, Labels: []label{{Name: "Stale"}}},
+		{Number: 2, CreatedAt: old, UpdatedAt: old, Labels: []label{{Name: "stale"}, {Name: "bug"}}},
+	}
+	cfg := config{MinIssues: 1, StaleDays: 90}
+	if rs := scoreIssues("api", issues, cfg, now); rs.UnlabeledCount != 0 {
+		t.Errorf("without -apply-stale-label: unlabeled %d, want 0", rs.UnlabeledCount)
+	}
+	*applyStaleLabel = "stale"
+	if rs := scoreIssues("api", issues, cfg, now); rs.UnlabeledCount != 1 {
+		t.Errorf("unlabeled %d, want 1: the stale label isn't triage", rs.UnlabeledCount)
+	}
+	if len(issues[0].Labels) != 1 {
+		t.Error("the caller's issues should keep their labels")
+	}
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// stalePolicy warns on issues idle past WarnAfterDays and closes them once
+// they reach CloseAfterDays without activity since the warning. Its actions
+// only ever land in the triage queue: they reach GitHub through a reviewed
+// -plan-out file and `apply`, never directly from a scan.
+type stalePolicy struct {
+	WarnAfterDays  int    `json:"warnAfterDays,omitempty"` // default: staleDays
+	CloseAfterDays int    `json:"closeAfterDays"`
+	Comment        string `json:"comment,omitempty"`
+	CloseComment   string `json:"closeComment,omitempty"`
+	// A repo opts out by defining this label.
+	OptOutLabel string `json:"optOutLabel,omitempty"`
+}
+
+const (
+	stalePolicySource   = "stale-policy"
+	staleMarker         = "<!-- fab-backlog:stale -->"
+	defaultOptOutLabel  = "fab-backlog:no-stale"
+	defaultStaleComment = "This issue has had no activity for a while and will be closed soon. Comment to keep it open."
+	defaultCloseComment = "Closing after no activity since the stale warning. Comment if this is still relevant and it can be reopened."
+)
+
+func (p stalePolicy) warnDays(staleDays int) int {
+	if p.WarnAfterDays > 0 {
+		return p.WarnAfterDays
+	}
+	return staleDays
+}
+
+func (p stalePolicy) optOutLabel() string {
+	if p.OptOutLabel != "" {
+		return p.OptOutLabel
+	}
+	return defaultOptOutLabel
+}
+
+func (p *stalePolicy) validate(staleDays int) error {
+	if p.CloseAfterDays <= p.warnDays(staleDays) {
+		return fmt.Errorf("stalePolicy: closeAfterDays must be greater than warnAfterDays (default staleDays)")
+	}
+	return nil
+}
+
+func orDefault(s, def string) string {
+	if s == "" {
+		return def
+	}
+	return s
+}
+
+// stalePolicyActions decides per issue. The warning comment carries a
+// marker so later runs can find it. Any activity after the warning — a
+// comment by someone else or another update — cancels it, and the issue
+// starts over.
+func stalePolicyActions(issues []issue, p stalePolicy, staleDays int, now time.Time) []triageItem {
+	warnDays := p.warnDays(staleDays)
+	grace := p.CloseAfterDays - warnDays
+	var items []triageItem
+	for _, is := range issues {
+		var warning *comment
+		for i := range is.Comments {
+			if strings.Contains(is.Comments[i].Body, staleMarker) {
+				warning = &is.Comments[i]
+			}
+		}
+		if warning != nil {
+			active := is.UpdatedAt.After(warning.CreatedAt.Add(time.Minute))
+			for _, c := range is.Comments {
+				if c.CreatedAt.After(warning.CreatedAt) && !strings.Contains(c.Body, staleMarker) {
+					active = true
+				}
+			}
+			if !active {
+				if waited := daysBetween(warning.CreatedAt, now); waited >= grace {
+					items = append(items, triageItem{Number: is.Number, Title: is.Title, Action: "close", Comment: orDefault(p.CloseComment, defaultCloseComment), Source: stalePolicySource,
+						Reasons: []string{fmt.Sprintf("no activity for %d days since the stale warning", waited)}})
+				}
+				continue
+			}
+		}
+		if idle := daysBetween(is.UpdatedAt, now); idle >= warnDays {
+			items = append(items, triageItem{Number: is.Number, Title: is.Title, Action: "comment", Comment: orDefault(p.Comment, defaultStaleComment) + "\n\n" + staleMarker, Source: stalePolicySource,
+				Reasons: []string{fmt.Sprintf("no activity for %d days", idle)}})
+		}
+	}
+	return items
+}
+
+func ghHasLabel(ctx context.Context, owner, repo, name string) (bool, error) {
+	_, err := runCmd(ctx, "gh", "api", "--silent", fmt.Sprintf("repos/%s/%s/labels/%s", owner, repo, url.PathEscape(name)))
+	if err != nil && strings.Contains(err.Error(), "HTTP 404") {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func addStalePolicy(ctx context.Context, score *repoScore, owner, repo string, cfg config, now time.Time) {
+	p := cfg.StalePolicy
+	if p == nil || len(score.issues) == 0 {
+		return
+	}
+	optedOut, err := ghHasLabel(ctx, owner, repo, p.optOutLabel())
+	if err != nil {
+		slog.Warn("stale policy skipped: opt-out label check failed", "repo", repo, "error", err)
+		return
+	}
+	if optedOut {
+		slog.Info("stale policy opted out", "repo", repo, "label", p.optOutLabel())
+		return
+	}
+	score.TriageQueue = append(score.TriageQueue, stalePolicyActions(score.staleCandidates(), *p, cfg.StaleDays, now)...)
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestStalePolicyActions(t *testing.T) {
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	days := func(n int) time.Time { return now.AddDate(0, 0, -n) }
+	warned := comment{Body: "stale!\n\n" + staleMarker, CreatedAt: days(40)}
+	issues := []issue{
+		{Number: 1, UpdatedAt: days(100)},                                                                        // warn
+		{Number: 2, UpdatedAt: days(10)},                                                                         // active
+		{Number: 3, UpdatedAt: warned.CreatedAt, Comments: []comment{warned}},                                    // close
+		{Number: 4, UpdatedAt: days(5), Comments: []comment{warned, {Body: "still broken", CreatedAt: days(5)}}}, // revived
+		{Number: 5, UpdatedAt: days(10), Comments: []comment{{Body: staleMarker, CreatedAt: days(10)}}},          // warned, within grace
+		{Number: 6, UpdatedAt: days(95), Comments: []comment{{Body: staleMarker, CreatedAt: days(200)}}},         // revived long ago, idle again
+	}
+	p := stalePolicy{CloseAfterDays: 120}
+	got := map[int]string{}
+	for _, it := range stalePolicyActions(issues, p, 90, now) {
+		got[it.Number] = it.Action
+		if it.Source != stalePolicySource || it.Comment == "" {
+			t.Errorf("item = %+v", it)
+		}
+	}
+	want := map[int]string{1: "comment", 3: "close", 6: "comment"}
+	if len(got) != len(want) {
+		t.Fatalf("actions = %v, want %v", got, want)
+	}
+	for n, a := range want {
+		if got[n] != a {
+			t.Errorf("#%d: %q, want %q", n, got[n], a)
+		}
+	}
+	if err := (&stalePolicy{CloseAfterDays: 60}).validate(90); err == nil {
+		t.Error("closeAfterDays below warnAfterDays should be rejected")
+	}
+}
@@ -0,0 +1,276 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Storage keeps past runs for the features that compare against them:
+// escalation, paging, smoothing and observe mode read the history saved with
+// the latest run. Drivers are chosen with the config's storage section.
+type Storage interface {
+	SaveRun(r storedRun) error
+	// ListRuns returns every stored run, newest first.
+	ListRuns() ([]runInfo, error)
+	GetRun(id string) (storedRun, error)
+	// Diff compares two stored runs repo by repo.
+	Diff(fromID, toID string) (runDiff, error)
+}
+
+// storageConfig selects a driver. memory lasts for the life of the process
+// (useful for serve); file keeps every run in one JSON file, easy to carry
+// between CI jobs as an artifact or cache; sqlite uses the sqlite3 CLI.
+type storageConfig struct {
+	Driver string `json:"driver"`
+	Path   string `json:"path,omitempty"`
+	Keep   int    `json:"keep,omitempty"` // newest runs to retain; 0 keeps all
+}
+
+func (s *storageConfig) validate() error {
+	switch s.Driver {
+	case "memory":
+		return nil
+	case "file", "sqlite":
+		if s.Path == "" {
+			return fmt.Errorf("storage: %s driver needs a path", s.Driver)
+		}
+		return nil
+	}
+	return fmt.Errorf("storage: unknown driver %q (want memory, file or sqlite)", s.Driver)
+}
+
+func openStorage(s *storageConfig) Storage {
+	switch s.Driver {
+	case "file":
+		return &fileStorage{path: s.Path, keep: s.Keep}
+	case "sqlite":
+		return &sqliteStorage{path: s.Path, keep: s.Keep, run: runBackground}
+	default:
+		return &memoryStorage{keep: s.Keep}
+	}
+}
+
+// storedRun is one run's report plus the cross-run state after it.
+type storedRun struct {
+	ID          string    `json:"id"`
+	GeneratedAt time.Time `json:"generatedAt"`
+	Report      output    `json:"report"`
+	History     history   `json:"history"`
+}
+
+type runInfo struct {
+	ID          string    `json:"id"`
+	GeneratedAt time.Time `json:"generatedAt"`
+	Summary     summary   `json:"summary"`
+}
+
+func runID(at time.Time) string {
+	return at.UTC().Format("20060102T150405Z")
+}
+
+func newRun(out output, h *history, at time.Time) storedRun {
+	return storedRun{ID: runID(at), GeneratedAt: at.UTC(), Report: out, History: *h}
+}
+
+func (r storedRun) info() runInfo {
+	return runInfo{ID: r.ID, GeneratedAt: r.GeneratedAt, Summary: r.Report.Summary}
+}
+
+var errRunNotFound = errors.New("run not found")
+
+// latestHistory returns the history saved with the newest run, or an empty
+// one if nothing is stored yet.
+func latestHistory(s Storage) (*history, error) {
+	runs, err := s.ListRuns()
+	if err != nil {
+		return nil, err
+	}
+	h := &history{Repos: map[string]*repoHistory{}}
+	if len(runs) == 0 {
+		return h, nil
+	}
+	r, err := s.GetRun(runs[0].ID)
+	if err != nil {
+		return nil, err
+	}
+	if r.History.Repos != nil {
+		h.Repos = r.History.Repos
+	}
+	h.Policies = r.History.Policies
+	return h, nil
+}
+
+type repoDiff struct {
+	Repo       string `json:"repo"`
+	FromScore  int    `json:"fromScore"`
+	ToScore    int    `json:"toScore"`
+	Delta      int    `json:"delta"`
+	FromStatus string `json:"fromStatus"`
+	ToStatus   string `json:"toStatus"`
+}
+
+type runDiff struct {
+	From    string     `json:"from"`
+	To      string     `json:"to"`
+	Changed []repoDiff `json:"changed"`
+	Added   []string   `json:"added,omitempty"`
+	Removed []string   `json:"removed,omitempty"`
+}
+
+func diffStored(s Storage, fromID, toID string) (runDiff, error) {
+	from, err := s.GetRun(fromID)
+	if err != nil {
+		return runDiff{}, err
+	}
+	to, err := s.GetRun(toID)
+	if err != nil {
+		return runDiff{}, err
+	}
+	return diffReports(fromID, toID, from.Report, to.Report), nil
+}
+
+// diffReports lists repos whose score or status changed, worst drop first,
+// and repos that appear in only one report. Repos that failed to scan in
+// either report are skipped.
+func diffReports(fromID, toID string, from, to output) runDiff {
+	d := runDiff{From: fromID, To: toID, Changed: []repoDiff{}}
+	before := map[string]repoScore{}
+	for _, rs := range from.Repos {
+		before[rs.Name] = rs
+	}
+	for _, rs := range to.Repos {
+		old, ok := before[rs.Name]
+		delete(before, rs.Name)
+		if !ok {
+			d.Added = append(d.Added, rs.Name)
+			continue
+		}
+		if rs.Error != "" || old.Error != "" || (rs.HealthScore == old.HealthScore && rs.Status == old.Status) {
+			continue
+		}
+		d.Changed = append(d.Changed, repoDiff{Repo: rs.Name, FromScore: old.HealthScore, ToScore: rs.HealthScore, Delta: rs.HealthScore - old.HealthScore, FromStatus: old.Status, ToStatus: rs.Status})
+	}
+	for name := range before {
+		d.Removed = append(d.Removed, name)
+	}
+	sort.Slice(d.Changed, func(i, j int) bool { return d.Changed[i].Delta < d.Changed[j].Delta })
+	sort.Strings(d.Added)
+	sort.Strings(d.Removed)
+	return d
+}
+
+// runList is shared by the memory and file drivers; runs are oldest first.
+type runList []storedRun
+
+func (l runList) infos() []runInfo {
+	infos := make([]runInfo, 0, len(l))
+	for i := len(l) - 1; i >= 0; i-- {
+		infos = append(infos, l[i].info())
+	}
+	return infos
+}
+
+func (l runList) get(id string) (storedRun, error) {
+	for i := len(l) - 1; i >= 0; i-- {
+		if l[i].ID == id {
+			return l[i], nil
+		}
+	}
+	return storedRun{}, fmt.Errorf("%w: %s", errRunNotFound, id)
+}
+
+func (l runList) add(r storedRun, keep int) runList {
+	l = append(l, r)
+	if keep > 0 && len(l) > keep {
+		l = l[len(l)-keep:]
+	}
+	return l
+}
+
+type memoryStorage struct {
+	mu   sync.Mutex
+	keep int
+	runs runList
+}
+
+func (m *memoryStorage) SaveRun(r storedRun) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.runs = m.runs.add(r, m.keep)
+	return nil
+}
+
+func (m *memoryStorage) ListRuns() ([]runInfo, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.runs.infos(), nil
+}
+
+func (m *memoryStorage) GetRun(id string) (storedRun, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.runs.get(id)
+}
+
+func (m *memoryStorage) Diff(fromID, toID string) (runDiff, error) {
+	return diffStored(m, fromID, toID)
+}
+
+type fileStorage struct {
+	path string
+	keep int
+}
+
+type storageFile struct {
+	Runs runList `json:"runs"`
+}
+
+func (f *fileStorage) load() (runList, error) {
+	data, err := os.ReadFile(f.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read storage: %w", err)
+	}
+	var sf storageFile
+	if err := json.Unmarshal(data, &sf); err != nil {
+		return nil, fmt.Errorf("parse storage %s: %w", f.path, err)
+	}
+	return sf.Runs, nil
+}
+
+func (f *fileStorage) SaveRun(r storedRun) error {
+	runs, err := f.load()
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(storageFile{Runs: runs.add(r, f.keep)})
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(f.path, append(data, '\n'))
+}
+
+func (f *fileStorage) ListRuns() ([]runInfo, error) {
+	runs, err := f.load()
+	return runs.infos(), err
+}
+
+func (f *fileStorage) GetRun(id string) (storedRun, error) {
+	runs, err := f.load()
+	if err != nil {
+		return storedRun{}, err
+	}
+	return runs.get(id)
+}
+
+func (f *fileStorage) Diff(fromID, toID string) (runDiff, error) {
+	return diffStored(f, fromID, toID)
+}
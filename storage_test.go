@@ -0,0 +1,85 @@
+package main
+
+import (
+	"errors"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func testStorage(t *testing.T, s Storage) {
+	t0 := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	reports := [][]repoScore{
+		{{Name: "a", HealthScore: 90, Status: "healthy"}},
+		{{Name: "a", HealthScore: 80, Status: "healthy"}, {Name: "b", HealthScore: 50, Status: "warning"}},
+		{{Name: "a", HealthScore: 35, Status: "critical"}, {Name: "b", HealthScore: 50, Status: "warning"}, {Name: "c", HealthScore: 100, Status: "healthy"}},
+	}
+	for i, repos := range reports {
+		h := &history{Repos: map[string]*repoHistory{"org/a": {CriticalRuns: i}}, Policies: map[string]int{"gate": i}}
+		out := output{Org: "org", Repos: repos, Summary: summarize(repos)}
+		if err := s.SaveRun(newRun(out, h, t0.Add(time.Duration(i)*time.Hour))); err != nil {
+			t.Fatal(err)
+		}
+	}
+	runs, err := s.ListRuns()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(runs) != 2 || runs[0].ID != "20250601T020000Z" || runs[0].Summary.Total != 3 || !runs[1].GeneratedAt.Equal(t0.Add(time.Hour)) {
+		t.Fatalf("runs = %+v, want the newest two, newest first", runs)
+	}
+	if _, err := s.GetRun(runID(t0)); !errors.Is(err, errRunNotFound) {
+		t.Errorf("pruned run: err = %v", err)
+	}
+	h, err := latestHistory(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if h.Repos["org/a"].CriticalRuns != 2 || h.Policies["gate"] != 2 {
+		t.Errorf("latest history = %+v", h)
+	}
+	d, err := s.Diff(runs[1].ID, runs[0].ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(d.Changed) != 1 || d.Changed[0] != (repoDiff{"a", 80, 35, -45, "healthy", "critical"}) || len(d.Added) != 1 || d.Added[0] != "c" {
+		t.Errorf("diff = %+v", d)
+	}
+	if v, err := runsCommand(s, []string{"diff"}); err != nil || v.(runDiff).To != runs[0].ID {
+		t.Errorf("runs diff = %+v, %v", v, err)
+	}
+	if _, err := runsCommand(s, []string{"show"}); !errors.Is(err, errRunsUsage) {
+		t.Errorf("runs show without id: err = %v", err)
+	}
+}
+
+func TestMemoryStorage(t *testing.T) {
+	testStorage(t, openStorage(&storageConfig{Driver: "memory", Keep: 2}))
+}
+
+func TestFileStorage(t *testing.T) {
+	testStorage(t, openStorage(&storageConfig{Driver: "file", Path: filepath.Join(t.TempDir(), "runs.json"), Keep: 2}))
+}
+
+func TestSQLiteStorage(t *testing.T) {
+	if _, err := exec.LookPath("sqlite3"); err != nil {
+		t.Skip("sqlite3 not installed")
+	}
+	testStorage(t, openStorage(&storageConfig{Driver: "sqlite", Path: filepath.Join(t.TempDir(), "runs.db"), Keep: 2}))
+}
+
+func TestLatestHistoryEmpty(t *testing.T) {
+	h, err := latestHistory(&memoryStorage{})
+	if err != nil || h.Repos == nil || len(h.Repos) != 0 {
+		t.Errorf("empty storage: %+v, %v", h, err)
+	}
+}
+
+func TestStorageConfigValidate(t *testing.T) {
+	for _, s := range []storageConfig{{Driver: "postgres"}, {Driver: "file"}, {Driver: "sqlite"}} {
+		if err := s.validate(); err == nil {
+			t.Errorf("%+v should be rejected", s)
+		}
+	}
+}
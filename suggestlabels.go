@@ -0,0 +1,141 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"time"
+)
+
+var applySuggestions = flag.Bool("apply", false, "with suggest-labels, add the suggested labels (combine with -dry-run to preview)")
+
+const suggestSource = "suggest-labels"
+
+// labelRule suggests Label for unlabeled issues whose title or body
+// matches Pattern, a case-insensitive regular expression.
+type labelRule struct {
+	Pattern string `json:"pattern"`
+	Label   string `json:"label"`
+}
+
+func validateLabelRules(rules []labelRule) error {
+	for _, r := range rules {
+		if r.Label == "" {
+			return fmt.Errorf("labelRules: rule %q has no label", r.Pattern)
+		}
+		if _, err := regexp.Compile("(?i)" + r.Pattern); err != nil {
+			return fmt.Errorf("labelRules: %w", err)
+		}
+	}
+	return nil
+}
+
+// suggestLabels returns one addLabel item per unlabeled issue and matching
+// label; rules suggesting the same label are merged.
+func suggestLabels(issues []issue, rules []labelRule) []triageItem {
+	res := make([]*regexp.Regexp, len(rules))
+	for i, r := range rules {
+		res[i] = regexp.MustCompile("(?i)" + r.Pattern)
+	}
+	var items []triageItem
+	for _, is := range issues {
+		if len(is.Labels) > 0 {
+			continue
+		}
+		text := is.Title + "\n" + is.Body
+		byLabel := map[string]int{}
+		for i, r := range rules {
+			if !res[i].MatchString(text) {
+				continue
+			}
+			reason := fmt.Sprintf("matches /%s/", r.Pattern)
+			if j, ok := byLabel[r.Label]; ok {
+				items[j].Reasons = append(items[j].Reasons, reason)
+				continue
+			}
+			byLabel[r.Label] = len(items)
+			items = append(items, triageItem{Number: is.Number, Title: is.Title, Action: "addLabel", Label: r.Label, Source: suggestSource, Reasons: []string{reason}})
+		}
+	}
+	return items
+}
+
+// suggestLabelPlan fetches each repo's open issues and plans the suggested
+// labels. Repos that fail to fetch are returned in errs, keyed by name.
+func suggestLabelPlan(ctx context.Context, org string, repos []string, cfg config, now time.Time) (p plan, errs map[string]string) {
+	limit := issueLimit
+	if cfg.Deep {
+		limit = deepIssueLimit
+	}
+	out := output{Org: org}
+	for _, repo := range repos {
+		owner, name := splitRepo(org, repo)
+		issues, err := ghListIssues(ctx, owner, name, "number,title,body,labels,createdAt,updatedAt", limit)
+		if err != nil {
+			slog.Warn("failed to list issues", "repo", repo, "error", err)
+			if errs == nil {
+				errs = map[string]string{}
+			}
+			errs[owner+"/"+name] = err.Error()
+			continue
+		}
+		rs := scoreIssues(name, issues, cfg, now)
+		rs.fullName, rs.issues = owner+"/"+name, issues
+		rs.TriageQueue = suggestLabels(issues, cfg.LabelRules)
+		out.Repos = append(out.Repos, rs)
+	}
+	return buildPlan(out, cfg, now), errs
+}
+
+func runSuggestLabels(ctx context.Context, sel selection, positional []string, cfg config) int {
+	if len(cfg.LabelRules) == 0 {
+		slog.Error("suggest-labels requires labelRules in -config")
+		emitJSON(map[string]any{"ok": false, "error": "suggest-labels requires labelRules in -config"})
+		return 2
+	}
+	primary := sel.primaryOrg()
+	repos := dedupeRepos(primary, positional)
+	if len(repos) == 0 {
+		var err error
+		if repos, err = sel.resolve(ctx); err != nil {
+			slog.Error("failed to list repos", "orgs", sel.Orgs, "error", err)
+			emitJSON(map[string]any{"ok": false, "error": "failed to list repos: " + err.Error()})
+			return 1
+		}
+	}
+	now := clock()
+	p, errs := suggestLabelPlan(ctx, primary, repos, cfg, now)
+	slog.Info("labels suggested", "repos", len(repos), "suggestions", len(p.Actions))
+	if *planOut != "" {
+		if err := writePlan(*planOut, p); err != nil {
+			slog.Error("failed to write plan", "path", *planOut, "error", err)
+			return 1
+		}
+	}
+	report := map[string]any{"ok": len(errs) == 0, "org": primary, "suggestions": p.Actions}
+	if len(errs) > 0 {
+		report["errors"] = errs
+	}
+	if *applySuggestions {
+		results, exps := applyPlan(p, *dryRun, runBackground, now)
+		for _, r := range results {
+			if r.Status == "failed" {
+				report["ok"] = false
+			}
+		}
+		if *remediationLog != "" && len(exps) > 0 {
+			if err := recordExpectations(*remediationLog, exps); err != nil {
+				slog.Error("failed to record remediation expectations", "path", *remediationLog, "error", err)
+			}
+		}
+		report["dryRun"] = *dryRun
+		report["applied"] = results
+	}
+	emitJSON(report)
+	if report["ok"] == false {
+		return 1
+	}
+	return 0
+}
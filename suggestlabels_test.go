@@ -0,0 +1,58 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSuggestLabels(t *testing.T) {
+	rules := []labelRule{{Pattern: `panic|crash`, Label: "bug"}, {Pattern: `segfault`, Label: "bug"}, {Pattern: `\bdocs?\b`, Label: "documentation"}}
+	issues := []issue{
+		{Number: 1, Title: "Crash on start", Body: "segfault in main"},
+		{Number: 2, Title: "Typo in docs"},
+		{Number: 3, Title: "panic", Labels: []label{{Name: "triage"}}},
+		{Number: 4, Title: "Feature idea"},
+	}
+	items := suggestLabels(issues, rules)
+	if len(items) != 2 {
+		t.Fatalf("items = %+v", items)
+	}
+	if items[0].Number != 1 || items[0].Label != "bug" || len(items[0].Reasons) != 2 || items[0].Action != "addLabel" {
+		t.Errorf("items[0] = %+v", items[0])
+	}
+	if items[1].Number != 2 || items[1].Label != "documentation" {
+		t.Errorf("items[1] = %+v", items[1])
+	}
+	if err := validateLabelRules([]labelRule{{Pattern: "(", Label: "bug"}}); err == nil {
+		t.Error("invalid pattern should be rejected")
+	}
+	if err := validateLabelRules([]labelRule{{Pattern: "x"}}); err == nil {
+		t.Error("missing label should be rejected")
+	}
+}
+
+func TestSuggestLabelPlanApply(t *testing.T) {
+	gh := newFakeGitHub(t)
+	now := time.Now()
+	gh.addRepo(&fakeRepo{Owner: "acme", Name: "api", Issues: []*fakeIssue{
+		{Number: 1, State: "open", Title: "crash when saving", CreatedAt: now, UpdatedAt: now},
+		{Number: 2, State: "open", Title: "crash again", CreatedAt: now, UpdatedAt: now, Labels: []string{"bug"}},
+	}})
+	cfg := config{MinIssues: 1, StaleDays: 90, LabelRules: []labelRule{{Pattern: "crash", Label: "bug"}}}
+	p, errs := suggestLabelPlan(t.Context(), "acme", []string{"api", "missing"}, cfg, now)
+	if len(p.Actions) != 1 || p.Actions[0].Repo != "acme/api" || p.Actions[0].Source != suggestSource {
+		t.Fatalf("actions = %+v", p.Actions)
+	}
+	if errs["acme/missing"] == "" {
+		t.Errorf("errs = %v", errs)
+	}
+	if p.Actions[0].ExpectedScoreImpact <= 0 {
+		t.Errorf("labeling an unlabeled issue should help, impact %d", p.Actions[0].ExpectedScoreImpact)
+	}
+	if _, exps := applyPlan(p, false, runBackground, now); len(exps) != 1 {
+		t.Errorf("expectations = %+v", exps)
+	}
+	if got := gh.issue("acme/api", 1).Labels; len(got) != 1 || got[0] != "bug" {
+		t.Errorf("labels = %v", got)
+	}
+}
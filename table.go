@@ -0,0 +1,133 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"unicode/utf8"
+)
+
+// ANSI colours for the terminal table.
+const (
+	ansiReset  = "\x1b[0m"
+	ansiBold   = "\x1b[1m"
+	ansiRed    = "\x1b[31m"
+	ansiGreen  = "\x1b[32m"
+	ansiYellow = "\x1b[33m"
+	ansiDim    = "\x1b[2m"
+)
+
+var statusColors = map[string]string{"healthy": ansiGreen, "warning": ansiYellow, "critical": ansiRed}
+
+// stdoutIsTerminal reports whether stdout is an interactive terminal rather
+// than a pipe or file.
+func stdoutIsTerminal() bool {
+	fi, err := os.Stdout.Stat()
+	return err == nil && fi.Mode()&os.ModeCharDevice != 0
+}
+
+// tableByDefault reports whether cmd should print a table rather than
+// JSON: a scan with no output options whose stdout is a person, not a
+// program. -format json asks for JSON explicitly.
+func tableByDefault(cmd string) bool {
+	return (cmd == "scan" || cmd == "focus") && *formatFlag == "" && *templateFile == "" && len(outFlags) == 0 &&
+		*outputDir == "" && (*outputFile == "" || *outputFile == "-") && stdoutIsTerminal()
+}
+
+// colorTable reports whether the table goes to a terminal that wants
+// colour; NO_COLOR turns it off.
+func colorTable() bool {
+	return (*outputFile == "" || *outputFile == "-") && os.Getenv("NO_COLOR") == "" && stdoutIsTerminal()
+}
+
+// renderTable renders the report as an aligned plain-text table, worst
+// repos first, followed by failures, the summary and the gate.
+func renderTable(out output, color bool) string {
+	paint := func(code, s string) string {
+		if !color || code == "" {
+			return s
+		}
+		return code + s + ansiReset
+	}
+	header := []string{"REPO", "SCORE", "STATUS", "OPEN", "STALE", "UNLABELED"}
+	right := []bool{false, true, false, true, true, true}
+	rows := [][]string{header}
+	var failed []repoScore
+	for _, rs := range out.Repos {
+		switch {
+		case rs.Error != "":
+			failed = append(failed, rs)
+		case rs.Status == unscored:
+			rows = append(rows, []string{rs.Name, "-", rs.Status, fmt.Sprint(rs.TotalOpen), "-", "-"})
+		default:
+			rows = append(rows, []string{rs.Name, fmt.Sprint(rs.HealthScore), rs.Status,
+				fmt.Sprint(rs.TotalOpen), fmt.Sprint(rs.StaleCount), fmt.Sprint(rs.UnlabeledCount)})
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString(alignRows(rows, right, func(n, i int, cell string) string {
+		// Colour the score and status; the header is bold throughout.
+		switch {
+		case n == 0:
+			return paint(ansiBold, cell)
+		case i == 1 || i == 2:
+			return paint(statusColors[rows[n][2]], cell)
+		}
+		return cell
+	}))
+	if len(failed) > 0 {
+		b.WriteString("\n" + paint(ansiRed, "Failed to scan:") + "\n")
+		for _, rs := range failed {
+			fmt.Fprintf(&b, "  %s: %s\n", rs.Name, rs.Error)
+		}
+	}
+	b.WriteString("\n" + summaryLine(out) + "\n")
+	if out.Truncated {
+		b.WriteString(paint(ansiYellow, "Scan truncated: some repos weren't scored.") + "\n")
+	}
+	if g := out.Gate; g != nil {
+		verdict, code := "Gate passed", ansiGreen
+		if !g.Passed {
+			verdict, code = "Gate failed", ansiRed
+		}
+		if g.Observed {
+			verdict += " (observe mode)"
+		}
+		b.WriteString(paint(code, verdict) + "\n")
+		for _, r := range g.Reasons {
+			b.WriteString(paint(ansiDim, "  - "+r) + "\n")
+		}
+	}
+	return b.String()
+}
+
+// alignRows pads rows into columns two spaces apart, right-aligning the
+// columns marked in right. style, when set, dresses each padded cell of
+// row n, column i.
+func alignRows(rows [][]string, right []bool, style func(n, i int, cell string) string) string {
+	widths := make([]int, len(right))
+	for _, row := range rows {
+		for i, cell := range row {
+			widths[i] = max(widths[i], utf8.RuneCountInString(cell))
+		}
+	}
+	var b strings.Builder
+	for n, row := range rows {
+		cells := make([]string, len(row))
+		for i, cell := range row {
+			pad := strings.Repeat(" ", widths[i]-utf8.RuneCountInString(cell))
+			if right[i] {
+				cell = pad + cell
+			} else if i < len(row)-1 {
+				cell += pad
+			}
+			if style != nil {
+				cell = style(n, i, cell)
+			}
+			cells[i] = cell
+		}
+		b.WriteString(strings.Join(cells, "  ") + "\n")
+	}
+	return b.String()
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestRenderTable(t *testing.T) {
+	out := sampleReport()
+	out.Repos = append(out.Repos, repoScore{Name: "tiny", Status: unscored, TotalOpen: 2})
+	out.Gate = &gateResult{Passed: false, Reasons: []string{"1 critical repos (max 0)"}}
+	got := renderTable(out, false)
+	want := `REPO       SCORE  STATUS    OPEN  STALE  UNLABELED
+neglected     35  critical     0      0          0
+meh           55  warning      0      0          0
+fine         100  healthy      0      0          0
+tiny           -  unscored     2      -          -
+
+Failed to scan:
+  broken: gh: HTTP 500
+
+3 repos: 1 healthy, 1 warning, 1 critical (1 failed to scan)
+Gate failed
+  - 1 critical repos (max 0)
+`
+	if got != want {
+		t.Errorf("table:\n%s\nwant:\n%s", got, want)
+	}
+
+	colored := renderTable(out, true)
+	for _, want := range []string{ansiRed + "   35" + ansiReset + "  " + ansiRed + "critical" + ansiReset, ansiGreen + "healthy " + ansiReset, ansiRed + "Gate failed" + ansiReset} {
+		if !strings.Contains(colored, want) {
+			t.Errorf("coloured table missing %q:\n%s", want, colored)
+		}
+	}
+}
+
+func TestEmitReportTable(t *testing.T) {
+	prev := *formatFlag
+	t.Cleanup(func() { *formatFlag = prev })
+	if tableByDefault("scan") {
+		t.Fatal("tests don't run on a terminal, so JSON stays the default")
+	}
+	*formatFlag = "table"
+	var b bytes.Buffer
+	if err := emitReport(&b, sampleReport()); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(b.String(), "REPO") || strings.Contains(b.String(), "\x1b[") {
+		t.Errorf("uncoloured table expected off a terminal:\n%s", b.String())
+	}
+}
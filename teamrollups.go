@@ -0,0 +1,116 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"flag"
+	"log/slog"
+	"math"
+	"slices"
+	"strings"
+
+	ghscan "github.com/misty-step/fab-backlog/pkg/scan"
+)
+
+var teamRollupsFlag = flag.Bool("team-rollups", false, "summarise the scanned repos per GitHub team, using the org's team-repository assignments (needs read:org)")
+
+// teamRollup is one GitHub team's slice of the backlog: the scanned repos
+// the team is assigned to, summarised as the report summary is.
+type teamRollup struct {
+	Org  string `json:"org"`
+	Team string `json:"team"` // slug
+	Name string `json:"name"`
+	summary
+	// HealthScore is the mean health score of the team's scored repos.
+	HealthScore float64  `json:"healthScore"`
+	Repos       []string `json:"repos"`
+}
+
+type ghTeam struct {
+	Slug string `json:"slug"`
+	Name string `json:"name"`
+}
+
+type ghTeamRepo struct {
+	FullName string `json:"full_name"`
+}
+
+// ghTeamRepos returns org's teams with the full names of the repos each is
+// assigned to, keyed by team slug.
+func ghTeamRepos(ctx context.Context, org string) ([]ghTeam, map[string][]string, error) {
+	teams, err := ghscan.APIList[ghTeam](ctx, runCmd, "orgs/"+org+"/teams")
+	if err != nil {
+		return nil, nil, err
+	}
+	repos := map[string][]string{}
+	for _, t := range teams {
+		list, err := ghscan.APIList[ghTeamRepo](ctx, runCmd, "orgs/"+org+"/teams/"+t.Slug+"/repos")
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, r := range list {
+			repos[t.Slug] = append(repos[t.Slug], r.FullName)
+		}
+	}
+	return teams, repos, nil
+}
+
+// rollupTeams summarises repos for each team assigned any of them, worst
+// mean score first. Repos no team is assigned to appear in no rollup.
+func rollupTeams(org string, teams []ghTeam, assigned map[string][]string, repos []repoScore) []teamRollup {
+	byName := map[string]repoScore{}
+	for _, rs := range repos {
+		byName[strings.ToLower(rs.fullName)] = rs
+	}
+	var out []teamRollup
+	for _, t := range teams {
+		var mine []repoScore
+		for _, full := range assigned[t.Slug] {
+			if rs, ok := byName[strings.ToLower(full)]; ok {
+				mine = append(mine, rs)
+			}
+		}
+		if len(mine) == 0 {
+			continue
+		}
+		r := teamRollup{Org: org, Team: t.Slug, Name: t.Name}
+		r.summary, r.HealthScore, r.Repos = rollupOf(mine)
+		out = append(out, r)
+	}
+	slices.SortFunc(out, func(a, b teamRollup) int {
+		return cmp.Or(cmp.Compare(a.HealthScore, b.HealthScore), cmp.Compare(a.Org, b.Org), cmp.Compare(a.Team, b.Team))
+	})
+	return out
+}
+
+// rollupOf summarises a group of repos: the report's summary counts, the
+// mean health score of the scored ones and every repo's name, sorted.
+func rollupOf(repos []repoScore) (s summary, mean float64, names []string) {
+	s = summarize(repos)
+	total := 0
+	for _, rs := range repos {
+		names = append(names, rs.Name)
+		if rs.Error == "" && rs.Status != unscored {
+			total += rs.HealthScore
+		}
+	}
+	slices.Sort(names)
+	if s.Total > 0 {
+		mean = math.Round(float64(total)/float64(s.Total)*10) / 10
+	}
+	return s, mean, names
+}
+
+// addTeamRollups fills in the per-team summaries for each scanned org. An
+// org whose teams can't be read is logged and left out.
+func addTeamRollups(ctx context.Context, out *output, orgs []string) {
+	for _, org := range orgs {
+		teams, assigned, err := ghTeamRepos(ctx, org)
+		if err != nil {
+			slog.Warn("team rollups unavailable", "org", org, "error", err)
+			continue
+		}
+		out.TeamRollups = append(out.TeamRollups, rollupTeams(org, teams, assigned, out.Repos)...)
+	}
+	slices.SortStableFunc(out.TeamRollups, func(a, b teamRollup) int { return cmp.Compare(a.HealthScore, b.HealthScore) })
+}
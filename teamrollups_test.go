@@ -0,0 +1,56 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestTeamRollups(t *testing.T) {
+	prev := runCmd
+	t.Cleanup(func() { runCmd = prev })
+	runCmd = func(ctx context.Context, bin string, args ...string) ([]byte, error) {
+		switch args[len(args)-1] {
+		case "orgs/acme/teams":
+			return []byte(`[{"slug":"payments","name":"Payments"},{"slug":"web","name":"Web"},{"slug":"infra","name":"Infra"}]`), nil
+		case "orgs/acme/teams/payments/repos":
+			return []byte(`[{"full_name":"acme/billing"},{"full_name":"acme/ledger"},{"full_name":"acme/archived"}]`), nil
+		case "orgs/acme/teams/web/repos":
+			return []byte(`[{"full_name":"ACME/site"},{"full_name":"acme/billing"}]`), nil
+		case "orgs/acme/teams/infra/repos":
+			return []byte(`[]`), nil
+		}
+		return nil, errors.New("gh: Not Found (HTTP 404)")
+	}
+	out := output{Repos: []repoScore{
+		{Name: "billing", fullName: "acme/billing", HealthScore: 30, Status: "critical"},
+		{Name: "ledger", fullName: "acme/ledger", HealthScore: 75, Status: "healthy"},
+		{Name: "site", fullName: "acme/site", HealthScore: 90, Status: "healthy"},
+		{Name: "broken", fullName: "acme/broken", Error: "gh: HTTP 500"},
+	}}
+	addTeamRollups(context.Background(), &out, []string{"acme", "globex"})
+
+	if len(out.TeamRollups) != 2 {
+		t.Fatalf("rollups = %+v, want payments and web only", out.TeamRollups)
+	}
+	pay, web := out.TeamRollups[0], out.TeamRollups[1]
+	if pay.Team != "payments" || pay.Name != "Payments" || pay.Org != "acme" || !slices.Equal(pay.Repos, []string{"billing", "ledger"}) {
+		t.Errorf("payments = %+v", pay)
+	}
+	if pay.Total != 2 || pay.Healthy != 1 || pay.Critical != 1 || pay.HealthScore != 52.5 {
+		t.Errorf("payments counts = %+v", pay)
+	}
+	if web.Team != "web" || !slices.Equal(web.Repos, []string{"billing", "site"}) || web.HealthScore != 60 {
+		t.Errorf("web = %+v", web)
+	}
+
+	md, err := renderMarkdown(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(md, "| Payments | 2 | 52.5 | 1 | 0 | 1 |") {
+		t.Errorf("markdown missing the team table:\n%s", md)
+	}
+}
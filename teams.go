@@ -0,0 +1,43 @@
+package main
+
+import (
+	"flag"
+	"strconv"
+)
+
+var notifyTeams = flag.String("notify-teams", "", "Microsoft Teams incoming webhook URL to post the scan summary to as an Adaptive Card")
+
+// teamsMessage wraps an Adaptive Card in the message envelope Teams
+// incoming webhooks (and Workflows "post to channel" triggers) accept.
+func teamsMessage(out output) map[string]any {
+	body := []map[string]any{
+		{"type": "TextBlock", "text": "Backlog health: " + out.Org, "weight": "Bolder", "size": "Medium"},
+		{"type": "TextBlock", "text": summaryLine(out), "wrap": true},
+	}
+	if worst := worstRepos(out, worstRepoCount); len(worst) > 0 {
+		var facts []map[string]string
+		for _, rs := range worst {
+			facts = append(facts, map[string]string{"title": rs.Name, "value": strconv.Itoa(rs.HealthScore) + " (" + rs.Status + ")"})
+		}
+		body = append(body,
+			map[string]any{"type": "TextBlock", "text": "Worst repos", "weight": "Bolder", "spacing": "Medium"},
+			map[string]any{"type": "FactSet", "facts": facts},
+		)
+	}
+	return adaptiveCard(body)
+}
+
+func adaptiveCard(body []map[string]any) map[string]any {
+	return map[string]any{
+		"type": "message",
+		"attachments": []map[string]any{{
+			"contentType": "application/vnd.microsoft.card.adaptive",
+			"content": map[string]any{
+				"$schema": "http://adaptivecards.io/schemas/adaptive-card.json",
+				"type":    "AdaptiveCard",
+				"version": "1.4",
+				"body":    body,
+			},
+		}},
+	}
+}
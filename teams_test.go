@@ -0,0 +1,47 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestTeamsMessage(t *testing.T) {
+	var got struct {
+		Type        string `json:"type"`
+		Attachments []struct {
+			ContentType string `json:"contentType"`
+			Content     struct {
+				Type string `json:"type"`
+				Body []struct {
+					Type  string `json:"type"`
+					Text  string `json:"text"`
+					Facts []struct{ Title, Value string }
+				} `json:"body"`
+			} `json:"content"`
+		} `json:"attachments"`
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&got)
+	}))
+	defer srv.Close()
+
+	if err := postJSON(srv.URL, teamsMessage(sampleReport()), nil); err != nil {
+		t.Fatal(err)
+	}
+	if got.Type != "message" || len(got.Attachments) != 1 || got.Attachments[0].ContentType != "application/vnd.microsoft.card.adaptive" {
+		t.Fatalf("bad envelope: %+v", got)
+	}
+	card := got.Attachments[0].Content
+	if card.Type != "AdaptiveCard" || len(card.Body) != 4 {
+		t.Fatalf("card = %+v", card)
+	}
+	if card.Body[1].Text != "3 repos: 1 healthy, 1 warning, 1 critical (1 failed to scan)" {
+		t.Errorf("summary = %q", card.Body[1].Text)
+	}
+	facts := card.Body[3].Facts
+	if len(facts) != 3 || facts[0].Title != "neglected" || facts[0].Value != "35 (critical)" {
+		t.Errorf("facts = %+v", facts)
+	}
+}
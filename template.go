@@ -0,0 +1,60 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"text/template"
+
+	"github.com/misty-step/fab-backlog/pkg/report"
+)
+
+var templateFile = flag.String("template", "", "render the report through this Go text/template file instead of as JSON or a built-in format")
+
+// customTmpl is the parsed -template, nil without one. It is parsed while
+// flags are validated, so a broken template fails before the scan.
+var customTmpl *template.Template
+
+// loadTemplate parses -template for the commands that render a report.
+func loadTemplate(cmd string) error {
+	if *templateFile == "" {
+		return nil
+	}
+	switch {
+	case cmd != "scan" && cmd != "focus" && cmd != "report":
+		return fmt.Errorf("-template doesn't apply to %s", cmd)
+	case *formatFlag != "":
+		return fmt.Errorf("-template and -format are mutually exclusive")
+	case *outputDir != "" && len(outFlags) == 0:
+		return fmt.Errorf("-output-dir keeps JSON reports; render -template with -out template=DEST alongside it")
+	case len(outFlags) > 0 && !slices.ContainsFunc(outFlags, func(s outputSpec) bool { return s.Format == "template" }):
+		return fmt.Errorf("-template with -out is rendered by -out template=DEST")
+	}
+	t, err := template.New(filepath.Base(*templateFile)).Funcs(renderFuncs).ParseFiles(*templateFile)
+	if err != nil {
+		return fmt.Errorf("-template: %w", err)
+	}
+	customTmpl = t
+	return nil
+}
+
+// renderTemplate renders out through -template, with the helpers the
+// built-in formats use bound to the report's locale.
+func renderTemplate(out output) (string, error) {
+	if customTmpl == nil {
+		return "", fmt.Errorf("no -template given")
+	}
+	f, err := report.Lookup(out.Config.Locale)
+	if err != nil {
+		return "", err
+	}
+	t, err := customTmpl.Clone()
+	if err != nil {
+		return "", err
+	}
+	var b bytes.Buffer
+	err = t.Funcs(f.Funcs()).Execute(&b, out)
+	return b.String(), err
+}
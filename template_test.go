@@ -0,0 +1,81 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestTemplateReport(t *testing.T) {
+	prevFile, prevTmpl, prevOut := *templateFile, customTmpl, *outputFile
+	t.Cleanup(func() { *templateFile, customTmpl, *outputFile = prevFile, prevTmpl, prevOut })
+	dir := t.TempDir()
+	*templateFile = filepath.Join(dir, "wiki.tmpl")
+	tmpl := `h1. {{.Org}}
+{{range .Repos}}{{if not .Error}}| {{.Name}} | {{num .TotalOpen}} | {{decimal .StalePercent 1}}% |
+{{end}}{{end}}{{summaryLine .}}
+`
+	if err := os.WriteFile(*templateFile, []byte(tmpl), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := loadTemplate("report"); err != nil {
+		t.Fatal(err)
+	}
+
+	in := filepath.Join(dir, "report.json")
+	rep := sampleReport()
+	rep.Config.Locale = "de-DE"
+	rep.Repos[0].TotalOpen, rep.Repos[0].StalePercent = 1234, 12.5
+	data, _ := json.Marshal(rep)
+	if err := os.WriteFile(in, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	*outputFile = filepath.Join(dir, "report.txt")
+	if code := runReport([]string{in}, config{}); code != 0 {
+		t.Fatalf("exit %d", code)
+	}
+	got, _ := os.ReadFile(*outputFile)
+	for _, want := range []string{"h1. misty-step\n", "| neglected | 1.234 | 12,5% |\n", "3 repos: 1 healthy"} {
+		if !strings.Contains(string(got), want) {
+			t.Errorf("rendered report missing %q:\n%s", want, got)
+		}
+	}
+	if strings.Contains(string(got), "broken") {
+		t.Errorf("the template skips failed repos:\n%s", got)
+	}
+}
+
+func TestLoadTemplate(t *testing.T) {
+	prevFile, prevTmpl, prevFormat, prevOuts, prevDir := *templateFile, customTmpl, *formatFlag, outFlags, *outputDir
+	t.Cleanup(func() {
+		*templateFile, customTmpl, *formatFlag, outFlags, *outputDir = prevFile, prevTmpl, prevFormat, prevOuts, prevDir
+	})
+	dir := t.TempDir()
+	*templateFile = filepath.Join(dir, "broken.tmpl")
+	if err := os.WriteFile(*templateFile, []byte("{{range .Repos}}"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := loadTemplate("scan"); err == nil || !strings.Contains(err.Error(), "-template") {
+		t.Errorf("a broken template should fail up front, got %v", err)
+	}
+	*templateFile = filepath.Join(dir, "missing.tmpl")
+	if err := loadTemplate("scan"); err == nil {
+		t.Error("a missing template should fail up front")
+	}
+	for _, tc := range []struct {
+		cmd, format, dir string
+		outs             outputSpecs
+	}{
+		{cmd: "runs"},
+		{cmd: "report", format: "html"},
+		{cmd: "scan", dir: "reports"},
+		{cmd: "scan", outs: outputSpecs{{"json", "r.json"}}},
+	} {
+		*formatFlag, *outputDir, outFlags = tc.format, tc.dir, tc.outs
+		if err := loadTemplate(tc.cmd); err == nil {
+			t.Errorf("%+v should be rejected", tc)
+		}
+	}
+}
@@ -0,0 +1,168 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"time"
+)
+
+var tenantsFile = flag.String("tenants", "", "serve: JSON file of tenants, each with its own selection, config, schedule, storage and notifiers, served under /tenants/NAME/")
+
+// tenantNameRE keeps tenant names usable as a URL path segment.
+var tenantNameRE = regexp.MustCompile(`^[a-z0-9][a-z0-9._-]*$`)
+
+// tenantSpec is one entry of a -tenants file.
+type tenantSpec struct {
+	Name   string   `json:"name"`
+	Orgs   []string `json:"orgs,omitempty"`
+	Repos  []string `json:"repos,omitempty"`
+	Ignore []string `json:"ignore,omitempty"`
+	// Config is a -config file, relative to the tenants file, layered over
+	// the settings the process was started with.
+	Config string `json:"config,omitempty"`
+	// Intervals default to -delta-interval and -full-interval.
+	DeltaInterval string    `json:"deltaInterval,omitempty"`
+	FullInterval  string    `json:"fullInterval,omitempty"`
+	Notify        notifiers `json:"notify,omitzero"`
+}
+
+// loadTenants reads a -tenants file and sets up a daemon per tenant. Each
+// tenant needs its own storage, so one tenant's runs never become another's
+// history.
+func loadTenants(path string, base config) ([]*daemon, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read tenants: %w", err)
+	}
+	var file struct {
+		Tenants []tenantSpec `json:"tenants"`
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&file); err != nil {
+		return nil, fmt.Errorf("parse tenants %s: %w", path, err)
+	}
+	if len(file.Tenants) == 0 {
+		return nil, fmt.Errorf("%s lists no tenants", path)
+	}
+
+	var daemons []*daemon
+	names := map[string]bool{}
+	stores := map[string]string{} // driver:path -> tenant
+	for _, t := range file.Tenants {
+		if !tenantNameRE.MatchString(t.Name) {
+			return nil, fmt.Errorf("tenant %q: name must be lowercase letters, digits, '.', '_' or '-'", t.Name)
+		}
+		if names[t.Name] {
+			return nil, fmt.Errorf("tenant %q is listed twice", t.Name)
+		}
+		names[t.Name] = true
+		if len(t.Orgs) == 0 && len(t.Repos) == 0 {
+			return nil, fmt.Errorf("tenant %q: set orgs and/or repos", t.Name)
+		}
+		cfg, err := tenantConfig(base, t.Config, filepath.Dir(path))
+		if err != nil {
+			return nil, fmt.Errorf("tenant %q: %w", t.Name, err)
+		}
+		if s := cfg.Storage; s != nil && s.Driver != "memory" {
+			key := s.Driver + ":" + filepath.Clean(s.Path)
+			if other, ok := stores[key]; ok {
+				return nil, fmt.Errorf("tenants %q and %q share %s storage %s; give each its own", other, t.Name, s.Driver, s.Path)
+			}
+			stores[key] = t.Name
+		}
+		delta, err := tenantInterval(t.DeltaInterval, *deltaInterval)
+		if err != nil {
+			return nil, fmt.Errorf("tenant %q: deltaInterval: %w", t.Name, err)
+		}
+		full, err := tenantInterval(t.FullInterval, *fullInterval)
+		if err != nil {
+			return nil, fmt.Errorf("tenant %q: fullInterval: %w", t.Name, err)
+		}
+		d := newDaemon(t.Name, selection{Orgs: t.Orgs, Repos: t.Repos, Ignore: t.Ignore}, cfg, delta, full)
+		d.notify = t.Notify
+		daemons = append(daemons, d)
+	}
+	return daemons, nil
+}
+
+// tenantConfig layers a tenant's config file over base. base goes through
+// JSON first, so decoding the file can't write into slices, maps or
+// pointers that other tenants share.
+func tenantConfig(base config, file, dir string) (config, error) {
+	var cfg config
+	data, err := json.Marshal(base)
+	if err == nil {
+		err = json.Unmarshal(data, &cfg)
+	}
+	if err != nil {
+		return cfg, err
+	}
+	if file != "" {
+		if !filepath.IsAbs(file) {
+			file = filepath.Join(dir, file)
+		}
+		if err := loadConfigFile(file, &cfg); err != nil {
+			return cfg, err
+		}
+	}
+	cfg.away, err = loadAway(cfg.OOO, *oooCalendar)
+	return cfg, err
+}
+
+func tenantInterval(s string, def time.Duration) (time.Duration, error) {
+	d := def
+	if s != "" {
+		var err error
+		if d, err = time.ParseDuration(s); err != nil {
+			return 0, err
+		}
+	}
+	if d <= 0 {
+		return 0, fmt.Errorf("must be positive, got %v", d)
+	}
+	return d, nil
+}
+
+// tenantStatus is one entry of the /tenants index.
+type tenantStatus struct {
+	Name     string   `json:"name"`
+	Orgs     []string `json:"orgs,omitempty"`
+	Repos    []string `json:"repos,omitempty"`
+	LastScan string   `json:"lastScan,omitempty"`
+	Report   string   `json:"report"`
+}
+
+// tenantsHandler serves each tenant's endpoints under /tenants/NAME/ and
+// an index of the tenants at /tenants.
+func tenantsHandler(daemons []*daemon) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /healthz", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("ok\n"))
+	})
+	mux.HandleFunc("GET /tenants", func(w http.ResponseWriter, r *http.Request) {
+		list := make([]tenantStatus, 0, len(daemons))
+		for _, d := range daemons {
+			s := tenantStatus{Name: d.name, Orgs: d.sel.Orgs, Repos: d.sel.Repos, Report: "/tenants/" + d.name + "/report"}
+			d.mu.RLock()
+			if !d.lastScan.IsZero() {
+				s.LastScan = d.lastScan.UTC().Format(time.RFC3339)
+			}
+			d.mu.RUnlock()
+			list = append(list, s)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = writeJSON(w, list)
+	})
+	for _, d := range daemons {
+		prefix := "/tenants/" + d.name
+		mux.Handle(prefix+"/", http.StripPrefix(prefix, d.handler()))
+	}
+	return mux
+}
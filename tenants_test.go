@@ -0,0 +1,142 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func writeTenantFiles(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, body := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(body), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return filepath.Join(dir, "tenants.json")
+}
+
+func TestLoadTenants(t *testing.T) {
+	path := writeTenantFiles(t, map[string]string{
+		"tenants.json": `{"tenants": [
+			{"name": "acme", "orgs": ["acme"], "config": "acme.json", "deltaInterval": "15m"},
+			{"name": "globex", "repos": ["globex/api"], "notify": {"slack": "https://hooks.example/g"}}
+		]}`,
+		"acme.json": `{"staleDays": 30, "storage": {"driver": "memory"}}`,
+	})
+	base := config{MinIssues: 5, StaleDays: 90, OnboardingLabels: []string{"good first issue"}}
+	daemons, err := loadTenants(path, base)
+	if err != nil {
+		t.Fatal(err)
+	}
+	acme, globex := daemons[0], daemons[1]
+	if acme.cfg.StaleDays != 30 || acme.cfg.MinIssues != 5 || acme.store == nil {
+		t.Errorf("acme should layer its config over the base: %+v", acme.cfg)
+	}
+	if globex.cfg.StaleDays != 90 || globex.store != nil {
+		t.Errorf("globex should keep the base config: %+v", globex.cfg)
+	}
+	if acme.deltaEvery != 15*time.Minute || acme.fullEvery != *fullInterval || globex.deltaEvery != *deltaInterval {
+		t.Errorf("intervals: acme %v/%v, globex %v", acme.deltaEvery, acme.fullEvery, globex.deltaEvery)
+	}
+	if globex.notify.Slack != "https://hooks.example/g" || acme.notify != (notifiers{}) {
+		t.Errorf("notifiers: acme %+v, globex %+v", acme.notify, globex.notify)
+	}
+
+	for name, tenants := range map[string]string{
+		"bad name":       `{"tenants": [{"name": "Acme Corp", "orgs": ["acme"]}]}`,
+		"duplicate":      `{"tenants": [{"name": "a", "orgs": ["acme"]}, {"name": "a", "orgs": ["globex"]}]}`,
+		"no selection":   `{"tenants": [{"name": "a"}]}`,
+		"shared storage": `{"tenants": [{"name": "a", "orgs": ["acme"], "config": "file.json"}, {"name": "b", "orgs": ["globex"], "config": "file.json"}]}`,
+		"bad interval":   `{"tenants": [{"name": "a", "orgs": ["acme"], "fullInterval": "0s"}]}`,
+		"empty":          `{"tenants": []}`,
+	} {
+		path := writeTenantFiles(t, map[string]string{
+			"tenants.json": tenants,
+			"file.json":    `{"storage": {"driver": "file", "path": "runs.json"}}`,
+		})
+		if _, err := loadTenants(path, base); err == nil {
+			t.Errorf("%s: want an error", name)
+		}
+	}
+}
+
+func TestTenantsAreIsolated(t *testing.T) {
+	gh := newFakeGitHub(t)
+	now := time.Now()
+	genFixture(gh, "acme", 1, 3, 4, now)
+	genFixture(gh, "globex", 2, 2, 4, now)
+
+	var posts [2]atomic.Int32
+	hook := func(i int) string {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { posts[i].Add(1) }))
+		t.Cleanup(srv.Close)
+		return srv.URL
+	}
+	path := writeTenantFiles(t, map[string]string{
+		"tenants.json": `{"tenants": [
+			{"name": "acme", "orgs": ["acme"], "config": "memory.json", "notify": {"slack": "` + hook(0) + `"}},
+			{"name": "globex", "orgs": ["globex"], "config": "memory.json", "notify": {"slack": "` + hook(1) + `"}}
+		]}`,
+		"memory.json": `{"storage": {"driver": "memory"}}`,
+	})
+	daemons, err := loadTenants(path, config{MinIssues: 1, StaleDays: 90})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var wg sync.WaitGroup
+	for _, d := range daemons {
+		wg.Go(func() { d.fullScan(t.Context(), now) })
+	}
+	wg.Wait()
+
+	h := tenantsHandler(daemons)
+	get := func(path string, v any) {
+		t.Helper()
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest("GET", path, nil))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("GET %s: %d %s", path, rec.Code, rec.Body)
+		}
+		if err := json.Unmarshal(rec.Body.Bytes(), v); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for i, want := range map[int]string{0: "acme", 1: "globex"} {
+		var report output
+		get("/tenants/"+want+"/report", &report)
+		if report.Org != want || len(report.Repos) == 0 {
+			t.Fatalf("%s report: org %q with %d repos", want, report.Org, len(report.Repos))
+		}
+		for _, rs := range report.Repos {
+			if strings.Contains(rs.Name, "/") {
+				t.Errorf("%s report has a foreign repo %s", want, rs.Name)
+			}
+		}
+		if runs, _ := daemons[i].store.ListRuns(); len(runs) != 1 {
+			t.Errorf("%s stored %d runs, want 1", want, len(runs))
+		}
+		if n := posts[i].Load(); n != 1 {
+			t.Errorf("%s notifier got %d posts, want 1", want, n)
+		}
+	}
+
+	var index []tenantStatus
+	get("/tenants", &index)
+	if len(index) != 2 || index[0].Name != "acme" || index[0].LastScan == "" || index[1].Report != "/tenants/globex/report" {
+		t.Errorf("index = %+v", index)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("GET", "/report", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("the single-tenant /report should not be served: %d", rec.Code)
+	}
+}
@@ -0,0 +1,21 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+)
+
+var scanTimeout = flag.Duration("timeout", 0, "stop scanning after this long and report the repos finished so far, marked truncated (0: no limit)")
+
+var errScanTimeout = errors.New("scan exceeded -timeout")
+
+// withScanTimeout bounds the fetching and scoring part of a run by -timeout.
+// What follows, such as saving and publishing the report, runs under the
+// parent context so a truncated report still goes out.
+func withScanTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if *scanTimeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeoutCause(ctx, *scanTimeout, errScanTimeout)
+}
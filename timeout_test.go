@@ -0,0 +1,54 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestScanTimeoutReportsFinishedRepos(t *testing.T) {
+	gh := newFakeGitHub(t)
+	genFixture(gh, "acme", 7, 6, 5, time.Now())
+	fake := runCmd
+	// repo-03 onwards hang until the scan gives up on them.
+	runCmd = func(ctx context.Context, bin string, args ...string) ([]byte, error) {
+		if slices.Contains(args, "acme/repo-03") || slices.Contains(args, "acme/repo-04") || slices.Contains(args, "acme/repo-05") {
+			<-ctx.Done()
+			return nil, context.Cause(ctx)
+		}
+		return fake(ctx, bin, args...)
+	}
+	prevTimeout, prevConcurrency := *scanTimeout, *concurrency
+	*scanTimeout, *concurrency = 100*time.Millisecond, 1
+	t.Cleanup(func() { *scanTimeout, *concurrency = prevTimeout, prevConcurrency })
+
+	ctx, cancel := withScanTimeout(t.Context())
+	defer cancel()
+	repos := []string{"acme/repo-00", "acme/repo-01", "acme/repo-02", "acme/repo-03", "acme/repo-04", "acme/repo-05"}
+	out := scan(ctx, selection{Orgs: []string{"acme"}}, repos, config{MinIssues: 1, StaleDays: 90, HallOfShame: 3})
+	if !out.Truncated || !errors.Is(context.Cause(ctx), errScanTimeout) {
+		t.Fatalf("truncated = %v, cause %v", out.Truncated, context.Cause(ctx))
+	}
+	var names []string
+	for _, rs := range out.Repos {
+		if rs.Error != "" {
+			t.Errorf("%s reported half-fetched: %s", rs.Name, rs.Error)
+		}
+		names = append(names, rs.Name)
+	}
+	slices.Sort(names)
+	if strings.Join(names, ",") != "repo-00,repo-01,repo-02" {
+		t.Errorf("repos = %v", names)
+	}
+	if out.HallOfShame != nil {
+		t.Error("org-wide sections should be skipped once the scan is cut short")
+	}
+
+	full := scan(t.Context(), selection{Orgs: []string{"acme"}}, repos[:3], config{MinIssues: 1, StaleDays: 90})
+	if full.Truncated || len(full.Repos) != 3 {
+		t.Errorf("uncancelled scan: truncated %v, %d repos", full.Truncated, len(full.Repos))
+	}
+}
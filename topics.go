@@ -0,0 +1,135 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"slices"
+	"strconv"
+)
+
+// Repo topics are configuration signals owners can set from the repo's
+// About box without a config PR. A few conventions are built in:
+//
+//	backlog-exempt         exempt the repo from the gate, escalation and paging
+//	backlog-opt-out        leave the repo out of scans
+//	backlog-stale-days-N   stale after N days in this repo
+//
+// and the central config's topics map gives other topics, such as tier-1,
+// overrides of the same shape as .github/fab-backlog.yml.
+const (
+	topicExempt = "backlog-exempt"
+	topicOptOut = "backlog-opt-out"
+)
+
+var (
+	topicStaleDays = regexp.MustCompile(`^backlog-stale-days-([1-9][0-9]{0,3})$`)
+	// validTopic is GitHub's rule for topic names.
+	validTopic = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{0,49}$`)
+)
+
+// topicAnnotation records what one topic did to a repo's settings, so the
+// report shows where each came from.
+type topicAnnotation struct {
+	Topic string `json:"topic"`
+	// Settings are the overrides the topic applied.
+	Settings *repoOverrides `json:"settings,omitempty"`
+	Exempt   bool           `json:"exempt,omitempty"`
+}
+
+// topicConfig is a repo's config once its topics are merged in.
+type topicConfig struct {
+	cfg         config
+	annotations []topicAnnotation
+	exemption   *exemption
+	optOut      bool
+}
+
+// readsTopics reports whether scans fetch repo topics.
+func (c config) readsTopics() bool {
+	return c.RepoTopics || len(c.Topics) > 0
+}
+
+func validateTopics(topics map[string]repoOverrides) error {
+	for name, o := range topics {
+		if !validTopic.MatchString(name) {
+			return fmt.Errorf("topics: %q isn't a valid topic name (lowercase letters, digits and hyphens)", name)
+		}
+		for _, p := range o.IgnoreLabels {
+			if err := validatePattern(p); err != nil {
+				return fmt.Errorf("topics %s: %w", name, err)
+			}
+		}
+		if _, err := newTitleMatcher(o.StaleExcludeTitles); err != nil {
+			return fmt.Errorf("topics %s: %w", name, err)
+		}
+		if o.StaleDays < 0 {
+			return fmt.Errorf("topics %s: staleDays must not be negative", name)
+		}
+	}
+	return nil
+}
+
+// ghRepoTopics returns a repo's topics.
+func ghRepoTopics(ctx context.Context, owner, repo string) ([]string, error) {
+	stdout, err := runCmd(ctx, "gh", "api", fmt.Sprintf("repos/%s/%s", owner, repo))
+	if err != nil {
+		return nil, err
+	}
+	var r struct {
+		Topics []string `json:"topics"`
+	}
+	if err := json.Unmarshal(stdout, &r); err != nil {
+		return nil, fmt.Errorf("parse repo: %w", err)
+	}
+	return r.Topics, nil
+}
+
+// applyTopics merges the settings a repo's topics ask for into cfg, in
+// topic name order, so a later topic wins a conflict. Topics with no
+// meaning are ignored.
+func applyTopics(cfg config, full string, topics []string) topicConfig {
+	tc := topicConfig{cfg: cfg}
+	for _, t := range slices.Sorted(slices.Values(topics)) {
+		switch {
+		case t == topicExempt:
+			tc.exemption = &exemption{Repo: repoPattern(full), Reason: "repo topic " + t, Source: "topic"}
+			tc.annotations = append(tc.annotations, topicAnnotation{Topic: t, Exempt: true})
+		case t == topicOptOut:
+			tc.optOut = true
+		case topicStaleDays.MatchString(t):
+			days, _ := strconv.Atoi(topicStaleDays.FindStringSubmatch(t)[1])
+			o := &repoOverrides{StaleDays: days}
+			tc.cfg = o.apply(tc.cfg)
+			tc.annotations = append(tc.annotations, topicAnnotation{Topic: t, Settings: o})
+		default:
+			o, ok := cfg.Topics[t]
+			if !ok {
+				continue
+			}
+			if o.OptOut {
+				tc.optOut = true
+				continue
+			}
+			tc.cfg = o.apply(tc.cfg)
+			tc.annotations = append(tc.annotations, topicAnnotation{Topic: t, Settings: &o})
+		}
+	}
+	return tc
+}
+
+// repoTopicConfig fetches a repo's topics and merges them into cfg. A
+// failed fetch is logged and leaves cfg alone.
+func repoTopicConfig(ctx context.Context, cfg config, owner, repo string) topicConfig {
+	if !cfg.readsTopics() {
+		return topicConfig{cfg: cfg}
+	}
+	topics, err := ghRepoTopics(ctx, owner, repo)
+	if err != nil {
+		slog.Warn("repo topics ignored", "repo", owner+"/"+repo, "error", err)
+		return topicConfig{cfg: cfg}
+	}
+	return applyTopics(cfg, owner+"/"+repo, topics)
+}
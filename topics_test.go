@@ -0,0 +1,84 @@
+package main
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestApplyTopics(t *testing.T) {
+	cfg := config{StaleDays: 90, IgnoreLabels: []string{"wontfix"}, Topics: map[string]repoOverrides{
+		"tier-1":   {StaleDays: 30},
+		"upstream": {IgnoreLabels: []string{"upstream"}},
+		"sunset":   {OptOut: true},
+	}}
+	tc := applyTopics(cfg, "acme/api", []string{"tier-1", "golang", "upstream", "backlog-exempt"})
+	if tc.cfg.StaleDays != 30 || !slices.Equal(tc.cfg.IgnoreLabels, []string{"wontfix", "upstream"}) || tc.optOut {
+		t.Errorf("merged config %+v", tc.cfg)
+	}
+	if tc.exemption == nil || tc.exemption.Repo != "acme/api" || tc.exemption.Source != "topic" {
+		t.Errorf("exemption %+v", tc.exemption)
+	}
+	var topics []string
+	for _, a := range tc.annotations {
+		topics = append(topics, a.Topic)
+	}
+	if !slices.Equal(topics, []string{"backlog-exempt", "tier-1", "upstream"}) {
+		t.Errorf("annotations %+v", tc.annotations)
+	}
+	// Later topics win: backlog-stale-days-365 sorts before tier-1.
+	if tc := applyTopics(cfg, "acme/api", []string{"tier-1", "backlog-stale-days-365"}); tc.cfg.StaleDays != 30 {
+		t.Errorf("staleDays %d", tc.cfg.StaleDays)
+	}
+	if tc := applyTopics(cfg, "acme/api", []string{"backlog-stale-days-0"}); tc.cfg.StaleDays != 90 || tc.annotations != nil {
+		t.Errorf("stale-days-0 should mean nothing: %+v", tc)
+	}
+	for _, opt := range []string{"backlog-opt-out", "sunset"} {
+		if !applyTopics(cfg, "acme/api", []string{opt}).optOut {
+			t.Errorf("%s should opt out", opt)
+		}
+	}
+	for name, bad := range map[string]map[string]repoOverrides{
+		"name":    {"Tier 1": {}},
+		"pattern": {"tier-1": {IgnoreLabels: []string{"/(/"}}},
+		"days":    {"tier-1": {StaleDays: -1}},
+	} {
+		if err := validateTopics(bad); err == nil {
+			t.Errorf("%s: want error", name)
+		}
+	}
+}
+
+func TestScanHonoursRepoTopics(t *testing.T) {
+	gh := newFakeGitHub(t)
+	now := time.Now()
+	issues := func() []*fakeIssue {
+		return []*fakeIssue{
+			{Number: 1, State: "open", CreatedAt: now.AddDate(0, 0, -400), UpdatedAt: now.AddDate(0, 0, -200)},
+			{Number: 2, State: "open", CreatedAt: now.AddDate(0, 0, -400), UpdatedAt: now.AddDate(0, 0, -60)},
+		}
+	}
+	gh.addRepo(&fakeRepo{Owner: "acme", Name: "core", Topics: []string{"tier-1"}, Issues: issues()})
+	gh.addRepo(&fakeRepo{Owner: "acme", Name: "lab", Topics: []string{"backlog-exempt", "backlog-stale-days-365"}, Issues: issues()})
+	gh.addRepo(&fakeRepo{Owner: "acme", Name: "old", Topics: []string{"backlog-opt-out"}, Issues: issues()})
+	gh.addRepo(&fakeRepo{Owner: "acme", Name: "docs", Topics: []string{"tier-1"}, Issues: issues(), Files: map[string]string{repoConfigPath: "staleDays: 90\n"}})
+
+	cfg := config{MinIssues: 1, StaleDays: 90, Topics: map[string]repoOverrides{"tier-1": {StaleDays: 30}}}
+	out := scan(t.Context(), selection{Orgs: []string{"acme"}}, []string{"core", "lab", "old", "docs"}, cfg)
+	byName := map[string]repoScore{}
+	for _, rs := range out.Repos {
+		byName[rs.Name] = rs
+	}
+	if _, ok := byName["old"]; ok || len(out.Repos) != 3 {
+		t.Errorf("backlog-opt-out repo reported: %d repos", len(out.Repos))
+	}
+	if rs := byName["core"]; rs.StaleCount != 2 || len(rs.Annotations) != 1 || rs.Annotations[0].Settings.StaleDays != 30 {
+		t.Errorf("core: stale %d, annotations %+v", rs.StaleCount, rs.Annotations)
+	}
+	if rs := byName["lab"]; rs.StaleCount != 0 || rs.Exemption == nil || len(rs.Annotations) != 2 {
+		t.Errorf("lab: stale %d, exemption %+v, annotations %+v", rs.StaleCount, rs.Exemption, rs.Annotations)
+	}
+	if rs := byName["docs"]; rs.StaleCount != 1 {
+		t.Errorf("docs: the repo file should beat its topics, stale %d", rs.StaleCount)
+	}
+}
@@ -0,0 +1,239 @@
+package main
+
+import (
+	"cmp"
+	"errors"
+	"flag"
+	"fmt"
+	"html"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+var svgDir = flag.String("svg-dir", "", "with trend, also write an SVG line chart of each repo's score history into this directory")
+
+// trendPoint is a repo's score in one stored run.
+type trendPoint struct {
+	Run         string    `json:"run"`
+	At          time.Time `json:"at"`
+	HealthScore int       `json:"healthScore"`
+	Status      string    `json:"status"`
+}
+
+// trendSeries is a repo's score history, oldest run first. Runs the repo
+// wasn't scored in have no point.
+type trendSeries struct {
+	Repo   string       `json:"repo"`
+	Points []trendPoint `json:"points"`
+}
+
+func (s trendSeries) last() trendPoint { return s.Points[len(s.Points)-1] }
+
+func (s trendSeries) change() int { return s.last().HealthScore - s.Points[0].HealthScore }
+
+// trend is the trend command's result: the runs it read, oldest first, and
+// each repo's series, worst latest score first.
+type trend struct {
+	Runs   []runInfo     `json:"runs"`
+	Series []trendSeries `json:"series"`
+}
+
+var errTrendUsage = errors.New("usage: fab-backlog trend [REPO] [-format table|json] [-svg-dir DIR]")
+
+// runTrend implements `fab-backlog trend [REPO]`.
+func runTrend(args []string, cfg config) int {
+	if len(args) > 1 {
+		return commandFailed(2, "trend failed", errTrendUsage)
+	}
+	if cfg.Storage == nil {
+		return commandFailed(2, "trend failed", errors.New("trend needs -history-file or storage in -config"))
+	}
+	repo := ""
+	if len(args) == 1 {
+		repo = args[0]
+	}
+	t, err := loadTrend(openStorage(cfg.Storage), repo)
+	if err != nil {
+		return commandFailed(1, "trend failed", err)
+	}
+	if repo != "" && len(t.Series) == 0 {
+		return commandFailed(1, "trend failed", fmt.Errorf("%s isn't in any stored run", repo))
+	}
+	if *svgDir != "" {
+		if err := writeTrendCharts(*svgDir, t.Series); err != nil {
+			return commandFailed(1, "trend failed", err)
+		}
+	}
+	if *formatFlag == "json" {
+		emitJSON(t)
+		return 0
+	}
+	fmt.Print(renderTrend(t, repo != ""))
+	return 0
+}
+
+// loadTrend reads every stored run into per-repo score histories. With a
+// repo, given as name or owner/name, only that repo's is kept.
+func loadTrend(store Storage, repo string) (trend, error) {
+	infos, err := store.ListRuns()
+	if err != nil {
+		return trend{}, err
+	}
+	slices.Reverse(infos)
+	t := trend{Runs: infos}
+	byRepo := map[string]*trendSeries{}
+	for _, info := range infos {
+		r, err := store.GetRun(info.ID)
+		if err != nil {
+			return trend{}, fmt.Errorf("run %s: %w", info.ID, err)
+		}
+		for _, rs := range r.Report.Repos {
+			if rs.Error != "" || rs.Status == unscored {
+				continue
+			}
+			if repo != "" && !strings.EqualFold(rs.Name, repo) && !strings.EqualFold(r.Report.Org+"/"+rs.Name, repo) {
+				continue
+			}
+			s := byRepo[rs.Name]
+			if s == nil {
+				s = &trendSeries{Repo: rs.Name}
+				byRepo[rs.Name] = s
+			}
+			s.Points = append(s.Points, trendPoint{Run: info.ID, At: info.GeneratedAt, HealthScore: rs.HealthScore, Status: rs.Status})
+		}
+	}
+	for _, s := range byRepo {
+		t.Series = append(t.Series, *s)
+	}
+	slices.SortFunc(t.Series, func(a, b trendSeries) int {
+		return cmp.Or(cmp.Compare(a.last().HealthScore, b.last().HealthScore), cmp.Compare(strings.ToLower(a.Repo), strings.ToLower(b.Repo)))
+	})
+	return t, nil
+}
+
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+
+// sparkline draws s with one character per run, on the fixed 0-100 scale
+// so repos compare at a glance. Runs the repo is missing from are blank.
+func sparkline(s trendSeries, runs []runInfo) string {
+	scores := map[string]int{}
+	for _, p := range s.Points {
+		scores[p.Run] = p.HealthScore
+	}
+	var b strings.Builder
+	for _, r := range runs {
+		score, ok := scores[r.ID]
+		if !ok {
+			b.WriteByte(' ')
+			continue
+		}
+		b.WriteRune(sparkBlocks[min(max(score, 0)*len(sparkBlocks)/101, len(sparkBlocks)-1)])
+	}
+	return b.String()
+}
+
+func signed(n int) string {
+	if n > 0 {
+		return fmt.Sprintf("+%d", n)
+	}
+	return fmt.Sprint(n)
+}
+
+// renderTrend renders t as an aligned table, one row per repo, with the
+// repo's run-by-run scores above it when detail is set.
+func renderTrend(t trend, detail bool) string {
+	var b strings.Builder
+	if detail && len(t.Series) == 1 {
+		rows := [][]string{{"RUN", "DATE", "SCORE", "STATUS"}}
+		for _, p := range t.Series[0].Points {
+			rows = append(rows, []string{p.Run, p.At.Format("2006-01-02"), fmt.Sprint(p.HealthScore), p.Status})
+		}
+		b.WriteString(alignRows(rows, []bool{false, false, true, false}, nil) + "\n")
+	}
+	rows := [][]string{{"REPO", "RUNS", "FIRST", "LAST", "CHANGE", "TREND"}}
+	for _, s := range t.Series {
+		rows = append(rows, []string{s.Repo, fmt.Sprint(len(s.Points)), fmt.Sprint(s.Points[0].HealthScore),
+			fmt.Sprint(s.last().HealthScore), signed(s.change()), sparkline(s, t.Runs)})
+	}
+	b.WriteString(alignRows(rows, []bool{false, true, true, true, true, false}, nil))
+	if len(t.Runs) > 0 {
+		fmt.Fprintf(&b, "\n%d runs, %s to %s\n", len(t.Runs),
+			t.Runs[0].GeneratedAt.Format("2006-01-02"), t.Runs[len(t.Runs)-1].GeneratedAt.Format("2006-01-02"))
+	}
+	return b.String()
+}
+
+// Chart geometry, in SVG user units.
+const (
+	chartWidth  = 320
+	chartHeight = 100
+	chartPad    = 6
+)
+
+// trendSVG draws s as a self-contained line chart on the 0-100 scale, with
+// the warning and critical thresholds as dashed guides, for embedding in
+// dashboards and READMEs.
+func trendSVG(s trendSeries) string {
+	y := func(score float64) float64 {
+		return chartPad + (100-score)/100*(chartHeight-2*chartPad)
+	}
+	first, span := s.Points[0].At, s.last().At.Sub(s.Points[0].At)
+	x := func(i int, at time.Time) float64 {
+		switch {
+		case len(s.Points) == 1:
+			return chartWidth / 2
+		case span <= 0:
+			return chartPad + float64(i)/float64(len(s.Points)-1)*(chartWidth-2*chartPad)
+		}
+		return chartPad + float64(at.Sub(first))/float64(span)*(chartWidth-2*chartPad)
+	}
+	points := make([]string, len(s.Points))
+	for i, p := range s.Points {
+		points[i] = fmt.Sprintf("%.1f,%.1f", x(i, p.At), y(float64(p.HealthScore)))
+	}
+	last := s.last()
+	color := map[string]string{"healthy": "#2da44e", "warning": "#bf8700", "critical": "#cf222e"}[last.Status]
+	if color == "" {
+		color = "#57606a"
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" role="img">`+"\n",
+		chartWidth, chartHeight, chartWidth, chartHeight)
+	fmt.Fprintf(&b, "<title>%s: health score %d (%s since %s)</title>\n",
+		html.EscapeString(s.Repo), last.HealthScore, signed(s.change()), s.Points[0].At.Format("2006-01-02"))
+	// The status thresholds: 70 and up is healthy, under 40 critical.
+	for _, t := range []float64{70, 40} {
+		fmt.Fprintf(&b, `<line x1="%d" y1="%.1f" x2="%d" y2="%.1f" stroke="#d0d7de" stroke-dasharray="4 3"/>`+"\n",
+			chartPad, y(t), chartWidth-chartPad, y(t))
+	}
+	fmt.Fprintf(&b, `<polyline points="%s" fill="none" stroke="%s" stroke-width="2" stroke-linejoin="round"/>`+"\n",
+		strings.Join(points, " "), color)
+	fmt.Fprintf(&b, `<circle cx="%.1f" cy="%.1f" r="3" fill="%s"/>`+"\n", x(len(s.Points)-1, last.At), y(float64(last.HealthScore)), color)
+	b.WriteString("</svg>\n")
+	return b.String()
+}
+
+// repoFile is the file a per-repo artifact is written to; owner/name
+// repos become owner_name.ext.
+func repoFile(repo, ext string) string {
+	return strings.ReplaceAll(repo, "/", "_") + ext
+}
+
+// writeTrendCharts writes one chart per series into dir, creating it.
+func writeTrendCharts(dir string, series []trendSeries) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	for _, s := range series {
+		path := filepath.Join(dir, repoFile(s.Repo, ".svg"))
+		if err := writeFileAtomic(path, []byte(trendSVG(s))); err != nil {
+			return err
+		}
+	}
+	slog.Info("trend charts written", "dir", dir, "charts", len(series))
+	return nil
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func trendStore(t *testing.T) Storage {
+	t.Helper()
+	s := &memoryStorage{}
+	day := time.Date(2025, 3, 1, 6, 0, 0, 0, time.UTC)
+	runs := [][]repoScore{
+		{{Name: "api", HealthScore: 80, Status: "healthy"}, {Name: "web", HealthScore: 50, Status: "warning"}},
+		{{Name: "api", HealthScore: 60, Status: "warning"}, {Name: "web", Error: "gh: HTTP 502"}},
+		{{Name: "api", HealthScore: 30, Status: "critical"}, {Name: "web", HealthScore: 100, Status: "healthy"}, {Name: "cli", Status: unscored}},
+	}
+	for i, repos := range runs {
+		at := day.AddDate(0, 0, 7*i)
+		if err := s.SaveRun(newRun(output{Org: "acme", Repos: repos}, &history{}, at)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return s
+}
+
+func TestLoadTrend(t *testing.T) {
+	tr, err := loadTrend(trendStore(t), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tr.Runs) != 3 || tr.Runs[0].ID != "20250301T060000Z" {
+		t.Fatalf("runs = %+v, want three, oldest first", tr.Runs)
+	}
+	if len(tr.Series) != 2 || tr.Series[0].Repo != "api" || tr.Series[1].Repo != "web" {
+		t.Fatalf("series = %+v, want api then web, worst latest score first, and no unscored cli", tr.Series)
+	}
+	api, web := tr.Series[0], tr.Series[1]
+	if len(api.Points) != 3 || api.change() != -50 || api.last().Status != "critical" {
+		t.Errorf("api = %+v", api)
+	}
+	if len(web.Points) != 2 || web.Points[1].Run != "20250315T060000Z" {
+		t.Errorf("web = %+v, want the failed run skipped", web)
+	}
+	if got := sparkline(api, tr.Runs); got != "▇▅▃" {
+		t.Errorf("api sparkline = %q", got)
+	}
+	if got := sparkline(web, tr.Runs); got != "▄ █" {
+		t.Errorf("web sparkline = %q, want a gap for the failed run", got)
+	}
+
+	one, err := loadTrend(trendStore(t), "ACME/web")
+	if err != nil || len(one.Series) != 1 || one.Series[0].Repo != "web" {
+		t.Errorf("trend for acme/web = %+v, %v", one.Series, err)
+	}
+}
+
+func TestRenderTrend(t *testing.T) {
+	tr, err := loadTrend(trendStore(t), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `REPO  RUNS  FIRST  LAST  CHANGE  TREND
+api      3     80    30     -50  ▇▅▃
+web      2     50   100     +50  ▄ █
+
+3 runs, 2025-03-01 to 2025-03-15
+`
+	if got := renderTrend(tr, false); got != want {
+		t.Errorf("table:\n%s\nwant:\n%s", got, want)
+	}
+	tr.Series = tr.Series[:1]
+	if got := renderTrend(tr, true); !strings.HasPrefix(got, "RUN               DATE        SCORE  STATUS\n20250301T060000Z  2025-03-01     80  healthy\n") {
+		t.Errorf("detail:\n%s", got)
+	}
+}
+
+func TestTrendCharts(t *testing.T) {
+	tr, err := loadTrend(trendStore(t), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tr.Series[1].Repo = "acme/web"
+	dir := filepath.Join(t.TempDir(), "charts")
+	if err := writeTrendCharts(dir, tr.Series); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "api.svg"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	svg := string(data)
+	for _, want := range []string{
+		`<svg xmlns="http://www.w3.org/2000/svg"`,
+		"<title>api: health score 30 (-50 since 2025-03-01)</title>",
+		`points="6.0,23.6 160.0,41.2 314.0,67.6"`, // evenly spaced weekly runs
+		`stroke="#cf222e"`, // critical now
+	} {
+		if !strings.Contains(svg, want) {
+			t.Errorf("chart lacks %q:\n%s", want, svg)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dir, "acme_web.svg")); err != nil {
+		t.Errorf("owner/name chart: %v", err)
+	}
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+)
+
+var uploadURL = flag.String("upload", "", "archive each report to object storage under this s3://bucket/prefix/ or gs://bucket/prefix/, in date-partitioned keys (needs the aws or gcloud CLI)")
+
+// validateUpload checks -upload names a bucket in a supported store.
+func validateUpload(cmd string) error {
+	if *uploadURL == "" {
+		return nil
+	}
+	if cmd != "scan" && cmd != "focus" {
+		return fmt.Errorf("-upload only applies to scan and focus")
+	}
+	scheme, rest, ok := strings.Cut(*uploadURL, "://")
+	if !ok || scheme != "s3" && scheme != "gs" {
+		return fmt.Errorf("-upload must be an s3:// or gs:// URL, got %q", *uploadURL)
+	}
+	if bucket, _, _ := strings.Cut(rest, "/"); bucket == "" {
+		return fmt.Errorf("-upload %s: bucket required", *uploadURL)
+	}
+	return nil
+}
+
+// uploadKey is where a report from at is archived under base: a
+// dt=YYYY-MM-DD partition, as Hive, Athena and BigQuery expect, holding the
+// same timestamped name -output-dir uses.
+func uploadKey(base string, at time.Time) string {
+	if !strings.HasSuffix(base, "/") {
+		base += "/"
+	}
+	return base + "dt=" + at.UTC().Format("2006-01-02") + "/" + artifactName(at)
+}
+
+// uploadCommand is the CLI invocation copying path to url with the given
+// content type.
+func uploadCommand(path, url, contentType string) (string, []string) {
+	if strings.HasPrefix(url, "gs://") {
+		return "gcloud", []string{"storage", "cp", "--content-type=" + contentType, path, url}
+	}
+	return "aws", []string{"s3", "cp", "--content-type", contentType, "--only-show-errors", path, url}
+}
+
+// uploadReport archives out as JSON under -upload, returning the URL.
+func uploadReport(run func(string, ...string) ([]byte, error), base string, out output, at time.Time) (string, error) {
+	var buf bytes.Buffer
+	if err := writeJSON(&buf, out); err != nil {
+		return "", err
+	}
+	path, err := tempFile("fab-backlog-upload-*.json", buf.Bytes())
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(path)
+	url := uploadKey(base, at)
+	bin, args := uploadCommand(path, url, "application/json")
+	if _, err := run(bin, args...); err != nil {
+		return "", err
+	}
+	return url, nil
+}
+
+// archiveReport uploads the report when -upload is set.
+func archiveReport(out output, at time.Time) error {
+	if *uploadURL == "" {
+		return nil
+	}
+	url, err := uploadReport(runBackground, *uploadURL, out, at)
+	if err != nil {
+		return err
+	}
+	slog.Info("report uploaded", "url", url)
+	return nil
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestUploadReport(t *testing.T) {
+	at := time.Date(2025, 6, 1, 6, 30, 0, 0, time.UTC)
+	for _, tc := range []struct {
+		base, bin, want string
+		flags           []string
+	}{
+		{"s3://lake/backlog/", "aws", "s3://lake/backlog/dt=2025-06-01/fab-backlog-20250601T063000Z.json", []string{"s3", "cp", "--content-type", "application/json"}},
+		{"gs://lake/backlog", "gcloud", "gs://lake/backlog/dt=2025-06-01/fab-backlog-20250601T063000Z.json", []string{"storage", "cp", "--content-type=application/json"}},
+	} {
+		var got []string
+		var sent output
+		run := func(bin string, args ...string) ([]byte, error) {
+			got = append([]string{bin}, args...)
+			data, err := os.ReadFile(args[len(args)-2])
+			if err != nil {
+				t.Fatal(err)
+			}
+			return nil, json.Unmarshal(data, &sent)
+		}
+		url, err := uploadReport(run, tc.base, sampleReport(), at)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if url != tc.want || got[0] != tc.bin || got[len(got)-1] != tc.want || !strings.HasPrefix(strings.Join(got[1:], " "), strings.Join(tc.flags, " ")) {
+			t.Errorf("%s: url %s, command %v", tc.base, url, got)
+		}
+		if len(sent.Repos) == 0 {
+			t.Errorf("%s: uploaded %+v", tc.base, sent)
+		}
+		if _, err := os.Stat(got[len(got)-2]); !os.IsNotExist(err) {
+			t.Errorf("%s: temp file should be removed", tc.base)
+		}
+	}
+}
+
+func TestValidateUpload(t *testing.T) {
+	prev := *uploadURL
+	t.Cleanup(func() { *uploadURL = prev })
+	for url, want := range map[string]string{
+		"s3://lake/backlog/": "",
+		"gs://lake":          "",
+		"https://lake/":      "must be an s3:// or gs:// URL",
+		"s3:///backlog/":     "bucket required",
+	} {
+		*uploadURL = url
+		err := validateUpload("scan")
+		if want == "" && err != nil || want != "" && (err == nil || !strings.Contains(err.Error(), want)) {
+			t.Errorf("%s: err = %v, want %q", url, err, want)
+		}
+	}
+	*uploadURL = "s3://lake/"
+	if err := validateUpload("report"); err == nil {
+		t.Error("-upload with report should fail")
+	}
+}
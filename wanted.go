@@ -0,0 +1,67 @@
+package main
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/misty-step/fab-backlog/pkg/score"
+)
+
+type reactionGroup = score.ReactionGroup
+
+// wantedIssue is an entry in the org-wide mostWanted list.
+type wantedIssue struct {
+	Repo     string  `json:"repo"`
+	Number   int     `json:"number"`
+	Title    string  `json:"title"`
+	ThumbsUp int     `json:"thumbsUp"`
+	AgeDays  int     `json:"ageDays"`
+	Priority float64 `json:"priority"`
+}
+
+func thumbsUp(is issue) int {
+	for _, g := range is.ReactionGroups {
+		if g.Content == "THUMBS_UP" {
+			return g.Users.TotalCount
+		}
+	}
+	return 0
+}
+
+// wantedPriority weights 👍 count by age: an issue people have wanted for a
+// year ranks twice as high as an equally popular one filed today.
+func wantedPriority(thumbs, ageDays int) float64 {
+	p := float64(thumbs) * (1 + float64(ageDays)/365)
+	return math.Round(p*100) / 100
+}
+
+// mostWanted ranks open issues with at least one 👍 across every scored
+// repo and returns the top n.
+func mostWanted(repos []repoScore, n int, now time.Time) []wantedIssue {
+	if n <= 0 {
+		return nil
+	}
+	var out []wantedIssue
+	for _, rs := range repos {
+		out = append(out, rs.wanted...)
+		for _, is := range rs.issues {
+			t := thumbsUp(is)
+			if t == 0 {
+				continue
+			}
+			age := daysBetween(is.CreatedAt, now)
+			out = append(out, wantedIssue{Repo: rs.Name, Number: is.Number, Title: is.Title, ThumbsUp: t, AgeDays: age, Priority: wantedPriority(t, age)})
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		if out[i].Priority != out[j].Priority {
+			return out[i].Priority > out[j].Priority
+		}
+		return out[i].AgeDays > out[j].AgeDays
+	})
+	if len(out) > n {
+		out = out[:n]
+	}
+	return out
+}
@@ -0,0 +1,33 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func withThumbs(n int) []reactionGroup {
+	g := reactionGroup{Content: "THUMBS_UP"}
+	g.Users.TotalCount = n
+	return []reactionGroup{{Content: "HEART"}, g}
+}
+
+func TestMostWanted(t *testing.T) {
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	repos := []repoScore{
+		{Name: "a", issues: []issue{
+			{Number: 1, CreatedAt: now, ReactionGroups: withThumbs(10)},
+			{Number: 2, CreatedAt: now.AddDate(0, 0, -365), ReactionGroups: withThumbs(6)},
+			{Number: 3, CreatedAt: now.AddDate(-5, 0, 0)},
+		}},
+		{Name: "b", issues: []issue{
+			{Number: 7, CreatedAt: now.AddDate(0, 0, -730), ReactionGroups: withThumbs(1)},
+		}},
+	}
+	got := mostWanted(repos, 2, now)
+	if len(got) != 2 || got[0].Number != 2 || got[0].Priority != 12 || got[1].Number != 1 {
+		t.Errorf("mostWanted = %+v, want #2 (priority 12) then #1", got)
+	}
+	if mostWanted(repos, 0, now) != nil {
+		t.Error("n=0 should disable the list")
+	}
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"flag"
+	"os"
+)
+
+var (
+	webhookURL        = flag.String("webhook", "", "URL to POST the full JSON report to")
+	webhookSecretFlag = flag.String("webhook-secret", "", "HMAC-SHA256 key for signing webhook bodies; $FAB_BACKLOG_WEBHOOK_SECRET, which keeps it out of process lists, takes precedence")
+)
+
+// signatureHeader mirrors GitHub's own webhook signature header so receivers
+// can reuse their verification code.
+const signatureHeader = "X-Fab-Backlog-Signature-256"
+
+// webhookSecret prefers the environment so the key stays out of process lists.
+func webhookSecret() string {
+	if s := os.Getenv("FAB_BACKLOG_WEBHOOK_SECRET"); s != "" {
+		return s
+	}
+	return *webhookSecretFlag
+}
+
+func sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// postWebhook POSTs the report exactly as it is written to stdout, signed
+// when a secret is set.
+func postWebhook(url, secret string, out output) error {
+	var buf bytes.Buffer
+	if err := writeJSON(&buf, out); err != nil {
+		return err
+	}
+	headers := map[string]string{}
+	if secret != "" {
+		headers[signatureHeader] = sign(secret, buf.Bytes())
+	}
+	return postBody(url, buf.Bytes(), headers)
+}
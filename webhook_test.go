@@ -0,0 +1,51 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestPostWebhookSigned(t *testing.T) {
+	var body []byte
+	var sig string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ = io.ReadAll(r.Body)
+		sig = r.Header.Get(signatureHeader)
+	}))
+	defer srv.Close()
+
+	if err := postWebhook(srv.URL, "s3cret", sampleReport()); err != nil {
+		t.Fatal(err)
+	}
+	if sig == "" || sig != sign("s3cret", body) {
+		t.Errorf("signature %q does not verify against the body", sig)
+	}
+	var got output
+	if err := json.Unmarshal(body, &got); err != nil || got.Org != "misty-step" || len(got.Repos) != 4 {
+		t.Errorf("body should be the full report: %s (err %v)", body, err)
+	}
+
+	if err := postWebhook(srv.URL, "", sampleReport()); err != nil {
+		t.Fatal(err)
+	}
+	if sig != "" {
+		t.Errorf("unsigned delivery sent signature %q", sig)
+	}
+}
+
+func TestWebhookSecretPrefersEnvironment(t *testing.T) {
+	prev := *webhookSecretFlag
+	t.Cleanup(func() { *webhookSecretFlag = prev })
+	*webhookSecretFlag = "from-flag"
+	t.Setenv("FAB_BACKLOG_WEBHOOK_SECRET", "")
+	if got := webhookSecret(); got != "from-flag" {
+		t.Errorf("flag only: %q", got)
+	}
+	t.Setenv("FAB_BACKLOG_WEBHOOK_SECRET", "from-env")
+	if got := webhookSecret(); got != "from-env" {
+		t.Errorf("env and flag: %q, want the environment's", got)
+	}
+}